@@ -15,7 +15,6 @@ import (
 var _ = Describe("Send a notification to all users of UAA", func() {
 	It("sends an email notification to all users of UAA", func() {
 		var templateID string
-		indexedResponses := map[string]support.NotifyResponse{}
 		clientID := "notifications-sender"
 		clientToken := GetClientTokenFor(clientID)
 		client := support.NewClient(Servers.Notifications.URL())
@@ -54,7 +53,7 @@ var _ = Describe("Send a notification to all users of UAA", func() {
 		})
 
 		By("sending a notification to all users", func() {
-			status, responses, err := client.Notify.AllUsers(clientToken.Access, support.Notify{
+			status, campaign, err := client.Notify.AllUsers(clientToken.Access, support.Notify{
 				KindID:  "acceptance-test",
 				HTML:    "<p>this is an acceptance-test</p>",
 				Text:    "oh no!",
@@ -62,23 +61,9 @@ var _ = Describe("Send a notification to all users of UAA", func() {
 			})
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-
-			Expect(responses).To(HaveLen(2))
-
-			for _, resp := range responses {
-				indexedResponses[resp.Recipient] = resp
-			}
-
-			responseItem := indexedResponses["091b6583-0933-4d17-a5b6-66e54666c88e"]
-			Expect(responseItem.Recipient).To(Equal("091b6583-0933-4d17-a5b6-66e54666c88e"))
-			Expect(responseItem.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(responseItem.NotificationID)).To(BeTrue())
-
-			responseItem = indexedResponses["943e6076-b1a5-4404-811b-a1ee9253bf56"]
-			Expect(responseItem.Recipient).To(Equal("943e6076-b1a5-4404-811b-a1ee9253bf56"))
-			Expect(responseItem.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(responseItem.NotificationID)).To(BeTrue())
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(campaign.Status).To(Equal("expanding"))
+			Expect(GUIDRegex.MatchString(campaign.CampaignID)).To(BeTrue())
 		})
 
 		By("confirming the messages were sent", func() {
@@ -102,7 +87,7 @@ var _ = Describe("Send a notification to all users of UAA", func() {
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + indexedResponses["091b6583-0933-4d17-a5b6-66e54666c88e"].NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp("^X-CF-Notification-ID: " + GUIDRegex.String())))
 			Expect(data).To(ContainElement("Subject: Genetics gone awry"))
 			Expect(data).To(ContainElement("\t\t<h1>T-Rex</h1><p>this is an acceptance-test</p><b>This message was sent to="))
 			Expect(data).To(ContainElement(" everyone.</b>"))
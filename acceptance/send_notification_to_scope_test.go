@@ -15,7 +15,6 @@ import (
 var _ = Describe("Sending notifications to users with certain scopes", func() {
 	It("sends a notification to each user with the scope", func() {
 		var templateID string
-		indexedResponses := map[string]support.NotifyResponse{}
 
 		client := support.NewClient(Servers.Notifications.URL())
 		clientID := "notifications-sender"
@@ -57,7 +56,7 @@ var _ = Describe("Sending notifications to users with certain scopes", func() {
 		})
 
 		By("sending a notification to all users with a UAA scope", func() {
-			status, responses, err := client.Notify.Scope(clientToken.Access, scope, support.Notify{
+			status, campaign, err := client.Notify.Scope(clientToken.Access, scope, support.Notify{
 				KindID:  "scope-test",
 				HTML:    "this is a scope test",
 				Text:    "this is a scope test",
@@ -65,20 +64,12 @@ var _ = Describe("Sending notifications to users with certain scopes", func() {
 			})
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(1))
-
-			for _, response := range responses {
-				indexedResponses[response.Recipient] = response
-			}
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(campaign.Status).To(Equal("expanding"))
+			Expect(GUIDRegex.MatchString(campaign.CampaignID)).To(BeTrue())
 		})
 
 		By("confirming that the messages were delivered", func() {
-			response := indexedResponses["user-369"]
-			Expect(response.Recipient).To(Equal("user-369"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
-
 			Eventually(func() int {
 				return len(Servers.SMTP.Deliveries)
 			}, 1*time.Second).Should(Equal(1))
@@ -90,7 +81,7 @@ var _ = Describe("Sending notifications to users with certain scopes", func() {
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + indexedResponses["user-369"].NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp("^X-CF-Notification-ID: " + GUIDRegex.String())))
 			Expect(data).To(ContainElement("Subject: Food scope-subject"))
 			Expect(data).To(ContainElement("\t\t<h1>Fish</h1>this is a scope test<b>You received this message because you ="))
 			Expect(data).To(ContainElement("have the this.scope scope.</b>"))
@@ -19,7 +19,6 @@ var _ = Describe("Sending notifications to all users in a space", func() {
 		clientID := "notifications-sender"
 		clientToken := GetClientTokenFor(clientID)
 		spaceID := "space-123"
-		indexedResponses := map[string]support.NotifyResponse{}
 
 		By("registering a client with a notification", func() {
 			status, err := client.Notifications.Register(clientToken.Access, support.RegisterClient{
@@ -56,7 +55,7 @@ var _ = Describe("Sending notifications to all users in a space", func() {
 		})
 
 		By("sending a notification to the users of a space", func() {
-			status, responses, err := client.Notify.Space(clientToken.Access, spaceID, support.Notify{
+			status, campaign, err := client.Notify.Space(clientToken.Access, spaceID, support.Notify{
 				KindID:  "space-test",
 				HTML:    "this is a space test",
 				Text:    "this is a space test",
@@ -64,27 +63,9 @@ var _ = Describe("Sending notifications to all users in a space", func() {
 			})
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(3))
-
-			for _, response := range responses {
-				indexedResponses[response.Recipient] = response
-			}
-
-			response := indexedResponses["user-456"]
-			Expect(response.Recipient).To(Equal("user-456"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
-
-			response = indexedResponses["user-789"]
-			Expect(response.Recipient).To(Equal("user-789"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
-
-			response = indexedResponses["user-000"]
-			Expect(response.Recipient).To(Equal("user-000"))
-			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(campaign.Status).To(Equal("expanding"))
+			Expect(GUIDRegex.MatchString(campaign.CampaignID)).To(BeTrue())
 		})
 
 		By("confirming the messages were sent", func() {
@@ -99,7 +80,7 @@ var _ = Describe("Sending notifications to all users in a space", func() {
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + indexedResponses["user-456"].NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp("^X-CF-Notification-ID: " + GUIDRegex.String())))
 			Expect(data).To(ContainElement("Subject: Aliens space-subject"))
 			Expect(data).To(ContainElement("\t\t<h1>Dogs</h1>this is a space test<h2>You received this message because you="))
 			Expect(data).To(ContainElement(" belong to the notifications-service space in the notifications-service orga="))
@@ -9,6 +9,8 @@ import (
 	"net/http/httptest"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,7 +29,6 @@ func NewUAA() UAA {
 	router.HandleFunc("/token_key", UAAGetTokenKey).Methods("GET")
 	router.Path("/Users").Queries("attributes", "").Handler(UAAGetUser).Methods("GET")
 	router.HandleFunc("/Users", UAAGetUsers).Methods("GET")
-	router.HandleFunc("/Groups", UAAGetUsersByScope).Methods("GET")
 	router.HandleFunc("/{anything:.*}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		fmt.Printf("UAA ROUTE REQUEST ---> %+v\n", req)
 		w.WriteHeader(http.StatusTeapot)
@@ -115,6 +116,11 @@ var UAAGetUser = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request)
 	}
 
 	filter := req.Form.Get("filter")
+	if !strings.Contains(filter, "Id eq") {
+		UAAGetUsers.ServeHTTP(w, req)
+		return
+	}
+
 	filterParts := strings.Split(filter, " or ")
 	queryRegexp := regexp.MustCompile(`Id eq "(.*)"`)
 	resources := []interface{}{}
@@ -142,44 +148,45 @@ var UAAGetUser = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request)
 })
 
 var UAAGetUsers = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-	response, err := json.Marshal(map[string]interface{}{
-		"resources":    allUsersResponse,
-		"startIndex":   1,
-		"itemsPerPage": 100,
-		"totalResults": 1,
-		"schemas":      []string{"urn:scim:schemas:core:1.0"},
-	})
+	err := req.ParseForm()
 	if err != nil {
 		panic(err)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write(response)
-})
+	filter := req.Form.Get("filter")
+	users := allUsersResponse
+	if matches := regexp.MustCompile(`groups.display eq "([^"]*)"`).FindStringSubmatch(filter); matches != nil {
+		users = usersInGroup(matches[1])
+	}
 
-var UAAGetUsersByScope = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-	err := req.ParseForm()
+	after := ""
+	if matches := regexp.MustCompile(`id gt "([^"]*)"`).FindStringSubmatch(filter); matches != nil {
+		after = matches[1]
+	}
+
+	count, err := strconv.Atoi(req.Form.Get("count"))
 	if err != nil {
-		panic(err)
+		count = 100
 	}
 
-	attribute := req.Form.Get("attributes")
-	if attribute != "members" {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte(`{"errors": "attribute not found"}`))
+	var ids []string
+	for _, user := range users {
+		if id := user["id"].(string); id > after {
+			ids = append(ids, id)
+		}
 	}
+	sort.Strings(ids)
 
-	filter := req.Form.Get("filter")
-	queryRegexp := regexp.MustCompile(`displayName eq "(.*)"`)
-	matches := queryRegexp.FindAllStringSubmatch(filter, 1)
-	match := matches[0]
-	resources := UAAUsersByScope[match[1]]
+	resources := []interface{}{}
+	for i := 0; i < len(ids) && i < count; i++ {
+		resources = append(resources, UAAUsers[ids[i]])
+	}
 
 	response, err := json.Marshal(map[string]interface{}{
 		"resources":    resources,
 		"startIndex":   1,
-		"itemsPerPage": 100,
-		"totalResults": 1,
+		"itemsPerPage": count,
+		"totalResults": len(ids),
 		"schemas":      []string{"urn:scim:schemas:core:1.0"},
 	})
 	if err != nil {
@@ -190,18 +197,17 @@ var UAAGetUsersByScope = http.HandlerFunc(func(w http.ResponseWriter, req *http.
 	w.Write(response)
 })
 
-var UAAUsersByScope = map[string]interface{}{
-	"this.scope": []map[string]interface{}{
-		{
-			"members": []map[string]string{
-				{
-					"origin": "uaa",
-					"type":   "user",
-					"value":  "user-369",
-				},
-			},
-		},
-	},
+func usersInGroup(display string) []map[string]interface{} {
+	var users []map[string]interface{}
+	for _, user := range UAAUsers {
+		for _, group := range user["groups"].([]map[string]string) {
+			if group["display"] == display {
+				users = append(users, user)
+			}
+		}
+	}
+
+	return users
 }
 
 var UAAUsers = map[string]map[string]interface{}{
@@ -48,6 +48,12 @@ type NotifyResponse struct {
 	Status         string `json:"status"`
 	Recipient      string `json:"recipient"`
 	NotificationID string `json:"notification_id"`
+	BatchID        string `json:"batch_id"`
+}
+
+type CampaignResponse struct {
+	CampaignID string `json:"campaign_id"`
+	Status     string `json:"status"`
 }
 
 type Message struct {
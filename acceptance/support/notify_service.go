@@ -53,12 +53,36 @@ func (s NotifyService) notify(token, path string, notify Notify, reqBody notifyR
 	return status, responses, nil
 }
 
+func (s NotifyService) launch(token, path string, notify Notify, reqBody notifyRequest) (int, CampaignResponse, error) {
+	var response CampaignResponse
+
+	reqBody = reqBody.Merge(notify)
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return 0, response, err
+	}
+
+	status, responseBody, err := s.client.makeRequest("POST", path, bytes.NewBuffer(body), token)
+	if err != nil {
+		return 0, response, err
+	}
+
+	if status == http.StatusAccepted {
+		err = json.NewDecoder(responseBody).Decode(&response)
+		if err != nil {
+			return 0, response, err
+		}
+	}
+
+	return status, response, nil
+}
+
 func (s NotifyService) User(token, userGUID string, notify Notify) (int, []NotifyResponse, error) {
 	return s.notify(token, s.client.UsersPath(userGUID), notify, notifyRequest{})
 }
 
-func (s NotifyService) AllUsers(token string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.EveryonePath(), notify, notifyRequest{})
+func (s NotifyService) AllUsers(token string, notify Notify) (int, CampaignResponse, error) {
+	return s.launch(token, s.client.EveryonePath(), notify, notifyRequest{})
 }
 
 func (s NotifyService) Email(token, email string, notify Notify) (int, []NotifyResponse, error) {
@@ -67,20 +91,20 @@ func (s NotifyService) Email(token, email string, notify Notify) (int, []NotifyR
 	})
 }
 
-func (s NotifyService) OrganizationRole(token, organizationGUID, role string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{
+func (s NotifyService) OrganizationRole(token, organizationGUID, role string, notify Notify) (int, CampaignResponse, error) {
+	return s.launch(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{
 		Role: role,
 	})
 }
 
-func (s NotifyService) Organization(token, organizationGUID string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{})
+func (s NotifyService) Organization(token, organizationGUID string, notify Notify) (int, CampaignResponse, error) {
+	return s.launch(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{})
 }
 
-func (s NotifyService) Scope(token, scope string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.ScopesPath(scope), notify, notifyRequest{})
+func (s NotifyService) Scope(token, scope string, notify Notify) (int, CampaignResponse, error) {
+	return s.launch(token, s.client.ScopesPath(scope), notify, notifyRequest{})
 }
 
-func (s NotifyService) Space(token, spaceGUID string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{})
+func (s NotifyService) Space(token, spaceGUID string, notify Notify) (int, CampaignResponse, error) {
+	return s.launch(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{})
 }
@@ -29,8 +29,8 @@ var _ = Describe("Default Template", func() {
 		Expect(template).To(Equal(support.Template{
 			Name:     "Default Template",
 			Subject:  "CF Notification: {{.Subject}}",
-			HTML:     "<p>{{.Endorsement}}</p>{{.HTML}}",
-			Text:     "{{.Endorsement}}\n{{.Text}}",
+			HTML:     `<p>{{.Endorsement}}</p>{{.HTML}}{{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>{{end}}`,
+			Text:     "{{.Endorsement}}\n{{.Text}}{{if .UnsubscribeURL}}\n\nUnsubscribe: {{.UnsubscribeURL}}{{end}}",
 			Metadata: map[string]interface{}{},
 		}))
 	})
@@ -4,6 +4,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/web"
 	"github.com/pivotal-cf/uaa-sso-golang/uaa"
@@ -35,9 +36,9 @@ func (app Application) Boot() {
 	app.RetrieveUAAPublicKey()
 	app.migrator.Migrate()
 	app.EnableDBLogging()
-	app.UnlockJobs()
 	app.StartWorkers()
 	app.StartMessageGC()
+	app.StartBounceServer()
 	app.StartServer()
 }
 
@@ -69,12 +70,15 @@ func (app Application) ConfigureSMTP() {
 
 	mailClient.Quit()
 
-	if !startTLSSupported && app.env.SMTPTLS {
-		logger.Panicln(`SMTP TLS configuration mismatch: Configured to use TLS over SMTP, but the mail server does not support the "STARTTLS" extension.`)
-	}
-
-	if startTLSSupported && !app.env.SMTPTLS {
-		logger.Panicln(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension.`)
+	switch app.env.SMTPTLSMode {
+	case SMTPTLSModeStartTLS:
+		if !startTLSSupported {
+			logger.Panicln(`SMTP TLS configuration mismatch: Configured to use STARTTLS over SMTP, but the mail server does not support the "STARTTLS" extension. Set SMTP_TLS_MODE to "implicit" if the server expects TLS from the start.`)
+		}
+	case SMTPTLSModeNone:
+		if startTLSSupported {
+			logger.Println(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension. Mail will be sent unencrypted.`)
+		}
 	}
 }
 
@@ -90,10 +94,6 @@ func (app Application) RetrieveUAAPublicKey() {
 	log.Printf("UAA Public Key: %s", UAAPublicKey)
 }
 
-func (app Application) UnlockJobs() {
-	app.mother.Queue().Unlock()
-}
-
 func (app Application) EnableDBLogging() {
 	if app.env.DBLoggingEnabled {
 		app.mother.Database().TraceOn("[DB]", app.mother.Logger())
@@ -101,24 +101,63 @@ func (app Application) EnableDBLogging() {
 }
 
 func (app Application) StartWorkers() {
+	mailRouter := app.mother.MailRouter(WorkerCount)
 	for i := 0; i < WorkerCount; i++ {
-		worker := postal.NewDeliveryWorker(i+1, app.mother.Logger(), app.mother.MailClient(), app.mother.Queue(),
-			app.mother.GlobalUnsubscribesRepo(), app.mother.UnsubscribesRepo(), app.mother.KindsRepo(), app.mother.MessagesRepo(),
-			app.mother.Database(), app.env.Sender, app.env.EncryptionKey, app.mother.UserLoader(), app.mother.TemplatesLoader(), app.mother.ReceiptsRepo(), app.mother.TokenLoader())
+		worker := postal.NewDeliveryWorker(i+1, app.mother.Logger(), mailRouter, app.mother.Queue(),
+			app.mother.GlobalUnsubscribesRepo(), app.mother.UnsubscribesRepo(), app.mother.SuppressionsRepo(), app.mother.DeliveryAddressesRepo(), app.mother.KindsRepo(), app.mother.MessagesRepo(), app.mother.MessageEventsRepo(),
+			app.mother.Database(), app.env.Sender, app.env.BounceDomain, app.env.EncryptionKey, postal.UnsubscribeConfig{PublicURL: app.env.PublicURL, Mailto: app.env.UnsubscribeMailto}, app.mother.UserLoader(), app.mother.TemplatesLoader(), app.mother.ReceiptsRepo(), app.mother.TokenLoader(), app.mother.CampaignExpander())
 		worker.Work()
 	}
 }
 
 func (app Application) StartMessageGC() {
-	messageLifetime := 24 * time.Hour
+	retention := postal.MessageRetention{
+		Lifetime:        time.Duration(app.env.MessageLifetime) * time.Minute,
+		ClientLifetimes: map[string]time.Duration{},
+	}
+	for clientID, lifetime := range app.env.ClientMessageLifetimes {
+		retention.ClientLifetimes[clientID] = time.Duration(lifetime) * time.Minute
+	}
+
+	var archiver postal.MessageArchiverInterface
+	if app.env.MessageArchivePath != "" {
+		archiver = postal.NewNDJSONArchiver(app.env.MessageArchivePath)
+	}
+
 	db := app.mother.Database()
 	messagesRepo := app.mother.MessagesRepo()
-	pollingInterval := 1 * time.Hour
+	messageEventsRepo := app.mother.MessageEventsRepo()
+	pollingInterval := time.Duration(app.env.MessageGCInterval) * time.Minute
 	logger := app.mother.Logger()
-	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
+	messageGC := postal.NewMessageGC(retention, db, messagesRepo, messageEventsRepo, archiver, pollingInterval, logger)
 	messageGC.Run()
 }
 
+// StartBounceServer listens for the bounces of mail sent with a VERP return
+// path in BOUNCE_DOMAIN, and for the unsubscribe requests mailed to
+// UNSUBSCRIBE_MAILTO. Mail for that domain must be routed to BOUNCE_PORT.
+func (app Application) StartBounceServer() {
+	if app.env.BounceDomain == "" {
+		return
+	}
+
+	logger := app.mother.Logger()
+	server := mail.NewBounceServer(mail.BounceServerConfig{
+		Domain:             app.env.BounceDomain,
+		Key:                app.env.EncryptionKey,
+		UnsubscribeAddress: app.env.UnsubscribeMailto,
+		Hostname:           app.env.BounceDomain,
+	}, app.mother.BounceProcessor(), app.mother.MailUnsubscriber(), logger)
+
+	go func() {
+		logger.Printf("Listening for bounces to %s on port %s", app.env.BounceDomain, app.env.BouncePort)
+		err := server.ListenAndServe(":" + app.env.BouncePort)
+		if err != nil {
+			logger.Panicln(err)
+		}
+	}()
+}
+
 func (app Application) StartServer() {
 	web.NewServer().Run(app.env.Port, app.mother)
 }
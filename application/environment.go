@@ -1,6 +1,7 @@
 package application
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"path"
@@ -13,37 +14,102 @@ const (
 	SMTPAuthNone    = "none"
 	SMTPAuthPlain   = "plain"
 	SMTPAuthCRAMMD5 = "cram-md5"
+	SMTPAuthLogin   = "login"
+	SMTPAuthXOAUTH2 = "xoauth2"
 )
 
-var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5}
+var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5, SMTPAuthLogin, SMTPAuthXOAUTH2}
+
+const (
+	SMTPTLSModeStartTLS = "starttls"
+	SMTPTLSModeImplicit = "implicit"
+	SMTPTLSModeNone     = "none"
+)
+
+var SMTPTLSModes = []string{SMTPTLSModeStartTLS, SMTPTLSModeImplicit, SMTPTLSModeNone}
 
 var UAAPublicKey string
 
+// SMTPRelay describes an SMTP relay, in addition to the one configured by the
+// SMTP_* variables, that mail can fail over to.
+type SMTPRelay struct {
+	Name              string `json:"name"`
+	Host              string `json:"host"`
+	Port              string `json:"port"`
+	User              string `json:"user"`
+	Pass              string `json:"pass"`
+	CRAMMD5Secret     string `json:"crammd5_secret"`
+	AuthMechanism     string `json:"auth_mechanism"`
+	TLSMode           string `json:"tls_mode"`
+	CACertificates    string `json:"ca_certificates"`
+	ClientCertificate string `json:"client_certificate"`
+	ClientKey         string `json:"client_key"`
+	OAuthTokenURL     string `json:"oauth_token_url"`
+	OAuthClientID     string `json:"oauth_client_id"`
+	OAuthClientSecret string `json:"oauth_client_secret"`
+	OAuthRefreshToken string `json:"oauth_refresh_token"`
+	OAuthScope        string `json:"oauth_scope"`
+	Priority          int    `json:"priority"`
+	Weight            int    `json:"weight"`
+}
+
 type Environment struct {
-	CCHost                string `env:"CC_HOST"                     env-required:"true"`
-	CORSOrigin            string `env:"CORS_ORIGIN"                 env-default:"*"`
-	DBLoggingEnabled      bool   `env:"DB_LOGGING_ENABLED"`
-	DatabaseURL           string `env:"DATABASE_URL"                env-required:"true"`
-	EncryptionKey         []byte `env:"ENCRYPTION_KEY"              env-required:"true"`
-	GobbleWaitMaxDuration int    `env:"GOBBLE_WAIT_MAX_DURATION"    env-default:"5000"`
-	ModelMigrationsDir    string
-	Port                  string `env:"PORT"                        env-default:"3000"`
-	RootPath              string `env:"ROOT_PATH"`
-	SMTPAuthMechanism     string `env:"SMTP_AUTH_MECHANISM"         env-required:"true"`
-	SMTPCRAMMD5Secret     string `env:"SMTP_CRAMMD5_SECRET"`
-	SMTPHost              string `env:"SMTP_HOST"                   env-required:"true"`
-	SMTPLoggingEnabled    bool   `env:"SMTP_LOGGING_ENABLED"        env-default:"false"`
-	SMTPPass              string `env:"SMTP_PASS"`
-	SMTPPort              string `env:"SMTP_PORT"                   env-required:"true"`
-	SMTPTLS               bool   `env:"SMTP_TLS"                    env-default:"true"`
-	SMTPUser              string `env:"SMTP_USER"`
-	Sender                string `env:"SENDER"                      env-required:"true"`
-	TestMode              bool   `env:"TEST_MODE"                   env-default:"false"`
-	UAAClientID           string `env:"UAA_CLIENT_ID"               env-required:"true"`
-	UAAClientSecret       string `env:"UAA_CLIENT_SECRET"           env-required:"true"`
-	UAAHost               string `env:"UAA_HOST"                    env-required:"true"`
-	VerifySSL             bool   `env:"VERIFY_SSL"                  env-default:"true"`
-	VCAPApplication       struct {
+	BounceDomain             string `env:"BOUNCE_DOMAIN"`
+	BouncePort               string `env:"BOUNCE_PORT"                 env-default:"2525"`
+	BulkEmailLimit           int    `env:"BULK_EMAIL_LIMIT"            env-default:"1000"`
+	CCHost                   string `env:"CC_HOST"                     env-required:"true"`
+	CORSOrigin               string `env:"CORS_ORIGIN"                 env-default:"*"`
+	DKIMDomain               string `env:"DKIM_DOMAIN"`
+	DKIMHeaderList           string `env:"DKIM_HEADERS"`
+	DKIMHeaders              []string
+	DKIMPrivateKey           string `env:"DKIM_PRIVATE_KEY"`
+	DKIMSelector             string `env:"DKIM_SELECTOR"`
+	ClientMessageLifetimes   map[string]int
+	DBLoggingEnabled         bool   `env:"DB_LOGGING_ENABLED"`
+	DatabaseURL              string `env:"DATABASE_URL"                env-required:"true"`
+	EncryptionKey            []byte `env:"ENCRYPTION_KEY"              env-required:"true"`
+	GobbleAgingInterval      int    `env:"GOBBLE_AGING_INTERVAL"       env-default:"300000"`
+	GobbleLeaseDuration      int    `env:"GOBBLE_LEASE_DURATION"       env-default:"60000"`
+	GobbleWaitMaxDuration    int    `env:"GOBBLE_WAIT_MAX_DURATION"    env-default:"5000"`
+	MessageArchivePath       string `env:"MESSAGE_ARCHIVE_PATH"`
+	MessageGCInterval        int    `env:"MESSAGE_GC_INTERVAL"         env-default:"60"`
+	MessageLifetime          int    `env:"MESSAGE_LIFETIME"            env-default:"1440"`
+	MessageLifetimeOverrides string `env:"MESSAGE_LIFETIME_OVERRIDES"`
+	ModelMigrationsDir       string
+	Port                     string `env:"PORT"                        env-default:"3000"`
+	PublicURL                string `env:"PUBLIC_URL"`
+	RootPath                 string `env:"ROOT_PATH"`
+	SMTPAuthMechanism        string `env:"SMTP_AUTH_MECHANISM"         env-required:"true"`
+	SMTPCACertificates       string `env:"SMTP_CA_CERTIFICATES"`
+	SMTPCRAMMD5Secret        string `env:"SMTP_CRAMMD5_SECRET"`
+	SMTPClientCertificate    string `env:"SMTP_CLIENT_CERTIFICATE"`
+	SMTPClientKey            string `env:"SMTP_CLIENT_KEY"`
+	SMTPHost                 string `env:"SMTP_HOST"                   env-required:"true"`
+	SMTPLoggingEnabled       bool   `env:"SMTP_LOGGING_ENABLED"        env-default:"false"`
+	SMTPOAuthClientID        string `env:"SMTP_OAUTH_CLIENT_ID"`
+	SMTPOAuthClientSecret    string `env:"SMTP_OAUTH_CLIENT_SECRET"`
+	SMTPOAuthRefreshToken    string `env:"SMTP_OAUTH_REFRESH_TOKEN"`
+	SMTPOAuthScope           string `env:"SMTP_OAUTH_SCOPE"`
+	SMTPOAuthTokenURL        string `env:"SMTP_OAUTH_TOKEN_URL"`
+	SMTPPass                 string `env:"SMTP_PASS"`
+	SMTPPoolMaxIdleTime      int    `env:"SMTP_POOL_MAX_IDLE_TIME"     env-default:"30"`
+	SMTPPoolMaxMessages      int    `env:"SMTP_POOL_MAX_MESSAGES"      env-default:"100"`
+	SMTPPort                 string `env:"SMTP_PORT"                   env-required:"true"`
+	SMTPRelayCooldown        int    `env:"SMTP_RELAY_COOLDOWN"         env-default:"60"`
+	SMTPRelayFailureLimit    int    `env:"SMTP_RELAY_FAILURE_LIMIT"    env-default:"5"`
+	SMTPRelayList            string `env:"SMTP_RELAYS"`
+	SMTPRelays               []SMTPRelay
+	SMTPTLS                  bool   `env:"SMTP_TLS"                    env-default:"true"`
+	SMTPTLSMode              string `env:"SMTP_TLS_MODE"`
+	SMTPUser                 string `env:"SMTP_USER"`
+	Sender                   string `env:"SENDER"                      env-required:"true"`
+	TestMode                 bool   `env:"TEST_MODE"                   env-default:"false"`
+	UAAClientID              string `env:"UAA_CLIENT_ID"               env-required:"true"`
+	UAAClientSecret          string `env:"UAA_CLIENT_SECRET"           env-required:"true"`
+	UAAHost                  string `env:"UAA_HOST"                    env-required:"true"`
+	UnsubscribeMailto        string `env:"UNSUBSCRIBE_MAILTO"`
+	VerifySSL                bool   `env:"VERIFY_SSL"                  env-default:"true"`
+	VCAPApplication          struct {
 		InstanceIndex int `json:"instance_index"`
 	} `env:"VCAP_APPLICATION" env-required:"true"`
 }
@@ -56,7 +122,14 @@ func NewEnvironment() Environment {
 	}
 	env.parseDatabaseURL()
 	env.validateSMTPAuthMechanism()
+	env.inferSMTPTLSMode()
+	env.parseSMTPRelays()
 	env.inferModelMigrationsDir()
+	env.parseMessageLifetimeOverrides()
+	env.validateMessageRetention()
+	env.parseDKIMHeaders()
+	env.validateDKIM()
+	env.validateUnsubscribeMailto()
 	return env
 }
 
@@ -64,6 +137,135 @@ func (env *Environment) inferModelMigrationsDir() {
 	env.ModelMigrationsDir = path.Join(env.RootPath, "models", "migrations")
 }
 
+func (env *Environment) parseMessageLifetimeOverrides() {
+	env.ClientMessageLifetimes = map[string]int{}
+	if env.MessageLifetimeOverrides == "" {
+		return
+	}
+
+	err := json.Unmarshal([]byte(env.MessageLifetimeOverrides), &env.ClientMessageLifetimes)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse MESSAGE_LIFETIME_OVERRIDES %q, it does not fit format %q", env.MessageLifetimeOverrides, `{"client-id":minutes}`))
+	}
+}
+
+// validateMessageRetention ensures that message statuses are kept for some
+// time, and that the collector waits between runs.
+func (env *Environment) validateMessageRetention() {
+	if env.MessageGCInterval <= 0 {
+		panic(fmt.Sprintf("MESSAGE_GC_INTERVAL must be a positive number of minutes, got %d", env.MessageGCInterval))
+	}
+
+	if env.MessageLifetime <= 0 {
+		panic(fmt.Sprintf("MESSAGE_LIFETIME must be a positive number of minutes, got %d", env.MessageLifetime))
+	}
+
+	for clientID, lifetime := range env.ClientMessageLifetimes {
+		if lifetime <= 0 {
+			panic(fmt.Sprintf("MESSAGE_LIFETIME_OVERRIDES must be a positive number of minutes, got %d for %q", lifetime, clientID))
+		}
+	}
+}
+
+func (env *Environment) parseDKIMHeaders() {
+	env.DKIMHeaders = []string{}
+	for _, header := range strings.Split(env.DKIMHeaderList, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" {
+			env.DKIMHeaders = append(env.DKIMHeaders, header)
+		}
+	}
+}
+
+func (env *Environment) validateDKIM() {
+	if env.DKIMDomain == "" {
+		return
+	}
+
+	if env.DKIMSelector == "" || env.DKIMPrivateKey == "" {
+		panic(fmt.Sprintf("DKIM_DOMAIN %q is set, DKIM_SELECTOR and DKIM_PRIVATE_KEY must be set as well", env.DKIMDomain))
+	}
+}
+
+// validateUnsubscribeMailto ensures that unsubscribe requests mailed to
+// UNSUBSCRIBE_MAILTO reach the bounce listener, which handles them.
+func (env *Environment) validateUnsubscribeMailto() {
+	if env.UnsubscribeMailto == "" {
+		return
+	}
+
+	at := strings.LastIndex(env.UnsubscribeMailto, "@")
+	if env.BounceDomain == "" || at < 0 || !strings.EqualFold(env.UnsubscribeMailto[at+1:], env.BounceDomain) {
+		panic(fmt.Sprintf("UNSUBSCRIBE_MAILTO %q is set, it must be an address in BOUNCE_DOMAIN %q", env.UnsubscribeMailto, env.BounceDomain))
+	}
+}
+
+// inferSMTPTLSMode falls back on SMTP_TLS when SMTP_TLS_MODE is not set.
+func (env *Environment) inferSMTPTLSMode() {
+	if env.SMTPTLSMode == "" {
+		env.SMTPTLSMode = SMTPTLSModeNone
+		if env.SMTPTLS {
+			env.SMTPTLSMode = SMTPTLSModeStartTLS
+		}
+	}
+
+	if !contains(SMTPTLSModes, env.SMTPTLSMode) {
+		panic(fmt.Sprintf("Could not parse SMTP_TLS_MODE %q, it is not one of the allowed values: %+v", env.SMTPTLSMode, SMTPTLSModes))
+	}
+}
+
+func (env *Environment) parseSMTPRelays() {
+	env.SMTPRelays = []SMTPRelay{}
+	if env.SMTPRelayList == "" {
+		return
+	}
+
+	format := `[{"host":"smtp.example.com","port":"587","auth_mechanism":"plain","user":"user","pass":"pass","tls_mode":"starttls","priority":1,"weight":1}]`
+
+	var relays []json.RawMessage
+	err := json.Unmarshal([]byte(env.SMTPRelayList), &relays)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse SMTP_RELAYS %q, it does not fit format %q", env.SMTPRelayList, format))
+	}
+
+	for _, document := range relays {
+		relay := SMTPRelay{
+			AuthMechanism: SMTPAuthNone,
+			TLSMode:       SMTPTLSModeStartTLS,
+			Weight:        1,
+		}
+
+		err = json.Unmarshal(document, &relay)
+		if err != nil || relay.Host == "" || relay.Port == "" {
+			panic(fmt.Sprintf("Could not parse SMTP_RELAYS %q, it does not fit format %q", env.SMTPRelayList, format))
+		}
+
+		if !contains(SMTPAuthMechanisms, relay.AuthMechanism) {
+			panic(fmt.Sprintf("Could not parse SMTP_RELAYS auth_mechanism %q, it is not one of the allowed values: %+v", relay.AuthMechanism, SMTPAuthMechanisms))
+		}
+
+		if relay.AuthMechanism == SMTPAuthXOAUTH2 && (relay.OAuthTokenURL == "" || relay.OAuthClientID == "") {
+			panic(fmt.Sprintf("SMTP_RELAYS relay %s:%s uses xoauth2, oauth_token_url and oauth_client_id must be set as well", relay.Host, relay.Port))
+		}
+
+		if !contains(SMTPTLSModes, relay.TLSMode) {
+			panic(fmt.Sprintf("Could not parse SMTP_RELAYS tls_mode %q, it is not one of the allowed values: %+v", relay.TLSMode, SMTPTLSModes))
+		}
+
+		env.SMTPRelays = append(env.SMTPRelays, relay)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (env *Environment) parseDatabaseURL() {
 	databaseURL := env.DatabaseURL
 	databaseURL = strings.TrimPrefix(databaseURL, "http://")
@@ -81,11 +283,11 @@ func (env *Environment) parseDatabaseURL() {
 }
 
 func (env *Environment) validateSMTPAuthMechanism() {
-	for _, mechanism := range SMTPAuthMechanisms {
-		if mechanism == env.SMTPAuthMechanism {
-			return
-		}
+	if !contains(SMTPAuthMechanisms, env.SMTPAuthMechanism) {
+		panic(fmt.Sprintf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, SMTPAuthMechanisms))
 	}
 
-	panic(fmt.Sprintf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, SMTPAuthMechanisms))
+	if env.SMTPAuthMechanism == SMTPAuthXOAUTH2 && (env.SMTPOAuthTokenURL == "" || env.SMTPOAuthClientID == "") {
+		panic("SMTP_AUTH_MECHANISM is xoauth2, SMTP_OAUTH_TOKEN_URL and SMTP_OAUTH_CLIENT_ID must be set as well")
+	}
 }
@@ -3,11 +3,13 @@ package application
 import (
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
 )
 
 type PersistenceProvider interface {
 	Database() models.DatabaseInterface
 	Queue() gobble.QueueInterface
+	TemplateEscapingChecker() postal.TemplateEscapingCheckerInterface
 }
 
 type Migrator struct {
@@ -26,5 +28,6 @@ func (m Migrator) Migrate() {
 	if m.shouldMigrate {
 		m.provider.Database().Seed()
 		m.provider.Queue()
+		m.provider.TemplateEscapingChecker().Report()
 	}
 }
@@ -41,6 +41,12 @@ var _ = Describe("Migrator", func() {
 
 				Expect(database.SeedWasCalled).To(BeTrue())
 			})
+
+			It("reports the stored templates that render differently with contextual escaping", func() {
+				migrator.Migrate()
+
+				Expect(provider.Checker.ReportWasCalled).To(BeTrue())
+			})
 		})
 
 		Context("when configured to skip migrations", func() {
@@ -65,6 +71,12 @@ var _ = Describe("Migrator", func() {
 
 				Expect(database.SeedWasCalled).To(BeFalse())
 			})
+
+			It("does not check the stored templates", func() {
+				migrator.Migrate()
+
+				Expect(provider.Checker.ReportWasCalled).To(BeFalse())
+			})
 		})
 	})
 })
@@ -16,9 +16,11 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/postal/utilities"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
 	"github.com/nu7hatch/gouuid"
 	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+	"github.com/pivotal-golang/conceal"
 	"github.com/ryanmoran/stack"
 )
 
@@ -44,6 +46,10 @@ func (m *Mother) Logger() *log.Logger {
 }
 
 func (m *Mother) Queue() gobble.QueueInterface {
+	return m.gobbleQueue()
+}
+
+func (m *Mother) gobbleQueue() *gobble.Queue {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -51,6 +57,8 @@ func (m *Mother) Queue() gobble.QueueInterface {
 		env := NewEnvironment()
 		m.queue = gobble.NewQueue(gobble.Config{
 			WaitMaxDuration: time.Duration(env.GobbleWaitMaxDuration) * time.Millisecond,
+			LeaseDuration:   time.Duration(env.GobbleLeaseDuration) * time.Millisecond,
+			AgingInterval:   time.Duration(env.GobbleAgingInterval) * time.Millisecond,
 		})
 	}
 
@@ -75,6 +83,12 @@ func (m Mother) UAAClient() *uaa.UAA {
 	return m.uaaClient
 }
 
+func (m Mother) UAAUsers() utilities.UAAUsers {
+	env := NewEnvironment()
+
+	return utilities.NewUAAUsers(m.UAAClient(), env.UAAHost)
+}
+
 func (m Mother) SpaceStrategy() strategies.SpaceStrategy {
 	env := NewEnvironment()
 	uaaClient := m.UAAClient()
@@ -83,10 +97,10 @@ func (m Mother) SpaceStrategy() strategies.SpaceStrategy {
 	tokenLoader := postal.NewTokenLoader(uaaClient)
 	spaceLoader := utilities.NewSpaceLoader(cloudController)
 	organizationLoader := utilities.NewOrganizationLoader(cloudController)
-	mailer := m.Mailer()
-	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, uaaClient)
+	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, m.UAAUsers())
+	launcher := m.CampaignLauncher()
 
-	return strategies.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserGUIDs, mailer)
+	return strategies.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserGUIDs, launcher)
 }
 
 func (m Mother) OrganizationStrategy() strategies.OrganizationStrategy {
@@ -96,19 +110,19 @@ func (m Mother) OrganizationStrategy() strategies.OrganizationStrategy {
 
 	tokenLoader := postal.NewTokenLoader(uaaClient)
 	organizationLoader := utilities.NewOrganizationLoader(cloudController)
-	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, uaaClient)
-	mailer := m.Mailer()
+	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, m.UAAUsers())
+	launcher := m.CampaignLauncher()
 
-	return strategies.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserGUIDs, mailer)
+	return strategies.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserGUIDs, launcher)
 }
 
 func (m Mother) EveryoneStrategy() strategies.EveryoneStrategy {
 	uaaClient := m.UAAClient()
 	tokenLoader := postal.NewTokenLoader(uaaClient)
-	allUsers := utilities.NewAllUsers(uaaClient)
-	mailer := m.Mailer()
+	allUsers := utilities.NewAllUsers(m.UAAUsers())
+	launcher := m.CampaignLauncher()
 
-	return strategies.NewEveryoneStrategy(tokenLoader, allUsers, mailer)
+	return strategies.NewEveryoneStrategy(tokenLoader, allUsers, launcher)
 }
 
 func (m Mother) UAAScopeStrategy() strategies.UAAScopeStrategy {
@@ -117,10 +131,30 @@ func (m Mother) UAAScopeStrategy() strategies.UAAScopeStrategy {
 	cloudController := cf.NewCloudController(env.CCHost, !env.VerifySSL)
 
 	tokenLoader := postal.NewTokenLoader(uaaClient)
-	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, uaaClient)
-	mailer := m.Mailer()
+	findsUserGUIDs := utilities.NewFindsUserGUIDs(cloudController, m.UAAUsers())
+	launcher := m.CampaignLauncher()
+
+	return strategies.NewUAAScopeStrategy(tokenLoader, findsUserGUIDs, launcher)
+}
+
+func (m Mother) CampaignLauncher() strategies.CampaignLauncher {
+	return strategies.NewCampaignLauncher(m.Queue(), uuid.NewV4, m.CampaignsRepo())
+}
+
+func (m Mother) CampaignExpander() strategies.CampaignExpander {
+	audiences := map[string]strategies.AudienceStrategyInterface{
+		strategies.SpaceAudience:        m.SpaceStrategy(),
+		strategies.OrganizationAudience: m.OrganizationStrategy(),
+		strategies.EveryoneAudience:     m.EveryoneStrategy(),
+		strategies.ScopeAudience:        m.UAAScopeStrategy(),
+	}
+
+	return strategies.NewCampaignExpander(audiences, m.Mailer(), m.CampaignsRepo(), m.Database())
+}
 
-	return strategies.NewUAAScopeStrategy(tokenLoader, findsUserGUIDs, mailer)
+func (m Mother) EmailValidator() params.EmailValidator {
+	env := NewEnvironment()
+	return params.EmailValidator{BulkLimit: env.BulkEmailLimit}
 }
 
 func (m Mother) EmailStrategy() strategies.EmailStrategy {
@@ -137,7 +171,7 @@ func (m Mother) NotificationsUpdater() services.NotificationsUpdater {
 }
 
 func (m Mother) Mailer() strategies.Mailer {
-	return strategies.NewMailer(m.Queue(), uuid.NewV4, m.MessagesRepo())
+	return strategies.NewMailer(m.Queue(), uuid.NewV4, m.MessagesRepo(), m.MessageEventsRepo())
 }
 
 func (m Mother) TemplatesLoader() postal.TemplatesLoader {
@@ -161,35 +195,141 @@ func (m Mother) TokenLoader() postal.TokenLoader {
 	return postal.NewTokenLoader(uaaClient)
 }
 
+func (m Mother) BounceProcessor() postal.BounceProcessor {
+	return postal.NewBounceProcessor(m.Database(), m.MessagesRepo(), m.MessageEventsRepo(), m.SuppressionsRepo(), m.Logger())
+}
+
 func (m Mother) MailClient() *mail.Client {
+	client, err := mail.NewClient(m.mailConfig(), m.Logger())
+	if err != nil {
+		m.Logger().Panicln(err)
+	}
+
+	return client
+}
+
+// MailRouter sends mail through the relay configured by the SMTP_* variables,
+// failing over to the relays listed in SMTP_RELAYS. Each relay holds a pool of
+// up to poolSize sessions.
+func (m Mother) MailRouter(poolSize int) *mail.Router {
 	env := NewEnvironment()
-	mailConfig := mail.Config{
-		User:           env.SMTPUser,
-		Pass:           env.SMTPPass,
-		Host:           env.SMTPHost,
-		Port:           env.SMTPPort,
-		Secret:         env.SMTPCRAMMD5Secret,
-		TestMode:       env.TestMode,
-		SkipVerifySSL:  env.VerifySSL,
-		DisableTLS:     !env.SMTPTLS,
-		LoggingEnabled: env.SMTPLoggingEnabled,
+	relays := []mail.Relay{
+		{Config: m.mailConfig(), Weight: 1},
 	}
 
-	switch env.SMTPAuthMechanism {
-	case SMTPAuthNone:
-		mailConfig.AuthMechanism = mail.AuthNone
-	case SMTPAuthPlain:
-		mailConfig.AuthMechanism = mail.AuthPlain
-	case SMTPAuthCRAMMD5:
-		mailConfig.AuthMechanism = mail.AuthCRAMMD5
+	for _, relay := range env.SMTPRelays {
+		config := m.mailConfig()
+		config.Host = relay.Host
+		config.Port = relay.Port
+		config.User = relay.User
+		config.Pass = relay.Pass
+		config.Secret = relay.CRAMMD5Secret
+		config.AuthMechanism = authMechanism(relay.AuthMechanism)
+		config.TokenSource = nil
+		if relay.AuthMechanism == SMTPAuthXOAUTH2 {
+			config.TokenSource = mail.NewOAuthTokenSource(mail.OAuthConfig{
+				TokenURL:     relay.OAuthTokenURL,
+				ClientID:     relay.OAuthClientID,
+				ClientSecret: relay.OAuthClientSecret,
+				RefreshToken: relay.OAuthRefreshToken,
+				Scope:        relay.OAuthScope,
+			})
+		}
+		config.TLSMode = tlsMode(relay.TLSMode)
+		if relay.CACertificates != "" {
+			config.CACertificates = []byte(relay.CACertificates)
+		}
+		if relay.ClientCertificate != "" || relay.ClientKey != "" {
+			config.ClientCertificate = []byte(relay.ClientCertificate)
+			config.ClientKey = []byte(relay.ClientKey)
+		}
+
+		relays = append(relays, mail.Relay{
+			Name:     relay.Name,
+			Config:   config,
+			Priority: relay.Priority,
+			Weight:   relay.Weight,
+		})
 	}
 
-	client, err := mail.NewClient(mailConfig, m.Logger())
+	routerConfig := mail.RouterConfig{
+		Pool: mail.PoolConfig{
+			Size:                     poolSize,
+			MaxIdleTime:              time.Duration(env.SMTPPoolMaxIdleTime) * time.Second,
+			MaxMessagesPerConnection: env.SMTPPoolMaxMessages,
+		},
+		FailureThreshold: env.SMTPRelayFailureLimit,
+		Cooldown:         time.Duration(env.SMTPRelayCooldown) * time.Second,
+	}
+
+	router, err := mail.NewRouter(relays, routerConfig, m.Logger())
 	if err != nil {
 		m.Logger().Panicln(err)
 	}
 
-	return client
+	return router
+}
+
+func (m Mother) mailConfig() mail.Config {
+	env := NewEnvironment()
+
+	var tokenSource mail.TokenSource
+	if env.SMTPAuthMechanism == SMTPAuthXOAUTH2 {
+		tokenSource = mail.NewOAuthTokenSource(mail.OAuthConfig{
+			TokenURL:     env.SMTPOAuthTokenURL,
+			ClientID:     env.SMTPOAuthClientID,
+			ClientSecret: env.SMTPOAuthClientSecret,
+			RefreshToken: env.SMTPOAuthRefreshToken,
+			Scope:        env.SMTPOAuthScope,
+		})
+	}
+
+	return mail.Config{
+		User:              env.SMTPUser,
+		Pass:              env.SMTPPass,
+		Host:              env.SMTPHost,
+		Port:              env.SMTPPort,
+		Secret:            env.SMTPCRAMMD5Secret,
+		AuthMechanism:     authMechanism(env.SMTPAuthMechanism),
+		TestMode:          env.TestMode,
+		SkipVerifySSL:     !env.VerifySSL,
+		TLSMode:           tlsMode(env.SMTPTLSMode),
+		CACertificates:    []byte(env.SMTPCACertificates),
+		ClientCertificate: []byte(env.SMTPClientCertificate),
+		ClientKey:         []byte(env.SMTPClientKey),
+		LoggingEnabled:    env.SMTPLoggingEnabled,
+		DKIMDomain:        env.DKIMDomain,
+		DKIMSelector:      env.DKIMSelector,
+		DKIMPrivateKey:    []byte(env.DKIMPrivateKey),
+		DKIMHeaders:       env.DKIMHeaders,
+		TokenSource:       tokenSource,
+	}
+}
+
+func tlsMode(name string) mail.TLSMode {
+	switch name {
+	case SMTPTLSModeImplicit:
+		return mail.TLSModeImplicit
+	case SMTPTLSModeNone:
+		return mail.TLSModeNone
+	default:
+		return mail.TLSModeStartTLS
+	}
+}
+
+func authMechanism(name string) mail.AuthMechanism {
+	switch name {
+	case SMTPAuthPlain:
+		return mail.AuthPlain
+	case SMTPAuthCRAMMD5:
+		return mail.AuthCRAMMD5
+	case SMTPAuthLogin:
+		return mail.AuthLogin
+	case SMTPAuthXOAUTH2:
+		return mail.AuthXOAUTH2
+	default:
+		return mail.AuthNone
+	}
 }
 
 func (m Mother) Repos() (models.ClientsRepo, models.KindsRepo) {
@@ -213,6 +353,10 @@ func (m Mother) Registrar() services.Registrar {
 	return services.NewRegistrar(clientsRepo, kindsRepo)
 }
 
+func (m *Mother) TemplateEscapingChecker() postal.TemplateEscapingCheckerInterface {
+	return postal.NewTemplateEscapingChecker(m.TemplatesRepo(), m.Database(), m.Logger())
+}
+
 func (m Mother) Database() models.DatabaseInterface {
 	env := NewEnvironment()
 	return models.NewDatabase(models.Config{
@@ -223,11 +367,25 @@ func (m Mother) Database() models.DatabaseInterface {
 }
 
 func (m Mother) PreferencesFinder() *services.PreferencesFinder {
-	return services.NewPreferencesFinder(models.NewPreferencesRepo(), m.GlobalUnsubscribesRepo(), m.Database())
+	return services.NewPreferencesFinder(models.NewPreferencesRepo(), m.GlobalUnsubscribesRepo(), m.DeliveryAddressesRepo(), m.Database())
 }
 
 func (m Mother) PreferenceUpdater() services.PreferenceUpdater {
-	return services.NewPreferenceUpdater(m.GlobalUnsubscribesRepo(), m.UnsubscribesRepo(), m.KindsRepo())
+	return services.NewPreferenceUpdater(m.GlobalUnsubscribesRepo(), m.UnsubscribesRepo(), m.KindsRepo(), m.DeliveryAddressesRepo(), m.UserLoader(), m.TokenLoader())
+}
+
+func (m Mother) Unsubscriber() services.Unsubscriber {
+	env := NewEnvironment()
+	cloak, err := conceal.NewCloak(env.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return services.NewUnsubscriber(cloak, m.PreferenceUpdater(), m.GlobalUnsubscribesRepo(), m.KindsRepo())
+}
+
+func (m Mother) MailUnsubscriber() services.MailUnsubscriber {
+	return services.NewMailUnsubscriber(m.Unsubscriber(), m.Database(), m.Logger())
 }
 
 func (m Mother) TemplateFinder() services.TemplateFinder {
@@ -240,8 +398,29 @@ func (m Mother) TemplateFinder() services.TemplateFinder {
 func (m Mother) MessageFinder() services.MessageFinder {
 	database := m.Database()
 	messagesRepo := m.MessagesRepo()
+	messageEventsRepo := m.MessageEventsRepo()
+
+	return services.NewMessageFinder(messagesRepo, messageEventsRepo, database)
+}
 
-	return services.NewMessageFinder(messagesRepo, database)
+func (m Mother) BatchFinder() services.BatchFinder {
+	return services.NewBatchFinder(m.MessagesRepo(), m.CampaignsRepo(), m.Database())
+}
+
+func (m *Mother) MessageCanceler() postal.MessageCanceler {
+	return postal.NewMessageCanceler(m.Queue(), m.MessagesRepo(), m.MessageEventsRepo(), m.Database())
+}
+
+func (m Mother) TemplateRenderer() postal.TemplateRenderer {
+	env := NewEnvironment()
+	return postal.NewTemplateRenderer(m.TemplatesRepo(), m.Database(), env.Sender, env.EncryptionKey,
+		postal.UnsubscribeConfig{PublicURL: env.PublicURL, Mailto: env.UnsubscribeMailto})
+}
+
+// TestSender mails test sends of templates through a router of its own, so
+// that they do not take sessions from the delivery workers.
+func (m Mother) TestSender() postal.TestSender {
+	return postal.NewTestSender(m.TemplateRenderer(), m.MailRouter(1), m.SuppressionsRepo(), m.Database(), m.Logger())
 }
 
 func (m Mother) TemplateServiceObjects() (services.TemplateCreator, services.TemplateFinder, services.TemplateUpdater,
@@ -250,16 +429,43 @@ func (m Mother) TemplateServiceObjects() (services.TemplateCreator, services.Tem
 	database := m.Database()
 	clientsRepo, kindsRepo := m.Repos()
 	templatesRepo := m.TemplatesRepo()
+	versionsRepo := m.TemplateVersionsRepo()
 
-	return services.NewTemplateCreator(templatesRepo, database),
+	return services.NewTemplateCreator(templatesRepo, versionsRepo, database),
 		services.NewTemplateFinder(templatesRepo, database),
-		services.NewTemplateUpdater(templatesRepo, database),
+		services.NewTemplateUpdater(templatesRepo, versionsRepo, database),
 		services.NewTemplateDeleter(templatesRepo, database),
 		services.NewTemplateLister(templatesRepo, database),
 		services.NewTemplateAssigner(clientsRepo, kindsRepo, templatesRepo, database),
 		services.NewTemplateAssociationLister(clientsRepo, kindsRepo, templatesRepo, database)
 }
 
+func (m Mother) TemplateVersionServiceObjects() (services.TemplateVersionFinder, services.TemplateRestorer) {
+	database := m.Database()
+	templatesRepo := m.TemplatesRepo()
+	versionsRepo := m.TemplateVersionsRepo()
+
+	return services.NewTemplateVersionFinder(templatesRepo, versionsRepo, database),
+		services.NewTemplateRestorer(templatesRepo, versionsRepo, database)
+}
+
+func (m *Mother) DeadLetterServiceObjects() (services.DeadLetterFinder, services.DeadLetterRequeuer, services.DeadLetterPurger) {
+	queue := m.gobbleQueue()
+
+	return services.NewDeadLetterFinder(queue),
+		services.NewDeadLetterRequeuer(queue),
+		services.NewDeadLetterPurger(queue)
+}
+
+func (m Mother) SuppressionServiceObjects() (services.SuppressionFinder, services.SuppressionUpdater, services.SuppressionDeleter) {
+	suppressionsRepo := m.SuppressionsRepo()
+	database := m.Database()
+
+	return services.NewSuppressionFinder(suppressionsRepo, database),
+		services.NewSuppressionUpdater(suppressionsRepo, database),
+		services.NewSuppressionDeleter(suppressionsRepo, database)
+}
+
 func (m Mother) KindsRepo() models.KindsRepo {
 	return models.NewKindsRepo()
 }
@@ -276,10 +482,30 @@ func (m Mother) TemplatesRepo() models.TemplatesRepo {
 	return models.NewTemplatesRepo()
 }
 
+func (m Mother) TemplateVersionsRepo() models.TemplateVersionsRepo {
+	return models.NewTemplateVersionsRepo()
+}
+
 func (m Mother) MessagesRepo() models.MessagesRepo {
 	return models.NewMessagesRepo()
 }
 
+func (m Mother) CampaignsRepo() models.CampaignsRepo {
+	return models.NewCampaignsRepo()
+}
+
+func (m Mother) MessageEventsRepo() models.MessageEventsRepo {
+	return models.NewMessageEventsRepo()
+}
+
+func (m Mother) DeliveryAddressesRepo() models.DeliveryAddressesRepo {
+	return models.NewDeliveryAddressesRepo()
+}
+
+func (m Mother) SuppressionsRepo() models.SuppressionsRepo {
+	return models.NewSuppressionsRepo()
+}
+
 func (m Mother) ReceiptsRepo() models.ReceiptsRepo {
 	return models.NewReceiptsRepo()
 }
@@ -9,6 +9,6 @@ func NewAllUsers() *AllUsers {
 	return &AllUsers{}
 }
 
-func (fake *AllUsers) AllUserGUIDs() ([]string, error) {
-	return fake.GUIDs, fake.LoadError
+func (fake *AllUsers) AllUserGUIDs(after string, count int) ([]string, error) {
+	return guidPage(fake.GUIDs, after, count), fake.LoadError
 }
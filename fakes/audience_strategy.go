@@ -0,0 +1,40 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+)
+
+type AudienceStrategy struct {
+	ExpandArguments     []interface{}
+	Audience            strategies.Audience
+	ExpandError         error
+	Users               []strategies.User
+	RecipientsArguments [][]interface{}
+	RecipientsError     error
+}
+
+func NewAudienceStrategy() *AudienceStrategy {
+	return &AudienceStrategy{}
+}
+
+func (fake *AudienceStrategy) Expand(guid string, options postal.Options) (strategies.Audience, error) {
+	fake.ExpandArguments = []interface{}{guid, options}
+	return fake.Audience, fake.ExpandError
+}
+
+func (fake *AudienceStrategy) Recipients(guid string, options postal.Options, after string, count int) ([]strategies.User, error) {
+	fake.RecipientsArguments = append(fake.RecipientsArguments, []interface{}{guid, options, after, count})
+	if fake.RecipientsError != nil {
+		return []strategies.User{}, fake.RecipientsError
+	}
+
+	users := []strategies.User{}
+	for _, user := range fake.Users {
+		if user.GUID > after && len(users) < count {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,18 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/web/services"
+
+type BatchFinder struct {
+	FindArguments []interface{}
+	Batch         services.Batch
+	FindError     error
+}
+
+func NewBatchFinder() *BatchFinder {
+	return &BatchFinder{}
+}
+
+func (fake *BatchFinder) Find(clientID, batchID string, page, perPage int) (services.Batch, error) {
+	fake.FindArguments = []interface{}{clientID, batchID, page, perPage}
+	return fake.Batch, fake.FindError
+}
@@ -0,0 +1,22 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+)
+
+type CampaignLauncher struct {
+	LaunchArguments []interface{}
+	Response        strategies.CampaignResponse
+	LaunchError     error
+}
+
+func NewCampaignLauncher() *CampaignLauncher {
+	return &CampaignLauncher{}
+}
+
+func (fake *CampaignLauncher) Launch(conn models.ConnectionInterface, expansion postal.Expansion) (strategies.CampaignResponse, error) {
+	fake.LaunchArguments = []interface{}{conn, expansion}
+	return fake.Response, fake.LaunchError
+}
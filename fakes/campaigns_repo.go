@@ -0,0 +1,54 @@
+package fakes
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type CampaignsRepo struct {
+	Campaigns     map[string]models.Campaign
+	Updates       []models.Campaign
+	CreateError   error
+	FindByIDError error
+	UpdateError   error
+}
+
+func NewCampaignsRepo() *CampaignsRepo {
+	return &CampaignsRepo{
+		Campaigns: map[string]models.Campaign{},
+	}
+}
+
+func (fake *CampaignsRepo) Create(conn models.ConnectionInterface, campaign models.Campaign) (models.Campaign, error) {
+	if fake.CreateError != nil {
+		return models.Campaign{}, fake.CreateError
+	}
+
+	fake.Campaigns[campaign.ID] = campaign
+	return campaign, nil
+}
+
+func (fake *CampaignsRepo) FindByID(conn models.ConnectionInterface, campaignID string) (models.Campaign, error) {
+	if fake.FindByIDError != nil {
+		return models.Campaign{}, fake.FindByIDError
+	}
+
+	campaign, ok := fake.Campaigns[campaignID]
+	if !ok {
+		return campaign, models.RecordNotFoundError(fmt.Sprintf("Campaign with ID %q could not be found", campaignID))
+	}
+
+	return campaign, nil
+}
+
+func (fake *CampaignsRepo) Update(conn models.ConnectionInterface, campaign models.Campaign) (models.Campaign, error) {
+	if fake.UpdateError != nil {
+		return campaign, fake.UpdateError
+	}
+
+	campaign.Version++
+	fake.Campaigns[campaign.ID] = campaign
+	fake.Updates = append(fake.Updates, campaign)
+	return campaign, nil
+}
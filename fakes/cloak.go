@@ -3,6 +3,9 @@ package fakes
 type Cloak struct {
 	EncryptedResult []byte
 	DataToEncrypt   []byte
+	DecryptedResult []byte
+	DataToDecrypt   []byte
+	UnveilError     error
 }
 
 func (cloaker *Cloak) Veil(data []byte) ([]byte, error) {
@@ -11,5 +14,6 @@ func (cloaker *Cloak) Veil(data []byte) ([]byte, error) {
 }
 
 func (cloaker *Cloak) Unveil(data []byte) ([]byte, error) {
-	return []byte("what"), nil
+	cloaker.DataToDecrypt = data
+	return cloaker.DecryptedResult, cloaker.UnveilError
 }
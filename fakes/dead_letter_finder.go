@@ -0,0 +1,31 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type DeadLetterFinder struct {
+	DeadLetters   []gobble.DeadLetter
+	ListError     error
+	ListArguments []int
+	FindArgument  string
+	FindError     error
+}
+
+func NewDeadLetterFinder() *DeadLetterFinder {
+	return &DeadLetterFinder{
+		DeadLetters: []gobble.DeadLetter{},
+	}
+}
+
+func (fake *DeadLetterFinder) List(page, perPage int) ([]gobble.DeadLetter, error) {
+	fake.ListArguments = []int{page, perPage}
+	return fake.DeadLetters, fake.ListError
+}
+
+func (fake *DeadLetterFinder) Find(deadLetterID string) (gobble.DeadLetter, error) {
+	fake.FindArgument = deadLetterID
+	if fake.FindError != nil {
+		return gobble.DeadLetter{}, fake.FindError
+	}
+
+	return fake.DeadLetters[0], nil
+}
@@ -0,0 +1,23 @@
+package fakes
+
+type DeadLetterPurger struct {
+	PurgeArgument     string
+	PurgeError        error
+	PurgeAllCount     int
+	PurgeAllError     error
+	PurgeAllWasCalled bool
+}
+
+func NewDeadLetterPurger() *DeadLetterPurger {
+	return &DeadLetterPurger{}
+}
+
+func (fake *DeadLetterPurger) Purge(deadLetterID string) error {
+	fake.PurgeArgument = deadLetterID
+	return fake.PurgeError
+}
+
+func (fake *DeadLetterPurger) PurgeAll() (int, error) {
+	fake.PurgeAllWasCalled = true
+	return fake.PurgeAllCount, fake.PurgeAllError
+}
@@ -0,0 +1,83 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type DeadLetterQueue struct {
+	Letters                map[int]gobble.DeadLetter
+	DeadLettersError       error
+	RequeueDeadLetterError error
+	PurgeDeadLetterError   error
+	RequeueDeadLetterIDs   []int
+	PurgeDeadLetterIDs     []int
+	DeadLettersArguments   [][]int
+	ConcurrentlyRemovedIDs map[int]bool
+}
+
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{
+		Letters:                map[int]gobble.DeadLetter{},
+		ConcurrentlyRemovedIDs: map[int]bool{},
+	}
+}
+
+func (fake *DeadLetterQueue) DeadLetters(page, perPage int) ([]gobble.DeadLetter, error) {
+	fake.DeadLettersArguments = append(fake.DeadLettersArguments, []int{page, perPage})
+
+	deadLetters := []gobble.DeadLetter{}
+	for id := 1; len(deadLetters) < len(fake.Letters); id++ {
+		if deadLetter, ok := fake.Letters[id]; ok {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+
+	start := (page - 1) * perPage
+	if start > len(deadLetters) {
+		start = len(deadLetters)
+	}
+	end := start + perPage
+	if end > len(deadLetters) {
+		end = len(deadLetters)
+	}
+
+	return deadLetters[start:end], fake.DeadLettersError
+}
+
+func (fake *DeadLetterQueue) FindDeadLetter(id int) (gobble.DeadLetter, error) {
+	deadLetter, ok := fake.Letters[id]
+	if !ok {
+		return deadLetter, gobble.DeadLetterNotFoundError{ID: id}
+	}
+
+	return deadLetter, nil
+}
+
+func (fake *DeadLetterQueue) RequeueDeadLetter(id int) (gobble.Job, error) {
+	if fake.RequeueDeadLetterError != nil {
+		return gobble.Job{}, fake.RequeueDeadLetterError
+	}
+
+	deadLetter, ok := fake.Letters[id]
+	if !ok || fake.ConcurrentlyRemovedIDs[id] {
+		return gobble.Job{}, gobble.DeadLetterNotFoundError{ID: id}
+	}
+
+	fake.RequeueDeadLetterIDs = append(fake.RequeueDeadLetterIDs, id)
+	delete(fake.Letters, id)
+
+	return deadLetter.Job(), nil
+}
+
+func (fake *DeadLetterQueue) PurgeDeadLetter(id int) error {
+	if fake.PurgeDeadLetterError != nil {
+		return fake.PurgeDeadLetterError
+	}
+
+	if _, ok := fake.Letters[id]; !ok || fake.ConcurrentlyRemovedIDs[id] {
+		return gobble.DeadLetterNotFoundError{ID: id}
+	}
+
+	fake.PurgeDeadLetterIDs = append(fake.PurgeDeadLetterIDs, id)
+	delete(fake.Letters, id)
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package fakes
+
+type DeadLetterRequeuer struct {
+	RequeueArgument     string
+	RequeueError        error
+	RequeueAllCount     int
+	RequeueAllError     error
+	RequeueAllWasCalled bool
+}
+
+func NewDeadLetterRequeuer() *DeadLetterRequeuer {
+	return &DeadLetterRequeuer{}
+}
+
+func (fake *DeadLetterRequeuer) Requeue(deadLetterID string) error {
+	fake.RequeueArgument = deadLetterID
+	return fake.RequeueError
+}
+
+func (fake *DeadLetterRequeuer) RequeueAll() (int, error) {
+	fake.RequeueAllWasCalled = true
+	return fake.RequeueAllCount, fake.RequeueAllError
+}
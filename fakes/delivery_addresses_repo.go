@@ -0,0 +1,38 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type DeliveryAddressesRepo struct {
+	Addresses map[string]models.DeliveryAddress
+	SetError  error
+	GetError  error
+}
+
+func NewDeliveryAddressesRepo() *DeliveryAddressesRepo {
+	return &DeliveryAddressesRepo{
+		Addresses: map[string]models.DeliveryAddress{},
+	}
+}
+
+func (fake *DeliveryAddressesRepo) Set(conn models.ConnectionInterface, address models.DeliveryAddress) error {
+	if fake.SetError != nil {
+		return fake.SetError
+	}
+
+	fake.Addresses[address.UserID] = address
+
+	return nil
+}
+
+func (fake *DeliveryAddressesRepo) Get(conn models.ConnectionInterface, userGUID string) (models.DeliveryAddress, error) {
+	if fake.GetError != nil {
+		return models.DeliveryAddress{}, fake.GetError
+	}
+
+	address, ok := fake.Addresses[userGUID]
+	if !ok {
+		return models.DeliveryAddress{UserID: userGUID, Mode: models.DeliveryAddressPrimary}, nil
+	}
+
+	return address, nil
+}
@@ -0,0 +1,17 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type Expander struct {
+	ExpandArguments []gobble.Job
+	ExpandError     error
+}
+
+func NewExpander() *Expander {
+	return &Expander{}
+}
+
+func (fake *Expander) Expand(job *gobble.Job) error {
+	fake.ExpandArguments = append(fake.ExpandArguments, *job)
+	return fake.ExpandError
+}
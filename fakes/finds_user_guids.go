@@ -1,5 +1,7 @@
 package fakes
 
+import "sort"
+
 type FindsUserGUIDs struct {
 	SpaceGuids                            map[string][]string
 	UserGUIDsBelongingToSpaceError        error
@@ -17,14 +19,30 @@ func NewFindsUserGUIDs() *FindsUserGUIDs {
 	}
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToSpace(spaceGUID, token string) ([]string, error) {
-	return finder.SpaceGuids[spaceGUID], finder.UserGUIDsBelongingToSpaceError
+func (finder FindsUserGUIDs) UserGUIDsBelongingToSpace(spaceGUID, token, after string, count int) ([]string, error) {
+	return guidPage(finder.SpaceGuids[spaceGUID], after, count), finder.UserGUIDsBelongingToSpaceError
+}
+
+func (finder FindsUserGUIDs) UserGUIDsBelongingToOrganization(orgGUID, role, token, after string, count int) ([]string, error) {
+	return guidPage(finder.OrganizationGuids[orgGUID], after, count), finder.UserGUIDsBelongingToOrganizationError
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToOrganization(orgGUID, role, token string) ([]string, error) {
-	return finder.OrganizationGuids[orgGUID], finder.UserGUIDsBelongingToOrganizationError
+func (finder FindsUserGUIDs) UserGUIDsBelongingToScope(scope, after string, count int) ([]string, error) {
+	return guidPage(finder.GUIDsWithScopes[scope], after, count), finder.UserGUIDsBelongingToScopeError
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToScope(scope string) ([]string, error) {
-	return finder.GUIDsWithScopes[scope], finder.UserGUIDsBelongingToScopeError
+func guidPage(guids []string, after string, count int) []string {
+	page := []string{}
+	for _, guid := range guids {
+		if guid > after {
+			page = append(page, guid)
+		}
+	}
+
+	sort.Strings(page)
+	if len(page) > count {
+		page = page[:count]
+	}
+
+	return page
 }
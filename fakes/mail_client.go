@@ -5,7 +5,13 @@ import "github.com/cloudfoundry-incubator/notifications/mail"
 type MailClient struct {
 	Messages     []mail.Message
 	SendError    error
+	SendErrorTo  string
 	ConnectError error
+	Relay        string
+
+	// RecipientErrors refuses the addresses it holds, while the message is
+	// still sent to the others.
+	RecipientErrors map[string]error
 }
 
 func NewMailClient() MailClient {
@@ -16,16 +22,27 @@ func (fake *MailClient) Connect() error {
 	return fake.ConnectError
 }
 
-func (fake *MailClient) Send(msg mail.Message) error {
+func (fake *MailClient) Send(msg mail.Message) (string, error) {
 	err := fake.Connect()
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	if fake.SendError != nil && (fake.SendErrorTo == "" || fake.SendErrorTo == msg.To) {
+		return fake.Relay, fake.SendError
 	}
 
-	if fake.SendError != nil {
-		return fake.SendError
+	refused := map[string]error{}
+	for _, recipient := range msg.Recipients() {
+		if recipientErr, ok := fake.RecipientErrors[recipient]; ok {
+			refused[recipient] = recipientErr
+		}
 	}
 
 	fake.Messages = append(fake.Messages, msg)
-	return nil
+	if len(refused) > 0 {
+		return fake.Relay, mail.RecipientsRefusedError{Refused: refused}
+	}
+
+	return fake.Relay, nil
 }
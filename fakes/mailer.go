@@ -9,7 +9,11 @@ import (
 
 type Mailer struct {
 	DeliverArguments map[string]interface{}
+	DeliverCalls     [][]strategies.User
 	Responses        []strategies.Response
+	EnqueueArguments map[string]interface{}
+	EnqueueCalls     [][]strategies.User
+	EnqueueError     error
 }
 
 func NewMailer() *Mailer {
@@ -26,6 +30,42 @@ func (fake *Mailer) Deliver(conn models.ConnectionInterface, users []strategies.
 		"client":     client,
 		"scope":      scope,
 	}
+	fake.DeliverCalls = append(fake.DeliverCalls, users)
 
-	return fake.Responses
+	return fake.responses(users)
+}
+
+func (fake *Mailer) Enqueue(conn models.ConnectionInterface, users []strategies.User, options postal.Options, space cf.CloudControllerSpace, org cf.CloudControllerOrganization, client, scope string) ([]strategies.Response, error) {
+	fake.EnqueueArguments = map[string]interface{}{
+		"connection": conn,
+		"users":      users,
+		"options":    options,
+		"space":      space,
+		"org":        org,
+		"client":     client,
+		"scope":      scope,
+	}
+	fake.EnqueueCalls = append(fake.EnqueueCalls, users)
+
+	if fake.EnqueueError != nil {
+		return []strategies.Response{}, fake.EnqueueError
+	}
+
+	return fake.responses(users), nil
+}
+
+func (fake *Mailer) responses(users []strategies.User) []strategies.Response {
+	if fake.Responses != nil {
+		return fake.Responses
+	}
+
+	responses := []strategies.Response{}
+	for _, user := range users {
+		responses = append(responses, strategies.Response{
+			Status:    postal.StatusQueued,
+			Recipient: user.GUID,
+		})
+	}
+
+	return responses
 }
@@ -0,0 +1,23 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/postal"
+
+type MessageArchiver struct {
+	Archived     []postal.ArchivedMessage
+	ArchiveCalls int
+	ArchiveError error
+}
+
+func NewMessageArchiver() *MessageArchiver {
+	return &MessageArchiver{}
+}
+
+func (fake *MessageArchiver) Archive(messages []postal.ArchivedMessage) error {
+	fake.ArchiveCalls++
+	if fake.ArchiveError != nil {
+		return fake.ArchiveError
+	}
+
+	fake.Archived = append(fake.Archived, messages...)
+	return nil
+}
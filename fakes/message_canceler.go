@@ -0,0 +1,15 @@
+package fakes
+
+type MessageCanceler struct {
+	CancelArguments []string
+	CancelError     error
+}
+
+func NewMessageCanceler() *MessageCanceler {
+	return &MessageCanceler{}
+}
+
+func (fake *MessageCanceler) Cancel(clientID, messageID string) error {
+	fake.CancelArguments = []string{clientID, messageID}
+	return fake.CancelError
+}
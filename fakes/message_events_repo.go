@@ -0,0 +1,83 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type MessageEventsRepo struct {
+	Events                   []models.MessageEvent
+	CreateError              error
+	FindAllError             error
+	DeleteByMessageIDsError  error
+	FindAllByMessageIDsCalls [][]string
+}
+
+func NewMessageEventsRepo() *MessageEventsRepo {
+	return &MessageEventsRepo{}
+}
+
+func (fake *MessageEventsRepo) Create(conn models.ConnectionInterface, event models.MessageEvent) (models.MessageEvent, error) {
+	if fake.CreateError != nil {
+		return models.MessageEvent{}, fake.CreateError
+	}
+
+	event.ID = len(fake.Events) + 1
+	fake.Events = append(fake.Events, event)
+	return event, nil
+}
+
+func (fake *MessageEventsRepo) FindAllByMessageID(conn models.ConnectionInterface, messageID string) ([]models.MessageEvent, error) {
+	if fake.FindAllError != nil {
+		return []models.MessageEvent{}, fake.FindAllError
+	}
+
+	events := []models.MessageEvent{}
+	for _, event := range fake.Events {
+		if event.MessageID == messageID {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (fake *MessageEventsRepo) FindAllByMessageIDs(conn models.ConnectionInterface, messageIDs []string) ([]models.MessageEvent, error) {
+	fake.FindAllByMessageIDsCalls = append(fake.FindAllByMessageIDsCalls, messageIDs)
+	if fake.FindAllError != nil {
+		return []models.MessageEvent{}, fake.FindAllError
+	}
+
+	events := []models.MessageEvent{}
+	for _, event := range fake.Events {
+		for _, messageID := range messageIDs {
+			if event.MessageID == messageID {
+				events = append(events, event)
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func (fake *MessageEventsRepo) DeleteByMessageIDs(conn models.ConnectionInterface, messageIDs []string) (int, error) {
+	if fake.DeleteByMessageIDsError != nil {
+		return 0, fake.DeleteByMessageIDsError
+	}
+
+	events := []models.MessageEvent{}
+	for _, event := range fake.Events {
+		deleted := false
+		for _, messageID := range messageIDs {
+			if event.MessageID == messageID {
+				deleted = true
+			}
+		}
+
+		if !deleted {
+			events = append(events, event)
+		}
+	}
+
+	count := len(fake.Events) - len(events)
+	fake.Events = events
+
+	return count, nil
+}
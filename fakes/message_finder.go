@@ -3,8 +3,9 @@ package fakes
 import "github.com/cloudfoundry-incubator/notifications/web/services"
 
 type MessageFinder struct {
-	Messages  map[string]services.Message
-	FindError error
+	Messages      map[string]services.Message
+	FindArguments []string
+	FindError     error
 }
 
 func NewMessageFinder() *MessageFinder {
@@ -13,6 +14,7 @@ func NewMessageFinder() *MessageFinder {
 	}
 }
 
-func (finder MessageFinder) Find(messageID string) (services.Message, error) {
+func (finder *MessageFinder) Find(clientID, messageID string) (services.Message, error) {
+	finder.FindArguments = []string{clientID, messageID}
 	return finder.Messages[messageID], finder.FindError
 }
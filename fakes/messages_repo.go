@@ -2,23 +2,28 @@ package fakes
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
 )
 
 type MessagesRepo struct {
-	Messages                map[string]models.Message
-	DeleteBeforeError       error
-	FindByIDError           error
-	UpsertError             error
-	DeleteBeforeInvocations []time.Time
+	Messages              map[string]models.Message
+	DeleteByIDsError      error
+	FindAllBeforeError    error
+	FindByIDError         error
+	FindAllByBatchIDError error
+	FindAllByJobIDError   error
+	CountByStatusError    error
+	UpsertError           error
+	FindAllBeforeTimes    []time.Time
+	FindAllBeforeScopes   []models.MessageScope
 }
 
 func NewMessagesRepo() *MessagesRepo {
 	return &MessagesRepo{
-		Messages:                make(map[string]models.Message),
-		DeleteBeforeInvocations: []time.Time{},
+		Messages: make(map[string]models.Message),
 	}
 }
 
@@ -40,14 +45,138 @@ func (fake MessagesRepo) Upsert(conn models.ConnectionInterface, message models.
 	return message, fake.UpsertError
 }
 
-func (fake *MessagesRepo) DeleteBefore(conn models.ConnectionInterface, thresholdTime time.Time) (int, error) {
+func (fake MessagesRepo) FindAllByBatchID(conn models.ConnectionInterface, clientID, batchID string, offset, limit int) ([]models.Message, error) {
+	if fake.FindAllByBatchIDError != nil {
+		return []models.Message{}, fake.FindAllByBatchIDError
+	}
+
+	var ids []string
+	for id, message := range fake.Messages {
+		if message.BatchID == batchID && message.ClientID == clientID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	messages := []models.Message{}
+	for i := offset; i < len(ids) && i < offset+limit; i++ {
+		messages = append(messages, fake.Messages[ids[i]])
+	}
+
+	return messages, nil
+}
+
+func (fake MessagesRepo) FindAllByJobID(conn models.ConnectionInterface, jobID int) ([]models.Message, error) {
+	if fake.FindAllByJobIDError != nil {
+		return []models.Message{}, fake.FindAllByJobIDError
+	}
+
+	var ids []string
+	for id, message := range fake.Messages {
+		if message.JobID == jobID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	messages := []models.Message{}
+	for _, id := range ids {
+		messages = append(messages, fake.Messages[id])
+	}
+
+	return messages, nil
+}
+
+func (fake MessagesRepo) CountByStatusForBatchID(conn models.ConnectionInterface, clientID, batchID string) (map[string]int, error) {
+	if fake.CountByStatusError != nil {
+		return map[string]int{}, fake.CountByStatusError
+	}
+
+	counts := map[string]int{}
+	for _, message := range fake.Messages {
+		if message.BatchID == batchID && message.ClientID == clientID {
+			counts[message.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (fake *MessagesRepo) FindAllBefore(conn models.ConnectionInterface, thresholdTime time.Time, scope models.MessageScope, after models.Message, limit int) ([]models.Message, error) {
+	fake.FindAllBeforeTimes = append(fake.FindAllBeforeTimes, time.Now())
+	fake.FindAllBeforeScopes = append(fake.FindAllBeforeScopes, scope)
+	if fake.FindAllBeforeError != nil {
+		return []models.Message{}, fake.FindAllBeforeError
+	}
+
+	var expired []models.Message
+	for _, message := range fake.Messages {
+		if message.UpdatedAt.Before(thresholdTime) && message.Status != "scheduled" && inScope(message, scope) {
+			expired = append(expired, message)
+		}
+	}
+	sort.Sort(messagesByUpdate(expired))
+
+	messages := []models.Message{}
+	for _, message := range expired {
+		if after.ID != "" && !messagesByUpdate([]models.Message{after, message}).Less(0, 1) {
+			continue
+		}
+
+		if len(messages) == limit {
+			break
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+type messagesByUpdate []models.Message
+
+func (messages messagesByUpdate) Len() int {
+	return len(messages)
+}
+
+func (messages messagesByUpdate) Swap(i, j int) {
+	messages[i], messages[j] = messages[j], messages[i]
+}
+
+func (messages messagesByUpdate) Less(i, j int) bool {
+	if messages[i].UpdatedAt.Equal(messages[j].UpdatedAt) {
+		return messages[i].ID < messages[j].ID
+	}
+
+	return messages[i].UpdatedAt.Before(messages[j].UpdatedAt)
+}
+
+func (fake *MessagesRepo) DeleteByIDs(conn models.ConnectionInterface, messageIDs []string) (int, error) {
+	if fake.DeleteByIDsError != nil {
+		return 0, fake.DeleteByIDsError
+	}
+
 	count := 0
-	for key, message := range fake.Messages {
-		if message.UpdatedAt.Before(thresholdTime) {
-			delete(fake.Messages, key)
-			count += 1
+	for _, messageID := range messageIDs {
+		if _, ok := fake.Messages[messageID]; ok {
+			delete(fake.Messages, messageID)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func inScope(message models.Message, scope models.MessageScope) bool {
+	if scope.ClientID != "" && message.ClientID != scope.ClientID {
+		return false
+	}
+
+	for _, clientID := range scope.ExcludedClientIDs {
+		if message.ClientID == clientID {
+			return false
 		}
 	}
-	fake.DeleteBeforeInvocations = append(fake.DeleteBeforeInvocations, time.Now())
-	return count, fake.DeleteBeforeError
+
+	return true
 }
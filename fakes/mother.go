@@ -2,9 +2,11 @@ package fakes
 
 import (
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
 	"github.com/ryanmoran/stack"
 )
@@ -23,6 +25,10 @@ func (mother Mother) EmailStrategy() strategies.EmailStrategy {
 	return strategies.EmailStrategy{}
 }
 
+func (mother Mother) EmailValidator() params.EmailValidator {
+	return params.EmailValidator{BulkLimit: 1000}
+}
+
 func (mother Mother) UserStrategy() strategies.UserStrategy {
 	return strategies.UserStrategy{}
 }
@@ -63,12 +69,48 @@ func (mother Mother) MessageFinder() services.MessageFinder {
 	return services.MessageFinder{}
 }
 
+func (mother Mother) MessageCanceler() postal.MessageCanceler {
+	return postal.MessageCanceler{}
+}
+
+func (mother Mother) TemplateRenderer() postal.TemplateRenderer {
+	return postal.TemplateRenderer{}
+}
+
+func (mother Mother) TemplateEscapingChecker() postal.TemplateEscapingCheckerInterface {
+	return NewTemplateEscapingChecker()
+}
+
+func (mother Mother) TestSender() postal.TestSender {
+	return postal.TestSender{}
+}
+
+func (mother Mother) BatchFinder() services.BatchFinder {
+	return services.BatchFinder{}
+}
+
+func (mother Mother) Unsubscriber() services.Unsubscriber {
+	return services.Unsubscriber{}
+}
+
 func (mother Mother) TemplateServiceObjects() (services.TemplateCreator, services.TemplateFinder,
 	services.TemplateUpdater, services.TemplateDeleter, services.TemplateLister,
 	services.TemplateAssigner, services.TemplateAssociationLister) {
 	return services.TemplateCreator{}, services.TemplateFinder{}, services.TemplateUpdater{}, services.TemplateDeleter{}, services.TemplateLister{}, services.TemplateAssigner{}, services.TemplateAssociationLister{}
 }
 
+func (mother Mother) TemplateVersionServiceObjects() (services.TemplateVersionFinder, services.TemplateRestorer) {
+	return services.TemplateVersionFinder{}, services.TemplateRestorer{}
+}
+
+func (mother Mother) DeadLetterServiceObjects() (services.DeadLetterFinder, services.DeadLetterRequeuer, services.DeadLetterPurger) {
+	return services.DeadLetterFinder{}, services.DeadLetterRequeuer{}, services.DeadLetterPurger{}
+}
+
+func (mother Mother) SuppressionServiceObjects() (services.SuppressionFinder, services.SuppressionUpdater, services.SuppressionDeleter) {
+	return services.SuppressionFinder{}, services.SuppressionUpdater{}, services.SuppressionDeleter{}
+}
+
 func (mother Mother) Database() models.DatabaseInterface {
 	return NewDatabase()
 }
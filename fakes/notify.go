@@ -10,9 +10,10 @@ import (
 )
 
 type Notify struct {
-	Response []byte
-	GUID     string
-	Error    error
+	Response  []byte
+	GUID      string
+	Validator handlers.ValidatorInterface
+	Error     error
 }
 
 func NewNotify() *Notify {
@@ -22,6 +23,14 @@ func NewNotify() *Notify {
 func (fake *Notify) Execute(connection models.ConnectionInterface, req *http.Request, context stack.Context,
 	guid string, strategy strategies.StrategyInterface, validator handlers.ValidatorInterface) ([]byte, error) {
 	fake.GUID = guid
+	fake.Validator = validator
+
+	return fake.Response, fake.Error
+}
+
+func (fake *Notify) Launch(connection models.ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy strategies.CampaignStrategyInterface, validator handlers.ValidatorInterface) ([]byte, error) {
+	fake.GUID = guid
 
 	return fake.Response, fake.Error
 }
@@ -3,17 +3,20 @@ package fakes
 import (
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
 )
 
 type PersistenceProvider struct {
 	database          *Database
 	DatabaseWasCalled bool
 	QueueWasCalled    bool
+	Checker           *TemplateEscapingChecker
 }
 
 func NewPersistenceProvider(database *Database) *PersistenceProvider {
 	return &PersistenceProvider{
 		database: database,
+		Checker:  NewTemplateEscapingChecker(),
 	}
 }
 
@@ -26,3 +29,7 @@ func (pp *PersistenceProvider) Queue() gobble.QueueInterface {
 	pp.QueueWasCalled = true
 	return NewQueue()
 }
+
+func (pp *PersistenceProvider) TemplateEscapingChecker() postal.TemplateEscapingCheckerInterface {
+	return pp.Checker
+}
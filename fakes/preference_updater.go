@@ -3,8 +3,10 @@ package fakes
 import "github.com/cloudfoundry-incubator/notifications/models"
 
 type PreferenceUpdater struct {
-	ExecuteArguments []interface{}
-	ExecuteError     error
+	ExecuteArguments        []interface{}
+	ExecuteError            error
+	DeliveryAddress         models.DeliveryAddress
+	SetDeliveryAddressError error
 }
 
 func NewPreferenceUpdater() *PreferenceUpdater {
@@ -15,3 +17,8 @@ func (fake *PreferenceUpdater) Execute(conn models.ConnectionInterface, preferen
 	fake.ExecuteArguments = append(fake.ExecuteArguments, preferences, globalUnsubscribe, userID)
 	return fake.ExecuteError
 }
+
+func (fake *PreferenceUpdater) SetDeliveryAddress(conn models.ConnectionInterface, address models.DeliveryAddress) error {
+	fake.DeliveryAddress = address
+	return fake.SetDeliveryAddressError
+}
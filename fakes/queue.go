@@ -3,9 +3,14 @@ package fakes
 import "github.com/cloudfoundry-incubator/notifications/gobble"
 
 type Queue struct {
-	jobs         chan gobble.Job
-	pk           int
-	EnqueueError error
+	jobs                chan gobble.Job
+	pk                  int
+	EnqueueError        error
+	EnqueueTransactions []gobble.ConnectionInterface
+	DeadLetters         []gobble.Job
+	CancelIDs           []int
+	CancelTransactions  []gobble.ConnectionInterface
+	CancelError         error
 }
 
 func NewQueue() *Queue {
@@ -27,10 +32,19 @@ func (fake *Queue) Enqueue(job gobble.Job) (gobble.Job, error) {
 	return job, nil
 }
 
+func (fake *Queue) EnqueueWithTransaction(job gobble.Job, transaction gobble.ConnectionInterface) (gobble.Job, error) {
+	fake.EnqueueTransactions = append(fake.EnqueueTransactions, transaction)
+	return fake.Enqueue(job)
+}
+
 func (fake *Queue) Reserve(string) <-chan gobble.Job {
 	return fake.jobs
 }
 
+func (fake *Queue) Heartbeat(job gobble.Job) error {
+	return nil
+}
+
 func (fake *Queue) Dequeue(job gobble.Job) {
 }
 
@@ -40,4 +54,16 @@ func (fake *Queue) Requeue(job gobble.Job) {
 	}(job)
 }
 
-func (fake *Queue) Unlock() {}
+func (fake *Queue) DeadLetter(job gobble.Job) {
+	fake.DeadLetters = append(fake.DeadLetters, job)
+}
+
+func (fake *Queue) CancelWithTransaction(id int, transaction gobble.ConnectionInterface) error {
+	fake.CancelTransactions = append(fake.CancelTransactions, transaction)
+	if fake.CancelError != nil {
+		return fake.CancelError
+	}
+
+	fake.CancelIDs = append(fake.CancelIDs, id)
+	return nil
+}
@@ -0,0 +1,15 @@
+package fakes
+
+type SuppressionDeleter struct {
+	DeleteArgument string
+	DeleteError    error
+}
+
+func NewSuppressionDeleter() *SuppressionDeleter {
+	return &SuppressionDeleter{}
+}
+
+func (fake *SuppressionDeleter) Delete(email string) error {
+	fake.DeleteArgument = email
+	return fake.DeleteError
+}
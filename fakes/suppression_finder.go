@@ -0,0 +1,29 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type SuppressionFinder struct {
+	Suppressions []models.Suppression
+	ListError    error
+	FindArgument string
+	FindError    error
+}
+
+func NewSuppressionFinder() *SuppressionFinder {
+	return &SuppressionFinder{
+		Suppressions: []models.Suppression{},
+	}
+}
+
+func (fake *SuppressionFinder) List() ([]models.Suppression, error) {
+	return fake.Suppressions, fake.ListError
+}
+
+func (fake *SuppressionFinder) Find(email string) (models.Suppression, error) {
+	fake.FindArgument = email
+	if fake.FindError != nil {
+		return models.Suppression{}, fake.FindError
+	}
+
+	return fake.Suppressions[0], nil
+}
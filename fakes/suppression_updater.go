@@ -0,0 +1,18 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type SuppressionUpdater struct {
+	SuppressArguments []string
+	Suppression       models.Suppression
+	SuppressError     error
+}
+
+func NewSuppressionUpdater() *SuppressionUpdater {
+	return &SuppressionUpdater{}
+}
+
+func (fake *SuppressionUpdater) Suppress(email, reason string) (models.Suppression, error) {
+	fake.SuppressArguments = []string{email, reason}
+	return fake.Suppression, fake.SuppressError
+}
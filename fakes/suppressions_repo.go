@@ -0,0 +1,80 @@
+package fakes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type SuppressionsRepo struct {
+	Suppressions map[string]models.Suppression
+	UpsertError  error
+	FindError    error
+	FindAllError error
+	DestroyError error
+}
+
+func NewSuppressionsRepo() *SuppressionsRepo {
+	return &SuppressionsRepo{
+		Suppressions: map[string]models.Suppression{},
+	}
+}
+
+func (fake *SuppressionsRepo) Upsert(conn models.ConnectionInterface, suppression models.Suppression) (models.Suppression, error) {
+	if fake.UpsertError != nil {
+		return models.Suppression{}, fake.UpsertError
+	}
+
+	suppression.Email = strings.ToLower(suppression.Email)
+	fake.Suppressions[suppression.Email] = suppression
+
+	return suppression, nil
+}
+
+func (fake *SuppressionsRepo) Find(conn models.ConnectionInterface, email string) (models.Suppression, error) {
+	if fake.FindError != nil {
+		return models.Suppression{}, fake.FindError
+	}
+
+	suppression, ok := fake.Suppressions[strings.ToLower(email)]
+	if !ok {
+		return models.Suppression{}, models.NewRecordNotFoundError("Suppression of %q could not be found", email)
+	}
+
+	return suppression, nil
+}
+
+func (fake *SuppressionsRepo) FindAll(conn models.ConnectionInterface) ([]models.Suppression, error) {
+	if fake.FindAllError != nil {
+		return []models.Suppression{}, fake.FindAllError
+	}
+
+	emails := []string{}
+	for email := range fake.Suppressions {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+
+	suppressions := []models.Suppression{}
+	for _, email := range emails {
+		suppressions = append(suppressions, fake.Suppressions[email])
+	}
+
+	return suppressions, nil
+}
+
+func (fake *SuppressionsRepo) Destroy(conn models.ConnectionInterface, email string) error {
+	if fake.DestroyError != nil {
+		return fake.DestroyError
+	}
+
+	email = strings.ToLower(email)
+	if _, ok := fake.Suppressions[email]; !ok {
+		return models.NewRecordNotFoundError("Suppression of %q could not be found", email)
+	}
+
+	delete(fake.Suppressions, email)
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/postal"
+
+type TemplateEscapingChecker struct {
+	CheckWasCalled  bool
+	ReportWasCalled bool
+	Flagged         []postal.FlaggedTemplate
+	CheckError      error
+}
+
+func NewTemplateEscapingChecker() *TemplateEscapingChecker {
+	return &TemplateEscapingChecker{
+		Flagged: []postal.FlaggedTemplate{},
+	}
+}
+
+func (fake *TemplateEscapingChecker) Check() ([]postal.FlaggedTemplate, error) {
+	fake.CheckWasCalled = true
+	return fake.Flagged, fake.CheckError
+}
+
+func (fake *TemplateEscapingChecker) Report() {
+	fake.ReportWasCalled = true
+}
@@ -0,0 +1,20 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/postal"
+
+type TemplateRenderer struct {
+	RenderTemplateID string
+	RenderDelivery   postal.Delivery
+	Rendered         postal.RenderedTemplate
+	RenderError      error
+}
+
+func NewTemplateRenderer() *TemplateRenderer {
+	return &TemplateRenderer{}
+}
+
+func (fake *TemplateRenderer) Render(templateID string, delivery postal.Delivery) (postal.RenderedTemplate, error) {
+	fake.RenderTemplateID = templateID
+	fake.RenderDelivery = delivery
+	return fake.Rendered, fake.RenderError
+}
@@ -0,0 +1,22 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type TemplateRestorer struct {
+	RestoreTemplateID string
+	RestoreVersion    int
+	RestoreAuthor     string
+	Restored          models.TemplateVersion
+	RestoreError      error
+}
+
+func NewTemplateRestorer() *TemplateRestorer {
+	return &TemplateRestorer{}
+}
+
+func (fake *TemplateRestorer) Restore(templateID string, number int, author string) (models.TemplateVersion, error) {
+	fake.RestoreTemplateID = templateID
+	fake.RestoreVersion = number
+	fake.RestoreAuthor = author
+	return fake.Restored, fake.RestoreError
+}
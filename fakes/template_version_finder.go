@@ -0,0 +1,28 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type TemplateVersionFinder struct {
+	Versions        []models.TemplateVersion
+	FindAllArgument string
+	FindAllError    error
+	FindTemplateID  string
+	FindVersion     int
+	Version         models.TemplateVersion
+	FindError       error
+}
+
+func NewTemplateVersionFinder() *TemplateVersionFinder {
+	return &TemplateVersionFinder{}
+}
+
+func (fake *TemplateVersionFinder) FindAll(templateID string) ([]models.TemplateVersion, error) {
+	fake.FindAllArgument = templateID
+	return fake.Versions, fake.FindAllError
+}
+
+func (fake *TemplateVersionFinder) Find(templateID string, number int) (models.TemplateVersion, error) {
+	fake.FindTemplateID = templateID
+	fake.FindVersion = number
+	return fake.Version, fake.FindError
+}
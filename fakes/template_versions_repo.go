@@ -0,0 +1,52 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type TemplateVersionsRepo struct {
+	Versions     map[string][]models.TemplateVersion
+	Created      []models.TemplateVersion
+	CreateError  error
+	FindAllError error
+	FindError    error
+}
+
+func NewTemplateVersionsRepo() *TemplateVersionsRepo {
+	return &TemplateVersionsRepo{
+		Versions: make(map[string][]models.TemplateVersion),
+	}
+}
+
+func (fake *TemplateVersionsRepo) Create(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	if fake.CreateError != nil {
+		return models.TemplateVersion{}, fake.CreateError
+	}
+
+	version.Version = len(fake.Versions[version.TemplateID]) + 1
+	fake.Versions[version.TemplateID] = append(fake.Versions[version.TemplateID], version)
+	fake.Created = append(fake.Created, version)
+
+	return version, nil
+}
+
+func (fake *TemplateVersionsRepo) FindAllByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	versions := []models.TemplateVersion{}
+	for i := len(fake.Versions[templateID]) - 1; i >= 0; i-- {
+		versions = append(versions, fake.Versions[templateID][i])
+	}
+
+	return versions, fake.FindAllError
+}
+
+func (fake *TemplateVersionsRepo) FindByVersion(conn models.ConnectionInterface, templateID string, number int) (models.TemplateVersion, error) {
+	if fake.FindError != nil {
+		return models.TemplateVersion{}, fake.FindError
+	}
+
+	for _, version := range fake.Versions[templateID] {
+		if version.Version == number {
+			return version, nil
+		}
+	}
+
+	return models.TemplateVersion{}, models.NewRecordNotFoundError("Version %d of template %q could not be found", number, templateID)
+}
@@ -0,0 +1,19 @@
+package fakes
+
+import "github.com/cloudfoundry-incubator/notifications/postal"
+
+type TestSender struct {
+	SendTemplateID string
+	SendDelivery   postal.Delivery
+	SendError      error
+}
+
+func NewTestSender() *TestSender {
+	return &TestSender{}
+}
+
+func (fake *TestSender) Send(templateID string, delivery postal.Delivery) error {
+	fake.SendTemplateID = templateID
+	fake.SendDelivery = delivery
+	return fake.SendError
+}
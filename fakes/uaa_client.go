@@ -3,21 +3,15 @@ package fakes
 import "github.com/pivotal-cf/uaa-sso-golang/uaa"
 
 type UAAClient struct {
-	ClientToken               uaa.Token
-	ClientTokenError          error
-	UsersByID                 map[string]uaa.User
-	ErrorForUserByID          error
-	AccessToken               string
-	UsersGUIDsByScopeResponse map[string][]string
-	UsersGUIDsByScopeError    error
-	AllUsersError             error
-	AllUsersData              []uaa.User
+	ClientToken      uaa.Token
+	ClientTokenError error
+	UsersByID        map[string]uaa.User
+	ErrorForUserByID error
+	AccessToken      string
 }
 
 func NewUAAClient() *UAAClient {
-	return &UAAClient{
-		UsersGUIDsByScopeResponse: make(map[string][]string),
-	}
+	return &UAAClient{}
 }
 
 func (fake *UAAClient) SetToken(token string) {
@@ -38,11 +32,3 @@ func (fake UAAClient) UsersEmailsByIDs(ids ...string) ([]uaa.User, error) {
 
 	return users, fake.ErrorForUserByID
 }
-
-func (fake *UAAClient) UsersGUIDsByScope(scope string) ([]string, error) {
-	return fake.UsersGUIDsByScopeResponse[scope], fake.UsersGUIDsByScopeError
-}
-
-func (fake *UAAClient) AllUsers() ([]uaa.User, error) {
-	return fake.AllUsersData, fake.AllUsersError
-}
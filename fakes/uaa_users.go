@@ -0,0 +1,18 @@
+package fakes
+
+type UAAUsers struct {
+	Pages              map[string][]string
+	UserGUIDsArguments [][]interface{}
+	UserGUIDsError     error
+}
+
+func NewUAAUsers() *UAAUsers {
+	return &UAAUsers{
+		Pages: make(map[string][]string),
+	}
+}
+
+func (fake *UAAUsers) UserGUIDs(filter string, count int) ([]string, error) {
+	fake.UserGUIDsArguments = append(fake.UserGUIDsArguments, []interface{}{filter, count})
+	return fake.Pages[filter], fake.UserGUIDsError
+}
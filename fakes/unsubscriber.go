@@ -0,0 +1,28 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+)
+
+type Unsubscriber struct {
+	FindArguments        []string
+	FindError            error
+	UnsubscribeArguments []string
+	Unsubscription       services.Unsubscription
+	UnsubscribeError     error
+}
+
+func NewUnsubscriber() *Unsubscriber {
+	return &Unsubscriber{}
+}
+
+func (fake *Unsubscriber) Find(conn models.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error) {
+	fake.FindArguments = append(fake.FindArguments, unsubscribeID)
+	return fake.Unsubscription, fake.FindError
+}
+
+func (fake *Unsubscriber) Unsubscribe(conn models.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error) {
+	fake.UnsubscribeArguments = append(fake.UnsubscribeArguments, unsubscribeID)
+	return fake.Unsubscription, fake.UnsubscribeError
+}
@@ -4,4 +4,6 @@ import "time"
 
 type Config struct {
 	WaitMaxDuration time.Duration
+	LeaseDuration   time.Duration
+	AgingInterval   time.Duration
 }
@@ -32,6 +32,7 @@ var _ = Describe("Database", func() {
 		}
 
 		Expect(tables).To(ContainElement("jobs"))
+		Expect(tables).To(ContainElement("dead_letters"))
 
 		rows, err = gobble.Database().Connection.Db.Query("SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_NAME = 'jobs'")
 		if err != nil {
@@ -75,6 +76,14 @@ var _ = Describe("Database", func() {
 			Field: "active_at",
 			Type:  "timestamp",
 		}))
+		Expect(columns).To(ContainElement(Column{
+			Field: "expires_at",
+			Type:  "datetime",
+		}))
+		Expect(columns).To(ContainElement(Column{
+			Field: "priority",
+			Type:  "int",
+		}))
 	})
 
 	It("only ever instantiates a single DB object", func() {
@@ -0,0 +1,42 @@
+package gobble
+
+import (
+	"fmt"
+	"time"
+)
+
+type DeadLetter struct {
+	ID         int       `db:"id"`
+	JobID      int       `db:"job_id"`
+	Payload    string    `db:"payload"`
+	RetryCount int       `db:"retry_count"`
+	Priority   int       `db:"priority"`
+	LastError  string    `db:"last_error"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewDeadLetter(job Job) DeadLetter {
+	return DeadLetter{
+		JobID:      job.ID,
+		Payload:    job.Payload,
+		RetryCount: job.RetryCount,
+		Priority:   job.Priority,
+		LastError:  job.LastError,
+		CreatedAt:  time.Now().Truncate(1 * time.Second).UTC(),
+	}
+}
+
+func (deadLetter DeadLetter) Job() Job {
+	return Job{
+		Payload:  deadLetter.Payload,
+		Priority: deadLetter.Priority,
+	}
+}
+
+type DeadLetterNotFoundError struct {
+	ID int
+}
+
+func (err DeadLetterNotFoundError) Error() string {
+	return fmt.Sprintf("Dead letter with ID %d could not be found", err.ID)
+}
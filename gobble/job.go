@@ -5,14 +5,26 @@ import (
 	"time"
 )
 
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
 type Job struct {
-	ID          int       `db:"id"`
-	WorkerID    string    `db:"worker_id"`
-	Payload     string    `db:"payload"`
-	Version     int64     `db:"version"`
-	RetryCount  int       `db:"retry_count"`
-	ActiveAt    time.Time `db:"active_at"`
-	ShouldRetry bool      `db:"-"`
+	ID               int       `db:"id"`
+	WorkerID         string    `db:"worker_id"`
+	Payload          string    `db:"payload"`
+	Version          int64     `db:"version"`
+	RetryCount       int       `db:"retry_count"`
+	ActiveAt         time.Time `db:"active_at"`
+	ExpiresAt        time.Time `db:"expires_at"`
+	Priority         int       `db:"priority"`
+	ShouldRetry      bool      `db:"-"`
+	ShouldDeadLetter bool      `db:"-"`
+	LastError        string    `db:"-"`
+
+	leaseLost chan struct{} `db:"-"`
 }
 
 func NewJob(data interface{}) Job {
@@ -30,9 +42,40 @@ func (job Job) Unmarshal(v interface{}) error {
 	return json.Unmarshal([]byte(job.Payload), v)
 }
 
+// LeaseLost returns a channel that is closed when the worker performing the
+// job loses its lease to another worker. The job should then be abandoned, as
+// the other worker performs it again.
+func (job Job) LeaseLost() <-chan struct{} {
+	return job.leaseLost
+}
+
+// IsLeaseLost reports whether the lease on the job has been lost.
+func (job Job) IsLeaseLost() bool {
+	select {
+	case <-job.leaseLost:
+		return true
+	default:
+		return false
+	}
+}
+
+// LoseLease marks the lease on the job as lost to another worker.
+func (job *Job) LoseLease() {
+	if job.leaseLost == nil {
+		job.leaseLost = make(chan struct{})
+	}
+	close(job.leaseLost)
+}
+
 func (job *Job) Retry(duration time.Duration) {
 	job.WorkerID = ""
 	job.RetryCount++
 	job.ActiveAt = time.Now().Add(duration)
 	job.ShouldRetry = true
 }
+
+func (job *Job) DeadLetter(lastError string) {
+	job.LastError = lastError
+	job.ShouldRetry = false
+	job.ShouldDeadLetter = true
+}
@@ -58,4 +58,33 @@ var _ = Describe("Job", func() {
 			Expect(job.ShouldRetry).To(BeTrue())
 		})
 	})
+
+	Describe("DeadLetter", func() {
+		It("sets up the job to be moved to the dead letters", func() {
+			job := gobble.NewJob("the data")
+			job.ShouldRetry = true
+
+			job.DeadLetter("SMTP server unavailable")
+
+			Expect(job.LastError).To(Equal("SMTP server unavailable"))
+			Expect(job.ShouldRetry).To(BeFalse())
+			Expect(job.ShouldDeadLetter).To(BeTrue())
+		})
+	})
+
+	Describe("IsLeaseLost", func() {
+		It("is false for a job that is not being performed", func() {
+			job := gobble.NewJob("the data")
+
+			Expect(job.IsLeaseLost()).To(BeFalse())
+		})
+
+		It("is true once the lease has been lost", func() {
+			job := gobble.NewJob("the data")
+			job.LoseLease()
+
+			Expect(job.IsLeaseLost()).To(BeTrue())
+			Eventually(job.LeaseLost()).Should(BeClosed())
+		})
+	})
 })
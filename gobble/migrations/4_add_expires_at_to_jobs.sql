@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE `jobs` ADD expires_at datetime NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+-- +goose Down
+ALTER TABLE `jobs` DROP COLUMN expires_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE `dead_letters` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `job_id` int(11) NOT NULL,
+  `payload` longtext,
+  `retry_count` int(11) NOT NULL DEFAULT 0,
+  `last_error` text,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8;
+
+-- +goose Down
+DROP TABLE dead_letters;
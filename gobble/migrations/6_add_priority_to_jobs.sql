@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE `jobs` ADD priority int(11) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE `jobs` DROP COLUMN priority;
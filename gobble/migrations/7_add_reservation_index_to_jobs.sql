@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX jobs_reservation ON `jobs` (worker_id, expires_at, active_at, priority);
+
+-- +goose Down
+DROP INDEX jobs_reservation ON `jobs`;
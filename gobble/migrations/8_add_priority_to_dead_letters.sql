@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE `dead_letters` ADD priority int(11) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE `dead_letters` DROP COLUMN priority;
@@ -2,20 +2,52 @@ package gobble
 
 import (
 	"database/sql"
+	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/coopernurse/gorp"
 )
 
-var WaitMaxDuration = 5 * time.Second
+var (
+	WaitMaxDuration = 5 * time.Second
+	LeaseDuration   = 1 * time.Minute
+	AgingInterval   = 5 * time.Minute
+)
 
 type QueueInterface interface {
 	Enqueue(Job) (Job, error)
+	EnqueueWithTransaction(Job, ConnectionInterface) (Job, error)
 	Reserve(string) <-chan Job
+	Heartbeat(Job) error
 	Dequeue(Job)
 	Requeue(Job)
-	Unlock()
+	DeadLetter(Job)
+	CancelWithTransaction(int, ConnectionInterface) error
+}
+
+// ConnectionInterface is satisfied by the connections and transactions of
+// other clients of the database that holds the jobs table.
+type ConnectionInterface interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+}
+
+type LeaseLostError struct {
+	JobID    int
+	WorkerID string
+}
+
+func (err LeaseLostError) Error() string {
+	return fmt.Sprintf("Job %d is no longer reserved by %s", err.JobID, err.WorkerID)
+}
+
+type JobNotCancelableError struct {
+	JobID int
+}
+
+func (err JobNotCancelableError) Error() string {
+	return fmt.Sprintf("Job %d has already been reserved or no longer exists", err.JobID)
 }
 
 type Queue struct {
@@ -29,6 +61,14 @@ func NewQueue(config Config) *Queue {
 		config.WaitMaxDuration = WaitMaxDuration
 	}
 
+	if config.LeaseDuration == 0 {
+		config.LeaseDuration = LeaseDuration
+	}
+
+	if config.AgingInterval == 0 {
+		config.AgingInterval = AgingInterval
+	}
+
 	return &Queue{
 		database: Database(),
 		config:   config,
@@ -36,6 +76,10 @@ func NewQueue(config Config) *Queue {
 }
 
 func (queue *Queue) Enqueue(job Job) (Job, error) {
+	if job.ActiveAt.IsZero() {
+		job.ActiveAt = time.Now()
+	}
+
 	err := queue.database.Connection.Insert(&job)
 	if err != nil {
 		return job, err
@@ -44,9 +88,34 @@ func (queue *Queue) Enqueue(job Job) (Job, error) {
 	return job, nil
 }
 
+// EnqueueWithTransaction inserts the job through the given transaction, so
+// that it only becomes visible to workers if that transaction commits.
+func (queue *Queue) EnqueueWithTransaction(job Job, transaction ConnectionInterface) (Job, error) {
+	if job.ActiveAt.IsZero() {
+		job.ActiveAt = time.Now()
+	}
+
+	result, err := transaction.Exec("INSERT INTO `jobs` (`worker_id`, `payload`, `version`, `retry_count`, `active_at`, `expires_at`, `priority`) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		job.WorkerID, job.Payload, job.Version, job.RetryCount, job.ActiveAt, job.ExpiresAt, job.Priority)
+	if err != nil {
+		return job, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return job, err
+	}
+	job.ID = int(id)
+
+	return job, nil
+}
+
 func (queue *Queue) Requeue(job Job) {
 	_, err := queue.database.Connection.Update(&job)
 	if err != nil {
+		if _, ok := err.(gorp.OptimisticLockError); ok {
+			return
+		}
 		panic(err)
 	}
 }
@@ -86,24 +155,202 @@ func (queue *Queue) reserve(channel chan Job, workerID string) {
 	channel <- job
 }
 
+// Dequeue and Requeue ignore jobs whose lease has expired and been reclaimed
+// by another worker, as that worker is now responsible for the job.
 func (queue *Queue) Dequeue(job Job) {
 	_, err := queue.database.Connection.Delete(&job)
 	if err != nil {
+		if _, ok := err.(gorp.OptimisticLockError); ok {
+			return
+		}
 		panic(err)
 	}
 }
 
-func (queue Queue) Unlock() {
-	_, err := queue.database.Connection.Exec("UPDATE `jobs` set `worker_id` = \"\" WHERE `worker_id` != \"\"")
+// CancelWithTransaction removes a job that has not yet been reserved by a
+// worker, through the given transaction, so that the job stays in the queue
+// unless that transaction commits. It returns a JobNotCancelableError when
+// the job is being worked or no longer exists.
+func (queue *Queue) CancelWithTransaction(id int, transaction ConnectionInterface) error {
+	result, err := transaction.Exec("DELETE FROM `jobs` WHERE `id` = ? AND `worker_id` = \"\"", id)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return JobNotCancelableError{JobID: id}
+	}
+
+	return nil
+}
+
+// DeadLetter moves a job that has exhausted its retries out of the queue and
+// into the dead_letters table so that it can be inspected and requeued later.
+func (queue *Queue) DeadLetter(job Job) {
+	transaction, err := queue.database.Connection.Begin()
 	if err != nil {
 		panic(err)
 	}
+
+	deadLetter := NewDeadLetter(job)
+	err = transaction.Insert(&deadLetter)
+	if err != nil {
+		transaction.Rollback()
+		panic(err)
+	}
+
+	_, err = transaction.Delete(&job)
+	if err != nil {
+		transaction.Rollback()
+		if _, ok := err.(gorp.OptimisticLockError); ok {
+			return
+		}
+		panic(err)
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeadLetters returns a page of the dead letters, oldest first. Pages start
+// at 1.
+func (queue *Queue) DeadLetters(page, perPage int) ([]DeadLetter, error) {
+	deadLetters := []DeadLetter{}
+	_, err := queue.database.Connection.Select(&deadLetters, "SELECT * FROM `dead_letters` ORDER BY `id` LIMIT ? OFFSET ?", perPage, (page-1)*perPage)
+	if err != nil {
+		return deadLetters, err
+	}
+
+	return deadLetters, nil
+}
+
+func (queue *Queue) FindDeadLetter(id int) (DeadLetter, error) {
+	deadLetter := DeadLetter{}
+	err := queue.database.Connection.SelectOne(&deadLetter, "SELECT * FROM `dead_letters` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return deadLetter, DeadLetterNotFoundError{ID: id}
+		}
+		return deadLetter, err
+	}
+
+	return deadLetter, nil
 }
 
+// RequeueDeadLetter puts the payload of a dead letter back on the queue as a
+// fresh job and removes the dead letter. The dead letter is locked while it is
+// requeued, so that concurrent requests cannot requeue it twice.
+func (queue *Queue) RequeueDeadLetter(id int) (Job, error) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		return Job{}, err
+	}
+
+	deadLetter := DeadLetter{}
+	err = transaction.SelectOne(&deadLetter, "SELECT * FROM `dead_letters` WHERE `id` = ? FOR UPDATE", id)
+	if err != nil {
+		transaction.Rollback()
+		if err == sql.ErrNoRows {
+			return Job{}, DeadLetterNotFoundError{ID: id}
+		}
+		return Job{}, err
+	}
+
+	job := deadLetter.Job()
+	job.ActiveAt = time.Now()
+	err = transaction.Insert(&job)
+	if err != nil {
+		transaction.Rollback()
+		return Job{}, err
+	}
+
+	_, err = transaction.Delete(&deadLetter)
+	if err != nil {
+		transaction.Rollback()
+		return Job{}, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return Job{}, err
+	}
+
+	return job, nil
+}
+
+func (queue *Queue) PurgeDeadLetter(id int) error {
+	deadLetter, err := queue.FindDeadLetter(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = queue.database.Connection.Delete(&deadLetter)
+	return err
+}
+
+// Heartbeat extends the lease on a reserved job. It returns a LeaseLostError
+// when the job is no longer reserved by the worker that holds it. Every
+// reservation bumps the version of the job, so a job that has been reclaimed
+// by a worker with the same ID, such as one in another process that happens
+// to share its PID, is not mistaken for the same reservation.
+func (queue *Queue) Heartbeat(job Job) error {
+	result, err := queue.database.Connection.Exec("UPDATE `jobs` SET `expires_at` = ? WHERE `id` = ? AND `worker_id` = ? AND `version` = ?",
+		time.Now().Add(queue.config.LeaseDuration), job.ID, job.WorkerID, job.Version)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return LeaseLostError{JobID: job.ID, WorkerID: job.WorkerID}
+	}
+
+	return nil
+}
+
+// priorityLanes lists the priorities jobs are reserved by. Jobs with any other
+// priority are never reserved.
+var priorityLanes = []int{PriorityHigh, PriorityNormal, PriorityLow}
+
+// findJob picks the available job with the highest effective priority. A job
+// below PriorityHigh gains one priority level for every AgingInterval it has
+// been waiting, so that low priority work is not starved by a steady stream of
+// normal priority jobs. The bonus never lifts a job into the PriorityHigh lane,
+// so high priority jobs are always reserved first.
+//
+// Within a lane the oldest job always has the highest effective priority, so
+// only the oldest available job of each lane is ranked.
 func (queue *Queue) findJob() Job {
+	lanes := make([]string, len(priorityLanes))
+	for i := range priorityLanes {
+		lanes[i] = "(SELECT * FROM `jobs` WHERE (`worker_id` = \"\" OR `expires_at` <= ?) AND `active_at` <= ? AND `priority` = ? " +
+			"ORDER BY `active_at` ASC, `id` ASC LIMIT 1)"
+	}
+	query := "SELECT * FROM (" + strings.Join(lanes, " UNION ALL ") + ") AS `candidates` " +
+		"ORDER BY LEAST(`priority` + FLOOR(TIMESTAMPDIFF(SECOND, `active_at`, ?) / ?), GREATEST(`priority`, ?)) DESC, `active_at` ASC, `id` ASC LIMIT 1"
+
 	job := Job{}
 	for job.ID == 0 {
-		err := queue.database.Connection.SelectOne(&job, "SELECT * FROM `jobs` WHERE `worker_id` = \"\" AND `active_at` <= ? LIMIT 1", time.Now())
+		now := time.Now()
+
+		args := []interface{}{}
+		for _, priority := range priorityLanes {
+			args = append(args, now, now, priority)
+		}
+		args = append(args, now, queue.config.AgingInterval.Seconds(), PriorityHigh-1)
+
+		err := queue.database.Connection.SelectOne(&job, query, args...)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				job = Job{}
@@ -118,6 +365,9 @@ func (queue *Queue) findJob() Job {
 
 func (queue *Queue) updateJob(job Job, workerID string) (Job, error) {
 	job.WorkerID = workerID
+	if workerID != "" {
+		job.ExpiresAt = time.Now().Add(queue.config.LeaseDuration)
+	}
 	_, err := queue.database.Connection.Update(&job)
 	if err != nil {
 		return job, err
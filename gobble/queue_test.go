@@ -25,6 +25,15 @@ var _ = Describe("Queue", func() {
 	})
 
 	Describe("Enqueue", func() {
+		It("makes the job active immediately when no active time is given", func() {
+			job, err := queue.Enqueue(gobble.Job{})
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(job.ActiveAt).To(BeTemporally("~", time.Now(), 5*time.Second))
+		})
+
 		It("sticks the job in the database table", func() {
 			job := gobble.NewJob(map[string]bool{
 				"testing": true,
@@ -48,6 +57,58 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("EnqueueWithTransaction", func() {
+		It("only makes the job visible once the transaction commits", func() {
+			transaction, err := gobble.Database().Connection.Begin()
+			if err != nil {
+				panic(err)
+			}
+
+			job, err := queue.EnqueueWithTransaction(gobble.NewJob(map[string]bool{"testing": true}), transaction)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).NotTo(BeZero())
+			Expect(job.ActiveAt).To(BeTemporally("~", time.Now(), 5*time.Second))
+
+			count, err := gobble.Database().Connection.SelectInt("SELECT COUNT(*) FROM `jobs`")
+			if err != nil {
+				panic(err)
+			}
+			Expect(count).To(Equal(int64(0)))
+
+			err = transaction.Commit()
+			if err != nil {
+				panic(err)
+			}
+
+			reloaded, err := gobble.Database().Connection.Get(gobble.Job{}, job.ID)
+			if err != nil {
+				panic(err)
+			}
+			Expect(reloaded.(*gobble.Job).Payload).To(Equal(job.Payload))
+		})
+
+		It("discards the job when the transaction rolls back", func() {
+			transaction, err := gobble.Database().Connection.Begin()
+			if err != nil {
+				panic(err)
+			}
+
+			_, err = queue.EnqueueWithTransaction(gobble.Job{}, transaction)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = transaction.Rollback()
+			if err != nil {
+				panic(err)
+			}
+
+			count, err := gobble.Database().Connection.SelectInt("SELECT COUNT(*) FROM `jobs`")
+			if err != nil {
+				panic(err)
+			}
+			Expect(count).To(Equal(int64(0)))
+		})
+	})
+
 	Describe("Requeue", func() {
 		It("updates the queue in the database", func() {
 			job := gobble.NewJob(map[string]bool{
@@ -181,6 +242,78 @@ var _ = Describe("Queue", func() {
 
 			Expect(job.ID).To(Equal(job2.ID))
 		})
+
+		It("picks higher priority jobs before lower priority jobs", func() {
+			_, err := queue.Enqueue(gobble.Job{Priority: gobble.PriorityLow})
+			if err != nil {
+				panic(err)
+			}
+			_, err = queue.Enqueue(gobble.Job{Priority: gobble.PriorityNormal})
+			if err != nil {
+				panic(err)
+			}
+			highPriorityJob, err := queue.Enqueue(gobble.Job{Priority: gobble.PriorityHigh})
+			if err != nil {
+				panic(err)
+			}
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(highPriorityJob.ID))
+		})
+
+		It("ages lower priority jobs so that they are not starved", func() {
+			queue = gobble.NewQueue(gobble.Config{
+				WaitMaxDuration: 50 * time.Millisecond,
+				AgingInterval:   1 * time.Minute,
+			})
+
+			oldJob, err := queue.Enqueue(gobble.Job{
+				Priority: gobble.PriorityLow,
+				ActiveAt: time.Now().Add(-3 * time.Minute),
+			})
+			if err != nil {
+				panic(err)
+			}
+			_, err = queue.Enqueue(gobble.Job{Priority: gobble.PriorityNormal})
+			if err != nil {
+				panic(err)
+			}
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(oldJob.ID))
+		})
+
+		It("never ages jobs past a high priority job", func() {
+			queue = gobble.NewQueue(gobble.Config{
+				WaitMaxDuration: 50 * time.Millisecond,
+				AgingInterval:   1 * time.Minute,
+			})
+
+			_, err := queue.Enqueue(gobble.Job{
+				Priority: gobble.PriorityNormal,
+				ActiveAt: time.Now().Add(-30 * time.Minute),
+			})
+			if err != nil {
+				panic(err)
+			}
+			_, err = queue.Enqueue(gobble.Job{
+				Priority: gobble.PriorityLow,
+				ActiveAt: time.Now().Add(-30 * time.Minute),
+			})
+			if err != nil {
+				panic(err)
+			}
+			highPriorityJob, err := queue.Enqueue(gobble.Job{Priority: gobble.PriorityHigh})
+			if err != nil {
+				panic(err)
+			}
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(highPriorityJob.ID))
+		})
 	})
 
 	Describe("Dequeue", func() {
@@ -204,24 +337,346 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
-	Describe("Unlock", func() {
-		It("clears the workerID values for any jobs in the queue", func() {
+	Describe("CancelWithTransaction", func() {
+		It("deletes a job that has not been reserved", func() {
+			job, err := queue.Enqueue(gobble.Job{
+				ActiveAt: time.Now().Add(1 * time.Hour),
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			err = queue.CancelWithTransaction(job.ID, gobble.Database().Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			if err != nil {
+				panic(err)
+			}
+			Expect(results).To(HaveLen(0))
+		})
+
+		It("returns a JobNotCancelableError when the job has been reserved", func() {
 			queue.Enqueue(gobble.Job{})
-			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+			job := <-queue.Reserve("my-worker")
+
+			err := queue.CancelWithTransaction(job.ID, gobble.Database().Connection)
+			Expect(err).To(Equal(gobble.JobNotCancelableError{JobID: job.ID}))
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
 			if err != nil {
 				panic(err)
 			}
 			Expect(results).To(HaveLen(1))
+		})
 
-			<-queue.Reserve("my-worker")
-			results, err = gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+		It("returns a JobNotCancelableError when the job does not exist", func() {
+			err := queue.CancelWithTransaction(42, gobble.Database().Connection)
+			Expect(err).To(Equal(gobble.JobNotCancelableError{JobID: 42}))
+		})
+	})
+
+	Describe("DeadLetter", func() {
+		It("moves the job into the dead letters table", func() {
+			job, err := queue.Enqueue(gobble.Job{
+				Payload:  "the-payload",
+				Priority: gobble.PriorityHigh,
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			job.RetryCount = 10
+			job.DeadLetter("SMTP server unavailable")
+			queue.DeadLetter(job)
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
 			if err != nil {
 				panic(err)
 			}
 			Expect(results).To(HaveLen(0))
 
-			queue.Unlock()
-			results, err = gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+			deadLetters, err := queue.DeadLetters(1, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(1))
+
+			deadLetter := deadLetters[0]
+			Expect(deadLetter.JobID).To(Equal(job.ID))
+			Expect(deadLetter.Payload).To(Equal("the-payload"))
+			Expect(deadLetter.RetryCount).To(Equal(10))
+			Expect(deadLetter.Priority).To(Equal(gobble.PriorityHigh))
+			Expect(deadLetter.LastError).To(Equal("SMTP server unavailable"))
+			Expect(deadLetter.CreatedAt).To(BeTemporally("~", time.Now(), 5*time.Second))
+		})
+	})
+
+	Describe("DeadLetters", func() {
+		It("returns a page of the dead letters, oldest first", func() {
+			for _, payload := range []string{"first", "second", "third"} {
+				job, err := queue.Enqueue(gobble.Job{Payload: payload})
+				if err != nil {
+					panic(err)
+				}
+				queue.DeadLetter(job)
+			}
+
+			deadLetters, err := queue.DeadLetters(1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(2))
+			Expect(deadLetters[0].Payload).To(Equal("first"))
+			Expect(deadLetters[1].Payload).To(Equal("second"))
+
+			deadLetters, err = queue.DeadLetters(2, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(1))
+			Expect(deadLetters[0].Payload).To(Equal("third"))
+		})
+	})
+
+	Describe("FindDeadLetter", func() {
+		It("finds the dead letter with the given ID", func() {
+			job, err := queue.Enqueue(gobble.Job{
+				Payload: "the-payload",
+			})
+			if err != nil {
+				panic(err)
+			}
+			queue.DeadLetter(job)
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+
+			deadLetter, err := queue.FindDeadLetter(deadLetters[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetter).To(Equal(deadLetters[0]))
+		})
+
+		It("returns a DeadLetterNotFoundError when the dead letter does not exist", func() {
+			_, err := queue.FindDeadLetter(42)
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("RequeueDeadLetter", func() {
+		It("puts the payload back on the queue and removes the dead letter", func() {
+			job, err := queue.Enqueue(gobble.Job{
+				Payload:    "the-payload",
+				RetryCount: 10,
+			})
+			if err != nil {
+				panic(err)
+			}
+			queue.DeadLetter(job)
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+
+			requeuedJob, err := queue.RequeueDeadLetter(deadLetters[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeuedJob.Payload).To(Equal("the-payload"))
+			Expect(requeuedJob.RetryCount).To(Equal(0))
+
+			var reservedJob gobble.Job
+			Eventually(queue.Reserve("worker-id")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(requeuedJob.ID))
+
+			deadLetters, err = queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+			Expect(deadLetters).To(HaveLen(0))
+		})
+
+		It("restores the priority of the job", func() {
+			job, err := queue.Enqueue(gobble.Job{
+				Payload:  "the-payload",
+				Priority: gobble.PriorityLow,
+			})
+			if err != nil {
+				panic(err)
+			}
+			queue.DeadLetter(job)
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+
+			requeuedJob, err := queue.RequeueDeadLetter(deadLetters[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeuedJob.Priority).To(Equal(gobble.PriorityLow))
+		})
+
+		It("only requeues a dead letter once", func() {
+			job, err := queue.Enqueue(gobble.Job{Payload: "the-payload"})
+			if err != nil {
+				panic(err)
+			}
+			queue.DeadLetter(job)
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+
+			_, err = queue.RequeueDeadLetter(deadLetters[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = queue.RequeueDeadLetter(deadLetters[0].ID)
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: deadLetters[0].ID}))
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			if err != nil {
+				panic(err)
+			}
+			Expect(results).To(HaveLen(1))
+		})
+
+		It("returns a DeadLetterNotFoundError when the dead letter does not exist", func() {
+			_, err := queue.RequeueDeadLetter(42)
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("PurgeDeadLetter", func() {
+		It("deletes the dead letter", func() {
+			job, err := queue.Enqueue(gobble.Job{})
+			if err != nil {
+				panic(err)
+			}
+			queue.DeadLetter(job)
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+
+			err = queue.PurgeDeadLetter(deadLetters[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			deadLetters, err = queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+			Expect(deadLetters).To(HaveLen(0))
+		})
+
+		It("returns a DeadLetterNotFoundError when the dead letter does not exist", func() {
+			err := queue.PurgeDeadLetter(42)
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("Heartbeat", func() {
+		It("extends the lease on a reserved job", func() {
+			queue.Enqueue(gobble.Job{})
+			job := <-queue.Reserve("my-worker")
+
+			reloadedJob := gobble.Job{}
+			err := gobble.Database().Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs` where id = ?", job.ID)
+			if err != nil {
+				panic(err)
+			}
+			Expect(reloadedJob.ExpiresAt).To(BeTemporally("~", time.Now().Add(gobble.LeaseDuration), 5*time.Second))
+
+			_, err = gobble.Database().Connection.Exec("UPDATE `jobs` SET `expires_at` = ? WHERE `id` = ?", time.Now().Add(10*time.Second), job.ID)
+			if err != nil {
+				panic(err)
+			}
+
+			err = queue.Heartbeat(job)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = gobble.Database().Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs` where id = ?", job.ID)
+			if err != nil {
+				panic(err)
+			}
+			Expect(reloadedJob.ExpiresAt).To(BeTemporally("~", time.Now().Add(gobble.LeaseDuration), 5*time.Second))
+		})
+
+		It("returns a LeaseLostError when the job is reserved by another worker", func() {
+			queue.Enqueue(gobble.Job{})
+			job := <-queue.Reserve("my-worker")
+
+			_, err := gobble.Database().Connection.Exec("UPDATE `jobs` SET `worker_id` = ? WHERE `id` = ?", "other-worker", job.ID)
+			if err != nil {
+				panic(err)
+			}
+
+			err = queue.Heartbeat(job)
+			Expect(err).To(Equal(gobble.LeaseLostError{
+				JobID:    job.ID,
+				WorkerID: "my-worker",
+			}))
+		})
+
+		It("returns a LeaseLostError when the job has been reclaimed by a worker with the same ID", func() {
+			queue.Enqueue(gobble.Job{})
+			job := <-queue.Reserve("my-worker")
+
+			_, err := gobble.Database().Connection.Exec("UPDATE `jobs` SET `expires_at` = ? WHERE `id` = ?", time.Now().Add(-1*time.Minute), job.ID)
+			if err != nil {
+				panic(err)
+			}
+			Eventually(queue.Reserve("my-worker")).Should(Receive())
+
+			err = queue.Heartbeat(job)
+			Expect(err).To(Equal(gobble.LeaseLostError{
+				JobID:    job.ID,
+				WorkerID: "my-worker",
+			}))
+		})
+	})
+
+	Describe("lease expiry", func() {
+		It("does not reserve jobs whose lease is still active", func() {
+			queue.Enqueue(gobble.Job{})
+			<-queue.Reserve("my-worker")
+
+			Consistently(queue.Reserve("other-worker")).ShouldNot(Receive())
+		})
+
+		It("reclaims jobs whose lease has expired", func() {
+			job, err := queue.Enqueue(gobble.Job{})
+			if err != nil {
+				panic(err)
+			}
+			<-queue.Reserve("my-worker")
+
+			_, err = gobble.Database().Connection.Exec("UPDATE `jobs` SET `expires_at` = ? WHERE `id` = ?", time.Now().Add(-1*time.Minute), job.ID)
+			if err != nil {
+				panic(err)
+			}
+
+			var reservedJob gobble.Job
+			Eventually(queue.Reserve("other-worker")).Should(Receive(&reservedJob))
+
+			Expect(reservedJob.ID).To(Equal(job.ID))
+			Expect(reservedJob.WorkerID).To(Equal("other-worker"))
+		})
+
+		It("ignores dequeues from a worker whose lease was reclaimed", func() {
+			job, err := queue.Enqueue(gobble.Job{})
+			if err != nil {
+				panic(err)
+			}
+			staleJob := <-queue.Reserve("my-worker")
+
+			_, err = gobble.Database().Connection.Exec("UPDATE `jobs` SET `expires_at` = ? WHERE `id` = ?", time.Now().Add(-1*time.Minute), job.ID)
+			if err != nil {
+				panic(err)
+			}
+			<-queue.Reserve("other-worker")
+
+			Expect(func() {
+				queue.Dequeue(staleJob)
+			}).NotTo(Panic())
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
 			if err != nil {
 				panic(err)
 			}
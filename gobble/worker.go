@@ -3,6 +3,7 @@ package gobble
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 type Worker struct {
@@ -24,10 +25,25 @@ func NewWorker(id int, queue QueueInterface, callback func(*Job)) Worker {
 func (worker *Worker) Perform() int {
 	select {
 	case job := <-worker.queue.Reserve(worker.ID):
+		job.leaseLost = make(chan struct{})
+		done := make(chan bool)
+		stopped := make(chan bool)
+		go func() {
+			worker.heartbeat(job, done)
+			close(stopped)
+		}()
 		worker.callback(&job)
+		close(done)
+		<-stopped
+
+		if job.IsLeaseLost() {
+			return 0
+		}
 
 		if job.ShouldRetry {
 			worker.queue.Requeue(job)
+		} else if job.ShouldDeadLetter {
+			worker.queue.DeadLetter(job)
 		} else {
 			worker.queue.Dequeue(job)
 		}
@@ -37,6 +53,33 @@ func (worker *Worker) Perform() int {
 	}
 }
 
+// heartbeat keeps the lease on a reserved job alive until the job is done.
+// When the lease has been lost to another worker, it tells the callback so,
+// and the job is left to the other worker. Other errors are retried on the
+// next beat.
+func (worker *Worker) heartbeat(job Job, done <-chan bool) {
+	interval := job.ExpiresAt.Sub(time.Now()) / 2
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := worker.queue.Heartbeat(job)
+			if _, ok := err.(LeaseLostError); ok {
+				job.LoseLease()
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
 func (worker *Worker) Work() {
 	go func() {
 		for {
@@ -75,6 +75,110 @@ var _ = Describe("Worker", func() {
 			Expect(retriedJob.RetryCount).To(Equal(1))
 			Expect(retriedJob.ActiveAt).To(BeTemporally("~", time.Now().Add(1*time.Minute), 1*time.Minute))
 		})
+
+		It("moves jobs that are marked as dead letters out of the queue", func() {
+			callback = func(job *gobble.Job) {
+				job.DeadLetter("it failed")
+			}
+			worker = gobble.NewWorker(1, queue, callback)
+
+			job, err := queue.Enqueue(gobble.Job{
+				Payload: "the-payload",
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			worker.Perform()
+
+			results, err := gobble.Database().Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			if err != nil {
+				panic(err)
+			}
+			Expect(results).To(HaveLen(0))
+
+			deadLetters, err := queue.DeadLetters(1, 10)
+			if err != nil {
+				panic(err)
+			}
+			Expect(deadLetters).To(HaveLen(1))
+			Expect(deadLetters[0].JobID).To(Equal(job.ID))
+			Expect(deadLetters[0].LastError).To(Equal("it failed"))
+		})
+
+		It("keeps the lease on the job alive while the callback runs", func() {
+			queue = gobble.NewQueue(gobble.Config{
+				LeaseDuration: 2 * time.Second,
+			})
+
+			var expiresAt time.Time
+			callback = func(job *gobble.Job) {
+				<-time.After(3 * time.Second)
+
+				reloadedJob := gobble.Job{}
+				err := gobble.Database().Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs` where id = ?", job.ID)
+				if err != nil {
+					panic(err)
+				}
+				expiresAt = reloadedJob.ExpiresAt
+			}
+			worker = gobble.NewWorker(1, queue, callback)
+
+			_, err := queue.Enqueue(gobble.Job{})
+			if err != nil {
+				panic(err)
+			}
+
+			worker.Perform()
+
+			Expect(expiresAt).To(BeTemporally(">", time.Now()))
+		})
+
+		Context("when the lease on the job is lost to another worker", func() {
+			var leaseLost bool
+
+			BeforeEach(func() {
+				leaseLost = false
+				queue = gobble.NewQueue(gobble.Config{
+					LeaseDuration: 2 * time.Second,
+				})
+
+				callback = func(job *gobble.Job) {
+					_, err := gobble.Database().Connection.Exec("UPDATE `jobs` SET `worker_id` = ? WHERE `id` = ?", "another-worker", job.ID)
+					if err != nil {
+						panic(err)
+					}
+
+					select {
+					case <-job.LeaseLost():
+						leaseLost = true
+					case <-time.After(3 * time.Second):
+					}
+
+					job.Retry(1 * time.Minute)
+				}
+				worker = gobble.NewWorker(1, queue, callback)
+			})
+
+			It("tells the callback, and leaves the job to the other worker", func() {
+				job, err := queue.Enqueue(gobble.Job{})
+				if err != nil {
+					panic(err)
+				}
+
+				worker.Perform()
+
+				Expect(leaseLost).To(BeTrue())
+
+				reloadedJob := gobble.Job{}
+				err = gobble.Database().Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs` where id = ?", job.ID)
+				if err != nil {
+					panic(err)
+				}
+				Expect(reloadedJob.WorkerID).To(Equal("another-worker"))
+				Expect(reloadedJob.RetryCount).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("Work", func() {
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+// LoginAuth returns an smtp.Auth that implements the LOGIN mechanism, as used
+// by Office 365 and other Exchange servers. Like smtp.PlainAuth, it refuses to
+// send the credentials unless the connection is encrypted or to localhost.
+func LoginAuth(username, password, host string) smtp.Auth {
+	return &loginAuth{
+		username: username,
+		password: password,
+		host:     host,
+	}
+}
+
+func (auth *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	err := checkServer(server, auth.host)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "LOGIN", nil, nil
+}
+
+func (auth *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(auth.username), nil
+	case "password:":
+		return []byte(auth.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge from server: %q", fromServer)
+	}
+}
+
+type xoauth2Auth struct {
+	username string
+	tokens   TokenSource
+	host     string
+}
+
+// XOAUTH2Auth returns an smtp.Auth that implements the XOAUTH2 mechanism, as
+// used by Google Workspace and Office 365, authenticating with an access token
+// from tokens. A token the server rejects is invalidated, so that the next
+// attempt fetches a new one.
+func XOAUTH2Auth(username string, tokens TokenSource, host string) smtp.Auth {
+	return &xoauth2Auth{
+		username: username,
+		tokens:   tokens,
+		host:     host,
+	}
+}
+
+func (auth *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	err := checkServer(server, auth.host)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := auth.tokens.Token()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "XOAUTH2", []byte("user=" + auth.username + "\x01auth=Bearer " + token + "\x01\x01"), nil
+}
+
+// Next answers the server's error challenge with an empty response, as the
+// mechanism requires, after which the server fails the authentication.
+func (auth *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		auth.tokens.Invalidate()
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+func checkServer(server *smtp.ServerInfo, host string) error {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return errors.New("unencrypted connection")
+	}
+
+	if server.Name != host {
+		return errors.New("wrong host name")
+	}
+
+	return nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,175 @@
+package mail_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net/smtp"
+
+	"bitbucket.org/chrj/smtpd"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FakeTokenSource struct {
+	AccessToken string
+	TokenError  error
+	Invalidated bool
+	TokenCalls  int
+}
+
+func (source *FakeTokenSource) Token() (string, error) {
+	source.TokenCalls++
+	return source.AccessToken, source.TokenError
+}
+
+func (source *FakeTokenSource) Invalidate() {
+	source.Invalidated = true
+}
+
+var _ = Describe("Auth", func() {
+	Describe("LoginAuth", func() {
+		var certificates Certificates
+		var server *SMTPDServer
+		var config mail.Config
+		var username, password string
+
+		BeforeEach(func() {
+			certificates = NewCertificates()
+			server = NewSMTPDServer()
+			server.Server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificates.Server}}
+			server.Server.Authenticator = func(peer smtpd.Peer, user, pass string) error {
+				username, password = user, pass
+				if pass != "secret" {
+					return smtpd.Error{Code: 535, Message: "Authentication failed"}
+				}
+				return nil
+			}
+
+			config = mail.Config{
+				User:           "user@example.com",
+				Pass:           "secret",
+				AuthMechanism:  mail.AuthLogin,
+				CACertificates: certificates.CAPEM,
+			}
+		})
+
+		JustBeforeEach(func() {
+			config.Host, config.Port = server.Start()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		send := func() error {
+			client, err := mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			return client.Send(mail.Message{
+				From:    "no-reply@example.com",
+				To:      "user@example.com",
+				Subject: "Hello",
+				Body:    []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+			})
+		}
+
+		It("authenticates with the username and password", func() {
+			Expect(send()).NotTo(HaveOccurred())
+
+			Expect(username).To(Equal("user@example.com"))
+			Expect(password).To(Equal("secret"))
+			Expect(server.Deliveries()).To(HaveLen(1))
+		})
+
+		It("returns the error when the server refuses the credentials", func() {
+			config.Pass = "wrong"
+
+			Expect(send()).To(HaveOccurred())
+			Expect(server.Deliveries()).To(BeEmpty())
+		})
+
+		It("refuses to send the credentials over an unencrypted connection", func() {
+			auth := mail.LoginAuth("user", "secret", "smtp.example.com")
+
+			_, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: false, Auth: []string{"LOGIN"}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error for a challenge it does not recognize", func() {
+			auth := mail.LoginAuth("user", "secret", "smtp.example.com")
+
+			_, err := auth.Next([]byte("Favorite color:"), true)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("XOAUTH2Auth", func() {
+		var tokens *FakeTokenSource
+		var server *smtp.ServerInfo
+
+		BeforeEach(func() {
+			tokens = &FakeTokenSource{AccessToken: "some-access-token"}
+			server = &smtp.ServerInfo{Name: "smtp.example.com", TLS: true, Auth: []string{"XOAUTH2"}}
+		})
+
+		It("sends the username and an access token in the initial response", func() {
+			auth := mail.XOAUTH2Auth("user@example.com", tokens, "smtp.example.com")
+
+			mechanism, response, err := auth.Start(server)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mechanism).To(Equal("XOAUTH2"))
+			Expect(string(response)).To(Equal("user=user@example.com\x01auth=Bearer some-access-token\x01\x01"))
+		})
+
+		It("invalidates the token and answers with an empty response when the server rejects it", func() {
+			auth := mail.XOAUTH2Auth("user@example.com", tokens, "smtp.example.com")
+
+			response, err := auth.Next([]byte(`{"status":"401","schemes":"bearer"}`), true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal([]byte{}))
+			Expect(tokens.Invalidated).To(BeTrue())
+		})
+
+		It("returns the error when no token can be fetched", func() {
+			tokens.TokenError = errors.New("token endpoint is down")
+			auth := mail.XOAUTH2Auth("user@example.com", tokens, "smtp.example.com")
+
+			_, _, err := auth.Start(server)
+			Expect(err).To(MatchError("token endpoint is down"))
+		})
+
+		It("refuses to send the token over an unencrypted connection", func() {
+			server.TLS = false
+			auth := mail.XOAUTH2Auth("user@example.com", tokens, "smtp.example.com")
+
+			_, _, err := auth.Start(server)
+			Expect(err).To(HaveOccurred())
+			Expect(tokens.TokenCalls).To(Equal(0))
+		})
+
+		It("is chosen by the client when configured", func() {
+			client, err := mail.NewClient(mail.Config{
+				User:          "user@example.com",
+				Host:          "smtp.example.com",
+				AuthMechanism: mail.AuthXOAUTH2,
+				TokenSource:   tokens,
+			}, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			mechanism, _, err := client.AuthMechanism().Start(server)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mechanism).To(Equal("XOAUTH2"))
+		})
+
+		It("cannot be configured without a token source", func() {
+			_, err := mail.NewClient(mail.Config{AuthMechanism: mail.AuthXOAUTH2}, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,235 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+
+	"bitbucket.org/chrj/smtpd"
+)
+
+const ReturnPathPrefix = "bounces+"
+
+// returnPathSignatureLength is the number of hex digits of the HMAC that signs
+// the message ID in a return path.
+const returnPathSignatureLength = 16
+
+// ReturnPath builds the VERP return path for a message, so that a bounce for
+// it names the message in the address it is delivered to. The message ID is
+// signed with the key, so that bounces cannot be forged for other messages.
+func ReturnPath(id, domain string, key []byte) string {
+	return ReturnPathPrefix + id + "." + returnPathSignature(id, key) + "@" + domain
+}
+
+// ParseReturnPath extracts the message ID from a VERP return path built by
+// ReturnPath with the same key. Addresses whose signature does not match are
+// refused.
+func ParseReturnPath(address, domain string, key []byte) (string, bool) {
+	at := strings.LastIndex(address, "@")
+	if at < 0 || !strings.EqualFold(address[at+1:], domain) {
+		return "", false
+	}
+
+	localPart := address[:at]
+	if !strings.HasPrefix(strings.ToLower(localPart), ReturnPathPrefix) {
+		return "", false
+	}
+
+	verp := localPart[len(ReturnPathPrefix):]
+	dot := strings.LastIndex(verp, ".")
+	if dot <= 0 {
+		return "", false
+	}
+
+	id, signature := verp[:dot], strings.ToLower(verp[dot+1:])
+	if !hmac.Equal([]byte(signature), []byte(returnPathSignature(id, key))) {
+		return "", false
+	}
+
+	return id, true
+}
+
+func returnPathSignature(id string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+
+	return hex.EncodeToString(mac.Sum(nil))[:returnPathSignatureLength]
+}
+
+type BounceHandlerInterface interface {
+	HandleBounce(messageID string, dsn DSN) error
+}
+
+// UnsubscribeHandlerInterface consumes the unsubscribe IDs of requests mailed
+// to the List-Unsubscribe mailto address. It returns an error only when the
+// request is worth retrying.
+type UnsubscribeHandlerInterface interface {
+	HandleUnsubscribe(unsubscribeID string) error
+}
+
+// ParseUnsubscribeRequest extracts the unsubscribe ID from a request mailed to
+// the List-Unsubscribe mailto address, whose subject is "unsubscribe <ID>".
+func ParseUnsubscribeRequest(data []byte) (string, bool) {
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		return "", false
+	}
+
+	fields := strings.Fields(subject)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "unsubscribe") {
+		return "", false
+	}
+
+	return fields[1], true
+}
+
+type BounceServerConfig struct {
+	Domain string
+	Key    []byte
+	// UnsubscribeAddress is the List-Unsubscribe mailto address, which must be
+	// in the domain as well. Requests mailed to it are handed to the
+	// unsubscribe handler.
+	UnsubscribeAddress string
+	Hostname           string
+	MaxMessageSize     int
+}
+
+// BounceServer accepts delivery status notifications addressed to VERP return
+// paths in its domain and hands them to its bounce handler. Return paths that
+// are not signed with its key are refused. It also accepts the unsubscribe
+// requests mailed to its unsubscribe address. Mail it cannot parse is
+// accepted and dropped, so that it never bounces mail back to a bounce.
+type BounceServer struct {
+	domain             string
+	key                []byte
+	unsubscribeAddress string
+	server             *smtpd.Server
+	handler            BounceHandlerInterface
+	unsubscribeHandler UnsubscribeHandlerInterface
+	logger             *log.Logger
+}
+
+func NewBounceServer(config BounceServerConfig, handler BounceHandlerInterface, unsubscribeHandler UnsubscribeHandlerInterface, logger *log.Logger) *BounceServer {
+	bounceServer := &BounceServer{
+		domain:             config.Domain,
+		key:                config.Key,
+		unsubscribeAddress: config.UnsubscribeAddress,
+		handler:            handler,
+		unsubscribeHandler: unsubscribeHandler,
+		logger:             logger,
+	}
+
+	welcomeMessage := ""
+	if config.Hostname != "" {
+		welcomeMessage = config.Hostname + " ESMTP ready."
+	}
+
+	bounceServer.server = &smtpd.Server{
+		WelcomeMessage:   welcomeMessage,
+		MaxMessageSize:   config.MaxMessageSize,
+		RecipientChecker: bounceServer.checkRecipient,
+		Handler:          bounceServer.handle,
+	}
+
+	return bounceServer
+}
+
+func (server *BounceServer) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return server.Serve(listener)
+}
+
+func (server *BounceServer) Serve(listener net.Listener) error {
+	return server.server.Serve(listener)
+}
+
+func (server *BounceServer) isUnsubscribeAddress(addr string) bool {
+	return server.unsubscribeAddress != "" && strings.EqualFold(addr, server.unsubscribeAddress)
+}
+
+func (server *BounceServer) checkRecipient(peer smtpd.Peer, addr string) error {
+	if server.isUnsubscribeAddress(addr) {
+		return nil
+	}
+
+	if _, ok := ParseReturnPath(addr, server.domain, server.key); !ok {
+		return smtpd.Error{Code: 550, Message: "No such mailbox"}
+	}
+
+	return nil
+}
+
+func (server *BounceServer) handle(peer smtpd.Peer, env smtpd.Envelope) error {
+	var returnPaths []string
+	for _, recipient := range env.Recipients {
+		if server.isUnsubscribeAddress(recipient) {
+			err := server.handleUnsubscribe(env)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		returnPaths = append(returnPaths, recipient)
+	}
+
+	if len(returnPaths) == 0 {
+		return nil
+	}
+
+	return server.handleBounce(env, returnPaths)
+}
+
+func (server *BounceServer) handleUnsubscribe(env smtpd.Envelope) error {
+	unsubscribeID, ok := ParseUnsubscribeRequest(env.Data)
+	if !ok {
+		server.logger.Printf("Dropping mail from %s to %s that is not an unsubscribe request", env.Sender, server.unsubscribeAddress)
+		return nil
+	}
+
+	err := server.unsubscribeHandler.HandleUnsubscribe(unsubscribeID)
+	if err != nil {
+		server.logger.Printf("Failed to process the unsubscribe request from %s: %s", env.Sender, err.Error())
+		return smtpd.Error{Code: 451, Message: "Unsubscribe request could not be processed, try again later"}
+	}
+
+	return nil
+}
+
+func (server *BounceServer) handleBounce(env smtpd.Envelope, returnPaths []string) error {
+	dsn, err := ParseDSN(env.Data)
+	if err != nil {
+		server.logger.Printf("Dropping mail from %s to %v that is not a bounce: %s", env.Sender, returnPaths, err.Error())
+		return nil
+	}
+
+	for _, recipient := range returnPaths {
+		messageID, ok := ParseReturnPath(recipient, server.domain, server.key)
+		if !ok {
+			continue
+		}
+
+		err = server.handler.HandleBounce(messageID, dsn)
+		if err != nil {
+			server.logger.Printf("Failed to process the bounce of notification %s: %s", messageID, err.Error())
+			return smtpd.Error{Code: 451, Message: "Bounce could not be processed, try again later"}
+		}
+	}
+
+	return nil
+}
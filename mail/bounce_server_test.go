@@ -0,0 +1,241 @@
+package mail_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FakeBounceHandler struct {
+	mutex   sync.Mutex
+	Bounces map[string]mail.DSN
+	Error   error
+}
+
+func (handler *FakeBounceHandler) HandleBounce(messageID string, dsn mail.DSN) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.Error != nil {
+		return handler.Error
+	}
+
+	handler.Bounces[messageID] = dsn
+	return nil
+}
+
+type FakeUnsubscribeHandler struct {
+	mutex          sync.Mutex
+	UnsubscribeIDs []string
+	Error          error
+}
+
+func (handler *FakeUnsubscribeHandler) HandleUnsubscribe(unsubscribeID string) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.Error != nil {
+		return handler.Error
+	}
+
+	handler.UnsubscribeIDs = append(handler.UnsubscribeIDs, unsubscribeID)
+	return nil
+}
+
+var bounceKey = []byte("0123456789abcdef")
+
+var _ = Describe("BounceServer", func() {
+	var handler *FakeBounceHandler
+	var unsubscribeHandler *FakeUnsubscribeHandler
+	var listener net.Listener
+	var buffer *bytes.Buffer
+	var returnPath string
+
+	BeforeEach(func() {
+		var err error
+
+		handler = &FakeBounceHandler{Bounces: map[string]mail.DSN{}}
+		unsubscribeHandler = &FakeUnsubscribeHandler{}
+		buffer = bytes.NewBuffer([]byte{})
+		server := mail.NewBounceServer(mail.BounceServerConfig{
+			Domain:             "bounces.example.com",
+			Key:                bounceKey,
+			UnsubscribeAddress: "unsubscribe@bounces.example.com",
+			Hostname:           "bounces.example.com",
+		}, handler, unsubscribeHandler, log.New(buffer, "", 0))
+
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		go server.Serve(listener)
+
+		returnPath = mail.ReturnPath("message-123", "bounces.example.com", bounceKey)
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	send := func(to, data string) error {
+		return smtp.SendMail(listener.Addr().String(), nil, "MAILER-DAEMON@mx.example.com", []string{to}, []byte(data))
+	}
+
+	It("hands bounces to the handler with the message ID from the return path", func() {
+		err := send(returnPath, hardBounce)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(handler.Bounces).To(HaveKey("message-123"))
+		Expect(handler.Bounces["message-123"].Recipients[0].Address).To(Equal("user@example.com"))
+	})
+
+	It("refuses mail for addresses that are not return paths in its domain", func() {
+		err := send("postmaster@bounces.example.com", hardBounce)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*textproto.Error).Code).To(Equal(550))
+
+		err = send(strings.Replace(returnPath, "bounces.example.com", "example.com", 1), hardBounce)
+		Expect(err).To(HaveOccurred())
+
+		Expect(handler.Bounces).To(BeEmpty())
+	})
+
+	It("refuses return paths that are not signed with its key", func() {
+		err := send("bounces+message-123@bounces.example.com", hardBounce)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*textproto.Error).Code).To(Equal(550))
+
+		forged := mail.ReturnPath("message-123", "bounces.example.com", []byte("some-other-key"))
+		err = send(forged, hardBounce)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*textproto.Error).Code).To(Equal(550))
+
+		Expect(handler.Bounces).To(BeEmpty())
+	})
+
+	It("accepts and drops mail that is not a bounce", func() {
+		err := send(returnPath, "Subject: Out of office\n\nI am on vacation.\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(handler.Bounces).To(BeEmpty())
+		Expect(buffer.String()).To(ContainSubstring("Dropping mail"))
+	})
+
+	It("asks the sender to try again later when the bounce cannot be processed", func() {
+		handler.Error = errors.New("database is down")
+
+		err := send(returnPath, hardBounce)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*textproto.Error).Code).To(Equal(451))
+	})
+
+	Context("when mail is sent to the unsubscribe address", func() {
+		It("hands the unsubscribe ID in the subject to the unsubscribe handler", func() {
+			err := send("Unsubscribe@Bounces.Example.com", "Subject: unsubscribe dW5zdWJzY3JpYmUtaWQ=\n\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(unsubscribeHandler.UnsubscribeIDs).To(Equal([]string{"dW5zdWJzY3JpYmUtaWQ="}))
+			Expect(handler.Bounces).To(BeEmpty())
+		})
+
+		It("accepts and drops mail that is not an unsubscribe request", func() {
+			err := send("unsubscribe@bounces.example.com", "Subject: Hello\n\nPlease stop.\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(unsubscribeHandler.UnsubscribeIDs).To(BeEmpty())
+			Expect(buffer.String()).To(ContainSubstring("Dropping mail"))
+		})
+
+		It("asks the sender to try again later when the request cannot be processed", func() {
+			unsubscribeHandler.Error = errors.New("database is down")
+
+			err := send("unsubscribe@bounces.example.com", "Subject: unsubscribe dW5zdWJzY3JpYmUtaWQ=\n\n")
+			Expect(err).To(HaveOccurred())
+			Expect(err.(*textproto.Error).Code).To(Equal(451))
+		})
+	})
+})
+
+var _ = Describe("ParseUnsubscribeRequest", func() {
+	It("reads the unsubscribe ID from the subject", func() {
+		id, ok := mail.ParseUnsubscribeRequest([]byte("Subject: UNSUBSCRIBE  the-id\n\n"))
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal("the-id"))
+	})
+
+	It("decodes encoded subjects", func() {
+		id, ok := mail.ParseUnsubscribeRequest([]byte("Subject: =?utf-8?q?unsubscribe_the-id?=\n\n"))
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal("the-id"))
+	})
+
+	It("refuses other subjects", func() {
+		for _, subject := range []string{"Hello", "unsubscribe", "unsubscribe the-id please", "subscribe the-id"} {
+			_, ok := mail.ParseUnsubscribeRequest([]byte("Subject: " + subject + "\n\n"))
+			Expect(ok).To(BeFalse())
+		}
+	})
+})
+
+var _ = Describe("ReturnPath", func() {
+	It("round trips the message ID through a signed VERP address", func() {
+		address := mail.ReturnPath("message-123", "bounces.example.com", bounceKey)
+		Expect(address).To(MatchRegexp(`^bounces\+message-123\.[0-9a-f]{16}@bounces\.example\.com$`))
+
+		id, ok := mail.ParseReturnPath(address, "Bounces.Example.com", bounceKey)
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal("message-123"))
+
+		_, ok = mail.ParseReturnPath(strings.ToUpper(address), "bounces.example.com", bounceKey)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("refuses addresses that are unsigned or signed with another key", func() {
+		_, ok := mail.ParseReturnPath("bounces+@bounces.example.com", "bounces.example.com", bounceKey)
+		Expect(ok).To(BeFalse())
+
+		_, ok = mail.ParseReturnPath("bounces+message-123@bounces.example.com", "bounces.example.com", bounceKey)
+		Expect(ok).To(BeFalse())
+
+		address := mail.ReturnPath("message-123", "bounces.example.com", []byte("some-other-key"))
+		_, ok = mail.ParseReturnPath(address, "bounces.example.com", bounceKey)
+		Expect(ok).To(BeFalse())
+
+		address = mail.ReturnPath("message-456", "bounces.example.com", bounceKey)
+		address = strings.Replace(address, "message-456", "message-123", 1)
+		_, ok = mail.ParseReturnPath(address, "bounces.example.com", bounceKey)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("is used as the envelope sender of the message", func() {
+		server := NewSMTPDServer()
+		defer server.Close()
+
+		config := mail.Config{TLSMode: mail.TLSModeNone}
+		config.Host, config.Port = server.Start()
+
+		client, err := mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = client.Send(mail.Message{
+			From:       "no-reply@example.com",
+			ReturnPath: "bounces+message-123@bounces.example.com",
+			To:         "user@example.com",
+			Subject:    "Hello",
+			Body:       []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(server.Deliveries()).To(HaveLen(1))
+		Expect(server.Deliveries()[0].Sender).To(Equal("bounces+message-123@bounces.example.com"))
+	})
+})
@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops traffic to a relay after FailureThreshold consecutive
+// failures. Once Cooldown has passed, a single trial is let through: its
+// success closes the breaker again and its failure keeps it open for another
+// Cooldown. A trial that proves nothing either way, such as one that only
+// reused an existing session, is released so that the next call can try.
+type CircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	mutex    sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+func (breaker *CircuitBreaker) Allow() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if breaker.failures < breaker.FailureThreshold {
+		return true
+	}
+
+	if breaker.trial || breaker.now().Sub(breaker.openedAt) < breaker.Cooldown {
+		return false
+	}
+
+	breaker.trial = true
+	return true
+}
+
+func (breaker *CircuitBreaker) Success() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures = 0
+	breaker.trial = false
+}
+
+func (breaker *CircuitBreaker) Release() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.trial = false
+}
+
+func (breaker *CircuitBreaker) Failure() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures++
+	if breaker.failures >= breaker.FailureThreshold {
+		breaker.openedAt = breaker.now()
+		breaker.trial = false
+	}
+}
+
+func (breaker *CircuitBreaker) Open() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	return breaker.failures >= breaker.FailureThreshold
+}
@@ -0,0 +1,91 @@
+package mail_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var breaker *mail.CircuitBreaker
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2015, time.March, 4, 12, 0, 0, 0, time.UTC)
+		breaker = mail.NewCircuitBreaker(3, time.Minute)
+		breaker.SetNow(func() time.Time {
+			return now
+		})
+	})
+
+	It("allows traffic until the failure threshold is reached", func() {
+		breaker.Failure()
+		breaker.Failure()
+		Expect(breaker.Allow()).To(BeTrue())
+		Expect(breaker.Open()).To(BeFalse())
+
+		breaker.Failure()
+		Expect(breaker.Allow()).To(BeFalse())
+		Expect(breaker.Open()).To(BeTrue())
+	})
+
+	It("counts only consecutive failures", func() {
+		breaker.Failure()
+		breaker.Failure()
+		breaker.Success()
+		breaker.Failure()
+
+		Expect(breaker.Allow()).To(BeTrue())
+	})
+
+	Context("when the breaker is open", func() {
+		BeforeEach(func() {
+			breaker.Failure()
+			breaker.Failure()
+			breaker.Failure()
+		})
+
+		It("lets a single trial through once the cooldown has passed", func() {
+			now = now.Add(59 * time.Second)
+			Expect(breaker.Allow()).To(BeFalse())
+
+			now = now.Add(time.Second)
+			Expect(breaker.Allow()).To(BeTrue())
+			Expect(breaker.Allow()).To(BeFalse())
+		})
+
+		It("closes when the trial succeeds", func() {
+			now = now.Add(time.Minute)
+			Expect(breaker.Allow()).To(BeTrue())
+
+			breaker.Success()
+			Expect(breaker.Open()).To(BeFalse())
+			Expect(breaker.Allow()).To(BeTrue())
+			Expect(breaker.Allow()).To(BeTrue())
+		})
+
+		It("lets another trial through when the trial is released", func() {
+			now = now.Add(time.Minute)
+			Expect(breaker.Allow()).To(BeTrue())
+
+			breaker.Release()
+			Expect(breaker.Open()).To(BeTrue())
+			Expect(breaker.Allow()).To(BeTrue())
+			Expect(breaker.Allow()).To(BeFalse())
+		})
+
+		It("stays open for another cooldown when the trial fails", func() {
+			now = now.Add(time.Minute)
+			Expect(breaker.Allow()).To(BeTrue())
+
+			breaker.Failure()
+			Expect(breaker.Allow()).To(BeFalse())
+
+			now = now.Add(time.Minute)
+			Expect(breaker.Allow()).To(BeTrue())
+		})
+	})
+})
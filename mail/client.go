@@ -2,12 +2,15 @@ package mail
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/smtp"
+	"net/textproto"
+	"sort"
 	"strings"
 	"time"
 )
@@ -16,14 +19,29 @@ const (
 	AuthNone AuthMechanism = iota
 	AuthPlain
 	AuthCRAMMD5
+	AuthLogin
+	AuthXOAUTH2
 )
 
 type AuthMechanism int
 
+const (
+	TLSModeStartTLS TLSMode = iota
+	TLSModeImplicit
+	TLSModeNone
+)
+
+// TLSMode selects how the connection to the server is secured: upgraded with
+// STARTTLS after connecting, wrapped in TLS from the start (SMTPS, usually on
+// port 465), or not at all.
+type TLSMode int
+
 type Client struct {
-	config Config
-	client *smtp.Client
-	logger *log.Logger
+	config    Config
+	client    *smtp.Client
+	logger    *log.Logger
+	signer    *DKIMSigner
+	tlsConfig *tls.Config
 }
 
 type Config struct {
@@ -35,9 +53,26 @@ type Config struct {
 	AuthMechanism  AuthMechanism
 	TestMode       bool
 	SkipVerifySSL  bool
-	DisableTLS     bool
+	TLSMode        TLSMode
 	ConnectTimeout time.Duration
 	LoggingEnabled bool
+	DKIMDomain     string
+	DKIMSelector   string
+	DKIMPrivateKey []byte
+	DKIMHeaders    []string
+
+	// TokenSource supplies the access tokens used to authenticate with
+	// AuthXOAUTH2.
+	TokenSource TokenSource
+
+	// CACertificates is a PEM bundle of the certificate authorities trusted
+	// to sign the server's certificate, in place of the system roots.
+	CACertificates []byte
+
+	// ClientCertificate and ClientKey are PEM encoded, and are presented to
+	// servers that require TLS client authentication.
+	ClientCertificate []byte
+	ClientKey         []byte
 }
 
 type ClientInterface interface {
@@ -60,9 +95,51 @@ func NewClient(config Config, logger *log.Logger) (*Client, error) {
 		client.config.ConnectTimeout = 15 * time.Second
 	}
 
+	if config.DKIMDomain != "" {
+		signer, err := NewDKIMSigner(config.DKIMDomain, config.DKIMSelector, config.DKIMPrivateKey, config.DKIMHeaders)
+		if err != nil {
+			return nil, err
+		}
+		client.signer = &signer
+	}
+
+	if config.AuthMechanism == AuthXOAUTH2 && config.TokenSource == nil {
+		return nil, errors.New("XOAUTH2 authentication requires a token source")
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.tlsConfig = tlsConfig
+
 	return client, nil
 }
 
+func newTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.Host,
+		InsecureSkipVerify: config.SkipVerifySSL,
+	}
+
+	if len(config.CACertificates) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(config.CACertificates) {
+			return nil, errors.New("SMTP CA certificates do not contain any PEM encoded certificate")
+		}
+	}
+
+	if len(config.ClientCertificate) > 0 || len(config.ClientKey) > 0 {
+		certificate, err := tls.X509KeyPair(config.ClientCertificate, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("SMTP client certificate could not be loaded: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
 func (c *Client) Connect() error {
 	c.PrintLog("Connecting...")
 	if c.config.TestMode {
@@ -95,7 +172,7 @@ func (c *Client) connect() chan connection {
 	channel := make(chan connection)
 
 	go func() {
-		client, err := smtp.Dial(net.JoinHostPort(c.config.Host, c.config.Port))
+		client, err := c.dial()
 		channel <- connection{
 			client: client,
 			err:    err,
@@ -105,70 +182,262 @@ func (c *Client) connect() chan connection {
 	return channel
 }
 
+func (c *Client) dial() (*smtp.Client, error) {
+	address := net.JoinHostPort(c.config.Host, c.config.Port)
+	if c.config.TLSMode != TLSModeImplicit {
+		return smtp.Dial(address)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: c.config.ConnectTimeout}, "tcp", address, c.tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return smtp.NewClient(conn, c.config.Host)
+}
+
 func (c *Client) Send(msg Message) error {
 	if c.config.TestMode {
 		c.logger.Println("TEST_MODE is enabled, emails not being sent")
 		return nil
 	}
 
-	err := c.Connect()
+	err := c.Open()
 	if err != nil {
 		return c.Error(err)
 	}
 
+	err = c.Deliver(msg)
+	if err != nil {
+		return c.Error(err)
+	}
+
+	c.PrintLog("Quitting...")
+	err = c.Quit()
+	if err != nil {
+		return c.Error(err)
+	}
+	c.PrintLog("Goodbye.")
+
+	return nil
+}
+
+// Open connects to the server and runs the handshake, leaving a session that
+// is ready to deliver messages.
+func (c *Client) Open() error {
+	err := c.Connect()
+	if err != nil {
+		return err
+	}
+
 	c.PrintLog("Initiating hello...")
 	err = c.Hello()
 	if err != nil {
-		return c.Error(err)
+		return err
 	}
 	c.PrintLog("Hello complete.")
 
-	if !c.config.DisableTLS {
+	if c.config.TLSMode == TLSModeStartTLS {
 		c.PrintLog("Starting TLS...")
 		err = c.StartTLS()
 		if err != nil {
-			return c.Error(err)
+			return err
 		}
 		c.PrintLog("TLS connection opened.")
+	}
 
+	if c.config.TLSMode != TLSModeNone {
 		c.PrintLog("Starting authentication...")
 		err = c.Auth()
 		if err != nil {
-			return c.Error(err)
+			return err
 		}
 		c.PrintLog("Authenticated.")
 	}
 
-	c.PrintLog("Sending mail from: %s", msg.From)
-	err = c.client.Mail(msg.From)
-	if err != nil {
-		return c.Error(err)
+	return nil
+}
+
+// RecipientsRefusedError is returned when a message was delivered, but the
+// server refused some of its recipients. Refused holds the reply to each of
+// the refused addresses.
+type RecipientsRefusedError struct {
+	Refused map[string]error
+}
+
+func (err RecipientsRefusedError) Error() string {
+	var addresses []string
+	for address := range err.Refused {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	var refusals []string
+	for _, address := range addresses {
+		refusals = append(refusals, fmt.Sprintf("%s (%s)", address, err.Refused[address]))
+	}
+
+	return "smtp: recipients were refused: " + strings.Join(refusals, ", ")
+}
+
+// Deliver sends a single message over an open session. When the server
+// supports PIPELINING, the envelope commands are sent without waiting for
+// each other's replies. The message is sent as long as the server accepts
+// one of its recipients; the others are returned in a RecipientsRefusedError.
+func (c *Client) Deliver(msg Message) error {
+	returnPath := msg.ReturnPath
+	if returnPath == "" {
+		returnPath = msg.From
 	}
 
+	c.PrintLog("Sending mail from: %s", returnPath)
 	c.PrintLog("Sending mail to: %s", msg.To)
-	err = c.client.Rcpt(msg.To)
+	refused, err := c.envelope(returnPath, msg.Recipients())
 	if err != nil {
-		return c.Error(err)
+		return err
 	}
 
 	c.PrintLog("Sending mail data...")
 	c.PrintLog("Message Data: %s", base64.StdEncoding.EncodeToString([]byte(msg.Data())))
 	err = c.Data(msg)
 	if err != nil {
-		return c.Error(err)
+		return err
 	}
 	c.PrintLog("Mail data sent.")
 
-	c.PrintLog("Quitting...")
-	err = c.Quit()
-	if err != nil {
-		return c.Error(err)
+	if len(refused) > 0 {
+		return RecipientsRefusedError{Refused: refused}
 	}
-	c.PrintLog("Goodbye.")
 
 	return nil
 }
 
+// envelope starts a mail transaction for the recipients, returning the reply
+// to each one the server refused. It returns an error when the transaction
+// could not be started or every recipient was refused.
+func (c *Client) envelope(from string, to []string) (map[string]error, error) {
+	refused := map[string]error{}
+
+	if ok, _ := c.Extension("PIPELINING"); !ok {
+		err := c.client.Mail(from)
+		if err != nil {
+			return refused, err
+		}
+
+		for _, recipient := range to {
+			err = c.client.Rcpt(recipient)
+			if err != nil {
+				if _, ok := err.(*textproto.Error); !ok {
+					return refused, err
+				}
+				refused[recipient] = err
+			}
+		}
+
+		return refused, allRefused(to, refused)
+	}
+
+	if strings.ContainsAny(from+strings.Join(to, ""), "\r\n") {
+		return refused, errors.New("smtp: A line must not contain CR or LF")
+	}
+
+	mailCommand := "MAIL FROM:<%s>"
+	if ok, _ := c.Extension("8BITMIME"); ok {
+		mailCommand += " BODY=8BITMIME"
+	}
+
+	mailID, err := c.client.Text.Cmd(mailCommand, from)
+	if err != nil {
+		return refused, err
+	}
+
+	rcptIDs := []uint{}
+	for _, recipient := range to {
+		rcptID, err := c.client.Text.Cmd("RCPT TO:<%s>", recipient)
+		if err != nil {
+			return refused, err
+		}
+		rcptIDs = append(rcptIDs, rcptID)
+	}
+
+	mailErr := c.readResponse(mailID, 250)
+
+	var connectionErr error
+	for i, rcptID := range rcptIDs {
+		err := c.readResponse(rcptID, 25)
+		if err == nil {
+			continue
+		}
+
+		if _, ok := err.(*textproto.Error); ok {
+			refused[to[i]] = err
+		} else if connectionErr == nil {
+			connectionErr = err
+		}
+	}
+
+	if mailErr != nil {
+		return refused, mailErr
+	}
+
+	if connectionErr != nil {
+		return refused, connectionErr
+	}
+
+	return refused, allRefused(to, refused)
+}
+
+// allRefused returns the reply to the first recipient when the server
+// refused every one of them, since then there is no one to send the message
+// to.
+func allRefused(to []string, refused map[string]error) error {
+	if len(to) == 0 {
+		return nil
+	}
+
+	for _, recipient := range to {
+		if _, ok := refused[recipient]; !ok {
+			return nil
+		}
+	}
+
+	return refused[to[0]]
+}
+
+func (c *Client) readResponse(id uint, expectCode int) error {
+	c.client.Text.StartResponse(id)
+	defer c.client.Text.EndResponse(id)
+
+	_, _, err := c.client.Text.ReadResponse(expectCode)
+	return err
+}
+
+// Reset aborts any mail transaction in progress so that the session can be
+// reused for the next message.
+func (c *Client) Reset() error {
+	return c.client.Reset()
+}
+
+// Noop checks that the session is still alive.
+func (c *Client) Noop() error {
+	return c.client.Noop()
+}
+
+// Close ends the session, dropping the connection when the server does not
+// respond to QUIT.
+func (c *Client) Close() {
+	if c.client == nil {
+		return
+	}
+
+	err := c.client.Quit()
+	if err != nil {
+		c.client.Close()
+	}
+
+	c.client = nil
+}
+
 func (c *Client) Hello() error {
 	err := c.client.Hello("localhost")
 	if err != nil {
@@ -184,10 +453,7 @@ func (c *Client) Extension(name string) (bool, string) {
 
 func (c *Client) StartTLS() error {
 	if ok, _ := c.Extension("STARTTLS"); ok {
-		err := c.client.StartTLS(&tls.Config{
-			ServerName:         c.config.Host,
-			InsecureSkipVerify: c.config.SkipVerifySSL,
-		})
+		err := c.client.StartTLS(c.tlsConfig)
 		if err != nil {
 			return err
 		}
@@ -217,6 +483,12 @@ func (c *Client) AuthMechanism() smtp.Auth {
 	case AuthPlain:
 		c.PrintLog("Using PLAIN to authenticate")
 		return smtp.PlainAuth("", c.config.User, c.config.Pass, c.config.Host)
+	case AuthLogin:
+		c.PrintLog("Using LOGIN to authenticate")
+		return LoginAuth(c.config.User, c.config.Pass, c.config.Host)
+	case AuthXOAUTH2:
+		c.PrintLog("Using XOAUTH2 to authenticate")
+		return XOAUTH2Auth(c.config.User, c.config.TokenSource, c.config.Host)
 	default:
 		c.PrintLog("No authentication mechanism configured")
 		return nil
@@ -229,7 +501,15 @@ func (c *Client) Data(msg Message) error {
 		return err
 	}
 
-	data := strings.Replace(string(msg.Data()), "%", "%%", -1)
+	data := msg.Data()
+	if c.signer != nil {
+		data, err = c.signer.Sign(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	data = strings.Replace(data, "%", "%%", -1)
 	_, err = fmt.Fprintf(wc, data)
 	if err != nil {
 		return err
@@ -34,7 +34,7 @@ var _ = Describe("Mail", func() {
 			Pass:          "pass",
 			TestMode:      false,
 			SkipVerifySSL: true,
-			DisableTLS:    false,
+			TLSMode:       mail.TLSModeStartTLS,
 		}
 
 		config.Host, config.Port, err = net.SplitHostPort(mailServer.URL.String())
@@ -270,7 +270,7 @@ var _ = Describe("Mail", func() {
 				var err error
 
 				mailServer.SupportsTLS = false
-				config.DisableTLS = true
+				config.TLSMode = mail.TLSModeNone
 				client, err = mail.NewClient(config, logger)
 				if err != nil {
 					panic(err)
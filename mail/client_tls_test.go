@@ -0,0 +1,149 @@
+package mail_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var certificates Certificates
+	var server *SMTPDServer
+	var config mail.Config
+	var logger *log.Logger
+	var msg mail.Message
+
+	BeforeEach(func() {
+		certificates = NewCertificates()
+		server = NewSMTPDServer()
+		config = mail.Config{
+			CACertificates: certificates.CAPEM,
+		}
+		logger = log.New(bytes.NewBuffer([]byte{}), "", 0)
+		msg = mail.Message{
+			From:    "no-reply@example.com",
+			To:      "user@example.com",
+			Subject: "Hello",
+			Body:    []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	send := func() error {
+		client, err := mail.NewClient(config, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		return client.Send(msg)
+	}
+
+	Context("when using STARTTLS", func() {
+		BeforeEach(func() {
+			server.Server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificates.Server}}
+			server.Server.ForceTLS = true
+			config.TLSMode = mail.TLSModeStartTLS
+		})
+
+		JustBeforeEach(func() {
+			config.Host, config.Port = server.Start()
+		})
+
+		It("upgrades the connection, trusting the configured certificate authorities", func() {
+			Expect(send()).NotTo(HaveOccurred())
+			Expect(server.Deliveries()).To(HaveLen(1))
+		})
+
+		It("refuses a server certificate that is not signed by a trusted authority", func() {
+			config.CACertificates = nil
+
+			Expect(send()).To(HaveOccurred())
+			Expect(server.Deliveries()).To(BeEmpty())
+		})
+
+		It("accepts any server certificate when verification is skipped", func() {
+			config.CACertificates = nil
+			config.SkipVerifySSL = true
+
+			Expect(send()).NotTo(HaveOccurred())
+			Expect(server.Deliveries()).To(HaveLen(1))
+		})
+	})
+
+	Context("when using implicit TLS", func() {
+		var serverTLSConfig *tls.Config
+
+		BeforeEach(func() {
+			serverTLSConfig = &tls.Config{Certificates: []tls.Certificate{certificates.Server}}
+			config.TLSMode = mail.TLSModeImplicit
+		})
+
+		JustBeforeEach(func() {
+			config.Host, config.Port = server.StartImplicitTLS(serverTLSConfig)
+		})
+
+		It("speaks SMTP over TLS from the start", func() {
+			Expect(send()).NotTo(HaveOccurred())
+			Expect(server.Deliveries()).To(HaveLen(1))
+		})
+
+		It("refuses a server certificate that is not signed by a trusted authority", func() {
+			config.CACertificates = nil
+
+			Expect(send()).To(HaveOccurred())
+		})
+
+		Context("when the server requires a client certificate", func() {
+			BeforeEach(func() {
+				serverTLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+				serverTLSConfig.ClientCAs = certificates.CAPool
+			})
+
+			It("presents the configured client certificate", func() {
+				config.ClientCertificate = certificates.ClientCertificate
+				config.ClientKey = certificates.ClientKey
+
+				Expect(send()).NotTo(HaveOccurred())
+				Expect(server.Deliveries()).To(HaveLen(1))
+			})
+
+			It("cannot send without one", func() {
+				Expect(send()).To(HaveOccurred())
+				Expect(server.Deliveries()).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when TLS is disabled", func() {
+		It("sends in plain text", func() {
+			config.TLSMode = mail.TLSModeNone
+			config.Host, config.Port = server.Start()
+
+			Expect(send()).NotTo(HaveOccurred())
+			Expect(server.Deliveries()).To(HaveLen(1))
+		})
+	})
+
+	Describe("NewClient", func() {
+		It("returns an error when the CA certificates contain no certificate", func() {
+			config.CACertificates = []byte("banana")
+
+			_, err := mail.NewClient(config, logger)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the client certificate does not match its key", func() {
+			config.ClientCertificate = certificates.ClientCertificate
+			config.ClientKey = NewCertificates().ClientKey
+
+			_, err := mail.NewClient(config, logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,214 @@
+package mail
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultDKIMHeaders are the headers signed when no header set is configured.
+// Headers that are missing from a message are left out of its signature.
+var DefaultDKIMHeaders = []string{
+	"From",
+	"Reply-To",
+	"To",
+	"Subject",
+	"Date",
+	"Mime-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+}
+
+var whitespaceRun = regexp.MustCompile(`[ \t]+`)
+
+// DKIMSigner adds a DKIM-Signature header (RFC 6376) to outgoing messages,
+// using relaxed canonicalization for both the headers and the body. RSA keys
+// sign with rsa-sha256 and Ed25519 keys with ed25519-sha256 (RFC 8463).
+type DKIMSigner struct {
+	domain    string
+	selector  string
+	headers   []string
+	key       crypto.Signer
+	algorithm string
+	now       func() time.Time
+}
+
+// NewDKIMSigner parses a PEM encoded private key, in either PKCS #1 or
+// PKCS #8 form.
+func NewDKIMSigner(domain, selector string, privateKeyPEM []byte, headers []string) (DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return DKIMSigner{}, errors.New("DKIM signing requires both a domain and a selector")
+	}
+
+	key, err := parseDKIMPrivateKey(privateKeyPEM)
+	if err != nil {
+		return DKIMSigner{}, err
+	}
+
+	var algorithm string
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		algorithm = "ed25519-sha256"
+	default:
+		return DKIMSigner{}, fmt.Errorf("DKIM private key of type %T is not supported, use an RSA or Ed25519 key", key)
+	}
+
+	if len(headers) == 0 {
+		headers = DefaultDKIMHeaders
+	}
+
+	return DKIMSigner{
+		domain:    domain,
+		selector:  selector,
+		headers:   headers,
+		key:       key,
+		algorithm: algorithm,
+		now:       time.Now,
+	}, nil
+}
+
+func parseDKIMPrivateKey(privateKeyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("DKIM private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("DKIM private key of type %T cannot sign", key)
+		}
+
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("DKIM private key has unsupported PEM type %q", block.Type)
+	}
+}
+
+// Sign returns the message data with a DKIM-Signature header prepended. The
+// data is signed as it will be sent, with every line ending in CRLF.
+func (signer DKIMSigner) Sign(data string) (string, error) {
+	wire := strings.Replace(strings.Replace(data, "\r\n", "\n", -1), "\n", "\r\n", -1)
+
+	header, body := wire, ""
+	if index := strings.Index(wire, "\r\n\r\n"); index >= 0 {
+		header, body = wire[:index+2], wire[index+4:]
+	}
+
+	bodyHash := sha256.Sum256([]byte(RelaxedBody(body)))
+
+	fields := parseHeaderFields(header)
+	var signedNames []string
+	var signedFields []string
+	used := map[int]bool{}
+	for _, name := range signer.headers {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(fields[i].name, name) {
+				continue
+			}
+
+			used[i] = true
+			signedNames = append(signedNames, strings.ToLower(name))
+			signedFields = append(signedFields, RelaxedHeader(fields[i].name, fields[i].value))
+			break
+		}
+	}
+
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		signer.algorithm, signer.domain, signer.selector, signer.now().Unix(),
+		strings.Join(signedNames, ":"), base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	signedData := strings.Join(signedFields, "") + strings.TrimSuffix(RelaxedHeader("DKIM-Signature", value), "\r\n")
+	digest := sha256.Sum256([]byte(signedData))
+
+	var opts crypto.SignerOpts = crypto.SHA256
+	if signer.algorithm == "ed25519-sha256" {
+		opts = crypto.Hash(0)
+	}
+
+	signature, err := signer.key.Sign(rand.Reader, digest[:], opts)
+	if err != nil {
+		return "", err
+	}
+
+	return "DKIM-Signature: " + value + base64.StdEncoding.EncodeToString(signature) + "\n" + data, nil
+}
+
+type headerField struct {
+	name  string
+	value string
+}
+
+func parseHeaderFields(header string) []headerField {
+	var fields []headerField
+	for _, line := range strings.SplitAfter(header, "\r\n") {
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1].value += line
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		fields = append(fields, headerField{name: parts[0], value: parts[1]})
+	}
+
+	return fields
+}
+
+// RelaxedHeader canonicalizes a header field with the "relaxed" algorithm of
+// RFC 6376, section 3.4.2.
+func RelaxedHeader(name, value string) string {
+	value = strings.Replace(value, "\r\n", "", -1)
+	value = whitespaceRun.ReplaceAllString(value, " ")
+	value = strings.Trim(value, " ")
+
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + value + "\r\n"
+}
+
+// RelaxedBody canonicalizes a message body with the "relaxed" algorithm of
+// RFC 6376, section 3.4.4.
+func RelaxedBody(body string) string {
+	lines := strings.Split(body, "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(whitespaceRun.ReplaceAllString(line, " "), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
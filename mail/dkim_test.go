@@ -0,0 +1,348 @@
+package mail_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DKIM", func() {
+	Describe("NewDKIMSigner", func() {
+		It("rejects keys that are not PEM encoded", func() {
+			_, err := mail.NewDKIMSigner("example.com", "notifications", []byte("not a key"), nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects PEM blocks that are not private keys", func() {
+			block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("banana")})
+
+			_, err := mail.NewDKIMSigner("example.com", "notifications", block, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("requires a domain and a selector", func() {
+			_, err := mail.NewDKIMSigner("example.com", "", ed25519PEM(), nil)
+			Expect(err).To(HaveOccurred())
+
+			_, err = mail.NewDKIMSigner("", "notifications", ed25519PEM(), nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("relaxed canonicalization", func() {
+		It("canonicalizes headers as in RFC 6376, section 3.4.5", func() {
+			Expect(mail.RelaxedHeader("A", " X\r\n")).To(Equal("a:X\r\n"))
+			Expect(mail.RelaxedHeader("B ", " Y\t\r\n\tZ  \r\n")).To(Equal("b:Y Z\r\n"))
+		})
+
+		It("canonicalizes bodies as in RFC 6376, section 3.4.5", func() {
+			Expect(mail.RelaxedBody(" C \r\nD \t E\r\n\r\n\r\n")).To(Equal(" C\r\nD E\r\n"))
+		})
+
+		It("canonicalizes an empty body to nothing", func() {
+			Expect(mail.RelaxedBody("\r\n\r\n")).To(Equal(""))
+		})
+	})
+
+	Describe("the verifier of these specs", func() {
+		It("verifies the Ed25519 example of RFC 8463", func() {
+			publicKey, err := base64.StdEncoding.DecodeString("11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=")
+			if err != nil {
+				panic(err)
+			}
+
+			message := strings.Join([]string{
+				"DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;",
+				" d=football.example.com; i=@football.example.com;",
+				" q=dns/txt; s=brisbane; t=1528637909; h=from : to :",
+				" subject : date : message-id : from : subject : date;",
+				" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;",
+				" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus",
+				" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==",
+				"From: Joe SixPack <joe@football.example.com>",
+				"To: Suzie Q <suzie@shopping.example.net>",
+				"Subject: Is dinner ready?",
+				"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)",
+				"Message-ID: <20030712040037.46341.5F8J@football.example.com>",
+				"",
+				"Hi.",
+				"",
+				"We lost the game.  Are you hungry yet?",
+				"",
+				"Joe.",
+				"",
+			}, "\r\n")
+
+			tags, err := verifyDKIM(message, ed25519.PublicKey(publicKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags["d"]).To(Equal("football.example.com"))
+
+			_, err = verifyDKIM(strings.Replace(message, "hungry", "thirsty", 1), ed25519.PublicKey(publicKey))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("sending signed mail", func() {
+		var server *SMTPDServer
+		var config mail.Config
+		var msg mail.Message
+
+		BeforeEach(func() {
+			server = NewSMTPDServer()
+			config = mail.Config{
+				TLSMode:      mail.TLSModeNone,
+				DKIMDomain:   "example.com",
+				DKIMSelector: "notifications",
+			}
+			config.Host, config.Port = server.Start()
+
+			msg = mail.Message{
+				From:    "no-reply@example.com",
+				ReplyTo: "reply-to@example.com",
+				To:      "user@example.com",
+				Subject: "Your   status\t report",
+				Headers: []string{"X-CF-Client-ID: some-client", "List-Unsubscribe: <https://notifications.example.com/unsubscribe/abc>"},
+				Body: []mail.Part{
+					{ContentType: "text/plain", Content: "Everything is fine.  \n\n\n"},
+					{ContentType: "text/html", Content: "<p>Everything is 100% fine.</p>"},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		send := func() string {
+			client, err := mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.Send(msg)
+			Expect(err).NotTo(HaveOccurred())
+
+			deliveries := server.Deliveries()
+			Expect(deliveries).To(HaveLen(1))
+
+			return string(deliveries[0].Data)
+		}
+
+		It("signs with an RSA key", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			config.DKIMPrivateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+			data := send()
+
+			tags, err := verifyDKIM(data, &key.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags["a"]).To(Equal("rsa-sha256"))
+			Expect(tags["c"]).To(Equal("relaxed/relaxed"))
+			Expect(tags["d"]).To(Equal("example.com"))
+			Expect(tags["s"]).To(Equal("notifications"))
+			Expect(tags["h"]).To(Equal("from:reply-to:to:subject:date:mime-version:content-type:list-unsubscribe"))
+		})
+
+		It("signs with an Ed25519 key", func() {
+			publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			config.DKIMPrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+			data := send()
+
+			tags, err := verifyDKIM(data, publicKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags["a"]).To(Equal("ed25519-sha256"))
+		})
+
+		It("signs only the configured headers", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			config.DKIMPrivateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+			config.DKIMHeaders = []string{"From", "Subject", "X-CF-Client-ID", "X-Missing"}
+
+			data := send()
+
+			tags, err := verifyDKIM(data, &key.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags["h"]).To(Equal("from:subject:x-cf-client-id"))
+		})
+
+		It("fails verification when a signed header is altered in transit", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			config.DKIMPrivateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+			data := strings.Replace(send(), "Subject: Your", "Subject: Our", 1)
+
+			_, err = verifyDKIM(data, &key.PublicKey)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("does not sign mail when no domain is configured", func() {
+			config.DKIMDomain = ""
+
+			Expect(send()).NotTo(ContainSubstring("DKIM-Signature"))
+		})
+
+		It("returns an error from NewClient when the key is invalid", func() {
+			config.DKIMPrivateKey = []byte("banana")
+
+			_, err := mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func ed25519PEM() []byte {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// verifyDKIM checks the first DKIM-Signature of a received message,
+// independently of the signer, and returns the signature's tags. It is itself
+// checked against the example of RFC 8463.
+func verifyDKIM(data string, publicKey crypto.PublicKey) (map[string]string, error) {
+	data = strings.Replace(strings.Replace(data, "\r\n", "\n", -1), "\n", "\r\n", -1)
+	parts := strings.SplitN(data, "\r\n\r\n", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("message has no body")
+	}
+
+	var fields [][2]string
+	for _, line := range strings.SplitAfter(parts[0]+"\r\n", "\r\n") {
+		if line == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			fields[len(fields)-1][1] += line
+			continue
+		}
+		field := strings.SplitN(line, ":", 2)
+		fields = append(fields, [2]string{field[0], field[1]})
+	}
+
+	signature := -1
+	for i, field := range fields {
+		if strings.EqualFold(field[0], "DKIM-Signature") {
+			signature = i
+			break
+		}
+	}
+	if signature < 0 {
+		return nil, errors.New("message has no DKIM-Signature")
+	}
+
+	tags := map[string]string{}
+	for _, tag := range strings.Split(fields[signature][1], ";") {
+		pair := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+		if len(pair) == 2 {
+			tags[pair[0]] = strings.Join(strings.Fields(pair[1]), "")
+		}
+	}
+
+	bodyHash := sha256.Sum256([]byte(relaxedBody(parts[1])))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return nil, errors.New("body hash does not match")
+	}
+
+	var signed string
+	used := map[int]bool{}
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if !used[i] && strings.EqualFold(fields[i][0], name) {
+				used[i] = true
+				signed += relaxedHeader(fields[i][0], fields[i][1])
+				break
+			}
+		}
+	}
+
+	value := regexp.MustCompile(`(;\s*b=)[^;]*`).ReplaceAllString(fields[signature][1], "$1")
+	signed += strings.TrimSuffix(relaxedHeader("DKIM-Signature", value), "\r\n")
+	digest := sha256.Sum256([]byte(signed))
+
+	b, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], b)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, digest[:], b) {
+			err = errors.New("ed25519 signature does not verify")
+		}
+	}
+
+	return tags, err
+}
+
+// relaxedHeader and relaxedBody canonicalize as RFC 6376, sections 3.4.2 and
+// 3.4.4 describe. They are written apart from the signer's, so that the specs
+// do not check the signer against itself.
+func relaxedHeader(name, value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	})
+
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Join(words, " ") + "\r\n"
+}
+
+func relaxedBody(body string) string {
+	var canonical bytes.Buffer
+	emptyLines := 0
+	for _, line := range strings.Split(body, "\r\n") {
+		var canonicalLine bytes.Buffer
+		space := false
+		for i := 0; i < len(line); i++ {
+			if line[i] == ' ' || line[i] == '\t' {
+				space = true
+				continue
+			}
+			if space {
+				canonicalLine.WriteByte(' ')
+				space = false
+			}
+			canonicalLine.WriteByte(line[i])
+		}
+
+		if canonicalLine.Len() == 0 {
+			emptyLines++
+			continue
+		}
+
+		canonical.WriteString(strings.Repeat("\r\n", emptyLines))
+		emptyLines = 0
+		canonical.Write(canonicalLine.Bytes())
+		canonical.WriteString("\r\n")
+	}
+
+	return canonical.String()
+}
@@ -0,0 +1,158 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+var NotADSNError = errors.New("message is not a delivery status notification")
+
+// DSN is a delivery status notification, as described by RFC 3464, reporting
+// on a message this service sent.
+type DSN struct {
+	Recipients []DSNRecipient
+}
+
+type DSNRecipient struct {
+	Address        string
+	Action         string
+	Status         string
+	DiagnosticCode string
+}
+
+// Failed reports whether the message could not be delivered to the recipient.
+func (recipient DSNRecipient) Failed() bool {
+	return recipient.Action == "failed"
+}
+
+// Permanent reports whether the failure is a hard bounce, one that will recur
+// on every further attempt to deliver to the recipient.
+func (recipient DSNRecipient) Permanent() bool {
+	return recipient.Failed() && strings.HasPrefix(recipient.Status, "5.")
+}
+
+// ParseDSN reads a multipart/report delivery status notification.
+func ParseDSN(data []byte) (DSN, error) {
+	var dsn DSN
+
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return dsn, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || !strings.EqualFold(params["report-type"], "delivery-status") {
+		return dsn, NotADSNError
+	}
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return dsn, err
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType != "message/delivery-status" {
+			continue
+		}
+
+		body, err := ioutil.ReadAll(decodePart(part))
+		if err != nil {
+			return dsn, err
+		}
+
+		dsn.Recipients, err = parseDeliveryStatus(body)
+		if err != nil {
+			return dsn, err
+		}
+	}
+
+	if len(dsn.Recipients) == 0 {
+		return dsn, NotADSNError
+	}
+
+	return dsn, nil
+}
+
+func decodePart(part *multipart.Part) io.Reader {
+	switch strings.ToLower(part.Header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, part)
+	case "quoted-printable":
+		return quotedprintable.NewReader(part)
+	default:
+		return part
+	}
+}
+
+// parseDeliveryStatus reads the per-recipient field groups that follow the
+// per-message fields of a message/delivery-status part.
+func parseDeliveryStatus(body []byte) ([]DSNRecipient, error) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(body)))
+
+	_, err := reader.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var recipients []DSNRecipient
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			address := fields.Get("Final-Recipient")
+			if address == "" {
+				address = fields.Get("Original-Recipient")
+			}
+
+			recipients = append(recipients, DSNRecipient{
+				Address:        addressValue(address),
+				Action:         strings.ToLower(firstWord(fields.Get("Action"))),
+				Status:         firstWord(fields.Get("Status")),
+				DiagnosticCode: addressValue(fields.Get("Diagnostic-Code")),
+			})
+		}
+
+		if err == io.EOF {
+			return recipients, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// addressValue strips the type from a typed field such as
+// "rfc822; user@example.com" or "smtp; 550 5.1.1 No such user".
+func addressValue(field string) string {
+	if index := strings.Index(field, ";"); index >= 0 {
+		field = field[index+1:]
+	}
+
+	return strings.TrimSpace(field)
+}
+
+func firstWord(field string) string {
+	words := strings.Fields(field)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return words[0]
+}
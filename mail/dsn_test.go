@@ -0,0 +1,123 @@
+package mail_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const hardBounce = `From: Mail Delivery System <MAILER-DAEMON@mx.example.com>
+To: bounces+message-123@bounces.example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/plain; charset=us-ascii
+
+The mail system could not deliver your message.
+
+--BOUNDARY
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+Arrival-Date: Tue, 20 Jan 2015 20:23:31 +0000
+
+Final-Recipient: rfc822; user@example.com
+Original-Recipient: rfc822;user@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 <user@example.com>: Recipient address
+    rejected: User unknown
+
+--BOUNDARY
+Content-Type: text/rfc822-headers
+
+From: no-reply@notifications.example.com
+To: user@example.com
+Subject: Hello
+X-CF-Client-ID: some-client
+X-CF-Notification-ID: message-123
+
+--BOUNDARY--
+`
+
+var _ = Describe("ParseDSN", func() {
+	It("reads the recipients from a delivery status notification", func() {
+		dsn, err := mail.ParseDSN([]byte(hardBounce))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dsn.Recipients).To(Equal([]mail.DSNRecipient{
+			{
+				Address:        "user@example.com",
+				Action:         "failed",
+				Status:         "5.1.1",
+				DiagnosticCode: "550 5.1.1 <user@example.com>: Recipient address rejected: User unknown",
+			},
+		}))
+		Expect(dsn.Recipients[0].Permanent()).To(BeTrue())
+	})
+
+	It("reads every recipient the notification reports on", func() {
+		report := strings.Replace(hardBounce, "Diagnostic-Code: smtp; 550 5.1.1 <user@example.com>: Recipient address\n    rejected: User unknown\n", `Diagnostic-Code: smtp; 550 User unknown
+
+Final-Recipient: rfc822; other@example.com
+Action: delayed
+Status: 4.2.2 (mailbox full)
+`, 1)
+
+		dsn, err := mail.ParseDSN([]byte(report))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dsn.Recipients).To(HaveLen(2))
+		Expect(dsn.Recipients[1]).To(Equal(mail.DSNRecipient{
+			Address: "other@example.com",
+			Action:  "delayed",
+			Status:  "4.2.2",
+		}))
+		Expect(dsn.Recipients[1].Failed()).To(BeFalse())
+	})
+
+	It("decodes a base64 encoded delivery status part", func() {
+		report := strings.Replace(hardBounce, `Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+Arrival-Date: Tue, 20 Jan 2015 20:23:31 +0000
+
+Final-Recipient: rfc822; user@example.com
+Original-Recipient: rfc822;user@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 <user@example.com>: Recipient address
+    rejected: User unknown
+`, `Content-Type: message/delivery-status
+Content-Transfer-Encoding: base64
+
+UmVwb3J0aW5nLU1UQTogZG5zOyBteC5leGFtcGxlLmNvbQoKRmluYWwtUmVjaXBpZW50OiByZmM4
+MjI7IHVzZXJAZXhhbXBsZS5jb20KQWN0aW9uOiBmYWlsZWQKU3RhdHVzOiA1LjEuMQo=
+`, 1)
+
+		dsn, err := mail.ParseDSN([]byte(report))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dsn.Recipients).To(Equal([]mail.DSNRecipient{
+			{Address: "user@example.com", Action: "failed", Status: "5.1.1"},
+		}))
+	})
+
+	It("returns an error for mail that is not a delivery status notification", func() {
+		_, err := mail.ParseDSN([]byte("From: someone@example.com\nSubject: Out of office\n\nI am on vacation.\n"))
+		Expect(err).To(Equal(mail.NotADSNError))
+	})
+
+	Describe("DSNRecipient", func() {
+		It("treats only failures with a 5.x.x status as permanent", func() {
+			Expect(mail.DSNRecipient{Action: "failed", Status: "5.2.1"}.Permanent()).To(BeTrue())
+			Expect(mail.DSNRecipient{Action: "failed", Status: "4.4.7"}.Permanent()).To(BeFalse())
+			Expect(mail.DSNRecipient{Action: "failed", Status: "4.4.7"}.Failed()).To(BeTrue())
+			Expect(mail.DSNRecipient{Action: "delivered", Status: "2.0.0"}.Failed()).To(BeFalse())
+		})
+	})
+})
@@ -5,3 +5,11 @@ import "time"
 func (c *Client) ConnectTimeout() time.Duration {
 	return c.config.ConnectTimeout
 }
+
+func (breaker *CircuitBreaker) SetNow(now func() time.Time) {
+	breaker.now = now
+}
+
+func (source *OAuthTokenSource) SetNow(now func() time.Time) {
+	source.now = now
+}
@@ -2,15 +2,24 @@ package mail_test
 
 import (
 	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"log"
+	"math/big"
 	"net"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"bitbucket.org/chrj/smtpd"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -268,3 +277,169 @@ func (server *SMTPServer) RespondToQuit(output *bufio.Writer) {
 	output.Flush()
 	server.ConnectionState = StateClosed
 }
+
+// SMTPDServer is a complete SMTP server, for specs that need a server to
+// behave like a real one across several messages and connections.
+type SMTPDServer struct {
+	Server      *smtpd.Server
+	listener    net.Listener
+	mutex       sync.Mutex
+	deliveries  []smtpd.Envelope
+	connections int
+}
+
+func NewSMTPDServer() *SMTPDServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	server := &SMTPDServer{listener: listener}
+	server.Server = &smtpd.Server{
+		Handler:           server.handle,
+		ConnectionChecker: server.checkConnection,
+	}
+
+	return server
+}
+
+// Start serves on the listener, returning the address it listens on.
+func (server *SMTPDServer) Start() (string, string) {
+	go server.Server.Serve(server.listener)
+
+	return server.address()
+}
+
+// StartImplicitTLS serves SMTPS, where the connection is wrapped in TLS
+// before the SMTP conversation starts.
+func (server *SMTPDServer) StartImplicitTLS(config *tls.Config) (string, string) {
+	go server.Server.Serve(tls.NewListener(server.listener, config))
+
+	return server.address()
+}
+
+func (server *SMTPDServer) address() (string, string) {
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	if err != nil {
+		panic(err)
+	}
+
+	return host, port
+}
+
+func (server *SMTPDServer) handle(peer smtpd.Peer, env smtpd.Envelope) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.deliveries = append(server.deliveries, env)
+	return nil
+}
+
+func (server *SMTPDServer) checkConnection(peer smtpd.Peer) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.connections++
+	return nil
+}
+
+func (server *SMTPDServer) Deliveries() []smtpd.Envelope {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.deliveries
+}
+
+func (server *SMTPDServer) Connections() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.connections
+}
+
+func (server *SMTPDServer) Close() {
+	server.listener.Close()
+}
+
+// Certificates is a certificate authority along with a server certificate
+// for 127.0.0.1 and a client certificate, both signed by it.
+type Certificates struct {
+	CAPEM             []byte
+	CAPool            *x509.CertPool
+	Server            tls.Certificate
+	ClientCertificate []byte
+	ClientKey         []byte
+}
+
+func NewCertificates() Certificates {
+	caKey, caTemplate := newCertificateTemplate("Test CA")
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		panic(err)
+	}
+
+	serverKey, serverTemplate := newCertificateTemplate("127.0.0.1")
+	serverTemplate.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverCertificate, serverKeyPEM := signCertificate(serverTemplate, serverKey, ca, caKey)
+	server, err := tls.X509KeyPair(serverCertificate, serverKeyPEM)
+	if err != nil {
+		panic(err)
+	}
+
+	clientKey, clientTemplate := newCertificateTemplate("notifications")
+	clientTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	clientCertificate, clientKeyPEM := signCertificate(clientTemplate, clientKey, ca, caKey)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca)
+
+	return Certificates{
+		CAPEM:             pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		CAPool:            caPool,
+		Server:            server,
+		ClientCertificate: clientCertificate,
+		ClientKey:         clientKeyPEM,
+	}
+}
+
+var serialNumber int64
+
+func newCertificateTemplate(commonName string) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	serialNumber++
+
+	return key, &x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+}
+
+func signCertificate(template *x509.Certificate, key *ecdsa.PrivateKey, ca *x509.Certificate, caKey *ecdsa.PrivateKey) ([]byte, []byte) {
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
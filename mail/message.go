@@ -17,7 +17,8 @@ Content-Type: {{.ContentType}}
 {{if .ContentTransferEncoding}}Content-Transfer-Encoding: {{.ContentTransferEncoding}}
 {{end}}From: {{.From}}{{if .ReplyTo}}
 Reply-To: {{.ReplyTo}}{{end}}
-To: {{.To}}
+To: {{.To}}{{if .CC}}
+Cc: {{.CCHeader}}{{end}}
 Subject: {{.Subject}}
 
 {{.CompiledBody}}`
@@ -30,10 +31,38 @@ type Message struct {
 	From                    string
 	ReplyTo                 string
 	To                      string
+	CC                      []string
+	BCC                     []string
 	Subject                 string
 	Body                    []Part
 	Headers                 []string
 	CompiledBody            string
+
+	// ReturnPath is the envelope sender that bounces are delivered to. The
+	// From address is used when it is empty.
+	ReturnPath string
+
+	// OmitTo leaves the To address out of the envelope, so that the message
+	// is only delivered to the CC and BCC addresses.
+	OmitTo bool
+}
+
+// Recipients lists the addresses the message is delivered to. BCC addresses
+// are included here even though they never appear in the headers.
+func (msg Message) Recipients() []string {
+	recipients := []string{}
+	if msg.To != "" && !msg.OmitTo {
+		recipients = append(recipients, msg.To)
+	}
+
+	recipients = append(recipients, msg.CC...)
+	recipients = append(recipients, msg.BCC...)
+
+	return recipients
+}
+
+func (msg Message) CCHeader() string {
+	return strings.Join(msg.CC, ", ")
 }
 
 type Part struct {
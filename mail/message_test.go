@@ -1,9 +1,12 @@
 package mail_test
 
 import (
+	"bytes"
+	"log"
 	"strings"
 	"time"
 
+	"bitbucket.org/chrj/smtpd"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 
 	. "github.com/onsi/ginkgo"
@@ -118,6 +121,16 @@ var _ = Describe("Message", func() {
 				}))
 			})
 
+			It("includes a Cc header but never the BCC addresses", func() {
+				msg.CC = []string{"copy@example.com", "other-copy@example.com"}
+				msg.BCC = []string{"hidden@example.com"}
+				data := msg.Data()
+				parts := strings.Split(data, "\n")
+
+				Expect(parts).To(ContainElement("Cc: copy@example.com, other-copy@example.com"))
+				Expect(data).NotTo(ContainSubstring("hidden@example.com"))
+			})
+
 			It("includes only the parts necessary", func() {
 				msg.Body = []mail.Part{
 					{
@@ -142,4 +155,112 @@ var _ = Describe("Message", func() {
 			})
 		})
 	})
+
+	Describe("Recipients", func() {
+		It("lists the To address as a single recipient", func() {
+			msg := mail.Message{To: `"Doe, Jane" <jane@example.com>`}
+			Expect(msg.Recipients()).To(Equal([]string{`"Doe, Jane" <jane@example.com>`}))
+		})
+
+		It("includes the CC and BCC addresses", func() {
+			msg := mail.Message{
+				To:  "you@example.com",
+				CC:  []string{"copy@example.com"},
+				BCC: []string{"hidden@example.com"},
+			}
+			Expect(msg.Recipients()).To(Equal([]string{"you@example.com", "copy@example.com", "hidden@example.com"}))
+		})
+
+		It("leaves out the To address when it is omitted", func() {
+			msg := mail.Message{
+				To:     "you@example.com",
+				CC:     []string{"copy@example.com"},
+				OmitTo: true,
+			}
+			Expect(msg.Recipients()).To(Equal([]string{"copy@example.com"}))
+		})
+
+		It("delivers the message to each of them", func() {
+			server := NewSMTPDServer()
+			defer server.Close()
+
+			config := mail.Config{TLSMode: mail.TLSModeNone}
+			config.Host, config.Port = server.Start()
+
+			client, err := mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.Send(mail.Message{
+				From:    "no-reply@example.com",
+				To:      "first@example.com",
+				CC:      []string{"copy@example.com"},
+				BCC:     []string{"hidden@example.com"},
+				Subject: "Hello",
+				Body:    []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(server.Deliveries()).To(HaveLen(1))
+			Expect(server.Deliveries()[0].Recipients).To(Equal([]string{"first@example.com", "copy@example.com", "hidden@example.com"}))
+		})
+
+		Context("when the server refuses some of them", func() {
+			var server *SMTPDServer
+			var client *mail.Client
+			var msg mail.Message
+
+			BeforeEach(func() {
+				server = NewSMTPDServer()
+				server.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+					if strings.HasPrefix(addr, "unknown") {
+						return smtpd.Error{Code: 550, Message: "User unknown"}
+					}
+					return nil
+				}
+
+				config := mail.Config{TLSMode: mail.TLSModeNone}
+				config.Host, config.Port = server.Start()
+
+				var err error
+				client, err = mail.NewClient(config, log.New(bytes.NewBuffer([]byte{}), "", 0))
+				Expect(err).NotTo(HaveOccurred())
+
+				msg = mail.Message{
+					From:    "no-reply@example.com",
+					To:      "first@example.com",
+					CC:      []string{"unknown@example.com", "copy@example.com"},
+					Subject: "Hello",
+					Body:    []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+				}
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("delivers the message to the others and returns the refused ones", func() {
+				err := client.Send(msg)
+				Expect(err).To(BeAssignableToTypeOf(mail.RecipientsRefusedError{}))
+
+				refused := err.(mail.RecipientsRefusedError).Refused
+				Expect(refused).To(HaveLen(1))
+				Expect(refused["unknown@example.com"].Error()).To(ContainSubstring("User unknown"))
+
+				Expect(server.Deliveries()).To(HaveLen(1))
+				Expect(server.Deliveries()[0].Recipients).To(Equal([]string{"first@example.com", "copy@example.com"}))
+			})
+
+			It("does not send the message when every recipient is refused", func() {
+				msg.To = "unknown@example.com"
+				msg.CC = []string{"unknown-copy@example.com"}
+
+				err := client.Send(msg)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("User unknown"))
+				Expect(err).NotTo(BeAssignableToTypeOf(mail.RecipientsRefusedError{}))
+
+				Expect(server.Deliveries()).To(BeEmpty())
+			})
+		})
+	})
 })
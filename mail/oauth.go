@@ -0,0 +1,153 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenExpiryMargin is how long before its expiry an access token is
+// replaced, so that it does not expire in the middle of a session. Tokens that
+// live for less than a few margins are replaced a quarter of their lifetime
+// early instead.
+const TokenExpiryMargin = time.Minute
+
+// DefaultTokenLifetime is how long an access token is cached when the token
+// endpoint does not say when it expires.
+const DefaultTokenLifetime = time.Hour
+
+type TokenSource interface {
+	Token() (string, error)
+	Invalidate()
+}
+
+type OAuthConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+
+	// RefreshToken is exchanged for access tokens when it is set; otherwise
+	// the client credentials grant is used.
+	RefreshToken string
+	Scope        string
+}
+
+type OAuthTokenError struct {
+	StatusCode int
+	Body       string
+}
+
+func (err OAuthTokenError) Error() string {
+	return fmt.Sprintf("OAuth token endpoint responded with %d: %s", err.StatusCode, err.Body)
+}
+
+// OAuthTokenSource fetches access tokens from an OAuth token endpoint and
+// caches each one until shortly before it expires. It is safe to share between
+// clients.
+type OAuthTokenSource struct {
+	config OAuthConfig
+	client *http.Client
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+func NewOAuthTokenSource(config OAuthConfig) *OAuthTokenSource {
+	return &OAuthTokenSource{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}
+}
+
+func (source *OAuthTokenSource) Token() (string, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.token != "" && source.now().Before(source.expiresAt) {
+		return source.token, nil
+	}
+
+	token, expiresIn, err := source.fetch()
+	if err != nil {
+		return "", err
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = DefaultTokenLifetime
+	}
+
+	margin := TokenExpiryMargin
+	if margin > expiresIn/4 {
+		margin = expiresIn / 4
+	}
+
+	source.token = token
+	source.expiresAt = source.now().Add(expiresIn - margin)
+
+	return source.token, nil
+}
+
+func (source *OAuthTokenSource) Invalidate() {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.token = ""
+}
+
+func (source *OAuthTokenSource) fetch() (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("client_id", source.config.ClientID)
+	form.Set("client_secret", source.config.ClientSecret)
+	if source.config.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", source.config.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if source.config.Scope != "" {
+		form.Set("scope", source.config.Scope)
+	}
+
+	request, err := http.NewRequest("POST", source.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := source.client.Do(request)
+	if err != nil {
+		return "", 0, err
+	}
+	defer response.Body.Close()
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 4096))
+		return "", 0, OAuthTokenError{StatusCode: response.StatusCode, Body: string(message)}
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if body.AccessToken == "" {
+		return "", 0, OAuthTokenError{StatusCode: response.StatusCode, Body: "no access_token in response"}
+	}
+
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
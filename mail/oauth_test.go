@@ -0,0 +1,170 @@
+package mail_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuthTokenSource", func() {
+	var server *httptest.Server
+	var mutex sync.Mutex
+	var requests []url.Values
+	var status int
+	var config mail.OAuthConfig
+	var now time.Time
+	var expiresIn int
+
+	BeforeEach(func() {
+		requests = []url.Values{}
+		status = http.StatusOK
+		expiresIn = 3600
+		now = time.Now()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			req.ParseForm()
+			requests = append(requests, req.PostForm)
+
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, len(requests), expiresIn)
+		}))
+
+		config = mail.OAuthConfig{
+			TokenURL:     server.URL,
+			ClientID:     "some-client",
+			ClientSecret: "some-secret",
+			RefreshToken: "some-refresh-token",
+			Scope:        "https://mail.google.com/",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newSource := func() *mail.OAuthTokenSource {
+		source := mail.NewOAuthTokenSource(config)
+		source.SetNow(func() time.Time {
+			return now
+		})
+		return source
+	}
+
+	It("exchanges the refresh token for an access token", func() {
+		token, err := newSource().Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-1"))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Get("grant_type")).To(Equal("refresh_token"))
+		Expect(requests[0].Get("refresh_token")).To(Equal("some-refresh-token"))
+		Expect(requests[0].Get("client_id")).To(Equal("some-client"))
+		Expect(requests[0].Get("client_secret")).To(Equal("some-secret"))
+		Expect(requests[0].Get("scope")).To(Equal("https://mail.google.com/"))
+	})
+
+	It("uses the client credentials grant when there is no refresh token", func() {
+		config.RefreshToken = ""
+
+		_, err := newSource().Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(requests[0].Get("grant_type")).To(Equal("client_credentials"))
+		Expect(requests[0]).NotTo(HaveKey("refresh_token"))
+	})
+
+	It("reuses the access token until shortly before it expires", func() {
+		source := newSource()
+
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-1"))
+
+		now = now.Add(time.Hour - mail.TokenExpiryMargin - time.Second)
+		token, err = source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-1"))
+
+		now = now.Add(time.Second)
+		token, err = source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-2"))
+		Expect(requests).To(HaveLen(2))
+	})
+
+	It("caches the access token for the default lifetime when the endpoint does not say when it expires", func() {
+		expiresIn = 0
+		source := newSource()
+
+		_, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(mail.DefaultTokenLifetime - mail.TokenExpiryMargin - time.Second)
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-1"))
+
+		now = now.Add(time.Second)
+		token, err = source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-2"))
+	})
+
+	It("replaces short-lived access tokens a quarter of their lifetime early", func() {
+		expiresIn = 60
+		source := newSource()
+
+		_, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(45*time.Second - time.Millisecond)
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-1"))
+
+		now = now.Add(time.Millisecond)
+		token, err = source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-2"))
+	})
+
+	It("fetches a new access token once the current one is invalidated", func() {
+		source := newSource()
+
+		_, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		source.Invalidate()
+
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-2"))
+	})
+
+	It("returns an error when the endpoint refuses the request", func() {
+		status = http.StatusBadRequest
+
+		_, err := newSource().Token()
+		Expect(err).To(Equal(mail.OAuthTokenError{
+			StatusCode: http.StatusBadRequest,
+			Body:       `{"error":"invalid_grant"}`,
+		}))
+	})
+})
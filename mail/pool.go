@@ -0,0 +1,312 @@
+package mail
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type PoolConfig struct {
+	Size                     int
+	MaxIdleTime              time.Duration
+	MaxMessagesPerConnection int
+}
+
+// SessionError reports that no session could be opened with the server, as
+// opposed to the server refusing a message over an open session.
+type SessionError struct {
+	Err error
+}
+
+func (err SessionError) Error() string {
+	return err.Err.Error()
+}
+
+// ErrPoolClosed is returned for a session requested after the pool has been
+// closed.
+var ErrPoolClosed = errors.New("the SMTP session pool is closed")
+
+type session struct {
+	client    *Client
+	delivered int
+	idleSince time.Time
+}
+
+// Pool shares authenticated SMTP sessions between the delivery workers. It
+// holds at most Size sessions open at once. Idle sessions are checked with a
+// NOOP and reset with RSET before they carry another message, and are closed
+// once they have been idle for longer than MaxIdleTime or have delivered
+// MaxMessagesPerConnection messages. Once the pool is closed, sessions are
+// closed as they are checked in and no new ones are opened.
+type Pool struct {
+	config     Config
+	poolConfig PoolConfig
+	logger     *log.Logger
+	idle       chan *session
+	slots      chan struct{}
+	closed     chan struct{}
+	mutex      sync.Mutex
+	now        func() time.Time
+}
+
+func NewPool(config Config, poolConfig PoolConfig, logger *log.Logger) (*Pool, error) {
+	if poolConfig.Size < 1 {
+		poolConfig.Size = 1
+	}
+
+	if poolConfig.MaxIdleTime == 0 {
+		poolConfig.MaxIdleTime = 30 * time.Second
+	}
+
+	if poolConfig.MaxMessagesPerConnection == 0 {
+		poolConfig.MaxMessagesPerConnection = 100
+	}
+
+	_, err := NewClient(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pool{
+		config:     config,
+		poolConfig: poolConfig,
+		logger:     logger,
+		idle:       make(chan *session, poolConfig.Size),
+		slots:      make(chan struct{}, poolConfig.Size),
+		closed:     make(chan struct{}),
+		now:        time.Now,
+	}, nil
+}
+
+// Connect makes sure that a session will be available for the next Send,
+// opening one when none is idle and the pool has room for it.
+func (p *Pool) Connect() error {
+	_, err := p.connect()
+	return err
+}
+
+// connect is Connect, also reporting whether a new session was opened with
+// the server rather than an idle one being left for the next Send.
+func (p *Pool) connect() (bool, error) {
+	if p.config.TestMode {
+		return false, nil
+	}
+
+	if p.isClosed() {
+		return false, ErrPoolClosed
+	}
+
+	if len(p.idle) > 0 {
+		return false, nil
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		return false, nil
+	}
+
+	s, err := p.open()
+	if err != nil {
+		return false, err
+	}
+
+	if !p.park(s) {
+		p.discard(s)
+		return true, ErrPoolClosed
+	}
+
+	return true, nil
+}
+
+func (p *Pool) Send(msg Message) error {
+	_, err := p.send(msg)
+	return err
+}
+
+// send is Send, also reporting whether the message went over a session that
+// was opened for it rather than an idle one.
+func (p *Pool) send(msg Message) (bool, error) {
+	if p.config.TestMode {
+		p.logger.Println("TEST_MODE is enabled, emails not being sent")
+		return false, nil
+	}
+
+	s, opened, err := p.checkout()
+	if err != nil {
+		p.logger.Printf("SMTP Error: %s", err.Error())
+		return false, SessionError{Err: err}
+	}
+
+	err = s.client.Deliver(msg)
+	if refusedErr, ok := err.(RecipientsRefusedError); ok {
+		p.logger.Printf("SMTP Error: %s", refusedErr.Error())
+		s.delivered++
+		p.checkin(s)
+		return opened, err
+	}
+
+	if err != nil {
+		p.logger.Printf("SMTP Error: %s", err.Error())
+		p.resetAndCheckin(s)
+		return opened, err
+	}
+
+	s.delivered++
+	p.checkin(s)
+
+	return opened, nil
+}
+
+// Close ends every idle session. Sessions that are in use are closed when
+// they are checked back in.
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	if !p.isClosed() {
+		close(p.closed)
+	}
+	p.mutex.Unlock()
+
+	for {
+		select {
+		case s := <-p.idle:
+			p.discard(s)
+		default:
+			return
+		}
+	}
+}
+
+func (p *Pool) isClosed() bool {
+	select {
+	case <-p.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkout also reports whether the session was opened for this checkout.
+func (p *Pool) checkout() (*session, bool, error) {
+	for {
+		if p.isClosed() {
+			return nil, false, ErrPoolClosed
+		}
+
+		select {
+		case s := <-p.idle:
+			if p.usable(s) {
+				return s, false, nil
+			}
+			continue
+		default:
+		}
+
+		select {
+		case s := <-p.idle:
+			if p.usable(s) {
+				return s, false, nil
+			}
+		case p.slots <- struct{}{}:
+			if p.isClosed() {
+				<-p.slots
+				return nil, false, ErrPoolClosed
+			}
+			s, err := p.open()
+			return s, err == nil, err
+		case <-p.closed:
+			return nil, false, ErrPoolClosed
+		}
+	}
+}
+
+// usable checks an idle session before it is reused, discarding it when it
+// has expired or no longer responds. A session that has carried a message is
+// reset with RSET, which doubles as its health check.
+func (p *Pool) usable(s *session) bool {
+	if p.now().Sub(s.idleSince) > p.poolConfig.MaxIdleTime {
+		s.client.PrintLog("Session idle for longer than %s, closing it.", p.poolConfig.MaxIdleTime)
+		p.discard(s)
+		return false
+	}
+
+	check := s.client.Noop
+	if s.delivered > 0 {
+		check = s.client.Reset
+	}
+
+	err := check()
+	if err != nil {
+		s.client.PrintLog("Session failed health check, closing it: %s", err.Error())
+		p.discard(s)
+		return false
+	}
+
+	return true
+}
+
+func (p *Pool) checkin(s *session) {
+	if s.delivered >= p.poolConfig.MaxMessagesPerConnection {
+		s.client.PrintLog("Session delivered %d messages, closing it.", s.delivered)
+		p.discard(s)
+		return
+	}
+
+	if !p.park(s) {
+		s.client.PrintLog("Pool is closed, closing the session.")
+		p.discard(s)
+	}
+}
+
+// resetAndCheckin checks a session back in after the server refused a message
+// over it, such as a rejected recipient. The transaction is reset with RSET
+// first, and the session is closed when that fails too.
+func (p *Pool) resetAndCheckin(s *session) {
+	err := s.client.Reset()
+	if err != nil {
+		s.client.PrintLog("Session failed to reset, closing it: %s", err.Error())
+		p.discard(s)
+		return
+	}
+
+	p.checkin(s)
+}
+
+// park makes a session idle, unless the pool has been closed. The idle channel
+// has room for every session the pool can hold, so this never blocks.
+func (p *Pool) park(s *session) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.isClosed() {
+		return false
+	}
+
+	s.idleSince = p.now()
+	p.idle <- s
+	return true
+}
+
+// open dials a new session. The caller must already hold a slot for it.
+func (p *Pool) open() (*session, error) {
+	client, err := NewClient(p.config, p.logger)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	err = client.Open()
+	if err != nil {
+		client.Close()
+		<-p.slots
+		return nil, err
+	}
+
+	return &session{client: client}, nil
+}
+
+func (p *Pool) discard(s *session) {
+	s.client.Close()
+	<-p.slots
+}
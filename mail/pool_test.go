@@ -0,0 +1,227 @@
+package mail_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"bitbucket.org/chrj/smtpd"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool", func() {
+	var server *SMTPDServer
+	var config mail.Config
+	var poolConfig mail.PoolConfig
+	var buffer *bytes.Buffer
+	var logger *log.Logger
+
+	message := func(i int) mail.Message {
+		return mail.Message{
+			From:    "no-reply@example.com",
+			To:      fmt.Sprintf("user-%d@example.com", i),
+			Subject: "Hello",
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "Hello"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		server = NewSMTPDServer()
+		config = mail.Config{TLSMode: mail.TLSModeNone}
+		poolConfig = mail.PoolConfig{
+			Size:                     2,
+			MaxIdleTime:              time.Minute,
+			MaxMessagesPerConnection: 100,
+		}
+		buffer = bytes.NewBuffer([]byte{})
+		logger = log.New(buffer, "", 0)
+	})
+
+	JustBeforeEach(func() {
+		config.Host, config.Port = server.Start()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reuses one connection for consecutive messages", func() {
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+		defer pool.Close()
+
+		for i := 0; i < 5; i++ {
+			Expect(pool.Connect()).NotTo(HaveOccurred())
+			Expect(pool.Send(message(i))).NotTo(HaveOccurred())
+		}
+
+		Expect(server.Connections()).To(Equal(1))
+
+		deliveries := server.Deliveries()
+		Expect(deliveries).To(HaveLen(5))
+		for i, delivery := range deliveries {
+			Expect(delivery.Recipients).To(Equal([]string{fmt.Sprintf("user-%d@example.com", i)}))
+		}
+	})
+
+	It("opens no more connections than its size when used concurrently", func() {
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+		defer pool.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				Expect(pool.Send(message(i))).NotTo(HaveOccurred())
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(server.Deliveries()).To(HaveLen(20))
+		Expect(server.Connections()).To(BeNumerically("<=", 2))
+	})
+
+	It("closes a connection once it has delivered the maximum number of messages", func() {
+		poolConfig.MaxMessagesPerConnection = 2
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+		defer pool.Close()
+
+		for i := 0; i < 5; i++ {
+			Expect(pool.Send(message(i))).NotTo(HaveOccurred())
+		}
+
+		Expect(server.Deliveries()).To(HaveLen(5))
+		Expect(server.Connections()).To(Equal(3))
+	})
+
+	It("replaces a connection that has been idle for too long", func() {
+		poolConfig.MaxIdleTime = 50 * time.Millisecond
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+		defer pool.Close()
+
+		Expect(pool.Send(message(0))).NotTo(HaveOccurred())
+		time.Sleep(100 * time.Millisecond)
+		Expect(pool.Send(message(1))).NotTo(HaveOccurred())
+
+		Expect(server.Deliveries()).To(HaveLen(2))
+		Expect(server.Connections()).To(Equal(2))
+	})
+
+	Context("when the server drops an idle connection", func() {
+		BeforeEach(func() {
+			server.Server.ReadTimeout = 50 * time.Millisecond
+		})
+
+		It("notices during the health check and delivers over a new connection", func() {
+			pool, err := mail.NewPool(config, poolConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			defer pool.Close()
+
+			Expect(pool.Send(message(0))).NotTo(HaveOccurred())
+			time.Sleep(150 * time.Millisecond)
+			Expect(pool.Send(message(1))).NotTo(HaveOccurred())
+
+			Expect(server.Deliveries()).To(HaveLen(2))
+			Expect(server.Connections()).To(Equal(2))
+		})
+	})
+
+	It("resets the connection and reuses it when the server rejects a message", func() {
+		server.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+			if addr == "user-1@example.com" {
+				return fmt.Errorf("no such user")
+			}
+			return nil
+		}
+
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+		defer pool.Close()
+
+		Expect(pool.Send(message(0))).NotTo(HaveOccurred())
+		Expect(pool.Send(message(1))).To(HaveOccurred())
+		Expect(pool.Send(message(2))).NotTo(HaveOccurred())
+
+		Expect(server.Deliveries()).To(HaveLen(2))
+		Expect(server.Connections()).To(Equal(1))
+		Expect(buffer.String()).To(ContainSubstring("SMTP Error"))
+	})
+
+	Context("when the pool is closed", func() {
+		It("opens no more sessions", func() {
+			pool, err := mail.NewPool(config, poolConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pool.Send(message(0))).NotTo(HaveOccurred())
+			pool.Close()
+
+			Expect(pool.Connect()).To(Equal(mail.ErrPoolClosed))
+			Expect(pool.Send(message(1))).To(Equal(mail.SessionError{Err: mail.ErrPoolClosed}))
+
+			Expect(server.Deliveries()).To(HaveLen(1))
+			Expect(server.Connections()).To(Equal(1))
+		})
+
+		It("closes the sessions in use when they are checked in", func() {
+			config.LoggingEnabled = true
+			delivering := make(chan bool)
+			server.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+				close(delivering)
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}
+
+			pool, err := mail.NewPool(config, poolConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			sent := make(chan error)
+			go func() {
+				sent <- pool.Send(message(0))
+			}()
+
+			<-delivering
+			pool.Close()
+
+			Expect(<-sent).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(ContainSubstring("Pool is closed, closing the session."))
+		})
+	})
+
+	It("returns an error when it cannot connect", func() {
+		config.Port = "1"
+		pool, err := mail.NewPool(config, poolConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pool.Connect()).To(HaveOccurred())
+
+		err = pool.Send(message(0))
+		Expect(err).To(BeAssignableToTypeOf(mail.SessionError{}))
+	})
+
+	Context("when in test mode", func() {
+		It("does not connect to the server", func() {
+			config.TestMode = true
+			pool, err := mail.NewPool(config, poolConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pool.Connect()).NotTo(HaveOccurred())
+			Expect(pool.Send(message(0))).NotTo(HaveOccurred())
+
+			Expect(server.Connections()).To(Equal(0))
+			Expect(buffer.String()).To(ContainSubstring("TEST_MODE is enabled, emails not being sent"))
+		})
+	})
+})
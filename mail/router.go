@@ -0,0 +1,192 @@
+package mail
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"net"
+	"net/textproto"
+	"sort"
+	"time"
+)
+
+var NoRelayAvailableError = errors.New("no SMTP relay is available")
+
+// Relay is one SMTP server that mail can be sent through. Relays with a lower
+// Priority are tried first; relays that share a priority split the traffic in
+// proportion to their Weight.
+type Relay struct {
+	Name     string
+	Config   Config
+	Priority int
+	Weight   int
+}
+
+type RouterConfig struct {
+	Pool             PoolConfig
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+type RouterInterface interface {
+	Connect() error
+	Send(Message) (string, error)
+}
+
+type route struct {
+	relay   Relay
+	pool    *Pool
+	breaker *CircuitBreaker
+}
+
+// Router sends each message through the first healthy relay, failing over to
+// the next one when a relay cannot be reached, drops the connection or is
+// shutting down (421). Every relay has its own pool of sessions and its own
+// circuit breaker, which only counts a relay as healthy again once a new
+// session has been opened with it.
+type Router struct {
+	routes []route
+	logger *log.Logger
+}
+
+func NewRouter(relays []Relay, config RouterConfig, logger *log.Logger) (*Router, error) {
+	if len(relays) == 0 {
+		return nil, errors.New("at least one SMTP relay must be configured")
+	}
+
+	router := &Router{logger: logger}
+	for _, relay := range relays {
+		if relay.Name == "" {
+			relay.Name = net.JoinHostPort(relay.Config.Host, relay.Config.Port)
+		}
+
+		if relay.Weight < 1 {
+			relay.Weight = 1
+		}
+
+		pool, err := NewPool(relay.Config, config.Pool, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		router.routes = append(router.routes, route{
+			relay:   relay,
+			pool:    pool,
+			breaker: NewCircuitBreaker(config.FailureThreshold, config.Cooldown),
+		})
+	}
+
+	return router, nil
+}
+
+func (router *Router) Connect() error {
+	var opened bool
+	err := NoRelayAvailableError
+	for _, route := range router.order() {
+		if !route.breaker.Allow() {
+			continue
+		}
+
+		opened, err = route.pool.connect()
+		if err == nil {
+			route.recordHealth(opened)
+			return nil
+		}
+
+		route.breaker.Failure()
+		router.logger.Printf("Could not connect to SMTP relay %s: %s", route.relay.Name, err.Error())
+	}
+
+	return err
+}
+
+// Send returns the name of the relay that accepted the message or, when the
+// relay replied with an error, the relay that replied. Any reply other than
+// 421 concerns the message rather than the relay, so it is returned to the
+// caller instead of being sent again elsewhere: a temporary (4xx) reply is
+// retried later with the rest of the failed deliveries.
+func (router *Router) Send(msg Message) (string, error) {
+	var opened bool
+	err := NoRelayAvailableError
+	for _, route := range router.order() {
+		if !route.breaker.Allow() {
+			continue
+		}
+
+		opened, err = route.pool.send(msg)
+		if !relayFailed(err) {
+			route.recordHealth(opened)
+			return route.relay.Name, err
+		}
+
+		route.breaker.Failure()
+		router.logger.Printf("SMTP relay %s failed to send the message: %s", route.relay.Name, err.Error())
+	}
+
+	return "", err
+}
+
+// recordHealth closes the breaker when a new session was opened with the
+// relay. Reusing an idle session says nothing about whether the relay would
+// accept a new connection, so it only releases a trial.
+func (route route) recordHealth(opened bool) {
+	if opened {
+		route.breaker.Success()
+		return
+	}
+
+	route.breaker.Release()
+}
+
+// order lists the relays by priority, shuffling relays of equal priority by
+// weight.
+func (router *Router) order() []route {
+	routes := make([]route, 0, len(router.routes))
+	remaining := append([]route{}, router.routes...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].relay.Priority < remaining[j].relay.Priority
+	})
+
+	for len(remaining) > 0 {
+		end := 1
+		for end < len(remaining) && remaining[end].relay.Priority == remaining[0].relay.Priority {
+			end++
+		}
+
+		group := remaining[:end]
+		for len(group) > 0 {
+			total := 0
+			for _, route := range group {
+				total += route.relay.Weight
+			}
+
+			pick := rand.Intn(total)
+			for i, route := range group {
+				pick -= route.relay.Weight
+				if pick < 0 {
+					routes = append(routes, route)
+					group = append(group[:i:i], group[i+1:]...)
+					break
+				}
+			}
+		}
+
+		remaining = remaining[end:]
+	}
+
+	return routes
+}
+
+// relayFailed reports whether the relay could not be reached, broke the
+// connection or is shutting down (421). Other replies concern the message, and
+// any other relay would give them as well.
+func relayFailed(err error) bool {
+	switch err := err.(type) {
+	case nil, RecipientsRefusedError:
+		return false
+	case *textproto.Error:
+		return err.Code == 421
+	default:
+		return true
+	}
+}
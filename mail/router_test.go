@@ -0,0 +1,238 @@
+package mail_test
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"time"
+
+	"bitbucket.org/chrj/smtpd"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Router", func() {
+	var primary, backup *SMTPDServer
+	var primaryRelay, backupRelay mail.Relay
+	var routerConfig mail.RouterConfig
+	var buffer *bytes.Buffer
+	var logger *log.Logger
+	var msg mail.Message
+
+	BeforeEach(func() {
+		primary = NewSMTPDServer()
+		backup = NewSMTPDServer()
+
+		primaryRelay = mail.Relay{
+			Name:     "primary",
+			Config:   mail.Config{TLSMode: mail.TLSModeNone},
+			Priority: 0,
+			Weight:   1,
+		}
+		backupRelay = mail.Relay{
+			Name:     "backup",
+			Config:   mail.Config{TLSMode: mail.TLSModeNone},
+			Priority: 1,
+			Weight:   1,
+		}
+
+		routerConfig = mail.RouterConfig{
+			Pool:             mail.PoolConfig{Size: 1},
+			FailureThreshold: 2,
+			Cooldown:         time.Hour,
+		}
+
+		buffer = bytes.NewBuffer([]byte{})
+		logger = log.New(buffer, "", 0)
+
+		msg = mail.Message{
+			From:    "no-reply@example.com",
+			To:      "user@example.com",
+			Subject: "Hello",
+			Body:    []mail.Part{{ContentType: "text/plain", Content: "Hello"}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		primaryRelay.Config.Host, primaryRelay.Config.Port = primary.Start()
+		backupRelay.Config.Host, backupRelay.Config.Port = backup.Start()
+	})
+
+	AfterEach(func() {
+		primary.Close()
+		backup.Close()
+	})
+
+	It("requires at least one relay", func() {
+		_, err := mail.NewRouter([]mail.Relay{}, routerConfig, logger)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("names relays after their address by default", func() {
+		primaryRelay.Name = ""
+		router, err := mail.NewRouter([]mail.Relay{primaryRelay}, routerConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		relay, err := router.Send(msg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(relay).To(Equal(primaryRelay.Config.Host + ":" + primaryRelay.Config.Port))
+	})
+
+	It("sends through the relay with the lowest priority number", func() {
+		router, err := mail.NewRouter([]mail.Relay{backupRelay, primaryRelay}, routerConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(router.Connect()).NotTo(HaveOccurred())
+		relay, err := router.Send(msg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(relay).To(Equal("primary"))
+		Expect(primary.Deliveries()).To(HaveLen(1))
+		Expect(backup.Deliveries()).To(BeEmpty())
+	})
+
+	It("splits traffic between relays of equal priority by weight", func() {
+		backupRelay.Priority = 0
+		backupRelay.Weight = 3
+		routerConfig.Pool.Size = 4
+		router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 200; i++ {
+			_, err := router.Send(msg)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(primary.Deliveries()).NotTo(BeEmpty())
+		Expect(len(backup.Deliveries())).To(BeNumerically(">", len(primary.Deliveries())))
+	})
+
+	Context("when a relay cannot be reached", func() {
+		BeforeEach(func() {
+			primary.Close()
+		})
+
+		It("fails over to the next relay", func() {
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(router.Connect()).NotTo(HaveOccurred())
+			relay, err := router.Send(msg)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(relay).To(Equal("backup"))
+			Expect(backup.Deliveries()).To(HaveLen(1))
+			Expect(buffer.String()).To(ContainSubstring("SMTP relay primary"))
+		})
+
+		It("returns the error when no relay can be reached", func() {
+			backup.Close()
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(router.Connect()).To(HaveOccurred())
+
+			relay, err := router.Send(msg)
+			Expect(err).To(HaveOccurred())
+			Expect(relay).To(BeEmpty())
+		})
+	})
+
+	Context("when a relay is shutting down", func() {
+		var mutex sync.Mutex
+		var attempts int
+
+		BeforeEach(func() {
+			attempts = 0
+			primary.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				attempts++
+				return smtpd.Error{Code: 421, Message: "Service not available"}
+			}
+		})
+
+		It("fails over, and stops trying the relay once its circuit breaker opens", func() {
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 5; i++ {
+				relay, err := router.Send(msg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(relay).To(Equal("backup"))
+			}
+
+			Expect(backup.Deliveries()).To(HaveLen(5))
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			Expect(attempts).To(Equal(2))
+		})
+	})
+
+	Context("when a relay temporarily refuses a message", func() {
+		BeforeEach(func() {
+			primary.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+				return smtpd.Error{Code: 451, Message: "Greylisted, try again later"}
+			}
+		})
+
+		It("returns the refusal to be retried, without trying another relay or opening the circuit breaker", func() {
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				relay, err := router.Send(msg)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Greylisted"))
+				Expect(relay).To(Equal("primary"))
+			}
+
+			Expect(backup.Deliveries()).To(BeEmpty())
+		})
+	})
+
+	Context("when a relay permanently rejects a message", func() {
+		BeforeEach(func() {
+			primary.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+				return smtpd.Error{Code: 550, Message: "No such user"}
+			}
+		})
+
+		It("returns the rejection without trying another relay", func() {
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			relay, err := router.Send(msg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("No such user"))
+			Expect(relay).To(Equal("primary"))
+			Expect(backup.Deliveries()).To(BeEmpty())
+		})
+	})
+	Context("when a relay refuses some of the recipients", func() {
+		BeforeEach(func() {
+			primary.Server.RecipientChecker = func(peer smtpd.Peer, addr string) error {
+				if addr == "unknown@example.com" {
+					return smtpd.Error{Code: 550, Message: "No such user"}
+				}
+				return nil
+			}
+			msg.CC = []string{"unknown@example.com"}
+		})
+
+		It("returns the refused recipients without sending the message through another relay", func() {
+			router, err := mail.NewRouter([]mail.Relay{primaryRelay, backupRelay}, routerConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			relay, err := router.Send(msg)
+			Expect(err).To(BeAssignableToTypeOf(mail.RecipientsRefusedError{}))
+			Expect(relay).To(Equal("primary"))
+			Expect(primary.Deliveries()).To(HaveLen(1))
+			Expect(backup.Deliveries()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	CampaignStatusExpanding = "expanding"
+	CampaignStatusComplete  = "complete"
+)
+
+// Campaign tracks the expansion of a notification sent to a space,
+// organization, UAA scope or everyone. Recipients are enqueued in order of
+// their GUIDs, and LastRecipientGUID is the last one that was enqueued, from
+// which a retried expansion resumes. Version guards against two workers
+// recording progress on the same campaign, so that the deliveries enqueued by
+// a worker that has fallen behind are rolled back.
+type Campaign struct {
+	ID                string    `db:"id"`
+	ClientID          string    `db:"client_id"`
+	Status            string    `db:"status"`
+	RecipientCount    int       `db:"recipient_count"`
+	LastRecipientGUID string    `db:"last_recipient_guid"`
+	Version           int64     `db:"version"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type CampaignsRepoInterface interface {
+	Create(ConnectionInterface, Campaign) (Campaign, error)
+	FindByID(ConnectionInterface, string) (Campaign, error)
+	Update(ConnectionInterface, Campaign) (Campaign, error)
+}
+
+type CampaignsRepo struct{}
+
+func NewCampaignsRepo() CampaignsRepo {
+	return CampaignsRepo{}
+}
+
+func (repo CampaignsRepo) Create(conn ConnectionInterface, campaign Campaign) (Campaign, error) {
+	campaign.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	campaign.UpdatedAt = campaign.CreatedAt
+	err := conn.Insert(&campaign)
+	if err != nil {
+		return Campaign{}, err
+	}
+
+	return campaign, nil
+}
+
+func (repo CampaignsRepo) FindByID(conn ConnectionInterface, campaignID string) (Campaign, error) {
+	campaign := Campaign{}
+	err := conn.SelectOne(&campaign, "SELECT * FROM `campaigns` WHERE `id` = ?", campaignID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Campaign{}, NewRecordNotFoundError("Campaign with ID %q could not be found", campaignID)
+		}
+		return Campaign{}, err
+	}
+
+	return campaign, nil
+}
+
+func (repo CampaignsRepo) Update(conn ConnectionInterface, campaign Campaign) (Campaign, error) {
+	campaign.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	_, err := conn.Update(&campaign)
+	if err != nil {
+		return campaign, err
+	}
+
+	return repo.FindByID(conn, campaign.ID)
+}
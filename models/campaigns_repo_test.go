@@ -0,0 +1,107 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/coopernurse/gorp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CampaignsRepo", func() {
+	var repo models.CampaignsRepo
+	var conn models.ConnectionInterface
+
+	BeforeEach(func() {
+		TruncateTables()
+		repo = models.NewCampaignsRepo()
+		env := application.NewEnvironment()
+		conn = models.NewDatabase(models.Config{
+			DatabaseURL:    env.DatabaseURL,
+			MigrationsPath: env.ModelMigrationsDir,
+		}).Connection()
+	})
+
+	Describe("Create", func() {
+		It("stores the campaign in the database", func() {
+			campaign, err := repo.Create(conn, models.Campaign{
+				ID:       "campaign-id",
+				ClientID: "client-id",
+				Status:   models.CampaignStatusExpanding,
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(campaign.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+			Expect(campaign.UpdatedAt).To(Equal(campaign.CreatedAt))
+
+			campaignFound, err := repo.FindByID(conn, "campaign-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(campaignFound).To(Equal(campaign))
+		})
+	})
+
+	Describe("FindByID", func() {
+		It("returns a RecordNotFoundError when the campaign does not exist", func() {
+			_, err := repo.FindByID(conn, "missing-id")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+
+	Describe("Update", func() {
+		It("records the progress of the campaign", func() {
+			campaign, err := repo.Create(conn, models.Campaign{
+				ID:       "campaign-id",
+				ClientID: "client-id",
+				Status:   models.CampaignStatusExpanding,
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			campaign.Status = models.CampaignStatusComplete
+			campaign.RecipientCount = 1000
+			campaign.LastRecipientGUID = "user-999"
+
+			campaign, err = repo.Update(conn, campaign)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(campaign.Status).To(Equal(models.CampaignStatusComplete))
+			Expect(campaign.RecipientCount).To(Equal(1000))
+			Expect(campaign.LastRecipientGUID).To(Equal("user-999"))
+		})
+
+		It("refuses to record progress over a campaign that has changed since it was read", func() {
+			campaign, err := repo.Create(conn, models.Campaign{
+				ID:       "campaign-id",
+				ClientID: "client-id",
+				Status:   models.CampaignStatusExpanding,
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			stale := campaign
+
+			campaign.LastRecipientGUID = "user-499"
+			_, err = repo.Update(conn, campaign)
+			if err != nil {
+				panic(err)
+			}
+
+			stale.LastRecipientGUID = "user-999"
+			_, err = repo.Update(conn, stale)
+			Expect(err).To(BeAssignableToTypeOf(gorp.OptimisticLockError{}))
+
+			campaign, err = repo.FindByID(conn, "campaign-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(campaign.LastRecipientGUID).To(Equal("user-499"))
+		})
+	})
+})
@@ -84,10 +84,16 @@ func (database DB) migrate(migrationsPath string) {
 	database.connection.AddTableWithName(GlobalUnsubscribe{}, "global_unsubscribes").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
 	database.connection.AddTableWithName(Template{}, "templates").SetKeys(true, "Primary").ColMap("Name").SetUnique(true)
 	database.connection.AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
+	database.connection.AddTableWithName(Campaign{}, "campaigns").SetKeys(false, "ID").SetVersionCol("Version")
+	database.connection.AddTableWithName(MessageEvent{}, "message_events").SetKeys(true, "ID")
+	database.connection.AddTableWithName(Suppression{}, "suppressions").SetKeys(true, "ID").ColMap("Email").SetUnique(true)
+	database.connection.AddTableWithName(DeliveryAddress{}, "delivery_addresses").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
+	database.connection.AddTableWithName(TemplateVersion{}, "template_versions").SetKeys(true, "Primary").SetUniqueTogether("template_id", "version")
 }
 
 func (database DB) Seed() {
 	repo := NewTemplatesRepo()
+	versionsRepo := NewTemplateVersionsRepo()
 	bytes, err := ioutil.ReadFile(database.config.DefaultTemplatePath)
 	if err != nil {
 		panic(err)
@@ -113,7 +119,7 @@ func (database DB) Seed() {
 			panic(err)
 		}
 
-		_, err = repo.create(conn, Template{
+		defaultTemplate, err := repo.create(conn, Template{
 			ID:       DefaultTemplateID,
 			Name:     template.Name,
 			Subject:  template.Subject,
@@ -125,10 +131,21 @@ func (database DB) Seed() {
 			panic(err)
 		}
 
+		_, err = versionsRepo.Create(conn, NewTemplateVersion(defaultTemplate, ""))
+		if err != nil {
+			panic(err)
+		}
+
 		return
 	}
 
 	if !existingTemplate.Overridden {
+		unchanged := existingTemplate.Name == template.Name &&
+			existingTemplate.Subject == template.Subject &&
+			existingTemplate.HTML == template.HTML &&
+			existingTemplate.Text == template.Text &&
+			existingTemplate.Metadata == string(template.Metadata)
+
 		existingTemplate.Name = template.Name
 		existingTemplate.Subject = template.Subject
 		existingTemplate.HTML = template.HTML
@@ -139,6 +156,13 @@ func (database DB) Seed() {
 		if err != nil {
 			panic(err)
 		}
+
+		if !unchanged {
+			_, err = versionsRepo.Create(conn, NewTemplateVersion(existingTemplate, ""))
+			if err != nil {
+				panic(err)
+			}
+		}
 	}
 }
 
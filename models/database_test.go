@@ -74,6 +74,7 @@ var _ = Describe("Database", func() {
 			Expect(tables).To(ContainElement("unsubscribes"))
 			Expect(tables).To(ContainElement("global_unsubscribes"))
 			Expect(tables).To(ContainElement("templates"))
+			Expect(tables).To(ContainElement("template_versions"))
 		})
 	})
 
@@ -93,11 +94,23 @@ var _ = Describe("Database", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(template.Name).To(Equal("Default Template"))
 			Expect(template.Subject).To(Equal("CF Notification: {{.Subject}}"))
-			Expect(template.HTML).To(Equal("<p>{{.Endorsement}}</p>{{.HTML}}"))
-			Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}"))
+			Expect(template.HTML).To(Equal(`<p>{{.Endorsement}}</p>{{.HTML}}{{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>{{end}}`))
+			Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}{{if .UnsubscribeURL}}\n\nUnsubscribe: {{.UnsubscribeURL}}{{end}}"))
 			Expect(template.Metadata).To(Equal("{}"))
 		})
 
+		It("records the seeded default template as its first version", func() {
+			db.Seed()
+			db.Seed()
+
+			versions, err := models.NewTemplateVersionsRepo().FindAllByTemplateID(connection, models.DefaultTemplateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Version).To(Equal(1))
+			Expect(versions[0].Name).To(Equal("Default Template"))
+			Expect(versions[0].Author).To(BeEmpty())
+		})
+
 		It("can be called multiple times without panicking", func() {
 			Expect(func() {
 				db.Seed()
@@ -128,8 +141,8 @@ var _ = Describe("Database", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(template.Name).To(Equal("Default Template"))
 				Expect(template.Subject).To(Equal("CF Notification: {{.Subject}}"))
-				Expect(template.HTML).To(Equal("<p>{{.Endorsement}}</p>{{.HTML}}"))
-				Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}"))
+				Expect(template.HTML).To(Equal(`<p>{{.Endorsement}}</p>{{.HTML}}{{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>{{end}}`))
+				Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}{{if .UnsubscribeURL}}\n\nUnsubscribe: {{.UnsubscribeURL}}{{end}}"))
 				Expect(template.Metadata).To(Equal("{}"))
 				Expect(template.Overridden).To(BeFalse())
 			})
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	DeliveryAddressPrimary  = "primary"
+	DeliveryAddressAll      = "all"
+	DeliveryAddressSpecific = "specific"
+)
+
+// DeliveryAddress records which of a user's email addresses notifications are
+// sent to: the first address UAA returns, all of them, or the one in Email.
+type DeliveryAddress struct {
+	Primary   int       `db:"primary"`
+	UserID    string    `db:"user_id"`
+	Mode      string    `db:"mode"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type DeliveryAddressesRepoInterface interface {
+	Set(ConnectionInterface, DeliveryAddress) error
+	Get(ConnectionInterface, string) (DeliveryAddress, error)
+}
+
+type DeliveryAddressesRepo struct{}
+
+func NewDeliveryAddressesRepo() DeliveryAddressesRepo {
+	return DeliveryAddressesRepo{}
+}
+
+func (repo DeliveryAddressesRepo) Set(conn ConnectionInterface, address DeliveryAddress) error {
+	existing, err := repo.find(conn, address.UserID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		address.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+		address.UpdatedAt = address.CreatedAt
+
+		return conn.Insert(&address)
+	}
+
+	existing.Mode = address.Mode
+	existing.Email = address.Email
+	existing.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	_, err = conn.Update(&existing)
+
+	return err
+}
+
+// Get returns the delivery address preference of the user, which is to use
+// the primary address when the user has not chosen one.
+func (repo DeliveryAddressesRepo) Get(conn ConnectionInterface, userGUID string) (DeliveryAddress, error) {
+	address, err := repo.find(conn, userGUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DeliveryAddress{UserID: userGUID, Mode: DeliveryAddressPrimary}, nil
+		}
+		return DeliveryAddress{}, err
+	}
+
+	return address, nil
+}
+
+func (repo DeliveryAddressesRepo) find(conn ConnectionInterface, userGUID string) (DeliveryAddress, error) {
+	address := DeliveryAddress{}
+	err := conn.SelectOne(&address, "SELECT * FROM `delivery_addresses` WHERE `user_id` = ?", userGUID)
+	if err != nil {
+		return DeliveryAddress{}, err
+	}
+
+	return address, nil
+}
@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeliveryAddressesRepo", func() {
+	var repo models.DeliveryAddressesRepo
+	var conn models.ConnectionInterface
+
+	BeforeEach(func() {
+		TruncateTables()
+		repo = models.NewDeliveryAddressesRepo()
+		env := application.NewEnvironment()
+		conn = models.NewDatabase(models.Config{
+			DatabaseURL:    env.DatabaseURL,
+			MigrationsPath: env.ModelMigrationsDir,
+		}).Connection()
+	})
+
+	Describe("Get", func() {
+		It("uses the primary address when the user has not chosen one", func() {
+			address, err := repo.Get(conn, "user-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(address).To(Equal(models.DeliveryAddress{
+				UserID: "user-123",
+				Mode:   models.DeliveryAddressPrimary,
+			}))
+		})
+	})
+
+	Describe("Set", func() {
+		It("stores the chosen address", func() {
+			err := repo.Set(conn, models.DeliveryAddress{
+				UserID: "user-123",
+				Mode:   models.DeliveryAddressSpecific,
+				Email:  "work@example.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			address, err := repo.Get(conn, "user-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(address.Mode).To(Equal(models.DeliveryAddressSpecific))
+			Expect(address.Email).To(Equal("work@example.com"))
+			Expect(address.CreatedAt).NotTo(BeZero())
+		})
+
+		It("replaces an earlier choice", func() {
+			err := repo.Set(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "work@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Set(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressAll})
+			Expect(err).NotTo(HaveOccurred())
+
+			address, err := repo.Get(conn, "user-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(address.Mode).To(Equal(models.DeliveryAddressAll))
+			Expect(address.Email).To(BeEmpty())
+		})
+	})
+})
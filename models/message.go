@@ -5,5 +5,10 @@ import "time"
 type Message struct {
 	ID        string    `db:"id"`
 	Status    string    `db:"status"`
+	JobID     int       `db:"job_id"`
+	BatchID   string    `db:"batch_id"`
+	ClientID  string    `db:"client_id"`
+	Recipient string    `db:"recipient"`
+	Email     string    `db:"email"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type MessageEvent struct {
+	ID        int       `db:"id"`
+	MessageID string    `db:"message_id"`
+	Status    string    `db:"status"`
+	Attempt   int       `db:"attempt"`
+	WorkerID  string    `db:"worker_id"`
+	Relay     string    `db:"relay"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
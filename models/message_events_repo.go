@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+type MessageEventsRepoInterface interface {
+	Create(ConnectionInterface, MessageEvent) (MessageEvent, error)
+	FindAllByMessageID(ConnectionInterface, string) ([]MessageEvent, error)
+	FindAllByMessageIDs(ConnectionInterface, []string) ([]MessageEvent, error)
+	DeleteByMessageIDs(ConnectionInterface, []string) (int, error)
+}
+
+type MessageEventsRepo struct{}
+
+func NewMessageEventsRepo() MessageEventsRepo {
+	return MessageEventsRepo{}
+}
+
+// Create appends an event to the history of a message. Events are never
+// updated once they are written.
+func (repo MessageEventsRepo) Create(conn ConnectionInterface, event MessageEvent) (MessageEvent, error) {
+	event.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	err := conn.Insert(&event)
+	if err != nil {
+		return MessageEvent{}, err
+	}
+
+	return event, nil
+}
+
+func (repo MessageEventsRepo) FindAllByMessageID(conn ConnectionInterface, messageID string) ([]MessageEvent, error) {
+	events := []MessageEvent{}
+	_, err := conn.Select(&events, "SELECT * FROM `message_events` WHERE `message_id`=? ORDER BY `created_at` ASC, `id` ASC", messageID)
+	if err != nil {
+		return []MessageEvent{}, err
+	}
+
+	return events, nil
+}
+
+// FindAllByMessageIDs finds the events of several messages with a single
+// query, ordered by message and then by the order they were written in.
+func (repo MessageEventsRepo) FindAllByMessageIDs(conn ConnectionInterface, messageIDs []string) ([]MessageEvent, error) {
+	events := []MessageEvent{}
+	if len(messageIDs) == 0 {
+		return events, nil
+	}
+
+	placeholders, args := inList(messageIDs)
+	_, err := conn.Select(&events, "SELECT * FROM `message_events` WHERE `message_id` IN ("+placeholders+") ORDER BY `message_id` ASC, `created_at` ASC, `id` ASC", args...)
+	if err != nil {
+		return []MessageEvent{}, err
+	}
+
+	return events, nil
+}
+
+// DeleteByMessageIDs removes the events of the given messages. It should be
+// called in the transaction that deletes the messages.
+func (repo MessageEventsRepo) DeleteByMessageIDs(conn ConnectionInterface, messageIDs []string) (int, error) {
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders, args := inList(messageIDs)
+	result, err := conn.Exec("DELETE FROM `message_events` WHERE `message_id` IN ("+placeholders+")", args...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
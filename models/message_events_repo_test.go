@@ -0,0 +1,111 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageEventsRepo", func() {
+	var repo models.MessageEventsRepo
+	var conn models.ConnectionInterface
+
+	BeforeEach(func() {
+		TruncateTables()
+		repo = models.NewMessageEventsRepo()
+		env := application.NewEnvironment()
+		conn = models.NewDatabase(models.Config{
+			DatabaseURL:    env.DatabaseURL,
+			MigrationsPath: env.ModelMigrationsDir,
+		}).Connection()
+	})
+
+	Describe("Create", func() {
+		It("appends an event to the history of the message", func() {
+			event, err := repo.Create(conn, models.MessageEvent{
+				MessageID: "message-123",
+				Status:    postal.StatusFailed,
+				Attempt:   2,
+				WorkerID:  "worker-1",
+				Error:     "550 mailbox unavailable",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.ID).NotTo(BeZero())
+			Expect(event.CreatedAt).NotTo(BeZero())
+
+			events, err := repo.FindAllByMessageID(conn, "message-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]models.MessageEvent{event}))
+		})
+	})
+
+	Describe("FindAllByMessageID", func() {
+		It("returns the events of the message in the order they were created", func() {
+			statuses := []string{postal.StatusQueued, postal.StatusFailed, postal.StatusDelivered}
+			for i, status := range statuses {
+				_, err := repo.Create(conn, models.MessageEvent{MessageID: "message-123", Status: status, Attempt: i})
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			_, err := repo.Create(conn, models.MessageEvent{MessageID: "message-456", Status: postal.StatusQueued})
+			if err != nil {
+				panic(err)
+			}
+
+			events, err := repo.FindAllByMessageID(conn, "message-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Status).To(Equal(postal.StatusQueued))
+			Expect(events[1].Status).To(Equal(postal.StatusFailed))
+			Expect(events[2].Status).To(Equal(postal.StatusDelivered))
+		})
+	})
+
+	Describe("FindAllByMessageIDs", func() {
+		It("returns the events of all of the messages", func() {
+			for _, messageID := range []string{"message-123", "message-456", "message-789"} {
+				_, err := repo.Create(conn, models.MessageEvent{MessageID: messageID, Status: postal.StatusQueued})
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			events, err := repo.FindAllByMessageIDs(conn, []string{"message-456", "message-123"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].MessageID).To(Equal("message-123"))
+			Expect(events[1].MessageID).To(Equal("message-456"))
+		})
+
+		It("returns no events when there are no messages", func() {
+			events, err := repo.FindAllByMessageIDs(conn, []string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteByMessageIDs", func() {
+		It("deletes the events of the given messages", func() {
+			for _, messageID := range []string{"message-123", "message-456", "message-789"} {
+				_, err := repo.Create(conn, models.MessageEvent{MessageID: messageID, Status: postal.StatusQueued})
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			count, err := repo.DeleteByMessageIDs(conn, []string{"message-123", "message-789"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			events, err := repo.FindAllByMessageIDs(conn, []string{"message-123", "message-456", "message-789"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].MessageID).To(Equal("message-456"))
+		})
+	})
+})
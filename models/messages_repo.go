@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 )
 
@@ -56,14 +57,123 @@ func (repo MessagesRepo) Upsert(conn ConnectionInterface, message Message) (Mess
 	}
 }
 
-func (repo MessagesRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
-	result, err := conn.Exec("DELETE FROM `messages` WHERE `updated_at` < ?", threshold.UTC())
+func (repo MessagesRepo) FindAllByBatchID(conn ConnectionInterface, clientID, batchID string, offset, limit int) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `batch_id`=? AND `client_id`=? ORDER BY `recipient` ASC, `id` ASC LIMIT ? OFFSET ?", batchID, clientID, limit, offset)
+	if err != nil {
+		return []Message{}, err
+	}
+
+	return messages, nil
+}
+
+// FindAllByJobID finds the messages that are delivered by the same job, such
+// as a message and its CC and BCC copies.
+func (repo MessagesRepo) FindAllByJobID(conn ConnectionInterface, jobID int) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `job_id`=? ORDER BY `id` ASC", jobID)
+	if err != nil {
+		return []Message{}, err
+	}
+
+	return messages, nil
+}
+
+func (repo MessagesRepo) CountByStatusForBatchID(conn ConnectionInterface, clientID, batchID string) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	_, err := conn.Select(&rows, "SELECT `status`, COUNT(*) AS `count` FROM `messages` WHERE `batch_id`=? AND `client_id`=? GROUP BY `status`", batchID, clientID)
+	if err != nil {
+		return map[string]int{}, err
+	}
+
+	counts := map[string]int{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// MessageScope narrows a query over messages to those sent by a single client,
+// or to those sent by any client except the excluded ones. The zero value
+// matches every message.
+type MessageScope struct {
+	ClientID          string
+	ExcludedClientIDs []string
+}
+
+func (scope MessageScope) where() (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if scope.ClientID != "" {
+		clauses = append(clauses, " AND `client_id` = ?")
+		args = append(args, scope.ClientID)
+	}
+
+	if len(scope.ExcludedClientIDs) > 0 {
+		placeholders, clientIDs := inList(scope.ExcludedClientIDs)
+		clauses = append(clauses, " AND `client_id` NOT IN ("+placeholders+")")
+		args = append(args, clientIDs...)
+	}
+
+	return strings.Join(clauses, ""), args
+}
+
+// inList builds the placeholders and arguments for an IN clause over the
+// given values, which must not be empty.
+func inList(values []string) (string, []interface{}) {
+	args := []interface{}{}
+	for _, value := range values {
+		args = append(args, value)
+	}
+
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), args
+}
+
+// FindAllBefore finds up to limit messages last updated before the threshold,
+// in the order of their last update. Scheduled messages are left out, since
+// they have yet to be sent however long ago they were scheduled. Passing the
+// last message of a page as after finds the next page; the zero Message finds
+// the first one. Within a transaction, the messages found are locked until it
+// ends.
+func (repo MessagesRepo) FindAllBefore(conn ConnectionInterface, threshold time.Time, scope MessageScope, after Message, limit int) ([]Message, error) {
+	clauses, args := scope.where()
+	args = append([]interface{}{threshold.UTC()}, args...)
+
+	if after.ID != "" {
+		clauses += " AND (`updated_at` > ? OR (`updated_at` = ? AND `id` > ?))"
+		args = append(args, after.UpdatedAt.UTC(), after.UpdatedAt.UTC(), after.ID)
+	}
+
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `updated_at` < ? AND `status` != 'scheduled'"+clauses+" ORDER BY `updated_at` ASC, `id` ASC LIMIT ? FOR UPDATE", append(args, limit)...)
+	if err != nil {
+		return []Message{}, err
+	}
+
+	return messages, nil
+}
+
+func (repo MessagesRepo) DeleteByIDs(conn ConnectionInterface, messageIDs []string) (int, error) {
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders, args := inList(messageIDs)
+	result, err := conn.Exec("DELETE FROM `messages` WHERE `id` IN ("+placeholders+")", args...)
 	if err != nil {
 		return 0, err
 	}
+
 	count, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
 	}
+
 	return int(count), nil
 }
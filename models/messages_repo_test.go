@@ -26,6 +26,7 @@ var _ = Describe("MessagesRepo", func() {
 		message = models.Message{
 			ID:     "message-id-123",
 			Status: postal.StatusDelivered,
+			JobID:  42,
 		}
 
 	})
@@ -94,35 +95,176 @@ var _ = Describe("MessagesRepo", func() {
 		})
 	})
 
-	Describe("DeleteBefore", func() {
-		It("Deletes messages older than the input time", func() {
-			_, err := repo.Create(conn, message)
-			if err != nil {
-				panic(err)
+	Context("finding messages by batch", func() {
+		BeforeEach(func() {
+			messages := []models.Message{
+				{ID: "message-1", Status: postal.StatusDelivered, BatchID: "batch-123", ClientID: "client-id", Recipient: "user-c"},
+				{ID: "message-2", Status: postal.StatusQueued, BatchID: "batch-123", ClientID: "client-id", Recipient: "user-a"},
+				{ID: "message-3", Status: postal.StatusDelivered, BatchID: "batch-123", ClientID: "client-id", Recipient: "user-b"},
+				{ID: "message-4", Status: postal.StatusFailed, BatchID: "batch-456", ClientID: "client-id", Recipient: "user-a"},
+				{ID: "message-5", Status: postal.StatusQueued, BatchID: "batch-123", ClientID: "other-client-id", Recipient: "user-d"},
 			}
 
-			itemsDeleted, err := repo.DeleteBefore(conn, time.Now().Add(1*time.Hour))
-			Expect(err).ToNot(HaveOccurred())
-			Expect(itemsDeleted).To(Equal(1))
+			for _, message := range messages {
+				_, err := repo.Create(conn, message)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
 
-			_, err = repo.FindByID(conn, message.ID)
-			Expect(err).To(HaveOccurred())
-			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		Describe("FindAllByBatchID", func() {
+			It("returns a page of the messages the client sent in the batch ordered by recipient", func() {
+				messages, err := repo.FindAllByBatchID(conn, "client-id", "batch-123", 0, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(messages).To(HaveLen(2))
+				Expect(messages[0].ID).To(Equal("message-2"))
+				Expect(messages[1].ID).To(Equal("message-3"))
+
+				messages, err = repo.FindAllByBatchID(conn, "client-id", "batch-123", 2, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(messages).To(HaveLen(1))
+				Expect(messages[0].ID).To(Equal("message-1"))
+				Expect(messages[0].Recipient).To(Equal("user-c"))
+			})
 
+			It("returns an empty list when the batch has no messages", func() {
+				messages, err := repo.FindAllByBatchID(conn, "client-id", "missing-batch", 0, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(messages).To(BeEmpty())
+			})
 		})
 
-		It("Does not delete messages younger than the input time", func() {
-			_, err := repo.Create(conn, message)
-			if err != nil {
-				panic(err)
+		Describe("CountByStatusForBatchID", func() {
+			It("counts the messages the client sent in the batch by status", func() {
+				counts, err := repo.CountByStatusForBatchID(conn, "client-id", "batch-123")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(counts).To(Equal(map[string]int{
+					postal.StatusDelivered: 2,
+					postal.StatusQueued:    1,
+				}))
+			})
+		})
+	})
+
+	Describe("FindAllByJobID", func() {
+		It("returns the messages delivered by the job", func() {
+			messages := []models.Message{
+				{ID: "message-1", Status: postal.StatusScheduled, JobID: 7, Recipient: "user-a"},
+				{ID: "message-2", Status: postal.StatusScheduled, JobID: 7, Recipient: "copy@example.com"},
+				{ID: "message-3", Status: postal.StatusScheduled, JobID: 8, Recipient: "user-b"},
 			}
 
-			itemsDeleted, err := repo.DeleteBefore(conn, time.Now().Add(-1*time.Hour))
-			Expect(err).ToNot(HaveOccurred())
+			for _, message := range messages {
+				_, err := repo.Create(conn, message)
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			found, err := repo.FindAllByJobID(conn, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(HaveLen(2))
+			Expect(found[0].ID).To(Equal("message-1"))
+			Expect(found[1].ID).To(Equal("message-2"))
+		})
+	})
+
+	Describe("DeleteByIDs", func() {
+		BeforeEach(func() {
+			for _, m := range []models.Message{
+				{ID: "message-a", ClientID: "client-a"},
+				{ID: "message-b", ClientID: "client-b"},
+				{ID: "message-c", ClientID: "client-c"},
+			} {
+				_, err := repo.Create(conn, m)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+
+		It("deletes the given messages", func() {
+			itemsDeleted, err := repo.DeleteByIDs(conn, []string{"message-a", "message-c"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(itemsDeleted).To(Equal(2))
+
+			_, err = repo.FindByID(conn, "message-a")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+
+			_, err = repo.FindByID(conn, "message-b")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.FindByID(conn, "message-c")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
+		It("deletes nothing when there are no messages", func() {
+			itemsDeleted, err := repo.DeleteByIDs(conn, []string{})
+			Expect(err).NotTo(HaveOccurred())
 			Expect(itemsDeleted).To(Equal(0))
+		})
+	})
 
-			_, err = repo.FindByID(conn, message.ID)
-			Expect(err).ToNot(HaveOccurred())
+	Describe("FindAllBefore", func() {
+		BeforeEach(func() {
+			for _, m := range []models.Message{
+				{ID: "message-a", ClientID: "client-a"},
+				{ID: "message-b", ClientID: "client-b"},
+			} {
+				_, err := repo.Create(conn, m)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+
+		It("finds messages older than the input time", func() {
+			messages, err := repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{}, models.Message{}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+		})
+
+		It("does not find messages younger than the input time", func() {
+			messages, err := repo.FindAllBefore(conn, time.Now().Add(-1*time.Hour), models.MessageScope{}, models.Message{}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("does not find scheduled messages, however old they are", func() {
+			_, err := repo.Create(conn, models.Message{ID: "message-c", ClientID: "client-a", Status: "scheduled"})
+			Expect(err).NotTo(HaveOccurred())
+
+			messages, err := repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{}, models.Message{}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+			Expect([]string{messages[0].ID, messages[1].ID}).To(Equal([]string{"message-a", "message-b"}))
+		})
+
+		It("respects the scope", func() {
+			messages, err := repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{
+				ExcludedClientIDs: []string{"client-a"},
+			}, models.Message{}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-b"))
+			Expect(messages[0].ClientID).To(Equal("client-b"))
+		})
+
+		It("pages through the messages after the given one", func() {
+			messages, err := repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{}, models.Message{}, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-a"))
+
+			messages, err = repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{}, messages[0], 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-b"))
+
+			messages, err = repo.FindAllBefore(conn, time.Now().Add(1*time.Hour), models.MessageScope{}, messages[0], 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
 		})
 	})
 })
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages` ADD `batch_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD `recipient` varchar(255) NOT NULL DEFAULT '';
+CREATE INDEX `messages_batch_id` ON `messages` (`batch_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX `messages_batch_id` ON `messages`;
+ALTER TABLE `messages` DROP COLUMN `recipient`;
+ALTER TABLE `messages` DROP COLUMN `batch_id`;
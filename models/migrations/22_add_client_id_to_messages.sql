@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages` ADD `client_id` varchar(255) NOT NULL DEFAULT '';
+CREATE INDEX `messages_client_id_updated_at` ON `messages` (`client_id`, `updated_at`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX `messages_client_id_updated_at` ON `messages`;
+ALTER TABLE `messages` DROP COLUMN `client_id`;
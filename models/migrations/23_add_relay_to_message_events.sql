@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `message_events` ADD `relay` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `message_events` DROP COLUMN `relay`;
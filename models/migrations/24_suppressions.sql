@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `suppressions` (
+      `id` int(11) NOT NULL AUTO_INCREMENT,
+      `email` varchar(255) NOT NULL,
+      `reason` text,
+      `message_id` varchar(255) NOT NULL DEFAULT '',
+      `created_at` datetime NOT NULL,
+      PRIMARY KEY (`id`),
+      UNIQUE KEY `email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `suppressions`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `delivery_addresses` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `user_id` varchar(255) NOT NULL,
+      `mode` varchar(255) NOT NULL DEFAULT 'primary',
+      `email` varchar(255) NOT NULL DEFAULT '',
+      `created_at` datetime NOT NULL,
+      `updated_at` datetime NOT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `delivery_addresses`;
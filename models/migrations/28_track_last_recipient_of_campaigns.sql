@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `campaigns` ADD `last_recipient_guid` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `campaigns` DROP COLUMN `enqueued_count`;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `campaigns` ADD `enqueued_count` int(11) NOT NULL DEFAULT 0;
+ALTER TABLE `campaigns` DROP COLUMN `last_recipient_guid`;
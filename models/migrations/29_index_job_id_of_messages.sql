@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE INDEX `messages_job_id` ON `messages` (`job_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX `messages_job_id` ON `messages`;
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Suppression records an email address that mail is no longer sent to, along
+// with why and, for bounces, the message that bounced.
+type Suppression struct {
+	ID        int       `db:"id"`
+	Email     string    `db:"email"`
+	Reason    string    `db:"reason"`
+	MessageID string    `db:"message_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type SuppressionsRepoInterface interface {
+	Upsert(ConnectionInterface, Suppression) (Suppression, error)
+	Find(ConnectionInterface, string) (Suppression, error)
+	FindAll(ConnectionInterface) ([]Suppression, error)
+	Destroy(ConnectionInterface, string) error
+}
+
+type SuppressionsRepo struct{}
+
+func NewSuppressionsRepo() SuppressionsRepo {
+	return SuppressionsRepo{}
+}
+
+// Upsert suppresses an address, replacing the reason and message of an
+// existing suppression of it. Addresses are compared case-insensitively.
+func (repo SuppressionsRepo) Upsert(conn ConnectionInterface, suppression Suppression) (Suppression, error) {
+	suppression.Email = strings.ToLower(suppression.Email)
+
+	existing, err := repo.Find(conn, suppression.Email)
+	switch err.(type) {
+	case RecordNotFoundError:
+		suppression.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+		err = conn.Insert(&suppression)
+		if err != nil {
+			return Suppression{}, err
+		}
+
+		return suppression, nil
+	case nil:
+		existing.Reason = suppression.Reason
+		existing.MessageID = suppression.MessageID
+		_, err = conn.Update(&existing)
+		if err != nil {
+			return Suppression{}, err
+		}
+
+		return existing, nil
+	default:
+		return Suppression{}, err
+	}
+}
+
+func (repo SuppressionsRepo) Find(conn ConnectionInterface, email string) (Suppression, error) {
+	suppression := Suppression{}
+	err := conn.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `email` = ?", strings.ToLower(email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Suppression{}, NewRecordNotFoundError("Suppression of %q could not be found", email)
+		}
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (repo SuppressionsRepo) FindAll(conn ConnectionInterface) ([]Suppression, error) {
+	suppressions := []Suppression{}
+	_, err := conn.Select(&suppressions, "SELECT * FROM `suppressions` ORDER BY `email`")
+	if err != nil {
+		return []Suppression{}, err
+	}
+
+	return suppressions, nil
+}
+
+func (repo SuppressionsRepo) Destroy(conn ConnectionInterface, email string) error {
+	suppression, err := repo.Find(conn, email)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&suppression)
+
+	return err
+}
@@ -0,0 +1,96 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsRepo", func() {
+	var repo models.SuppressionsRepo
+	var conn models.ConnectionInterface
+
+	BeforeEach(func() {
+		TruncateTables()
+		repo = models.NewSuppressionsRepo()
+		env := application.NewEnvironment()
+		conn = models.NewDatabase(models.Config{
+			DatabaseURL:    env.DatabaseURL,
+			MigrationsPath: env.ModelMigrationsDir,
+		}).Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("suppresses the address", func() {
+			suppression, err := repo.Upsert(conn, models.Suppression{
+				Email:     "User@Example.com",
+				Reason:    "550 5.1.1 No such user",
+				MessageID: "message-123",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression.ID).NotTo(BeZero())
+			Expect(suppression.CreatedAt).NotTo(BeZero())
+
+			found, err := repo.Find(conn, "user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(Equal(suppression))
+			Expect(found.Email).To(Equal("user@example.com"))
+		})
+
+		It("replaces the reason of an address that is already suppressed", func() {
+			original, err := repo.Upsert(conn, models.Suppression{Email: "user@example.com", Reason: "first", MessageID: "message-123"})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, err := repo.Upsert(conn, models.Suppression{Email: "user@example.com", Reason: "second", MessageID: "message-456"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(updated.ID).To(Equal(original.ID))
+			Expect(updated.CreatedAt).To(Equal(original.CreatedAt))
+			Expect(updated.Reason).To(Equal("second"))
+			Expect(updated.MessageID).To(Equal("message-456"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a RecordNotFoundError when the address is not suppressed", func() {
+			_, err := repo.Find(conn, "user@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+
+	Describe("FindAll", func() {
+		It("returns every suppressed address", func() {
+			_, err := repo.Upsert(conn, models.Suppression{Email: "second@example.com", Reason: "complained"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.Suppression{Email: "first@example.com", Reason: "bounced"})
+			Expect(err).NotTo(HaveOccurred())
+
+			suppressions, err := repo.FindAll(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressions).To(HaveLen(2))
+			Expect(suppressions[0].Email).To(Equal("first@example.com"))
+			Expect(suppressions[1].Email).To(Equal("second@example.com"))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the suppression of the address", func() {
+			_, err := repo.Upsert(conn, models.Suppression{Email: "user@example.com", Reason: "complained"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Destroy(conn, "User@Example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "user@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
+		It("returns a RecordNotFoundError when the address is not suppressed", func() {
+			err := repo.Destroy(conn, "user@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
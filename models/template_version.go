@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TemplateVersion is a copy of a template as it was saved, along with the
+// client that saved it. Versions are numbered from 1 for each template, and
+// are never changed once they are written.
+type TemplateVersion struct {
+	Primary    int       `db:"primary"`
+	TemplateID string    `db:"template_id"`
+	Version    int       `db:"version"`
+	Name       string    `db:"name"`
+	Subject    string    `db:"subject"`
+	Text       string    `db:"text"`
+	HTML       string    `db:"html"`
+	Metadata   string    `db:"metadata"`
+	Author     string    `db:"author"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewTemplateVersion(template Template, author string) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: template.ID,
+		Name:       template.Name,
+		Subject:    template.Subject,
+		Text:       template.Text,
+		HTML:       template.HTML,
+		Metadata:   template.Metadata,
+		Author:     author,
+	}
+}
+
+// ToTemplate returns the content of the version as a template.
+func (version TemplateVersion) ToTemplate() Template {
+	return Template{
+		ID:       version.TemplateID,
+		Name:     version.Name,
+		Subject:  version.Subject,
+		Text:     version.Text,
+		HTML:     version.HTML,
+		Metadata: version.Metadata,
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type TemplateVersionsRepoInterface interface {
+	Create(ConnectionInterface, TemplateVersion) (TemplateVersion, error)
+	FindAllByTemplateID(ConnectionInterface, string) ([]TemplateVersion, error)
+	FindByVersion(ConnectionInterface, string, int) (TemplateVersion, error)
+}
+
+type TemplateVersionsRepo struct{}
+
+func NewTemplateVersionsRepo() TemplateVersionsRepo {
+	return TemplateVersionsRepo{}
+}
+
+// Create writes the next version of a template. The template row is locked
+// before the next number is worked out, so that when it is called within a
+// transaction, concurrent saves of the same template are numbered one after
+// the other rather than colliding.
+func (repo TemplateVersionsRepo) Create(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	_, err := conn.Select(&[]Template{}, "SELECT * FROM `templates` WHERE `id` = ? FOR UPDATE", version.TemplateID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	var latest int
+	err = conn.SelectOne(&latest, "SELECT COALESCE(MAX(`version`), 0) FROM `template_versions` WHERE `template_id` = ?", version.TemplateID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	version.Version = latest + 1
+	version.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	err = conn.Insert(&version)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return TemplateVersion{}, DuplicateRecordError{}
+		}
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+// FindAllByTemplateID returns the versions of a template, newest first.
+func (repo TemplateVersionsRepo) FindAllByTemplateID(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+	_, err := conn.Select(&versions, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (repo TemplateVersionsRepo) FindByVersion(conn ConnectionInterface, templateID string, number int) (TemplateVersion, error) {
+	version := TemplateVersion{}
+	err := conn.SelectOne(&version, "SELECT * FROM `template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return TemplateVersion{}, NewRecordNotFoundError("Version %d of template %q could not be found", number, templateID)
+		}
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
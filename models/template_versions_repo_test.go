@@ -0,0 +1,124 @@
+package models_test
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var repo models.TemplateVersionsRepo
+	var conn models.ConnectionInterface
+
+	BeforeEach(func() {
+		TruncateTables()
+		repo = models.NewTemplateVersionsRepo()
+		env := application.NewEnvironment()
+		conn = models.NewDatabase(models.Config{
+			DatabaseURL:    env.DatabaseURL,
+			MigrationsPath: env.ModelMigrationsDir,
+		}).Connection()
+	})
+
+	Describe("Create", func() {
+		It("numbers the versions of each template from 1", func() {
+			first, err := repo.Create(conn, models.TemplateVersion{TemplateID: "template-id", Name: "first", Author: "my-client"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Version).To(Equal(1))
+			Expect(first.CreatedAt).NotTo(BeZero())
+
+			second, err := repo.Create(conn, models.TemplateVersion{TemplateID: "template-id", Name: "second", Author: "my-client"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Version).To(Equal(2))
+
+			other, err := repo.Create(conn, models.TemplateVersion{TemplateID: "other-id", Name: "other", Author: "my-client"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(other.Version).To(Equal(1))
+		})
+
+		It("numbers concurrent versions of a template one after the other", func() {
+			template, err := models.NewTemplatesRepo().Create(conn, models.Template{Name: "some-template"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var group sync.WaitGroup
+			errs := make(chan error, 5)
+			for i := 0; i < 5; i++ {
+				group.Add(1)
+				go func() {
+					defer group.Done()
+					defer GinkgoRecover()
+
+					transaction := conn.Transaction()
+					transaction.Begin()
+
+					_, err := repo.Create(transaction, models.TemplateVersion{TemplateID: template.ID, Name: "concurrent"})
+					if err != nil {
+						transaction.Rollback()
+						errs <- err
+						return
+					}
+
+					errs <- transaction.Commit()
+				}()
+			}
+			group.Wait()
+			close(errs)
+
+			for err := range errs {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			versions, err := repo.FindAllByTemplateID(conn, template.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(5))
+			for i, version := range versions {
+				Expect(version.Version).To(Equal(5 - i))
+			}
+		})
+	})
+
+	Describe("FindAllByTemplateID", func() {
+		It("returns the versions of the template, newest first", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "template-id", Name: "first"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repo.Create(conn, models.TemplateVersion{TemplateID: "template-id", Name: "second"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repo.Create(conn, models.TemplateVersion{TemplateID: "other-id", Name: "other"})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.FindAllByTemplateID(conn, "template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Name).To(Equal("second"))
+			Expect(versions[1].Name).To(Equal("first"))
+		})
+	})
+
+	Describe("FindByVersion", func() {
+		It("returns the numbered version of the template", func() {
+			created, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "template-id",
+				Name:       "first",
+				Subject:    "{{.Subject}}",
+				Text:       "{{.Text}}",
+				HTML:       "{{.HTML}}",
+				Metadata:   "{}",
+				Author:     "my-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.FindByVersion(conn, "template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(created))
+		})
+
+		It("returns a RecordNotFoundError when there is no such version", func() {
+			_, err := repo.FindByVersion(conn, "template-id", 3)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package postal
+
+import (
+	"log"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type bounceMessagesRepoInterface interface {
+	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	FindAllByJobID(models.ConnectionInterface, int) ([]models.Message, error)
+	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
+}
+
+// BounceProcessor records the bounces reported by delivery status
+// notifications. A message that failed is marked bounced, and an address that
+// hard-bounced is suppressed so that no further mail is sent to it.
+type BounceProcessor struct {
+	db                models.DatabaseInterface
+	messagesRepo      bounceMessagesRepoInterface
+	messageEventsRepo models.MessageEventsRepoInterface
+	suppressionsRepo  models.SuppressionsRepoInterface
+	logger            *log.Logger
+}
+
+func NewBounceProcessor(db models.DatabaseInterface, messagesRepo bounceMessagesRepoInterface,
+	messageEventsRepo models.MessageEventsRepoInterface, suppressionsRepo models.SuppressionsRepoInterface,
+	logger *log.Logger) BounceProcessor {
+
+	return BounceProcessor{
+		db:                db,
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
+		suppressionsRepo:  suppressionsRepo,
+		logger:            logger,
+	}
+}
+
+// HandleBounce processes a notification for the message with the given ID,
+// which is taken from the signed VERP return path the bounce was delivered
+// to. The rest of the notification can be forged by anyone who can mail the
+// return path, so only the recipients it reports on that the message, or one
+// of the CC and BCC copies sent along with it, was sent to are taken into
+// account.
+func (processor BounceProcessor) HandleBounce(messageID string, dsn mail.DSN) error {
+	conn := processor.db.Connection()
+	message, err := processor.messagesRepo.FindByID(conn, messageID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			processor.logger.Printf("Ignoring the bounce of notification %s, its status no longer exists", messageID)
+			return nil
+		}
+		return err
+	}
+
+	for _, recipient := range dsn.Recipients {
+		bounced, ok, err := processor.bouncedMessage(conn, message, recipient.Address)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			processor.logger.Printf("Ignoring delivery status of notification %s for %s, which it was not sent to", messageID, recipient.Address)
+			continue
+		}
+
+		if !recipient.Failed() {
+			processor.logger.Printf("Ignoring %q delivery status of notification %s for %s", recipient.Action, bounced.ID, recipient.Address)
+			continue
+		}
+
+		if recipient.Permanent() {
+			_, err := processor.suppressionsRepo.Upsert(conn, models.Suppression{
+				Email:     recipient.Address,
+				Reason:    bounceReason(recipient),
+				MessageID: bounced.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			processor.logger.Printf("Suppressed %s after notification %s hard-bounced", recipient.Address, bounced.ID)
+		}
+
+		bounced, err = processor.markBounced(conn, bounced, recipient)
+		if err != nil {
+			return err
+		}
+
+		if bounced.ID == message.ID {
+			message = bounced
+		}
+	}
+
+	return nil
+}
+
+// bouncedMessage finds the message that was sent to the address: either the
+// message itself, or one of its CC and BCC copies, which were delivered by the
+// same job under the return path of the message.
+func (processor BounceProcessor) bouncedMessage(conn models.ConnectionInterface, message models.Message, address string) (models.Message, bool, error) {
+	if sentTo(message, address) {
+		return message, true, nil
+	}
+
+	if message.JobID == 0 {
+		return models.Message{}, false, nil
+	}
+
+	copies, err := processor.messagesRepo.FindAllByJobID(conn, message.JobID)
+	if err != nil {
+		return models.Message{}, false, err
+	}
+
+	for _, copy := range copies {
+		if copy.ID != message.ID && sentTo(copy, address) {
+			return copy, true, nil
+		}
+	}
+
+	return models.Message{}, false, nil
+}
+
+// sentTo reports whether the message was sent to the address. Messages that
+// were recorded before their address was only name it as their recipient.
+func sentTo(message models.Message, address string) bool {
+	email := message.Email
+	if email == "" {
+		email = message.Recipient
+	}
+
+	return address != "" && strings.EqualFold(email, address)
+}
+
+func (processor BounceProcessor) markBounced(conn models.ConnectionInterface, message models.Message, recipient mail.DSNRecipient) (models.Message, error) {
+	message.Status = StatusBounced
+	message, err := processor.messagesRepo.Upsert(conn, message)
+	if err != nil {
+		return message, err
+	}
+
+	_, err = processor.messageEventsRepo.Create(conn, models.MessageEvent{
+		MessageID: message.ID,
+		Status:    StatusBounced,
+		Error:     bounceReason(recipient),
+	})
+
+	return message, err
+}
+
+func bounceReason(recipient mail.DSNRecipient) string {
+	return strings.TrimSpace(recipient.Status + " " + recipient.DiagnosticCode)
+}
@@ -0,0 +1,218 @@
+package postal_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BounceProcessor", func() {
+	var processor postal.BounceProcessor
+	var messagesRepo *fakes.MessagesRepo
+	var messageEventsRepo *fakes.MessageEventsRepo
+	var suppressionsRepo *fakes.SuppressionsRepo
+	var buffer *bytes.Buffer
+	var dsn mail.DSN
+
+	BeforeEach(func() {
+		messagesRepo = fakes.NewMessagesRepo()
+		messageEventsRepo = fakes.NewMessageEventsRepo()
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		buffer = bytes.NewBuffer([]byte{})
+		processor = postal.NewBounceProcessor(fakes.NewDatabase(), messagesRepo, messageEventsRepo, suppressionsRepo, log.New(buffer, "", 0))
+
+		messagesRepo.Messages["message-123"] = models.Message{
+			ID:        "message-123",
+			Status:    postal.StatusDelivered,
+			ClientID:  "some-client",
+			BatchID:   "some-batch",
+			Recipient: "user-123",
+			Email:     "user@example.com",
+		}
+
+		dsn = mail.DSN{
+			Recipients: []mail.DSNRecipient{
+				{
+					Address:        "User@Example.com",
+					Action:         "failed",
+					Status:         "5.1.1",
+					DiagnosticCode: "550 User unknown",
+				},
+			},
+		}
+	})
+
+	Context("when the message hard-bounced", func() {
+		It("marks the message bounced, keeping the rest of its status", func() {
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.Messages["message-123"]).To(Equal(models.Message{
+				ID:        "message-123",
+				Status:    postal.StatusBounced,
+				ClientID:  "some-client",
+				BatchID:   "some-batch",
+				Recipient: "user-123",
+				Email:     "user@example.com",
+			}))
+		})
+
+		It("records the bounce in the history of the message", func() {
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messageEventsRepo.Events).To(Equal([]models.MessageEvent{
+				{
+					ID:        1,
+					MessageID: "message-123",
+					Status:    postal.StatusBounced,
+					Error:     "5.1.1 550 User unknown",
+				},
+			}))
+		})
+
+		It("suppresses the address", func() {
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.Suppressions).To(Equal(map[string]models.Suppression{
+				"user@example.com": {
+					Email:     "user@example.com",
+					Reason:    "5.1.1 550 User unknown",
+					MessageID: "message-123",
+				},
+			}))
+		})
+
+		It("returns the error when the address cannot be suppressed", func() {
+			suppressionsRepo.UpsertError = errors.New("database is down")
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).To(MatchError("database is down"))
+		})
+	})
+
+	Context("when the message failed for a transient reason", func() {
+		It("marks the message bounced without suppressing the address", func() {
+			dsn.Recipients[0].Status = "4.4.7"
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.Messages["message-123"].Status).To(Equal(postal.StatusBounced))
+			Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		})
+	})
+
+	Context("when the delivery was only delayed", func() {
+		It("ignores the notification", func() {
+			dsn.Recipients[0].Action = "delayed"
+			dsn.Recipients[0].Status = "4.2.2"
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.Messages["message-123"].Status).To(Equal(postal.StatusDelivered))
+			Expect(messageEventsRepo.Events).To(BeEmpty())
+			Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		})
+	})
+
+	It("ignores the recipients the message was not sent to", func() {
+		dsn.Recipients[0].Address = "someone-else@example.com"
+
+		err := processor.HandleBounce("message-123", dsn)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		Expect(messagesRepo.Messages["message-123"].Status).To(Equal(postal.StatusDelivered))
+		Expect(buffer.String()).To(ContainSubstring("Ignoring delivery status of notification message-123 for someone-else@example.com, which it was not sent to"))
+	})
+
+	Context("when a copy of the message bounced", func() {
+		BeforeEach(func() {
+			message := messagesRepo.Messages["message-123"]
+			message.JobID = 42
+			messagesRepo.Messages["message-123"] = message
+
+			messagesRepo.Messages["copy-message-id"] = models.Message{
+				ID:        "copy-message-id",
+				Status:    postal.StatusDelivered,
+				JobID:     42,
+				Recipient: "copy@example.com",
+				Email:     "copy@example.com",
+			}
+			messagesRepo.Messages["unrelated-message-id"] = models.Message{
+				ID:        "unrelated-message-id",
+				Status:    postal.StatusDelivered,
+				JobID:     43,
+				Recipient: "unrelated@example.com",
+				Email:     "unrelated@example.com",
+			}
+		})
+
+		It("marks the copy bounced and suppresses its address", func() {
+			dsn.Recipients[0].Address = "copy@example.com"
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.Messages["copy-message-id"].Status).To(Equal(postal.StatusBounced))
+			Expect(messagesRepo.Messages["message-123"].Status).To(Equal(postal.StatusDelivered))
+			Expect(suppressionsRepo.Suppressions).To(HaveKey("copy@example.com"))
+			Expect(suppressionsRepo.Suppressions["copy@example.com"].MessageID).To(Equal("copy-message-id"))
+		})
+
+		It("ignores addresses that only messages of other jobs were sent to", func() {
+			dsn.Recipients[0].Address = "unrelated@example.com"
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.Messages["unrelated-message-id"].Status).To(Equal(postal.StatusDelivered))
+			Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		})
+
+		It("returns the error when the copies cannot be found", func() {
+			dsn.Recipients[0].Address = "copy@example.com"
+			messagesRepo.FindAllByJobIDError = errors.New("database is down")
+
+			err := processor.HandleBounce("message-123", dsn)
+			Expect(err).To(MatchError("database is down"))
+		})
+	})
+
+	It("matches the recipient of messages recorded before their address was", func() {
+		messagesRepo.Messages["message-456"] = models.Message{ID: "message-456", Status: postal.StatusDelivered, Recipient: "user@example.com"}
+
+		err := processor.HandleBounce("message-456", dsn)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(messagesRepo.Messages["message-456"].Status).To(Equal(postal.StatusBounced))
+		Expect(suppressionsRepo.Suppressions).To(HaveKey("user@example.com"))
+	})
+
+	It("suppresses nothing when the status of the message no longer exists", func() {
+		err := processor.HandleBounce("message-789", dsn)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		Expect(buffer.String()).To(ContainSubstring("Ignoring the bounce of notification message-789"))
+	})
+
+	It("returns the error when the message cannot be found", func() {
+		messagesRepo.FindByIDError = errors.New("database is down")
+
+		err := processor.HandleBounce("message-123", dsn)
+		Expect(err).To(MatchError("database is down"))
+		Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+	})
+})
@@ -1,6 +1,7 @@
 package postal
 
 import (
+	"fmt"
 	"log"
 	"math"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/nu7hatch/gouuid"
 	"github.com/pivotal-golang/conceal"
 )
 
@@ -23,49 +25,81 @@ type Delivery struct {
 	ClientID     string
 	MessageID    string
 	Scope        string
+	Data         map[string]interface{}
+	CC           []Recipient
+	BCC          []Recipient
+
+	// withheld is set when the notification is not sent to Email, because
+	// the address is suppressed or its user unsubscribed, so that it is only
+	// sent to the CC and BCC copies.
+	withheld bool
 }
 
+// Recipient is an address that is copied on a delivery. Each copy is tracked
+// as a message of its own.
+type Recipient struct {
+	Email     string
+	MessageID string
+}
+
+const MaxRetries = 10
+
 type MessagesRepoInterface interface {
+	FindByID(models.ConnectionInterface, string) (models.Message, error)
 	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
 }
 
 type DeliveryWorker struct {
 	logger                 *log.Logger
-	mailClient             mail.ClientInterface
+	mailClient             mail.RouterInterface
 	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface
 	unsubscribesRepo       models.UnsubscribesRepoInterface
+	suppressionsRepo       models.SuppressionsRepoInterface
+	deliveryAddressesRepo  models.DeliveryAddressesRepoInterface
 	kindsRepo              models.KindsRepoInterface
 	userLoader             UserLoaderInterface
 	templatesLoader        TemplatesLoaderInterface
 	tokenLoader            TokenLoaderInterface
 	messagesRepo           MessagesRepoInterface
+	messageEventsRepo      models.MessageEventsRepoInterface
 	receiptsRepo           models.ReceiptsRepoInterface
 	database               models.DatabaseInterface
+	expander               ExpanderInterface
 	sender                 string
+	bounceDomain           string
 	encryptionKey          []byte
+	unsubscribeConfig      UnsubscribeConfig
 	gobble.Worker
 }
 
-func NewDeliveryWorker(id int, logger *log.Logger, mailClient mail.ClientInterface, queue gobble.QueueInterface,
+func NewDeliveryWorker(id int, logger *log.Logger, mailClient mail.RouterInterface, queue gobble.QueueInterface,
 	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface, unsubscribesRepo models.UnsubscribesRepoInterface,
+	suppressionsRepo models.SuppressionsRepoInterface, deliveryAddressesRepo models.DeliveryAddressesRepoInterface,
 	kindsRepo models.KindsRepoInterface, messagesRepo MessagesRepoInterface,
-	database models.DatabaseInterface, sender string, encryptionKey []byte, userLoader UserLoaderInterface,
-	templatesLoader TemplatesLoaderInterface, receiptsRepo models.ReceiptsRepoInterface, tokenLoader TokenLoaderInterface) DeliveryWorker {
+	messageEventsRepo models.MessageEventsRepoInterface, database models.DatabaseInterface, sender, bounceDomain string, encryptionKey []byte, unsubscribeConfig UnsubscribeConfig, userLoader UserLoaderInterface,
+	templatesLoader TemplatesLoaderInterface, receiptsRepo models.ReceiptsRepoInterface, tokenLoader TokenLoaderInterface,
+	expander ExpanderInterface) DeliveryWorker {
 
 	worker := DeliveryWorker{
 		logger:                 logger,
 		mailClient:             mailClient,
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
 		unsubscribesRepo:       unsubscribesRepo,
+		suppressionsRepo:       suppressionsRepo,
+		deliveryAddressesRepo:  deliveryAddressesRepo,
 		kindsRepo:              kindsRepo,
 		messagesRepo:           messagesRepo,
+		messageEventsRepo:      messageEventsRepo,
 		database:               database,
 		sender:                 sender,
+		bounceDomain:           bounceDomain,
 		encryptionKey:          encryptionKey,
+		unsubscribeConfig:      unsubscribeConfig,
 		userLoader:             userLoader,
 		tokenLoader:            tokenLoader,
 		templatesLoader:        templatesLoader,
 		receiptsRepo:           receiptsRepo,
+		expander:               expander,
 	}
 	worker.Worker = gobble.NewWorker(id, queue, worker.Deliver)
 
@@ -73,6 +107,11 @@ func NewDeliveryWorker(id int, logger *log.Logger, mailClient mail.ClientInterfa
 }
 
 func (worker DeliveryWorker) Deliver(job *gobble.Job) {
+	if IsExpansion(*job) {
+		worker.expand(job)
+		return
+	}
+
 	var delivery Delivery
 
 	err := job.Unmarshal(&delivery)
@@ -81,84 +120,291 @@ func (worker DeliveryWorker) Deliver(job *gobble.Job) {
 			"name": "notifications.worker.panic.json",
 		}).Log()
 
-		worker.retry(job)
+		worker.retry(job, err)
 		return
 	}
 
 	err = worker.receiptsRepo.CreateReceipts(worker.database.Connection(), []string{delivery.UserGUID}, delivery.ClientID, delivery.Options.KindID)
 	if err != nil {
-		worker.retry(job)
+		worker.retry(job, err)
 		return
 	}
 
+	deliveries := []Delivery{delivery}
 	if delivery.Email == "" {
 		token, err := worker.tokenLoader.Load()
 		if err != nil {
-			worker.retry(job)
+			worker.retry(job, err)
 			return
 		}
 
 		users, err := worker.userLoader.Load([]string{delivery.UserGUID}, token)
-		if err != nil || len(users) < 1 {
-			worker.retry(job)
+		if err != nil {
+			worker.retry(job, err)
 			return
 		}
 
-		emails := users[delivery.UserGUID].Emails
-		if len(emails) > 0 {
-			delivery.Email = emails[0]
+		if len(users) < 1 {
+			worker.retry(job, UAAUserNotFoundError("User "+delivery.UserGUID+" could not be found"))
+			return
 		}
-	}
 
-	if worker.shouldDeliver(delivery) {
-		status := worker.deliver(delivery)
+		addresses, err := worker.deliveryAddresses(delivery.UserGUID, users[delivery.UserGUID].Emails)
+		if err != nil {
+			worker.retry(job, err)
+			return
+		}
+		deliveries = splitByAddress(delivery, addresses)
+	}
 
-		if status != StatusDelivered {
-			worker.retry(job)
+	var retryErr error
+	for _, delivery := range deliveries {
+		if job.IsLeaseLost() {
+			worker.logger.Printf("Lost the lease on job %d, leaving it to the worker that holds it", job.ID)
 			return
-		} else {
-			metrics.NewMetric("counter", map[string]interface{}{
-				"name": "notifications.worker.delivered",
-			}).Log()
 		}
-	} else {
+
+		if len(deliveries) > 1 && worker.delivered(delivery.MessageID) {
+			continue
+		}
+
+		err = worker.send(job, delivery)
+		if err != nil && retryErr == nil {
+			retryErr = err
+		}
+	}
+
+	if retryErr != nil {
+		worker.retry(job, retryErr)
+	}
+}
+
+// send delivers the notification to a single address and its copies,
+// returning an error when the delivery should be retried. The copies are
+// still delivered when the address itself is not.
+func (worker DeliveryWorker) send(job *gobble.Job, delivery Delivery) error {
+	var err error
+
+	delivery.CC, err = worker.dropSuppressedCopies(job, delivery, delivery.CC)
+	if err != nil {
+		return err
+	}
+
+	delivery.BCC, err = worker.dropSuppressedCopies(job, delivery, delivery.BCC)
+	if err != nil {
+		return err
+	}
+
+	err = worker.checkSuppression(delivery)
+	if err != nil {
+		if _, ok := err.(RecipientSuppressedError); !ok {
+			return err
+		}
+
+		worker.logger.Printf("Not delivering because %s", err.Error())
+		worker.recordStatus(job, delivery, primaryMessage(delivery), StatusUndeliverable, "", err)
+		delivery.withheld = true
+
+		metrics.NewMetric("counter", map[string]interface{}{
+			"name": "notifications.worker.suppressed",
+		}).Log()
+	} else if !worker.shouldDeliver(delivery) {
+		worker.recordStatus(job, delivery, primaryMessage(delivery), StatusUndeliverable, "", nil)
+		delivery.withheld = true
+
 		metrics.NewMetric("counter", map[string]interface{}{
 			"name": "notifications.worker.unsubscribed",
 		}).Log()
 	}
+
+	if delivery.withheld && len(delivery.CC) == 0 && len(delivery.BCC) == 0 {
+		return nil
+	}
+
+	status, err := worker.deliver(job, delivery)
+	if status != StatusDelivered {
+		return err
+	}
+
+	metrics.NewMetric("counter", map[string]interface{}{
+		"name": "notifications.worker.delivered",
+	}).Log()
+	return nil
+}
+
+// splitByAddress makes a delivery for each of the user's addresses, so that
+// none of them sees the others and each is tracked as a message of its own.
+// The first address keeps the ID of the message, along with its CC and BCC
+// copies. The IDs of the others are derived from it, so that they stay the
+// same when the job is retried.
+func splitByAddress(delivery Delivery, addresses []string) []Delivery {
+	if len(addresses) == 0 {
+		return []Delivery{delivery}
+	}
+
+	deliveries := []Delivery{}
+	for i, address := range addresses {
+		split := delivery
+		split.Email = address
+		if i > 0 {
+			split.MessageID = addressMessageID(delivery.MessageID, address)
+			split.CC = nil
+			split.BCC = nil
+		}
+		deliveries = append(deliveries, split)
+	}
+
+	return deliveries
 }
 
-func (worker DeliveryWorker) deliver(delivery Delivery) string {
+func addressMessageID(messageID, address string) string {
+	guid, err := uuid.NewV5(uuid.NamespaceOID, []byte(messageID+" "+strings.ToLower(address)))
+	if err != nil {
+		panic(err)
+	}
+
+	return guid.String()
+}
+
+// delivered reports whether an earlier attempt at the job already sent the
+// message, so that a retry does not send it to the same address again.
+func (worker DeliveryWorker) delivered(messageID string) bool {
+	message, err := worker.messagesRepo.FindByID(worker.database.Connection(), messageID)
+	return err == nil && message.Status == StatusDelivered
+}
+
+func (worker DeliveryWorker) expand(job *gobble.Job) {
+	err := worker.expander.Expand(job)
+	if err != nil {
+		worker.retry(job, err)
+		return
+	}
+
+	if job.IsLeaseLost() {
+		worker.logger.Printf("Lost the lease on job %d, leaving it to the worker that holds it", job.ID)
+		return
+	}
+
+	metrics.NewMetric("counter", map[string]interface{}{
+		"name": "notifications.worker.expanded",
+	}).Log()
+}
+
+func (worker DeliveryWorker) deliver(job *gobble.Job, delivery Delivery) (string, error) {
 	message, err := worker.pack(delivery)
 	if err != nil {
 		worker.logger.Printf("Not delivering because template failed to pack")
-		worker.updateMessageStatus(delivery.MessageID, StatusFailed)
-		return StatusFailed
+		worker.updateMessageStatus(job, delivery, StatusFailed, "", err, nil)
+		return StatusFailed, err
+	}
+
+	status, relay, err := worker.sendMail(message)
+
+	var refused map[string]error
+	if refusedErr, ok := err.(mail.RecipientsRefusedError); ok {
+		refused = refusedErr.Refused
+		err = nil
+	}
+
+	worker.updateMessageStatus(job, delivery, status, relay, err, refused)
+
+	return status, err
+}
+
+// updateMessageStatus records the outcome of a delivery attempt, both as the
+// current status of the message and as an event in its history. The CC and
+// BCC copies of the delivery share its outcome, except for the addresses the
+// server refused, which are undeliverable. When the delivery was withheld from
+// its own address, only the copies are recorded.
+func (worker DeliveryWorker) updateMessageStatus(job *gobble.Job, delivery Delivery, status, relay string, deliveryErr error, refused map[string]error) {
+	var messages []models.Message
+	if !delivery.withheld {
+		messages = append(messages, primaryMessage(delivery))
+	}
+
+	for _, copy := range append(delivery.CC, delivery.BCC...) {
+		messages = append(messages, models.Message{
+			ID:        copy.MessageID,
+			Recipient: copy.Email,
+			Email:     copy.Email,
+		})
+	}
+
+	for _, message := range messages {
+		if refusal, ok := refused[message.Email]; ok {
+			worker.recordStatus(job, delivery, message, StatusUndeliverable, relay, refusal)
+			continue
+		}
+
+		worker.recordStatus(job, delivery, message, status, relay, deliveryErr)
 	}
+}
 
-	status := worker.sendMail(message)
-	worker.updateMessageStatus(delivery.MessageID, status)
+// primaryMessage is the message that tracks the delivery to its own address,
+// as opposed to its copies.
+func primaryMessage(delivery Delivery) models.Message {
+	recipient := delivery.UserGUID
+	if recipient == "" {
+		recipient = delivery.Email
+	}
 
-	return status
+	return models.Message{
+		ID:        delivery.MessageID,
+		Recipient: recipient,
+		Email:     delivery.Email,
+	}
 }
 
-func (worker DeliveryWorker) updateMessageStatus(messageID, status string) {
-	_, err := worker.messagesRepo.Upsert(worker.database.Connection(), models.Message{ID: messageID, Status: status})
+// recordStatus records the outcome for one of the messages of the delivery,
+// which is given by its ID and recipient. The job is kept with the message, so
+// that a bounce of a copy can be traced through the job to the copy.
+func (worker DeliveryWorker) recordStatus(job *gobble.Job, delivery Delivery, message models.Message, status, relay string, deliveryErr error) {
+	message.Status = status
+	message.JobID = job.ID
+	message.BatchID = delivery.Options.BatchID
+	message.ClientID = delivery.ClientID
+
+	conn := worker.database.Connection()
+	_, err := worker.messagesRepo.Upsert(conn, message)
+	if err != nil {
+		worker.logger.Printf("Failed to upsert status '%s' of notification %s. Error: %s", status, message.ID, err.Error())
+	}
+
+	event := models.MessageEvent{
+		MessageID: message.ID,
+		Status:    status,
+		Attempt:   job.RetryCount + 1,
+		WorkerID:  worker.ID,
+		Relay:     relay,
+	}
+	if deliveryErr != nil {
+		event.Error = deliveryErr.Error()
+	}
+
+	_, err = worker.messageEventsRepo.Create(conn, event)
 	if err != nil {
-		worker.logger.Printf("Failed to upsert status '%s' of notification %s. Error: %s", status, messageID, err.Error())
+		worker.logger.Printf("Failed to record status '%s' in the history of notification %s. Error: %s", status, message.ID, err.Error())
 	}
 }
 
-func (worker DeliveryWorker) retry(job *gobble.Job) {
-	if job.RetryCount < 10 {
+func (worker DeliveryWorker) retry(job *gobble.Job, err error) {
+	if job.RetryCount < MaxRetries {
 		duration := time.Duration(int64(math.Pow(2, float64(job.RetryCount))))
 		job.Retry(duration * time.Minute)
 		layout := "Jan 2, 2006 at 3:04pm (MST)"
 		worker.logger.Printf("Message failed to send, retrying at: %s", job.ActiveAt.Format(layout))
+
+		metrics.NewMetric("counter", map[string]interface{}{
+			"name": "notifications.worker.retry",
+		}).Log()
+		return
 	}
 
+	job.DeadLetter(err.Error())
+	worker.logger.Printf("Message failed to send after %d attempts, moving it to the dead letters: %s", job.RetryCount+1, err.Error())
+
 	metrics.NewMetric("counter", map[string]interface{}{
-		"name": "notifications.worker.retry",
+		"name": "notifications.worker.dead_letter",
 	}).Log()
 }
 
@@ -198,6 +444,83 @@ func (worker DeliveryWorker) shouldDeliver(delivery Delivery) bool {
 	return false
 }
 
+// deliveryAddresses picks the addresses of the user that the notification is
+// sent to, according to the user's delivery address preference. A chosen
+// address that UAA no longer holds for the user falls back to the primary one.
+func (worker DeliveryWorker) deliveryAddresses(userGUID string, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return emails, nil
+	}
+
+	preference, err := worker.deliveryAddressesRepo.Get(worker.database.Connection(), userGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch preference.Mode {
+	case models.DeliveryAddressAll:
+		return emails, nil
+	case models.DeliveryAddressSpecific:
+		for _, email := range emails {
+			if strings.EqualFold(email, preference.Email) {
+				return []string{email}, nil
+			}
+		}
+
+		worker.logger.Printf("Delivering to the primary address of user %s because %s is no longer one of their addresses", userGUID, preference.Email)
+	}
+
+	return emails[:1], nil
+}
+
+// checkSuppression returns a RecipientSuppressedError when mail to the
+// recipient's address is suppressed. Suppressed addresses have hard-bounced,
+// so even critical notifications are not sent to them.
+func (worker DeliveryWorker) checkSuppression(delivery Delivery) error {
+	if delivery.Email == "" {
+		return nil
+	}
+
+	suppression, err := worker.suppressionsRepo.Find(worker.database.Connection(), delivery.Email)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+
+	return RecipientSuppressedError(fmt.Sprintf("%s is suppressed: %s", delivery.Email, suppression.Reason))
+}
+
+// dropSuppressedCopies returns the CC or BCC copies whose addresses are not
+// suppressed. Each suppressed copy is recorded as undeliverable on its own,
+// without holding up the rest of the delivery.
+func (worker DeliveryWorker) dropSuppressedCopies(job *gobble.Job, delivery Delivery, copies []Recipient) ([]Recipient, error) {
+	conn := worker.database.Connection()
+	var remaining []Recipient
+
+	for _, copy := range copies {
+		suppression, err := worker.suppressionsRepo.Find(conn, copy.Email)
+		if err != nil {
+			if _, ok := err.(models.RecordNotFoundError); ok {
+				remaining = append(remaining, copy)
+				continue
+			}
+			return copies, err
+		}
+
+		worker.logger.Printf("Not delivering to %s because it is suppressed: %s", copy.Email, suppression.Reason)
+		suppressedErr := RecipientSuppressedError(fmt.Sprintf("%s is suppressed: %s", copy.Email, suppression.Reason))
+		worker.recordStatus(job, delivery, models.Message{
+			ID:        copy.MessageID,
+			Recipient: copy.Email,
+			Email:     copy.Email,
+		}, StatusUndeliverable, "", suppressedErr)
+	}
+
+	return remaining, nil
+}
+
 func (worker DeliveryWorker) isCritical(conn models.ConnectionInterface, kindID, clientID string) bool {
 	kind, err := worker.kindsRepo.Find(conn, kindID, clientID)
 	if _, ok := err.(models.RecordNotFoundError); ok {
@@ -220,7 +543,14 @@ func (worker DeliveryWorker) pack(delivery Delivery) (mail.Message, error) {
 		return message, err
 	}
 
-	context := NewMessageContext(delivery, worker.sender, cloak, templates)
+	// Critical notifications cannot be unsubscribed from, so they carry no
+	// unsubscribe links.
+	unsubscribeConfig := worker.unsubscribeConfig
+	if worker.isCritical(worker.database.Connection(), delivery.Options.KindID, delivery.ClientID) {
+		unsubscribeConfig = UnsubscribeConfig{}
+	}
+
+	context := NewMessageContext(delivery, worker.sender, cloak, templates, unsubscribeConfig)
 	packager := NewPackager()
 
 	message, err = packager.Pack(context)
@@ -228,24 +558,45 @@ func (worker DeliveryWorker) pack(delivery Delivery) (mail.Message, error) {
 		return message, err
 	}
 
+	for _, copy := range delivery.CC {
+		message.CC = append(message.CC, copy.Email)
+	}
+
+	for _, copy := range delivery.BCC {
+		message.BCC = append(message.BCC, copy.Email)
+	}
+
+	message.OmitTo = delivery.withheld
+
+	if worker.bounceDomain != "" {
+		message.ReturnPath = mail.ReturnPath(delivery.MessageID, worker.bounceDomain, worker.encryptionKey)
+	}
+
 	return message, nil
 }
 
-func (worker DeliveryWorker) sendMail(message mail.Message) string {
+// sendMail returns the delivery status along with the name of the SMTP relay
+// that handled the message, if any did.
+func (worker DeliveryWorker) sendMail(message mail.Message) (string, string, error) {
 	err := worker.mailClient.Connect()
 	if err != nil {
 		worker.logger.Printf("Error Establishing SMTP Connection: %s", err.Error())
-		return StatusUnavailable
+		return StatusUnavailable, "", err
 	}
 
 	worker.logger.Printf("Attempting to deliver message to %s", message.To)
-	err = worker.mailClient.Send(message)
+	relay, err := worker.mailClient.Send(message)
+	if _, ok := err.(mail.RecipientsRefusedError); ok {
+		worker.logger.Printf("Message was sent to %s, but some of its recipients were refused: %s", message.To, err.Error())
+		return StatusDelivered, relay, err
+	}
+
 	if err != nil {
 		worker.logger.Printf("Failed to deliver message due to SMTP error: %s", err.Error())
-		return StatusFailed
+		return StatusFailed, relay, err
 	}
 
 	worker.logger.Printf("Message was successfully sent to %s", message.To)
 
-	return StatusDelivered
+	return StatusDelivered, relay, nil
 }
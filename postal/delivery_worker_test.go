@@ -39,8 +39,11 @@ var _ = Describe("DeliveryWorker", func() {
 	var queue *fakes.Queue
 	var unsubscribesRepo *fakes.UnsubscribesRepo
 	var globalUnsubscribesRepo *fakes.GlobalUnsubscribesRepo
+	var suppressionsRepo *fakes.SuppressionsRepo
+	var deliveryAddressesRepo *fakes.DeliveryAddressesRepo
 	var kindsRepo *fakes.KindsRepo
 	var messagesRepo *fakes.MessagesRepo
+	var messageEventsRepo *fakes.MessageEventsRepo
 	var database *fakes.Database
 	var conn models.ConnectionInterface
 	var userLoader *fakes.UserLoader
@@ -49,6 +52,9 @@ var _ = Describe("DeliveryWorker", func() {
 	var templateLoader *fakes.TemplatesLoader
 	var receiptsRepo *fakes.ReceiptsRepo
 	var tokenLoader *fakes.TokenLoader
+	var expander *fakes.Expander
+	var unsubscribeConfig postal.UnsubscribeConfig
+	var bounceDomain string
 
 	BeforeEach(func() {
 		buffer = bytes.NewBuffer([]byte{})
@@ -58,8 +64,11 @@ var _ = Describe("DeliveryWorker", func() {
 		queue = fakes.NewQueue()
 		unsubscribesRepo = fakes.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = fakes.NewGlobalUnsubscribesRepo()
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		deliveryAddressesRepo = fakes.NewDeliveryAddressesRepo()
 		kindsRepo = fakes.NewKindsRepo()
 		messagesRepo = fakes.NewMessagesRepo()
+		messageEventsRepo = fakes.NewMessageEventsRepo()
 		database = fakes.NewDatabase()
 		conn = database.Connection()
 		userGUID = "user-123"
@@ -78,9 +87,12 @@ var _ = Describe("DeliveryWorker", func() {
 			Subject: "{{.Subject}}",
 		}
 		receiptsRepo = fakes.NewReceiptsRepo()
+		expander = fakes.NewExpander()
+		unsubscribeConfig = postal.UnsubscribeConfig{}
+		bounceDomain = ""
 
-		worker = postal.NewDeliveryWorker(id, logger, &mailClient, queue, globalUnsubscribesRepo, unsubscribesRepo, kindsRepo,
-			messagesRepo, database, sender, encryptionKey, userLoader, templateLoader, receiptsRepo, tokenLoader)
+		worker = postal.NewDeliveryWorker(id, logger, &mailClient, queue, globalUnsubscribesRepo, unsubscribesRepo, suppressionsRepo, deliveryAddressesRepo, kindsRepo,
+			messagesRepo, messageEventsRepo, database, sender, bounceDomain, encryptionKey, unsubscribeConfig, userLoader, templateLoader, receiptsRepo, tokenLoader, expander)
 
 		delivery = postal.Delivery{
 			ClientID: "some-client",
@@ -90,6 +102,7 @@ var _ = Describe("DeliveryWorker", func() {
 				Text:    "body content",
 				ReplyTo: "thesender@example.com",
 				KindID:  "some-kind",
+				BatchID: "some-batch",
 			},
 			MessageID: "randomly-generated-guid",
 		}
@@ -143,6 +156,18 @@ var _ = Describe("DeliveryWorker", func() {
 			Expect(results).To(ContainElement("Message was successfully sent to user-123@example.com"))
 		})
 
+		It("lets the recipient unsubscribe in one click when the public URL is known", func() {
+			unsubscribeConfig.PublicURL = "https://notifications.example.com"
+			worker = postal.NewDeliveryWorker(id, logger, &mailClient, queue, globalUnsubscribesRepo, unsubscribesRepo, suppressionsRepo, deliveryAddressesRepo, kindsRepo,
+				messagesRepo, messageEventsRepo, database, "from@email.com", bounceDomain, []byte("0123456789abcdef"), unsubscribeConfig, userLoader, templateLoader, receiptsRepo, tokenLoader, expander)
+
+			worker.Deliver(&job)
+
+			Expect(mailClient.Messages).To(HaveLen(1))
+			Expect(mailClient.Messages[0].Headers).To(ContainElement(MatchRegexp(`^List-Unsubscribe: <https://notifications\.example\.com/unsubscribe/.+>$`)))
+			Expect(mailClient.Messages[0].Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+		})
+
 		It("upserts the StatusDelivered to the database", func() {
 			messageID := getMessageIDFromJob(job)
 			worker.Deliver(&job)
@@ -155,6 +180,44 @@ var _ = Describe("DeliveryWorker", func() {
 			Expect(message.Status).To(Equal(postal.StatusDelivered))
 		})
 
+		It("records the delivery, and the relay that handled it, in the history of the message", func() {
+			mailClient.Relay = "smtp.example.com:587"
+			worker.Deliver(&job)
+
+			events, err := messageEventsRepo.FindAllByMessageID(conn, getMessageIDFromJob(job))
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Status).To(Equal(postal.StatusDelivered))
+			Expect(events[0].Attempt).To(Equal(1))
+			Expect(events[0].Relay).To(Equal("smtp.example.com:587"))
+			Expect(events[0].Error).To(BeEmpty())
+		})
+
+		It("logs when the history of the message cannot be recorded", func() {
+			messageEventsRepo.CreateError = errors.New("BOOM!")
+			worker.Deliver(&job)
+
+			Expect(buffer.String()).To(ContainSubstring("Failed to record status 'delivered' in the history of notification"))
+		})
+
+		It("keeps the batch, client and recipient of the message when upserting its status", func() {
+			job = gobble.NewJob(delivery)
+			worker.Deliver(&job)
+
+			message, err := messagesRepo.FindByID(conn, "randomly-generated-guid")
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(message.BatchID).To(Equal("some-batch"))
+			Expect(message.ClientID).To(Equal("some-client"))
+			Expect(message.Recipient).To(Equal(userGUID))
+			Expect(message.Email).To(Equal(fakeUserEmail))
+		})
+
 		It("creates a reciept for the delivery", func() {
 			worker.Deliver(&job)
 
@@ -250,6 +313,22 @@ var _ = Describe("DeliveryWorker", func() {
 					Expect(message.Status).To(Equal(postal.StatusFailed))
 				})
 
+				It("records the attempt and the SMTP error in the history of the message", func() {
+					job.RetryCount = 2
+					worker.Deliver(&job)
+
+					events, err := messageEventsRepo.FindAllByMessageID(conn, getMessageIDFromJob(job))
+					if err != nil {
+						panic(err)
+					}
+
+					Expect(events).To(HaveLen(1))
+					Expect(events[0].Status).To(Equal(postal.StatusFailed))
+					Expect(events[0].Attempt).To(Equal(3))
+					Expect(events[0].WorkerID).To(Equal(worker.ID))
+					Expect(events[0].Error).To(Equal("Error sending message!!!"))
+				})
+
 				Context("when the StatusFailed fails to be upserted into the db", func() {
 					It("logs the failure", func() {
 						messagesRepo.UpsertError = errors.New("An unforseen error in upserting to our db")
@@ -345,6 +424,352 @@ var _ = Describe("DeliveryWorker", func() {
 					worker.Deliver(&job)
 					Expect(job.ShouldRetry).To(BeFalse())
 				})
+
+				It("marks the job as a dead letter once it has exhausted its retries", func() {
+					mailClient.ConnectError = errors.New("BOOM!")
+					job.RetryCount = postal.MaxRetries
+
+					worker.Deliver(&job)
+
+					Expect(job.ShouldRetry).To(BeFalse())
+					Expect(job.ShouldDeadLetter).To(BeTrue())
+					Expect(job.LastError).To(Equal("BOOM!"))
+					Expect(buffer.String()).To(ContainSubstring("Message failed to send after 11 attempts, moving it to the dead letters: BOOM!"))
+				})
+			})
+		})
+
+		Context("when a bounce domain is configured", func() {
+			It("sends the message with a signed VERP return path that identifies it", func() {
+				worker = postal.NewDeliveryWorker(id, logger, &mailClient, queue, globalUnsubscribesRepo, unsubscribesRepo, suppressionsRepo, deliveryAddressesRepo, kindsRepo,
+					messagesRepo, messageEventsRepo, database, "from@email.com", "bounces.example.com", []byte("0123456789abcdef"), unsubscribeConfig, userLoader, templateLoader, receiptsRepo, tokenLoader, expander)
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].ReturnPath).To(Equal(mail.ReturnPath("randomly-generated-guid", "bounces.example.com", []byte("0123456789abcdef"))))
+			})
+		})
+
+		Context("when the user has several email addresses", func() {
+			BeforeEach(func() {
+				userLoader.Users["user-123"] = uaa.User{Emails: []string{fakeUserEmail, "work@example.com"}}
+			})
+
+			It("delivers to the primary address by default", func() {
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].To).To(Equal(fakeUserEmail))
+			})
+
+			Context("when the user chose all of them", func() {
+				BeforeEach(func() {
+					deliveryAddressesRepo.Addresses["user-123"] = models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressAll}
+				})
+
+				It("sends a message of its own to every address", func() {
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(2))
+					Expect(mailClient.Messages[0].To).To(Equal(fakeUserEmail))
+					Expect(mailClient.Messages[1].To).To(Equal("work@example.com"))
+				})
+
+				It("records the status of each message under an ID of its own", func() {
+					worker.Deliver(&job)
+
+					Expect(messagesRepo.Messages).To(HaveLen(2))
+
+					message, err := messagesRepo.FindByID(conn, "randomly-generated-guid")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Email).To(Equal(fakeUserEmail))
+					Expect(message.Status).To(Equal(postal.StatusDelivered))
+
+					for id, message := range messagesRepo.Messages {
+						if id == "randomly-generated-guid" {
+							continue
+						}
+
+						Expect(message.Email).To(Equal("work@example.com"))
+						Expect(message.Recipient).To(Equal(userGUID))
+						Expect(message.BatchID).To(Equal("some-batch"))
+						Expect(message.Status).To(Equal(postal.StatusDelivered))
+					}
+				})
+
+				It("does not send to the addresses that were delivered to when the job is retried", func() {
+					mailClient.SendError = errors.New("550 mailbox unavailable")
+					mailClient.SendErrorTo = "work@example.com"
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(1))
+					Expect(job.RetryCount).To(Equal(1))
+
+					mailClient.SendError = nil
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(2))
+					Expect(mailClient.Messages[1].To).To(Equal("work@example.com"))
+					Expect(job.RetryCount).To(Equal(1))
+				})
+			})
+
+			It("delivers to the address the user chose", func() {
+				deliveryAddressesRepo.Addresses["user-123"] = models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "Work@Example.com"}
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].To).To(Equal("work@example.com"))
+			})
+
+			It("falls back to the primary address when the chosen one is gone", func() {
+				deliveryAddressesRepo.Addresses["user-123"] = models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "old@example.com"}
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].To).To(Equal(fakeUserEmail))
+				Expect(buffer.String()).To(ContainSubstring("old@example.com is no longer one of their addresses"))
+			})
+
+			It("skips suppressed addresses but delivers to the others", func() {
+				deliveryAddressesRepo.Addresses["user-123"] = models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressAll}
+				suppressionsRepo.Suppressions["work@example.com"] = models.Suppression{Email: "work@example.com", Reason: "complained"}
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].To).To(Equal(fakeUserEmail))
+			})
+
+			It("retries when the preference cannot be read", func() {
+				deliveryAddressesRepo.GetError = errors.New("database is down")
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(BeEmpty())
+				Expect(job.RetryCount).To(Equal(1))
+			})
+		})
+
+		Context("when the delivery has cc and bcc copies", func() {
+			BeforeEach(func() {
+				delivery.CC = []postal.Recipient{{Email: "copy@example.com", MessageID: "copy-message-id"}}
+				delivery.BCC = []postal.Recipient{{Email: "hidden@example.com", MessageID: "hidden-message-id"}}
+				job = gobble.NewJob(delivery)
+			})
+
+			It("sends a single message that is copied to each address", func() {
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].To).To(Equal(fakeUserEmail))
+				Expect(mailClient.Messages[0].CC).To(Equal([]string{"copy@example.com"}))
+				Expect(mailClient.Messages[0].BCC).To(Equal([]string{"hidden@example.com"}))
+			})
+
+			It("records the status of each copy as a message of its own, along with the job that delivered it", func() {
+				mailClient.Relay = "smtp.example.com:587"
+				job.ID = 42
+				worker.Deliver(&job)
+
+				for _, copy := range []postal.Recipient{delivery.CC[0], delivery.BCC[0]} {
+					message, err := messagesRepo.FindByID(conn, copy.MessageID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Status).To(Equal(postal.StatusDelivered))
+					Expect(message.Recipient).To(Equal(copy.Email))
+					Expect(message.JobID).To(Equal(42))
+
+					events, err := messageEventsRepo.FindAllByMessageID(conn, copy.MessageID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(events).To(HaveLen(1))
+					Expect(events[0].Relay).To(Equal("smtp.example.com:587"))
+				}
+			})
+
+			It("shares the outcome of the delivery with the copies", func() {
+				mailClient.SendError = errors.New("BOOM!")
+				worker.Deliver(&job)
+
+				message, err := messagesRepo.FindByID(conn, "hidden-message-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Status).To(Equal(postal.StatusFailed))
+			})
+
+			It("marks only the copies the server refused undeliverable", func() {
+				mailClient.RecipientErrors = map[string]error{
+					"copy@example.com": errors.New("550 5.1.1 User unknown"),
+				}
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(job.RetryCount).To(Equal(0))
+
+				message, err := messagesRepo.FindByID(conn, "copy-message-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+
+				events, err := messageEventsRepo.FindAllByMessageID(conn, "copy-message-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Error).To(ContainSubstring("User unknown"))
+
+				for _, messageID := range []string{getMessageIDFromJob(job), "hidden-message-id"} {
+					message, err := messagesRepo.FindByID(conn, messageID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Status).To(Equal(postal.StatusDelivered))
+
+					events, err := messageEventsRepo.FindAllByMessageID(conn, messageID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(events[0].Error).To(BeEmpty())
+				}
+			})
+
+			Context("when the address of the delivery is suppressed", func() {
+				BeforeEach(func() {
+					suppressionsRepo.Suppressions[fakeUserEmail] = models.Suppression{Email: fakeUserEmail, Reason: "bounced"}
+				})
+
+				It("still delivers the copies, leaving the address out of the envelope", func() {
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(1))
+					Expect(mailClient.Messages[0].OmitTo).To(BeTrue())
+					Expect(mailClient.Messages[0].Recipients()).To(Equal([]string{"copy@example.com", "hidden@example.com"}))
+				})
+
+				It("marks only the message to the address undeliverable", func() {
+					worker.Deliver(&job)
+
+					message, err := messagesRepo.FindByID(conn, getMessageIDFromJob(job))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+
+					for _, messageID := range []string{"copy-message-id", "hidden-message-id"} {
+						message, err := messagesRepo.FindByID(conn, messageID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(message.Status).To(Equal(postal.StatusDelivered))
+
+						events, err := messageEventsRepo.FindAllByMessageID(conn, messageID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(events).To(HaveLen(1))
+						Expect(events[0].Error).To(BeEmpty())
+					}
+				})
+			})
+
+			Context("when the user has unsubscribed", func() {
+				BeforeEach(func() {
+					_, err := unsubscribesRepo.Create(conn, models.Unsubscribe{
+						UserID:   userGUID,
+						ClientID: "some-client",
+						KindID:   "some-kind",
+					})
+					if err != nil {
+						panic(err)
+					}
+				})
+
+				It("still delivers the copies", func() {
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(1))
+					Expect(mailClient.Messages[0].OmitTo).To(BeTrue())
+
+					message, err := messagesRepo.FindByID(conn, getMessageIDFromJob(job))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+
+					message, err = messagesRepo.FindByID(conn, "copy-message-id")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.Status).To(Equal(postal.StatusDelivered))
+				})
+			})
+
+			It("drops suppressed copies and marks them undeliverable", func() {
+				suppressionsRepo.Suppressions["copy@example.com"] = models.Suppression{Email: "copy@example.com", Reason: "complained"}
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(HaveLen(1))
+				Expect(mailClient.Messages[0].CC).To(BeEmpty())
+				Expect(mailClient.Messages[0].BCC).To(Equal([]string{"hidden@example.com"}))
+
+				message, err := messagesRepo.FindByID(conn, "copy-message-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+
+				events, err := messageEventsRepo.FindAllByMessageID(conn, "copy-message-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Error).To(ContainSubstring("copy@example.com is suppressed: complained"))
+
+				message, err = messagesRepo.FindByID(conn, getMessageIDFromJob(job))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Status).To(Equal(postal.StatusDelivered))
+			})
+		})
+
+		Context("when the recipient's address is suppressed", func() {
+			BeforeEach(func() {
+				_, err := suppressionsRepo.Upsert(conn, models.Suppression{
+					Email:  fakeUserEmail,
+					Reason: "5.1.1 smtp; 550 No such user",
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				_, err = kindsRepo.Create(conn, models.Kind{
+					ID:       "some-kind",
+					ClientID: "some-client",
+					Critical: true,
+				})
+				if err != nil {
+					panic(err)
+				}
+			})
+
+			It("does not send the message, even when it is critical", func() {
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(BeEmpty())
+				Expect(buffer.String()).To(ContainSubstring("Not delivering because user-123@example.com is suppressed"))
+			})
+
+			It("marks the message undeliverable and records why", func() {
+				worker.Deliver(&job)
+
+				message, err := messagesRepo.FindByID(conn, getMessageIDFromJob(job))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+
+				Expect(messageEventsRepo.Events).To(HaveLen(1))
+				Expect(messageEventsRepo.Events[0].Error).To(ContainSubstring("550 No such user"))
+				Expect(job.RetryCount).To(Equal(0))
+			})
+
+			It("does not send mail addressed directly to the address", func() {
+				delivery.UserGUID = ""
+				delivery.Email = "User-123@Example.com"
+				job = gobble.NewJob(delivery)
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(BeEmpty())
+				Expect(job.RetryCount).To(Equal(0))
+			})
+
+			It("retries when the suppression list cannot be read", func() {
+				suppressionsRepo.FindError = errors.New("database is down")
+
+				worker.Deliver(&job)
+
+				Expect(mailClient.Messages).To(BeEmpty())
+				Expect(job.RetryCount).To(Equal(1))
 			})
 		})
 
@@ -364,6 +789,15 @@ var _ = Describe("DeliveryWorker", func() {
 			It("does not send any non-critical notifications", func() {
 				Expect(mailClient.Messages).To(HaveLen(0))
 			})
+
+			It("upserts the StatusUndeliverable to the database", func() {
+				message, err := messagesRepo.FindByID(conn, getMessageIDFromJob(job))
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(message.Status).To(Equal(postal.StatusUndeliverable))
+			})
 		})
 
 		Context("when the recipient hasn't unsubscribed, but doesn't have a valid email address", func() {
@@ -453,6 +887,18 @@ var _ = Describe("DeliveryWorker", func() {
 
 					Expect(len(mailClient.Messages)).To(Equal(1))
 				})
+
+				It("does not offer a way to unsubscribe", func() {
+					unsubscribeConfig.PublicURL = "https://notifications.example.com"
+					unsubscribeConfig.Mailto = "unsubscribe@example.com"
+					worker = postal.NewDeliveryWorker(id, logger, &mailClient, queue, globalUnsubscribesRepo, unsubscribesRepo, suppressionsRepo, deliveryAddressesRepo, kindsRepo,
+						messagesRepo, messageEventsRepo, database, "from@email.com", bounceDomain, []byte("0123456789abcdef"), unsubscribeConfig, userLoader, templateLoader, receiptsRepo, tokenLoader, expander)
+
+					worker.Deliver(&job)
+
+					Expect(mailClient.Messages).To(HaveLen(1))
+					Expect(mailClient.Messages[0].Headers).NotTo(ContainElement(MatchRegexp("^List-Unsubscribe")))
+				})
 			})
 		})
 
@@ -521,6 +967,34 @@ var _ = Describe("DeliveryWorker", func() {
 			})
 		})
 
+		Context("when the job is an expansion", func() {
+			BeforeEach(func() {
+				job = gobble.NewJob(postal.Expansion{
+					CampaignID: "some-campaign",
+					Audience:   "space",
+					GUID:       "space-001",
+					ClientID:   "some-client",
+				})
+			})
+
+			It("hands the job to the expander instead of delivering it", func() {
+				worker.Deliver(&job)
+
+				Expect(expander.ExpandArguments).To(Equal([]gobble.Job{job}))
+				Expect(mailClient.Messages).To(BeEmpty())
+				Expect(job.ShouldRetry).To(BeFalse())
+			})
+
+			It("marks the job for retry when the expansion fails", func() {
+				expander.ExpandError = errors.New("CC is down")
+
+				worker.Deliver(&job)
+
+				Expect(job.ShouldRetry).To(BeTrue())
+				Expect(job.RetryCount).To(Equal(1))
+			})
+		})
+
 		Context("when the message status fails to be upserted into the db", func() {
 			It("logs the failure", func() {
 				messagesRepo.UpsertError = errors.New("An unforseen error in upserting to our db")
@@ -1,8 +1,11 @@
 package postal
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pivotal-cf/uaa-sso-golang/uaa"
@@ -22,12 +25,70 @@ func (err UAAUserNotFoundError) Error() string {
 	return string(err)
 }
 
+type MessageNotCancelableError string
+
+func (err MessageNotCancelableError) Error() string {
+	return string(err)
+}
+
+type RecipientSuppressedError string
+
+func (err RecipientSuppressedError) Error() string {
+	return string(err)
+}
+
+type TestSendError string
+
+func (err TestSendError) Error() string {
+	return string(err)
+}
+
 type TemplateLoadError string
 
 func (err TemplateLoadError) Error() string {
 	return string(err)
 }
 
+// TemplateError reports a template that could not be parsed or executed,
+// naming the part of the message it belongs to ("subject", "endorsement",
+// "text", "html" or "wrapper") and, when known, where in that part it failed.
+type TemplateError struct {
+	Part    string `json:"part"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+var templateErrorFormat = regexp.MustCompile(`^(?:html/)?template: ?[^:]+:(?:(\d+)(?::(\d+))?: )?(.*)$`)
+
+func newTemplateError(part string, err error) TemplateError {
+	templateErr := TemplateError{
+		Part:    part,
+		Message: err.Error(),
+	}
+
+	matches := templateErrorFormat.FindStringSubmatch(err.Error())
+	if matches != nil {
+		templateErr.Line, _ = strconv.Atoi(matches[1])
+		templateErr.Column, _ = strconv.Atoi(matches[2])
+		templateErr.Message = matches[3]
+	}
+
+	return templateErr
+}
+
+func (err TemplateError) Error() string {
+	location := err.Part
+	if err.Line != 0 {
+		location += fmt.Sprintf(":%d", err.Line)
+	}
+	if err.Column != 0 {
+		location += fmt.Sprintf(":%d", err.Column)
+	}
+
+	return fmt.Sprintf("template %s: %s", location, err.Message)
+}
+
 type CriticalNotificationError struct {
 	kindID string
 }
@@ -0,0 +1,31 @@
+package postal
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+// Expansion is the payload of a job that resolves the recipients of a
+// notification sent to a space, organization, UAA scope or everyone, and
+// enqueues a Delivery for each of them.
+type Expansion struct {
+	CampaignID string
+	Audience   string
+	GUID       string
+	ClientID   string
+	Options    Options
+}
+
+type ExpanderInterface interface {
+	Expand(*gobble.Job) error
+}
+
+// IsExpansion reports whether the job carries an Expansion rather than a
+// Delivery. Only expansions are given an audience.
+func IsExpansion(job gobble.Job) bool {
+	var expansion Expansion
+
+	err := job.Unmarshal(&expansion)
+	if err != nil {
+		return false
+	}
+
+	return expansion.Audience != ""
+}
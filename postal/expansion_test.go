@@ -0,0 +1,30 @@
+package postal_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IsExpansion", func() {
+	It("is true for jobs that carry an expansion", func() {
+		job := gobble.NewJob(postal.Expansion{
+			CampaignID: "campaign-001",
+			Audience:   "space",
+			GUID:       "space-001",
+		})
+
+		Expect(postal.IsExpansion(job)).To(BeTrue())
+	})
+
+	It("is false for jobs that carry a delivery", func() {
+		job := gobble.NewJob(postal.Delivery{
+			UserGUID:  "user-123",
+			MessageID: "message-001",
+		})
+
+		Expect(postal.IsExpansion(job)).To(BeFalse())
+	})
+})
@@ -0,0 +1,64 @@
+package postal
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+type ArchivedMessageEvent struct {
+	Status    string    `json:"status"`
+	Attempt   int       `json:"attempt"`
+	WorkerID  string    `json:"worker_id"`
+	Relay     string    `json:"relay"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ArchivedMessage struct {
+	ID        string                 `json:"id"`
+	ClientID  string                 `json:"client_id"`
+	BatchID   string                 `json:"batch_id"`
+	Recipient string                 `json:"recipient"`
+	Status    string                 `json:"status"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Events    []ArchivedMessageEvent `json:"events"`
+}
+
+type MessageArchiverInterface interface {
+	Archive([]ArchivedMessage) error
+}
+
+// NDJSONArchiver appends each archived message to a file as a single line of
+// JSON, so that the archive can be streamed and concatenated.
+type NDJSONArchiver struct {
+	path string
+}
+
+func NewNDJSONArchiver(path string) NDJSONArchiver {
+	return NDJSONArchiver{
+		path: path,
+	}
+}
+
+func (archiver NDJSONArchiver) Archive(messages []ArchivedMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(archiver.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, message := range messages {
+		err = encoder.Encode(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,79 @@
+package postal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NDJSONArchiver", func() {
+	var archiver postal.NDJSONArchiver
+	var dir string
+	var path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "message-archive")
+		if err != nil {
+			panic(err)
+		}
+
+		path = filepath.Join(dir, "messages.ndjson")
+		archiver = postal.NewNDJSONArchiver(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("appends each message to the file as a line of JSON", func() {
+		updatedAt := time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC)
+
+		err := archiver.Archive([]postal.ArchivedMessage{
+			{
+				ID:        "message-1",
+				ClientID:  "some-client",
+				BatchID:   "some-batch",
+				Recipient: "user-1",
+				Status:    postal.StatusDelivered,
+				UpdatedAt: updatedAt,
+				Events: []postal.ArchivedMessageEvent{
+					{Status: postal.StatusDelivered, Attempt: 1, WorkerID: "worker-1", Relay: "smtp.example.com:587", CreatedAt: updatedAt},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = archiver.Archive([]postal.ArchivedMessage{
+			{ID: "message-2", Status: postal.StatusFailed, UpdatedAt: updatedAt, Events: []postal.ArchivedMessageEvent{}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal(`{"id":"message-1","client_id":"some-client","batch_id":"some-batch","recipient":"user-1","status":"delivered","updated_at":"2015-03-04T12:30:00Z","events":[{"status":"delivered","attempt":1,"worker_id":"worker-1","relay":"smtp.example.com:587","error":"","created_at":"2015-03-04T12:30:00Z"}]}
+{"id":"message-2","client_id":"","batch_id":"","recipient":"","status":"failed","updated_at":"2015-03-04T12:30:00Z","events":[]}
+`))
+	})
+
+	It("does not create the file when there is nothing to archive", func() {
+		err := archiver.Archive([]postal.ArchivedMessage{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("returns an error when the file cannot be opened", func() {
+		archiver = postal.NewNDJSONArchiver(filepath.Join(dir, "missing", "messages.ndjson"))
+
+		err := archiver.Archive([]postal.ArchivedMessage{{ID: "message-1"}})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,99 @@
+package postal
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type MessageCanceler struct {
+	queue             gobble.QueueInterface
+	messagesRepo      messageCancelerRepoInterface
+	messageEventsRepo models.MessageEventsRepoInterface
+	database          models.DatabaseInterface
+}
+
+type messageCancelerRepoInterface interface {
+	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	FindAllByJobID(models.ConnectionInterface, int) ([]models.Message, error)
+	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
+}
+
+func NewMessageCanceler(queue gobble.QueueInterface, messagesRepo messageCancelerRepoInterface,
+	messageEventsRepo models.MessageEventsRepoInterface, database models.DatabaseInterface) MessageCanceler {
+
+	return MessageCanceler{
+		queue:             queue,
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
+		database:          database,
+	}
+}
+
+// Cancel removes the job for a scheduled message from the queue, as long as
+// no worker has picked it up yet, and marks the message as canceled. The CC
+// and BCC copies of the message share its job, so they are canceled with it.
+// The job is removed and the messages are updated in a single transaction.
+// Messages sent by other clients are reported as not found.
+func (canceler MessageCanceler) Cancel(clientID, messageID string) error {
+	transaction := canceler.database.Connection().Transaction()
+	transaction.Begin()
+
+	message, err := canceler.messagesRepo.FindByID(transaction, messageID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if message.ClientID != clientID {
+		transaction.Rollback()
+		return models.NewRecordNotFoundError("Message with ID %q could not be found", messageID)
+	}
+
+	if message.Status != StatusScheduled || message.JobID == 0 {
+		transaction.Rollback()
+		return MessageNotCancelableError(fmt.Sprintf("Message %q is %s and cannot be canceled", messageID, message.Status))
+	}
+
+	err = canceler.queue.CancelWithTransaction(message.JobID, transaction)
+	if err != nil {
+		transaction.Rollback()
+		if _, ok := err.(gobble.JobNotCancelableError); ok {
+			return MessageNotCancelableError(fmt.Sprintf("Message %q is already being delivered and cannot be canceled", messageID))
+		}
+		return err
+	}
+
+	messages, err := canceler.messagesRepo.FindAllByJobID(transaction, message.JobID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	for _, message := range messages {
+		message.Status = StatusCanceled
+		message.JobID = 0
+		_, err = canceler.messagesRepo.Upsert(transaction, message)
+		if err != nil {
+			transaction.Rollback()
+			return err
+		}
+
+		_, err = canceler.messageEventsRepo.Create(transaction, models.MessageEvent{
+			MessageID: message.ID,
+			Status:    StatusCanceled,
+		})
+		if err != nil {
+			transaction.Rollback()
+			return err
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return models.NewTransactionCommitError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package postal_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageCanceler", func() {
+	var canceler postal.MessageCanceler
+	var queue *fakes.Queue
+	var repo *fakes.MessagesRepo
+	var eventsRepo *fakes.MessageEventsRepo
+	var database *fakes.Database
+
+	BeforeEach(func() {
+		queue = fakes.NewQueue()
+		repo = fakes.NewMessagesRepo()
+		repo.Messages["message-id"] = models.Message{
+			ID:       "message-id",
+			ClientID: "my-client",
+			Status:   postal.StatusScheduled,
+			JobID:    42,
+		}
+
+		eventsRepo = fakes.NewMessageEventsRepo()
+		database = fakes.NewDatabase()
+		canceler = postal.NewMessageCanceler(queue, repo, eventsRepo, database)
+	})
+
+	It("removes the job from the queue and marks the message as canceled", func() {
+		err := canceler.Cancel("my-client", "message-id")
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(queue.CancelIDs).To(Equal([]int{42}))
+		Expect(repo.Messages["message-id"].Status).To(Equal(postal.StatusCanceled))
+	})
+
+	It("removes the job and updates the messages in a single transaction", func() {
+		err := canceler.Cancel("my-client", "message-id")
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(queue.CancelTransactions).To(Equal([]gobble.ConnectionInterface{database.Conn}))
+		Expect(database.Conn.BeginWasCalled).To(BeTrue())
+		Expect(database.Conn.CommitWasCalled).To(BeTrue())
+		Expect(database.Conn.RollbackWasCalled).To(BeFalse())
+	})
+
+	It("rolls back the removal of the job when a message cannot be updated", func() {
+		repo.UpsertError = errors.New("database is down")
+
+		err := canceler.Cancel("my-client", "message-id")
+		Expect(err).To(MatchError("database is down"))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		Expect(database.Conn.CommitWasCalled).To(BeFalse())
+	})
+
+	It("records the cancelation in the history of the message", func() {
+		err := canceler.Cancel("my-client", "message-id")
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(eventsRepo.Events).To(HaveLen(1))
+		Expect(eventsRepo.Events[0].MessageID).To(Equal("message-id"))
+		Expect(eventsRepo.Events[0].Status).To(Equal(postal.StatusCanceled))
+	})
+
+	It("cancels the copies of the message along with it", func() {
+		repo.Messages["copy-message-id"] = models.Message{
+			ID:       "copy-message-id",
+			ClientID: "my-client",
+			Status:   postal.StatusScheduled,
+			JobID:    42,
+		}
+
+		err := canceler.Cancel("my-client", "copy-message-id")
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(queue.CancelIDs).To(Equal([]int{42}))
+		Expect(repo.Messages["message-id"].Status).To(Equal(postal.StatusCanceled))
+		Expect(repo.Messages["copy-message-id"].Status).To(Equal(postal.StatusCanceled))
+		Expect(eventsRepo.Events).To(HaveLen(2))
+	})
+
+	It("returns errors finding the copies of the message", func() {
+		repo.FindAllByJobIDError = errors.New("database is down")
+
+		err := canceler.Cancel("my-client", "message-id")
+		Expect(err).To(MatchError("database is down"))
+	})
+
+	It("returns an error when the message cannot be found", func() {
+		err := canceler.Cancel("my-client", "missing-message-id")
+		Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+	})
+
+	It("returns a RecordNotFoundError when the message was sent by another client", func() {
+		err := canceler.Cancel("other-client", "message-id")
+		Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		Expect(queue.CancelIDs).To(BeEmpty())
+		Expect(repo.Messages["message-id"].Status).To(Equal(postal.StatusScheduled))
+	})
+
+	It("returns a MessageNotCancelableError when the message is not scheduled", func() {
+		repo.Messages["message-id"] = models.Message{
+			ID:       "message-id",
+			ClientID: "my-client",
+			Status:   postal.StatusDelivered,
+		}
+
+		err := canceler.Cancel("my-client", "message-id")
+		Expect(err).To(BeAssignableToTypeOf(postal.MessageNotCancelableError("")))
+		Expect(queue.CancelIDs).To(BeEmpty())
+	})
+
+	It("returns a MessageNotCancelableError when a worker has already reserved the job", func() {
+		queue.CancelError = gobble.JobNotCancelableError{JobID: 42}
+
+		err := canceler.Cancel("my-client", "message-id")
+		Expect(err).To(BeAssignableToTypeOf(postal.MessageNotCancelableError("")))
+		Expect(repo.Messages["message-id"].Status).To(Equal(postal.StatusScheduled))
+	})
+
+	It("returns other queue errors as they are", func() {
+		queue.CancelError = errors.New("BOOM!")
+
+		err := canceler.Cancel("my-client", "message-id")
+		Expect(err).To(Equal(queue.CancelError))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+	})
+})
@@ -1,11 +1,19 @@
 package postal
 
 import (
-	"html"
+	"net/url"
+	"strings"
 
 	"github.com/pivotal-golang/conceal"
 )
 
+// UnsubscribeConfig describes where recipients can unsubscribe: the public URL
+// of this service and, optionally, a mailbox that accepts unsubscribe requests.
+type UnsubscribeConfig struct {
+	PublicURL string
+	Mailto    string
+}
+
 type MessageContext struct {
 	From              string
 	ReplyTo           string
@@ -27,12 +35,15 @@ type MessageContext struct {
 	Organization      string
 	OrganizationGUID  string
 	UnsubscribeID     string
+	UnsubscribeURL    string
+	UnsubscribeMailto string
 	Scope             string
 	Endorsement       string
 	OrganizationRole  string
+	Data              map[string]interface{}
 }
 
-func NewMessageContext(delivery Delivery, sender string, cloak conceal.CloakInterface, templates Templates) MessageContext {
+func NewMessageContext(delivery Delivery, sender string, cloak conceal.CloakInterface, templates Templates, unsubscribe UnsubscribeConfig) MessageContext {
 	options := delivery.Options
 
 	var kindDescription string
@@ -72,6 +83,7 @@ func NewMessageContext(delivery Delivery, sender string, cloak conceal.CloakInte
 		Scope:             delivery.Scope,
 		Endorsement:       options.Endorsement,
 		OrganizationRole:  options.Role,
+		Data:              mergeData(options.Data, delivery.Data),
 	}
 
 	if messageContext.Subject == "" {
@@ -84,20 +96,35 @@ func NewMessageContext(delivery Delivery, sender string, cloak conceal.CloakInte
 	}
 
 	messageContext.UnsubscribeID = string(unsubscribeID)
+
+	if delivery.UserGUID != "" && options.KindID != "" {
+		if unsubscribe.PublicURL != "" {
+			messageContext.UnsubscribeURL = strings.TrimSuffix(unsubscribe.PublicURL, "/") + "/unsubscribe/" + messageContext.UnsubscribeID
+		}
+
+		if unsubscribe.Mailto != "" {
+			messageContext.UnsubscribeMailto = "mailto:" + unsubscribe.Mailto + "?subject=" + url.QueryEscape("unsubscribe "+messageContext.UnsubscribeID)
+		}
+	}
+
 	return messageContext
 }
 
-func (context *MessageContext) Escape() {
-	context.From = html.EscapeString(context.From)
-	context.To = html.EscapeString(context.To)
-	context.ReplyTo = html.EscapeString(context.ReplyTo)
-	context.Subject = html.EscapeString(context.Subject)
-	context.Text = html.EscapeString(context.Text)
-	context.KindDescription = html.EscapeString(context.KindDescription)
-	context.SourceDescription = html.EscapeString(context.SourceDescription)
-	context.ClientID = html.EscapeString(context.ClientID)
-	context.MessageID = html.EscapeString(context.MessageID)
-	context.Space = html.EscapeString(context.Space)
-	context.Organization = html.EscapeString(context.Organization)
-	context.Endorsement = html.EscapeString(context.Endorsement)
+// mergeData combines the data given for the whole send with the data given for
+// a single recipient, which takes precedence.
+func mergeData(sendData, recipientData map[string]interface{}) map[string]interface{} {
+	if sendData == nil && recipientData == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{}
+	for key, value := range sendData {
+		data[key] = value
+	}
+
+	for key, value := range recipientData {
+		data[key] = value
+	}
+
+	return data
 }
@@ -67,7 +67,7 @@ var _ = Describe("MessageContext", func() {
 
 	Describe("NewMessageContext", func() {
 		It("returns the appropriate MessageContext when all options are specified", func() {
-			context := postal.NewMessageContext(delivery, sender, cloak, templates)
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
 
 			Expect(context.From).To(Equal(sender))
 			Expect(context.ReplyTo).To(Equal(options.ReplyTo))
@@ -95,72 +95,75 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.OrganizationRole).To(Equal("OrgRole"))
 		})
 
+		It("gives the templates the data of the delivery", func() {
+			delivery.Data = map[string]interface{}{"invoice_total": "$12.50"}
+
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
+			Expect(context.Data).To(Equal(map[string]interface{}{"invoice_total": "$12.50"}))
+		})
+
+		It("gives the templates the data of the send, overridden by the data of the delivery", func() {
+			delivery.Options.Data = map[string]interface{}{"month": "May", "invoice_total": "$0.00"}
+			delivery.Data = map[string]interface{}{"invoice_total": "$12.50"}
+
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
+			Expect(context.Data).To(Equal(map[string]interface{}{"month": "May", "invoice_total": "$12.50"}))
+			Expect(delivery.Options.Data["invoice_total"]).To(Equal("$0.00"))
+		})
+
 		It("falls back to Kind if KindDescription is missing", func() {
 			delivery.Options.KindDescription = ""
-			context := postal.NewMessageContext(delivery, sender, cloak, templates)
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
 
 			Expect(context.KindDescription).To(Equal("the-kind-id"))
 		})
 
 		It("falls back to clientID when SourceDescription is missing", func() {
 			delivery.Options.SourceDescription = ""
-			context := postal.NewMessageContext(delivery, sender, cloak, templates)
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
 
 			Expect(context.SourceDescription).To(Equal("the-client-id"))
 		})
 
-		It("fills in subject when subject is not specified", func() {
-			delivery.Options.Subject = ""
-			context := postal.NewMessageContext(delivery, sender, cloak, templates)
-			Expect(context.Subject).To(Equal("[no subject]"))
+		It("builds an unsubscribe URL when the public URL is known", func() {
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{PublicURL: "https://notifications.example.com/"})
+
+			Expect(context.UnsubscribeURL).To(Equal("https://notifications.example.com/unsubscribe/the-encoded-result"))
 		})
-	})
 
-	Describe("Escape", func() {
-		BeforeEach(func() {
-			options = postal.Options{
-				ReplyTo:           "awesomeness",
-				Subject:           "the & subject",
-				KindDescription:   "the & kind description",
-				SourceDescription: "the & source description",
-				Text:              "user & supplied email text",
-				HTML:              postal.HTML{BodyContent: "user & supplied html"},
-				KindID:            "the & kind",
-				Endorsement:       "this & is the endorsement",
-				Role:              "OrgRole",
-			}
-
-			delivery.Options = options
-			delivery.ClientID = "the\"client id"
-			delivery.MessageID = "some>id"
-			delivery.Space.Name = "the<space"
-			delivery.Organization.Name = "the>org"
-			delivery.Scope = ""
+		It("builds an unsubscribe mailto link when an unsubscribe mailbox is configured", func() {
+			cloak.EncryptedResult = []byte("the-encoded=")
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{Mailto: "unsubscribe@example.com"})
+
+			Expect(context.UnsubscribeURL).To(BeEmpty())
+			Expect(context.UnsubscribeMailto).To(Equal("mailto:unsubscribe@example.com?subject=unsubscribe+the-encoded%3D"))
 		})
 
-		It("html escapes various fields on the message context", func() {
-			context := postal.NewMessageContext(delivery, sender, cloak, templates)
-			context.Escape()
-
-			Expect(context.From).To(Equal("no-reply@notifications.example.com"))
-			Expect(context.ReplyTo).To(Equal("awesomeness"))
-			Expect(context.To).To(Equal("bounce@example.com"))
-			Expect(context.Subject).To(Equal("the &amp; subject"))
-			Expect(context.Text).To(Equal("user &amp; supplied email text"))
-			Expect(context.HTML).To(Equal("user & supplied html"))
-			Expect(context.TextTemplate).To(Equal("the plainText email < template"))
-			Expect(context.HTMLTemplate).To(Equal("the html <h1> email < template</h1>"))
-			Expect(context.SubjectTemplate).To(Equal("the subject < template"))
-			Expect(context.KindDescription).To(Equal("the &amp; kind description"))
-			Expect(context.SourceDescription).To(Equal("the &amp; source description"))
-			Expect(context.UserGUID).To(Equal("the-user"))
-			Expect(context.ClientID).To(Equal("the&#34;client id"))
-			Expect(context.MessageID).To(Equal("some&gt;id"))
-			Expect(context.Space).To(Equal("the&lt;space"))
-			Expect(context.Organization).To(Equal("the&gt;org"))
-			Expect(context.Scope).To(Equal(""))
-			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
-			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+		It("leaves the unsubscribe URL empty when the public URL is not known", func() {
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
+
+			Expect(context.UnsubscribeURL).To(BeEmpty())
+		})
+
+		It("leaves the unsubscribe URL empty when there is no user to unsubscribe", func() {
+			delivery.UserGUID = ""
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{PublicURL: "https://notifications.example.com", Mailto: "unsubscribe@example.com"})
+
+			Expect(context.UnsubscribeURL).To(BeEmpty())
+			Expect(context.UnsubscribeMailto).To(BeEmpty())
+		})
+
+		It("leaves the unsubscribe URL empty when there is no kind to unsubscribe from", func() {
+			delivery.Options.KindID = ""
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{PublicURL: "https://notifications.example.com", Mailto: "unsubscribe@example.com"})
+
+			Expect(context.UnsubscribeURL).To(BeEmpty())
+		})
+
+		It("fills in subject when subject is not specified", func() {
+			delivery.Options.Subject = ""
+			context := postal.NewMessageContext(delivery, sender, cloak, templates, postal.UnsubscribeConfig{})
+			Expect(context.Subject).To(Equal("[no subject]"))
 		})
 	})
 })
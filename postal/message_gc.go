@@ -2,42 +2,184 @@ package postal
 
 import (
 	"log"
+	"sort"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
 )
 
+// CollectionPageSize is the number of expired messages that are archived and
+// deleted at a time, so that collecting a large backlog does not hold it all
+// in memory.
+const CollectionPageSize = 500
+
+// MessageRetention describes how long message statuses are kept after their
+// last update. Clients listed in ClientLifetimes are kept for their own
+// lifetime instead of the default one.
+type MessageRetention struct {
+	Lifetime        time.Duration
+	ClientLifetimes map[string]time.Duration
+}
+
 type MessageGC struct {
-	messagesRepo    messagesRepoInterface
-	db              models.DatabaseInterface
-	lifetime        time.Duration
-	logger          *log.Logger
-	timer           <-chan time.Time
-	pollingInterval time.Duration
+	messagesRepo      messagesRepoInterface
+	messageEventsRepo messageEventsRepoInterface
+	archiver          MessageArchiverInterface
+	db                models.DatabaseInterface
+	retention         MessageRetention
+	logger            *log.Logger
+	timer             <-chan time.Time
+	pollingInterval   time.Duration
 }
 
 type messagesRepoInterface interface {
-	DeleteBefore(models.ConnectionInterface, time.Time) (int, error)
+	FindAllBefore(models.ConnectionInterface, time.Time, models.MessageScope, models.Message, int) ([]models.Message, error)
+	DeleteByIDs(models.ConnectionInterface, []string) (int, error)
 }
 
-func NewMessageGC(lifetime time.Duration, db models.DatabaseInterface,
-	messagesRepo messagesRepoInterface, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+type messageEventsRepoInterface interface {
+	FindAllByMessageIDs(models.ConnectionInterface, []string) ([]models.MessageEvent, error)
+	DeleteByMessageIDs(models.ConnectionInterface, []string) (int, error)
+}
+
+// NewMessageGC builds a collector for expired message statuses. The archiver
+// may be nil, in which case expired statuses are deleted without being
+// archived first.
+func NewMessageGC(retention MessageRetention, db models.DatabaseInterface,
+	messagesRepo messagesRepoInterface, messageEventsRepo messageEventsRepoInterface,
+	archiver MessageArchiverInterface, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+
 	return MessageGC{
-		messagesRepo:    messagesRepo,
-		db:              db,
-		lifetime:        lifetime,
-		logger:          logger,
-		pollingInterval: pollingInterval,
-		timer:           time.After(0),
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
+		archiver:          archiver,
+		db:                db,
+		retention:         retention,
+		logger:            logger,
+		pollingInterval:   pollingInterval,
+		timer:             time.After(0),
 	}
 }
 
 func (gc MessageGC) Collect() {
-	threshold := time.Now().Add(-1 * gc.lifetime)
-	_, err := gc.messagesRepo.DeleteBefore(gc.db.Connection(), threshold)
+	now := time.Now()
+	conn := gc.db.Connection()
+
+	var clientIDs []string
+	for clientID := range gc.retention.ClientLifetimes {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	for _, clientID := range clientIDs {
+		threshold := now.Add(-1 * gc.retention.ClientLifetimes[clientID])
+		gc.collect(conn, threshold, models.MessageScope{ClientID: clientID})
+	}
+
+	threshold := now.Add(-1 * gc.retention.Lifetime)
+	gc.collect(conn, threshold, models.MessageScope{ExcludedClientIDs: clientIDs})
+}
+
+// collect pages through the expired messages. Each page is archived, and then
+// deleted along with the history of its messages, in a transaction that keeps
+// the messages locked from when they are found until they are deleted.
+func (gc MessageGC) collect(conn models.ConnectionInterface, threshold time.Time, scope models.MessageScope) {
+	after := models.Message{}
+	for {
+		messages, err := gc.collectPage(conn, threshold, scope, after)
+		if err != nil {
+			gc.logger.Printf("MessageGC.Collect() failed, leaving the messages in place: %s", err)
+			return
+		}
+
+		if len(messages) < CollectionPageSize {
+			return
+		}
+
+		after = messages[len(messages)-1]
+	}
+}
+
+func (gc MessageGC) collectPage(conn models.ConnectionInterface, threshold time.Time, scope models.MessageScope, after models.Message) ([]models.Message, error) {
+	transaction := conn.Transaction()
+	transaction.Begin()
+
+	messages, err := gc.messagesRepo.FindAllBefore(transaction, threshold, scope, after, CollectionPageSize)
+	if err != nil {
+		transaction.Rollback()
+		return messages, err
+	}
+
+	if len(messages) == 0 {
+		transaction.Rollback()
+		return messages, nil
+	}
+
+	var messageIDs []string
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+
+	if gc.archiver != nil {
+		err = gc.archivePage(transaction, messages, messageIDs)
+		if err != nil {
+			transaction.Rollback()
+			return messages, err
+		}
+	}
+
+	_, err = gc.messageEventsRepo.DeleteByMessageIDs(transaction, messageIDs)
+	if err != nil {
+		transaction.Rollback()
+		return messages, err
+	}
+
+	_, err = gc.messagesRepo.DeleteByIDs(transaction, messageIDs)
 	if err != nil {
-		gc.logger.Printf("MessageGC.Collect() failed: " + err.Error())
+		transaction.Rollback()
+		return messages, err
 	}
+
+	return messages, transaction.Commit()
+}
+
+func (gc MessageGC) archivePage(conn models.ConnectionInterface, messages []models.Message, messageIDs []string) error {
+	events, err := gc.messageEventsRepo.FindAllByMessageIDs(conn, messageIDs)
+	if err != nil {
+		return err
+	}
+
+	archivedEvents := map[string][]ArchivedMessageEvent{}
+	for _, event := range events {
+		archivedEvents[event.MessageID] = append(archivedEvents[event.MessageID], ArchivedMessageEvent{
+			Status:    event.Status,
+			Attempt:   event.Attempt,
+			WorkerID:  event.WorkerID,
+			Relay:     event.Relay,
+			Error:     event.Error,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	archived := []ArchivedMessage{}
+	for _, message := range messages {
+		messageEvents := archivedEvents[message.ID]
+		if messageEvents == nil {
+			messageEvents = []ArchivedMessageEvent{}
+		}
+
+		archived = append(archived, ArchivedMessage{
+			ID:        message.ID,
+			ClientID:  message.ClientID,
+			BatchID:   message.BatchID,
+			Recipient: message.Recipient,
+			Status:    message.Status,
+			UpdatedAt: message.UpdatedAt,
+			Events:    messageEvents,
+		})
+	}
+
+	return gc.archiver.Archive(archived)
 }
 
 func (gc MessageGC) Run() {
@@ -3,6 +3,7 @@ package postal_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -17,12 +18,15 @@ import (
 var _ = Describe("MessageGC", func() {
 	var messageGC postal.MessageGC
 	var repo *fakes.MessagesRepo
+	var eventsRepo *fakes.MessageEventsRepo
+	var archiver *fakes.MessageArchiver
 	var oldMessageID string
 	var newMessageID string
 	var database *fakes.Database
 	var conn models.ConnectionInterface
 	var loggerBuffer *bytes.Buffer
 	var lifetime time.Duration
+	var retention postal.MessageRetention
 	var pollingInterval time.Duration
 
 	BeforeEach(func() {
@@ -33,7 +37,10 @@ var _ = Describe("MessageGC", func() {
 		repo = fakes.NewMessagesRepo()
 		lifetime = 2 * time.Minute
 		pollingInterval = 500 * time.Millisecond
-		messageGC = postal.NewMessageGC(lifetime, database, repo, pollingInterval, logger)
+		eventsRepo = fakes.NewMessageEventsRepo()
+		archiver = fakes.NewMessageArchiver()
+		retention = postal.MessageRetention{Lifetime: lifetime}
+		messageGC = postal.NewMessageGC(retention, database, repo, eventsRepo, archiver, pollingInterval, logger)
 		oldMessageID = "that-message"
 		newMessageID = "this-message"
 	})
@@ -43,11 +50,11 @@ var _ = Describe("MessageGC", func() {
 			messageGC.Run()
 
 			Eventually(func() int {
-				return len(repo.DeleteBeforeInvocations)
+				return len(repo.FindAllBeforeTimes)
 			}).Should(BeNumerically(">=", 2))
 
-			call1 := repo.DeleteBeforeInvocations[0]
-			call2 := repo.DeleteBeforeInvocations[1]
+			call1 := repo.FindAllBeforeTimes[0]
+			call2 := repo.FindAllBeforeTimes[1]
 			Expect(call2).To(BeTemporally(">", call1.Add(pollingInterval-50*time.Millisecond)))
 			Expect(call2).To(BeTemporally("<", call1.Add(pollingInterval+50*time.Millisecond)))
 		})
@@ -57,6 +64,8 @@ var _ = Describe("MessageGC", func() {
 		BeforeEach(func() {
 			_, err := repo.Upsert(conn, models.Message{
 				ID:        oldMessageID,
+				ClientID:  "some-client",
+				Status:    postal.StatusDelivered,
 				UpdatedAt: time.Now().Add(-2 * lifetime),
 			})
 			if err != nil {
@@ -65,6 +74,7 @@ var _ = Describe("MessageGC", func() {
 
 			_, err = repo.Upsert(conn, models.Message{
 				ID:        newMessageID,
+				ClientID:  "some-client",
 				UpdatedAt: time.Now(),
 			})
 			if err != nil {
@@ -97,13 +107,190 @@ var _ = Describe("MessageGC", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("keeps scheduled messages until they are sent, however long ago they were scheduled", func() {
+			_, err := repo.Upsert(conn, models.Message{
+				ID:        "scheduled-message",
+				ClientID:  "some-client",
+				Status:    postal.StatusScheduled,
+				UpdatedAt: time.Now().Add(-2 * lifetime),
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			messageGC.Collect()
+
+			_, err = repo.FindByID(conn, "scheduled-message")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("archives the messages and their history before deleting them", func() {
+			createdAt := time.Now().Add(-3 * lifetime).Truncate(time.Second)
+			eventsRepo.Events = []models.MessageEvent{
+				{ID: 1, MessageID: oldMessageID, Status: postal.StatusQueued, CreatedAt: createdAt},
+				{ID: 2, MessageID: oldMessageID, Status: postal.StatusDelivered, Attempt: 1, WorkerID: "worker-1", Relay: "smtp.example.com:587", CreatedAt: createdAt},
+			}
+
+			messageGC.Collect()
+
+			Expect(archiver.Archived).To(HaveLen(1))
+			Expect(archiver.Archived[0].ID).To(Equal(oldMessageID))
+			Expect(archiver.Archived[0].ClientID).To(Equal("some-client"))
+			Expect(archiver.Archived[0].Status).To(Equal(postal.StatusDelivered))
+			Expect(archiver.Archived[0].Events).To(Equal([]postal.ArchivedMessageEvent{
+				{Status: postal.StatusQueued, CreatedAt: createdAt},
+				{Status: postal.StatusDelivered, Attempt: 1, WorkerID: "worker-1", Relay: "smtp.example.com:587", CreatedAt: createdAt},
+			}))
+		})
+
+		It("archives the expired messages a page at a time", func() {
+			for i := 0; i < postal.CollectionPageSize; i++ {
+				_, err := repo.Upsert(conn, models.Message{
+					ID:        fmt.Sprintf("expired-message-%03d", i),
+					ClientID:  "some-client",
+					UpdatedAt: time.Now().Add(-3 * lifetime),
+				})
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			messageGC.Collect()
+
+			Expect(archiver.ArchiveCalls).To(Equal(2))
+			Expect(archiver.Archived).To(HaveLen(postal.CollectionPageSize + 1))
+			Expect(archiver.Archived[0].ID).To(Equal("expired-message-000"))
+			Expect(archiver.Archived[postal.CollectionPageSize].ID).To(Equal(oldMessageID))
+
+			Expect(eventsRepo.FindAllByMessageIDsCalls).To(HaveLen(2))
+			Expect(eventsRepo.FindAllByMessageIDsCalls[0]).To(HaveLen(postal.CollectionPageSize))
+			Expect(eventsRepo.FindAllByMessageIDsCalls[1]).To(Equal([]string{oldMessageID}))
+		})
+
+		It("deletes messages without archiving them when there is no archiver", func() {
+			messageGC = postal.NewMessageGC(retention, database, repo, eventsRepo, nil, pollingInterval, log.New(loggerBuffer, "", 0))
+
+			messageGC.Collect()
+
+			_, err := repo.FindByID(conn, oldMessageID)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
+		Context("when the archiver fails", func() {
+			BeforeEach(func() {
+				archiver.ArchiveError = errors.New("disk is full")
+			})
+
+			It("leaves the messages in place", func() {
+				messageGC.Collect()
+
+				_, err := repo.FindByID(conn, oldMessageID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+				Expect(database.Conn.CommitWasCalled).To(BeFalse())
+			})
+
+			It("logs the error", func() {
+				messageGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring("disk is full"))
+			})
+		})
+
+		Context("when a client has its own lifetime", func() {
+			BeforeEach(func() {
+				_, err := repo.Upsert(conn, models.Message{
+					ID:        "compliance-message",
+					ClientID:  "compliance-client",
+					UpdatedAt: time.Now().Add(-2 * lifetime),
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				_, err = repo.Upsert(conn, models.Message{
+					ID:        "chatty-message",
+					ClientID:  "chatty-client",
+					UpdatedAt: time.Now().Add(-2 * time.Minute),
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				retention.ClientLifetimes = map[string]time.Duration{
+					"compliance-client": 30 * 24 * time.Hour,
+					"chatty-client":     1 * time.Minute,
+				}
+				messageGC = postal.NewMessageGC(retention, database, repo, eventsRepo, archiver, pollingInterval, log.New(loggerBuffer, "", 0))
+			})
+
+			It("keeps the messages of that client for its lifetime", func() {
+				messageGC.Collect()
+
+				_, err := repo.FindByID(conn, "compliance-message")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.FindByID(conn, "chatty-message")
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+
+				_, err = repo.FindByID(conn, oldMessageID)
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			})
+
+			It("collects each client separately from the rest", func() {
+				messageGC.Collect()
+
+				Expect(repo.FindAllBeforeScopes).To(Equal([]models.MessageScope{
+					{ClientID: "chatty-client"},
+					{ClientID: "compliance-client"},
+					{ExcludedClientIDs: []string{"chatty-client", "compliance-client"}},
+				}))
+			})
+		})
+
+		It("deletes the history of the deleted messages in the same transaction", func() {
+			eventsRepo.Events = []models.MessageEvent{
+				{ID: 1, MessageID: oldMessageID, Status: postal.StatusDelivered},
+				{ID: 2, MessageID: newMessageID, Status: postal.StatusQueued},
+			}
+
+			messageGC.Collect()
+
+			Expect(eventsRepo.Events).To(Equal([]models.MessageEvent{
+				{ID: 2, MessageID: newMessageID, Status: postal.StatusQueued},
+			}))
+			Expect(database.Conn.BeginWasCalled).To(BeTrue())
+			Expect(database.Conn.CommitWasCalled).To(BeTrue())
+		})
+
 		Context("When the repo errors unexpectantly", func() {
 			It("logs the error", func() {
-				repo.DeleteBeforeError = errors.New("messages table is totally corrupt or something")
+				repo.DeleteByIDsError = errors.New("messages table is totally corrupt or something")
 
 				messageGC.Collect()
 
-				Expect(loggerBuffer.String()).To(ContainSubstring(repo.DeleteBeforeError.Error()))
+				Expect(loggerBuffer.String()).To(ContainSubstring(repo.DeleteByIDsError.Error()))
+				Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+			})
+		})
+
+		Context("When the history cannot be deleted", func() {
+			BeforeEach(func() {
+				eventsRepo.DeleteByMessageIDsError = errors.New("message events table is gone")
+			})
+
+			It("leaves the messages in place", func() {
+				messageGC.Collect()
+
+				_, err := repo.FindByID(conn, oldMessageID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+			})
+
+			It("logs the error", func() {
+				messageGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring(eventsRepo.DeleteByMessageIDsError.Error()))
 			})
 		})
 
@@ -1,12 +1,26 @@
 package postal
 
-import "github.com/nu7hatch/gouuid"
+import (
+	"time"
+
+	"github.com/nu7hatch/gouuid"
+)
 
 const (
-	StatusUnavailable = "unavailable"
-	StatusFailed      = "failed"
-	StatusDelivered   = "delivered"
-	StatusQueued      = "queued"
+	StatusUnavailable   = "unavailable"
+	StatusFailed        = "failed"
+	StatusDelivered     = "delivered"
+	StatusQueued        = "queued"
+	StatusScheduled     = "scheduled"
+	StatusCanceled      = "canceled"
+	StatusUndeliverable = "undeliverable"
+	StatusBounced       = "bounced"
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
 )
 
 type Templates struct {
@@ -34,6 +48,21 @@ type Options struct {
 	HTML              HTML
 	KindID            string
 	To                string
+	Recipients        []EmailRecipient
+	CC                []string
+	BCC               []string
 	Role              string
 	Endorsement       string
+	SendAt            time.Time
+	Priority          string
+	Critical          bool
+	BatchID           string
+	Data              map[string]interface{}
+}
+
+// EmailRecipient is one of the addresses of a bulk send, along with the data
+// that is given to the templates of its message.
+type EmailRecipient struct {
+	Email string
+	Data  map[string]interface{}
 }
@@ -3,6 +3,7 @@ package postal
 import (
 	"bytes"
 	"fmt"
+	htmltemplate "html/template"
 	"strings"
 	"text/template"
 
@@ -29,35 +30,64 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 		return mail.Message{}, err
 	}
 
-	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate, false)
+	compiledSubject, err := packager.compileTemplate(context, "subject", context.SubjectTemplate)
 	if err != nil {
 		return mail.Message{}, err
 	}
 
+	headers := []string{
+		fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
+		fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
+	}
+	headers = append(headers, packager.unsubscribeHeaders(context)...)
+
 	return mail.Message{
 		From:    context.From,
 		ReplyTo: context.ReplyTo,
 		To:      context.To,
 		Subject: compiledSubject,
 		Body:    parts,
-		Headers: []string{
-			fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
-			fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
-		},
+		Headers: headers,
 	}, nil
 }
 
+// unsubscribeHeaders builds the List-Unsubscribe header of RFC 2369 and, when
+// there is an unsubscribe URL to POST to, the one-click List-Unsubscribe-Post
+// header of RFC 8058.
+func (packager Packager) unsubscribeHeaders(context MessageContext) []string {
+	var links []string
+	if context.UnsubscribeURL != "" {
+		links = append(links, "<"+context.UnsubscribeURL+">")
+	}
+	if context.UnsubscribeMailto != "" {
+		links = append(links, "<"+context.UnsubscribeMailto+">")
+	}
+
+	if len(links) == 0 {
+		return []string{}
+	}
+
+	headers := []string{
+		fmt.Sprintf("List-Unsubscribe: %s", strings.Join(links, ", ")),
+	}
+	if context.UnsubscribeURL != "" {
+		headers = append(headers, "List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+	}
+
+	return headers
+}
+
 func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, error) {
 	var parts []mail.Part
 	var err error
 
-	context.Endorsement, err = packager.compileTemplate(context, context.Endorsement, false)
+	context.Endorsement, err = packager.compileTemplate(context, "endorsement", context.Endorsement)
 	if err != nil {
 		return parts, err
 	}
 
 	if context.Text != "" {
-		plainText, err := packager.compileTemplate(context, context.TextTemplate, false)
+		plainText, err := packager.compileTemplate(context, "text", context.TextTemplate)
 		if err != nil {
 			return parts, err
 		}
@@ -70,14 +100,13 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	}
 
 	if context.HTML != "" {
-		var err error
-
-		context.HTMLComponents.BodyContent, err = packager.compileTemplate(context, context.HTMLTemplate, true)
+		body, err := packager.compileHTMLTemplate(context, "html", context.HTMLTemplate)
 		if err != nil {
 			return parts, err
 		}
+		context.HTMLComponents.BodyContent = body
 
-		htmlPart, err := packager.compileTemplate(context, HTMLWrapperTemplate, true)
+		htmlPart, err := packager.compileHTMLTemplate(context, "wrapper", HTMLWrapperTemplate)
 		if err != nil {
 			return parts, err
 		}
@@ -91,20 +120,67 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
-func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
+func (packager Packager) compileTemplate(context MessageContext, name, theTemplate string) (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+	source, err := template.New(name).Parse(theTemplate)
+	if err != nil {
+		return "", newTemplateError(name, err)
+	}
+
+	err = source.Execute(buffer, context)
 	if err != nil {
-		return "", err
+		return "", newTemplateError(name, err)
 	}
+	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
 
-	if escapeContext {
-		context.Escape()
+	return compiledTemplate, nil
+}
+
+// compileHTMLTemplate renders an HTML template with html/template, which
+// escapes every value according to where it appears in the document. Only the
+// HTML supplied by the sender, and the body it is compiled into, are trusted.
+func (packager Packager) compileHTMLTemplate(context MessageContext, name, theTemplate string) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	source, err := htmltemplate.New(name).Parse(theTemplate)
+	if err != nil {
+		return "", newTemplateError(name, err)
 	}
 
-	source.Execute(buffer, context)
+	err = source.Execute(buffer, newHTMLMessageContext(context))
+	if err != nil {
+		return "", newTemplateError(name, err)
+	}
 	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
 
 	return compiledTemplate, nil
 }
+
+// htmlMessageContext is the MessageContext given to HTML templates, with the
+// HTML supplied by the sender marked as safe to render as it is.
+type htmlMessageContext struct {
+	MessageContext
+	HTML           htmltemplate.HTML
+	HTMLComponents htmlComponents
+}
+
+type htmlComponents struct {
+	BodyContent    htmltemplate.HTML
+	BodyAttributes htmltemplate.HTMLAttr
+	Head           htmltemplate.HTML
+	Doctype        htmltemplate.HTML
+}
+
+func newHTMLMessageContext(context MessageContext) htmlMessageContext {
+	return htmlMessageContext{
+		MessageContext: context,
+		HTML:           htmltemplate.HTML(context.HTML),
+		HTMLComponents: htmlComponents{
+			BodyContent:    htmltemplate.HTML(context.HTMLComponents.BodyContent),
+			BodyAttributes: htmltemplate.HTMLAttr(context.HTMLComponents.BodyAttributes),
+			Head:           htmltemplate.HTML(context.HTMLComponents.Head),
+			Doctype:        htmltemplate.HTML(context.HTMLComponents.Doctype),
+		},
+	}
+}
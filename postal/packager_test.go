@@ -11,10 +11,8 @@ import (
 var _ = Describe("Packager", func() {
 	var packager postal.Packager
 	var context postal.MessageContext
-	var client mail.Client
 
 	BeforeEach(func() {
-		client = mail.Client{}
 		html := postal.HTML{
 			BodyContent:    "<p>user supplied banana html</p>",
 			BodyAttributes: "class=\"bananaBody\"",
@@ -43,6 +41,55 @@ var _ = Describe("Packager", func() {
 		packager = postal.NewPackager()
 	})
 
+	Describe("Pack", func() {
+		It("identifies the client and the notification in the headers", func() {
+			message, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(message.Headers).To(Equal([]string{
+				"X-CF-Client-ID: 3&3",
+				"X-CF-Notification-ID: 4'4",
+			}))
+		})
+
+		Context("when the recipient can unsubscribe", func() {
+			It("adds the one-click unsubscribe headers", func() {
+				context.UnsubscribeURL = "https://notifications.example.com/unsubscribe/the-id"
+
+				message, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(message.Headers).To(Equal([]string{
+					"X-CF-Client-ID: 3&3",
+					"X-CF-Notification-ID: 4'4",
+					"List-Unsubscribe: <https://notifications.example.com/unsubscribe/the-id>",
+					"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+				}))
+			})
+
+			It("lists the unsubscribe mailbox after the URL", func() {
+				context.UnsubscribeURL = "https://notifications.example.com/unsubscribe/the-id"
+				context.UnsubscribeMailto = "mailto:unsubscribe@example.com?subject=unsubscribe+the-id"
+
+				message, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(message.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/unsubscribe/the-id>, <mailto:unsubscribe@example.com?subject=unsubscribe+the-id>"))
+				Expect(message.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+			})
+
+			It("does not offer one-click unsubscribe with only a mailbox", func() {
+				context.UnsubscribeMailto = "mailto:unsubscribe@example.com?subject=unsubscribe+the-id"
+
+				message, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(message.Headers).To(ContainElement("List-Unsubscribe: <mailto:unsubscribe@example.com?subject=unsubscribe+the-id>"))
+				Expect(message.Headers).NotTo(ContainElement(MatchRegexp("^List-Unsubscribe-Post")))
+			})
+		})
+	})
+
 	Describe("CompileParts", func() {
 		It("returns the compiled parts containing both the plaintext and html portions, escaping variables for the html portion only", func() {
 			parts, err := packager.CompileParts(context)
@@ -71,6 +118,82 @@ Banana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;bana
 			}))
 		})
 
+		It("renders the data of the message, escaping it for the html portion only", func() {
+			context.Data = map[string]interface{}{
+				"name":  "Tom & Jerry",
+				"items": []interface{}{"apples", "pears"},
+			}
+			context.TextTemplate = "Dear {{.Data.name}}:{{range .Data.items}} {{.}}{{end}}"
+			context.HTMLTemplate = "Dear {{.Data.name}}:{{range .Data.items}} {{.}}{{end}}"
+
+			parts, err := packager.CompileParts(context)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(parts[0].Content).To(Equal("Dear Tom & Jerry: apples pears"))
+			Expect(parts[1].Content).To(ContainSubstring("Dear Tom &amp; Jerry: apples pears"))
+		})
+
+		It("escapes every field of the context for the html portion, except the html of the sender", func() {
+			context.UserGUID = "<user>"
+			context.Scope = "<scope>"
+			context.HTMLTemplate = "{{.HTML}} {{.UserGUID}} {{.Scope}}"
+
+			parts, err := packager.CompileParts(context)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(parts[1].Content).To(ContainSubstring("<p>user supplied banana html</p> &lt;user&gt; &lt;scope&gt;"))
+		})
+
+		It("escapes the fields of the context according to where they appear in the html portion", func() {
+			context.Text = `"; alert(1); "`
+			context.UnsubscribeURL = "javascript:alert(1)"
+			context.HTMLTemplate = `<script>var text = "{{.Text}}";</script><a href="{{.UnsubscribeURL}}">Unsubscribe</a>`
+
+			parts, err := packager.CompileParts(context)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(parts[1].Content).To(ContainSubstring(`<script>var text = "\u0022; alert(1); \u0022";</script>`))
+			Expect(parts[1].Content).To(ContainSubstring(`<a href="#ZgotmplZ">Unsubscribe</a>`))
+		})
+
+		It("returns an error when the html template cannot be rendered", func() {
+			context.HTMLTemplate = `<a href="{{.UnsubscribeURL}}`
+
+			_, err := packager.CompileParts(context)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports where a template fails to execute", func() {
+			context.TextTemplate = "Hello\n  {{.Missing}}"
+
+			_, err := packager.CompileParts(context)
+			Expect(err).To(BeAssignableToTypeOf(postal.TemplateError{}))
+
+			templateErr := err.(postal.TemplateError)
+			Expect(templateErr.Part).To(Equal("text"))
+			Expect(templateErr.Line).To(Equal(2))
+			Expect(templateErr.Column).To(Equal(4))
+			Expect(templateErr.Message).To(ContainSubstring("can't evaluate field Missing"))
+		})
+
+		It("reports where a template fails to parse", func() {
+			context.HTMLTemplate = "<p>\n{{end}}</p>"
+
+			_, err := packager.CompileParts(context)
+			Expect(err).To(Equal(postal.TemplateError{
+				Part:    "html",
+				Line:    2,
+				Message: "unexpected {{end}}",
+			}))
+			Expect(err.Error()).To(Equal("template html:2: unexpected {{end}}"))
+		})
+
 		Context("when no html is set", func() {
 			It("only sends a plaintext of the email", func() {
 				context.HTML = ""
@@ -0,0 +1,122 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+)
+
+const ExpansionPageSize = 500
+
+type CampaignExpander struct {
+	audiences     map[string]AudienceStrategyInterface
+	mailer        MailerInterface
+	campaignsRepo models.CampaignsRepoInterface
+	database      models.DatabaseInterface
+}
+
+func NewCampaignExpander(audiences map[string]AudienceStrategyInterface, mailer MailerInterface,
+	campaignsRepo models.CampaignsRepoInterface, database models.DatabaseInterface) CampaignExpander {
+
+	return CampaignExpander{
+		audiences:     audiences,
+		mailer:        mailer,
+		campaignsRepo: campaignsRepo,
+		database:      database,
+	}
+}
+
+// Expand pages through the recipients of an expansion job in order of their
+// GUIDs and enqueues their deliveries. The deliveries of each page are
+// enqueued in the same transaction that records the last of its recipients on
+// the campaign, so a retried expansion resumes right after the recipients
+// that were already enqueued. Expansion stops once the lease on the job is
+// lost, leaving the rest to the worker that holds it; should the two overlap,
+// the version of the campaign makes the enqueue that is behind roll back.
+func (expander CampaignExpander) Expand(job *gobble.Job) error {
+	var expansion postal.Expansion
+
+	err := job.Unmarshal(&expansion)
+	if err != nil {
+		return err
+	}
+
+	strategy, ok := expander.audiences[expansion.Audience]
+	if !ok {
+		return fmt.Errorf("Campaign %s has an unknown audience %q", expansion.CampaignID, expansion.Audience)
+	}
+
+	conn := expander.database.Connection()
+	campaign, err := expander.campaignsRepo.FindByID(conn, expansion.CampaignID)
+	if err != nil {
+		return err
+	}
+
+	if campaign.Status == models.CampaignStatusComplete {
+		return nil
+	}
+
+	audience, err := strategy.Expand(expansion.GUID, expansion.Options)
+	if err != nil {
+		return err
+	}
+	audience.Options.BatchID = campaign.ID
+
+	for {
+		if job.IsLeaseLost() {
+			return nil
+		}
+
+		users, err := strategy.Recipients(expansion.GUID, expansion.Options, campaign.LastRecipientGUID, ExpansionPageSize)
+		if err != nil {
+			return err
+		}
+
+		if len(users) == 0 {
+			break
+		}
+
+		campaign, err = expander.enqueue(conn, campaign, users, audience, expansion.ClientID)
+		if err != nil {
+			return err
+		}
+
+		if len(users) < ExpansionPageSize {
+			break
+		}
+	}
+
+	campaign.Status = models.CampaignStatusComplete
+	_, err = expander.campaignsRepo.Update(conn, campaign)
+
+	return err
+}
+
+func (expander CampaignExpander) enqueue(conn models.ConnectionInterface, campaign models.Campaign, users []User, audience Audience, clientID string) (models.Campaign, error) {
+	transaction := conn.Transaction()
+	transaction.Begin()
+
+	_, err := expander.mailer.Enqueue(transaction, users, audience.Options, audience.Space, audience.Organization, clientID, audience.Scope)
+	if err != nil {
+		transaction.Rollback()
+		return campaign, fmt.Errorf("Campaign %s failed to enqueue deliveries after %d recipients: %s", campaign.ID, campaign.RecipientCount, err)
+	}
+
+	campaign.LastRecipientGUID = users[len(users)-1].GUID
+	campaign.RecipientCount += len(users)
+
+	updated, err := expander.campaignsRepo.Update(transaction, campaign)
+	if err != nil {
+		transaction.Rollback()
+		return campaign, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return campaign, err
+	}
+
+	return updated, nil
+}
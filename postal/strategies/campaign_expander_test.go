@@ -0,0 +1,231 @@
+package strategies_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CampaignExpander", func() {
+	var expander strategies.CampaignExpander
+	var audienceStrategy *fakes.AudienceStrategy
+	var mailer *fakes.Mailer
+	var campaignsRepo *fakes.CampaignsRepo
+	var database *fakes.Database
+	var job gobble.Job
+	var options postal.Options
+
+	BeforeEach(func() {
+		audienceStrategy = fakes.NewAudienceStrategy()
+		mailer = fakes.NewMailer()
+		campaignsRepo = fakes.NewCampaignsRepo()
+		database = fakes.NewDatabase()
+
+		campaignsRepo.Campaigns["campaign-001"] = models.Campaign{
+			ID:       "campaign-001",
+			ClientID: "the-client",
+			Status:   models.CampaignStatusExpanding,
+		}
+
+		options = postal.Options{KindID: "the-kind", Endorsement: strategies.SpaceEndorsement}
+		audienceStrategy.Users = []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}}
+		audienceStrategy.Audience = strategies.Audience{
+			Options:      options,
+			Space:        cf.CloudControllerSpace{GUID: "space-001"},
+			Organization: cf.CloudControllerOrganization{GUID: "org-001"},
+		}
+
+		job = gobble.NewJob(postal.Expansion{
+			CampaignID: "campaign-001",
+			Audience:   strategies.SpaceAudience,
+			GUID:       "space-001",
+			ClientID:   "the-client",
+			Options:    postal.Options{KindID: "the-kind"},
+		})
+
+		expander = strategies.NewCampaignExpander(map[string]strategies.AudienceStrategyInterface{
+			strategies.SpaceAudience: audienceStrategy,
+		}, mailer, campaignsRepo, database)
+	})
+
+	Describe("Expand", func() {
+		It("expands the audience of the campaign", func() {
+			err := expander.Expand(&job)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(audienceStrategy.ExpandArguments).To(Equal([]interface{}{"space-001", postal.Options{KindID: "the-kind"}}))
+		})
+
+		It("pages through the recipients of the audience", func() {
+			err := expander.Expand(&job)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(audienceStrategy.RecipientsArguments).To(Equal([][]interface{}{
+				{"space-001", postal.Options{KindID: "the-kind"}, "", strategies.ExpansionPageSize},
+			}))
+		})
+
+		It("enqueues deliveries to the recipients within a transaction, batched under the campaign", func() {
+			err := expander.Expand(&job)
+			Expect(err).NotTo(HaveOccurred())
+
+			options.BatchID = "campaign-001"
+			Expect(mailer.EnqueueArguments).To(Equal(map[string]interface{}{
+				"connection": database.Conn,
+				"users":      []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}},
+				"options":    options,
+				"space":      cf.CloudControllerSpace{GUID: "space-001"},
+				"org":        cf.CloudControllerOrganization{GUID: "org-001"},
+				"client":     "the-client",
+				"scope":      "",
+			}))
+			Expect(database.Conn.BeginWasCalled).To(BeTrue())
+			Expect(database.Conn.CommitWasCalled).To(BeTrue())
+			Expect(database.Conn.RollbackWasCalled).To(BeFalse())
+		})
+
+		It("marks the campaign as complete", func() {
+			err := expander.Expand(&job)
+			Expect(err).NotTo(HaveOccurred())
+
+			campaign := campaignsRepo.Campaigns["campaign-001"]
+			Expect(campaign.Status).To(Equal(models.CampaignStatusComplete))
+			Expect(campaign.RecipientCount).To(Equal(2))
+			Expect(campaign.LastRecipientGUID).To(Equal("user-2"))
+		})
+
+		Context("when the audience spans several pages", func() {
+			BeforeEach(func() {
+				var users []strategies.User
+				for i := 0; i < strategies.ExpansionPageSize*2+1; i++ {
+					users = append(users, strategies.User{GUID: fmt.Sprintf("user-%04d", i)})
+				}
+				audienceStrategy.Users = users
+			})
+
+			It("enqueues a page at a time, recording the last recipient of each page", func() {
+				err := expander.Expand(&job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mailer.EnqueueCalls).To(HaveLen(3))
+				Expect(mailer.EnqueueCalls[0]).To(HaveLen(strategies.ExpansionPageSize))
+				Expect(mailer.EnqueueCalls[1]).To(HaveLen(strategies.ExpansionPageSize))
+				Expect(mailer.EnqueueCalls[2]).To(HaveLen(1))
+
+				var cursors []string
+				var counts []int
+				for _, update := range campaignsRepo.Updates {
+					cursors = append(cursors, update.LastRecipientGUID)
+					counts = append(counts, update.RecipientCount)
+				}
+				Expect(cursors).To(Equal([]string{"user-0499", "user-0999", "user-1000", "user-1000"}))
+				Expect(counts).To(Equal([]int{500, 1000, 1001, 1001}))
+			})
+
+			It("resumes after the last recipient that was enqueued", func() {
+				campaign := campaignsRepo.Campaigns["campaign-001"]
+				campaign.LastRecipientGUID = "user-0499"
+				campaign.RecipientCount = strategies.ExpansionPageSize
+				campaignsRepo.Campaigns["campaign-001"] = campaign
+
+				err := expander.Expand(&job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(audienceStrategy.RecipientsArguments[0][2]).To(Equal("user-0499"))
+				Expect(mailer.EnqueueCalls).To(HaveLen(2))
+				Expect(mailer.EnqueueCalls[0][0].GUID).To(Equal("user-0500"))
+				Expect(mailer.EnqueueCalls[1][0].GUID).To(Equal("user-1000"))
+				Expect(campaignsRepo.Campaigns["campaign-001"].RecipientCount).To(Equal(1001))
+			})
+		})
+
+		Context("when the lease on the job is lost", func() {
+			It("stops expanding, leaving the campaign to the worker that holds the lease", func() {
+				job.LoseLease()
+
+				err := expander.Expand(&job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mailer.EnqueueCalls).To(BeEmpty())
+				Expect(campaignsRepo.Updates).To(BeEmpty())
+				Expect(campaignsRepo.Campaigns["campaign-001"].Status).To(Equal(models.CampaignStatusExpanding))
+			})
+		})
+
+		Context("when the campaign is already complete", func() {
+			It("does nothing", func() {
+				campaign := campaignsRepo.Campaigns["campaign-001"]
+				campaign.Status = models.CampaignStatusComplete
+				campaignsRepo.Campaigns["campaign-001"] = campaign
+
+				err := expander.Expand(&job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(audienceStrategy.ExpandArguments).To(BeNil())
+				Expect(mailer.EnqueueCalls).To(BeEmpty())
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the audience is unknown", func() {
+				job = gobble.NewJob(postal.Expansion{
+					CampaignID: "campaign-001",
+					Audience:   "martians",
+				})
+
+				err := expander.Expand(&job)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("returns an error when the campaign cannot be found", func() {
+				campaignsRepo.FindByIDError = errors.New("BOOM!")
+
+				err := expander.Expand(&job)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+
+			It("returns an error when the audience cannot be expanded", func() {
+				audienceStrategy.ExpandError = errors.New("BOOM!")
+
+				err := expander.Expand(&job)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+
+			It("returns an error when the recipients cannot be loaded", func() {
+				audienceStrategy.RecipientsError = errors.New("BOOM!")
+
+				err := expander.Expand(&job)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+				Expect(mailer.EnqueueCalls).To(BeEmpty())
+			})
+
+			It("rolls back without recording progress when deliveries cannot be enqueued", func() {
+				mailer.EnqueueError = errors.New("BOOM!")
+
+				err := expander.Expand(&job)
+				Expect(err).To(HaveOccurred())
+				Expect(campaignsRepo.Updates).To(BeEmpty())
+				Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+				Expect(database.Conn.CommitWasCalled).To(BeFalse())
+			})
+
+			It("rolls back the deliveries when progress cannot be recorded", func() {
+				campaignsRepo.UpdateError = errors.New("BOOM!")
+
+				err := expander.Expand(&job)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+				Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+				Expect(database.Conn.CommitWasCalled).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,65 @@
+package strategies
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+)
+
+type CampaignLauncherInterface interface {
+	Launch(models.ConnectionInterface, postal.Expansion) (CampaignResponse, error)
+}
+
+type CampaignLauncher struct {
+	queue         gobble.QueueInterface
+	guidGenerator postal.GUIDGenerationFunc
+	campaignsRepo models.CampaignsRepoInterface
+}
+
+func NewCampaignLauncher(queue gobble.QueueInterface, guidGenerator postal.GUIDGenerationFunc, campaignsRepo models.CampaignsRepoInterface) CampaignLauncher {
+	return CampaignLauncher{
+		queue:         queue,
+		guidGenerator: guidGenerator,
+		campaignsRepo: campaignsRepo,
+	}
+}
+
+func (launcher CampaignLauncher) Launch(conn models.ConnectionInterface, expansion postal.Expansion) (CampaignResponse, error) {
+	guid, err := launcher.guidGenerator()
+	if err != nil {
+		panic(err)
+	}
+	expansion.CampaignID = guid.String()
+
+	transaction := conn.Transaction()
+	transaction.Begin()
+
+	campaign, err := launcher.campaignsRepo.Create(transaction, models.Campaign{
+		ID:       expansion.CampaignID,
+		ClientID: expansion.ClientID,
+		Status:   models.CampaignStatusExpanding,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return CampaignResponse{}, err
+	}
+
+	job := gobble.NewJob(expansion)
+	job.Priority = jobPriority(expansion.Options)
+
+	_, err = launcher.queue.EnqueueWithTransaction(job, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return CampaignResponse{}, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return CampaignResponse{}, err
+	}
+
+	return CampaignResponse{
+		CampaignID: campaign.ID,
+		Status:     campaign.Status,
+	}, nil
+}
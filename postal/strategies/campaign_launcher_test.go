@@ -0,0 +1,107 @@
+package strategies_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CampaignLauncher", func() {
+	var launcher strategies.CampaignLauncher
+	var queue *fakes.Queue
+	var conn *fakes.DBConn
+	var campaignsRepo *fakes.CampaignsRepo
+	var expansion postal.Expansion
+
+	BeforeEach(func() {
+		queue = fakes.NewQueue()
+		conn = fakes.NewDBConn()
+		campaignsRepo = fakes.NewCampaignsRepo()
+		launcher = strategies.NewCampaignLauncher(queue, fakes.GUIDGenerator, campaignsRepo)
+		expansion = postal.Expansion{
+			Audience: strategies.SpaceAudience,
+			GUID:     "space-001",
+			ClientID: "the-client",
+			Options:  postal.Options{KindID: "the-kind"},
+		}
+	})
+
+	Describe("Launch", func() {
+		It("records a campaign that is expanding", func() {
+			response, err := launcher.Launch(conn, expansion)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(strategies.CampaignResponse{
+				CampaignID: "deadbeef-aabb-ccdd-eeff-001122334455",
+				Status:     models.CampaignStatusExpanding,
+			}))
+
+			campaign, err := campaignsRepo.FindByID(conn, "deadbeef-aabb-ccdd-eeff-001122334455")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(campaign.ClientID).To(Equal("the-client"))
+			Expect(campaign.Status).To(Equal(models.CampaignStatusExpanding))
+
+			Expect(conn.BeginWasCalled).To(BeTrue())
+			Expect(conn.CommitWasCalled).To(BeTrue())
+			Expect(conn.RollbackWasCalled).To(BeFalse())
+		})
+
+		It("enqueues an expansion job for the campaign within the transaction that creates it", func() {
+			_, err := launcher.Launch(conn, expansion)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queue.EnqueueTransactions).To(Equal([]gobble.ConnectionInterface{conn}))
+
+			job := <-queue.Reserve("me")
+			Expect(job.Priority).To(Equal(gobble.PriorityNormal))
+			Expect(postal.IsExpansion(job)).To(BeTrue())
+
+			var enqueued postal.Expansion
+			err = job.Unmarshal(&enqueued)
+			if err != nil {
+				panic(err)
+			}
+
+			expansion.CampaignID = "deadbeef-aabb-ccdd-eeff-001122334455"
+			Expect(enqueued).To(Equal(expansion))
+		})
+
+		It("enqueues the expansion job with the priority of its deliveries", func() {
+			expansion.Options.Critical = true
+
+			_, err := launcher.Launch(conn, expansion)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("me")
+			Expect(job.Priority).To(Equal(gobble.PriorityHigh))
+		})
+
+		Context("when the campaign cannot be created", func() {
+			It("rolls back and returns the error", func() {
+				campaignsRepo.CreateError = errors.New("BOOM!")
+
+				_, err := launcher.Launch(conn, expansion)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+				Expect(conn.RollbackWasCalled).To(BeTrue())
+				Expect(conn.CommitWasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the expansion job cannot be enqueued", func() {
+			It("rolls back and returns the error", func() {
+				queue.EnqueueError = errors.New("BOOM!")
+
+				_, err := launcher.Launch(conn, expansion)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+				Expect(conn.RollbackWasCalled).To(BeTrue())
+				Expect(conn.CommitWasCalled).To(BeFalse())
+			})
+		})
+	})
+})
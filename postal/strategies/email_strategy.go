@@ -20,7 +20,19 @@ func NewEmailStrategy(mailer MailerInterface) EmailStrategy {
 
 func (strategy EmailStrategy) Dispatch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) ([]Response, error) {
 	options.Endorsement = EmailEndorsement
-	responses := strategy.mailer.Deliver(conn, []User{{Email: options.To}}, options, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, clientID, "")
+
+	users := []User{{Email: options.To}}
+	if len(options.Recipients) > 0 {
+		users = []User{}
+		for _, recipient := range options.Recipients {
+			users = append(users, User{Email: recipient.Email, Data: recipient.Data})
+		}
+
+		// Every job carries the options, so they must not carry the whole list.
+		options.Recipients = nil
+	}
+
+	responses := strategy.mailer.Deliver(conn, users, options, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, clientID, "")
 
 	return responses, nil
 }
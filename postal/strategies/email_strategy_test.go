@@ -52,5 +52,30 @@ var _ = Describe("EmailStrategy", func() {
 				"scope":      "",
 			}))
 		})
+
+		Context("when the options list several recipients", func() {
+			BeforeEach(func() {
+				options.To = ""
+				options.Recipients = []postal.EmailRecipient{
+					{Email: "first@example.com", Data: map[string]interface{}{"total": "$10"}},
+					{Email: "second@example.com"},
+				}
+			})
+
+			It("delivers to all of them at once, along with their data", func() {
+				emailStrategy.Dispatch(clientID, emailID, options, conn)
+
+				Expect(mailer.DeliverArguments["users"]).To(Equal([]strategies.User{
+					{Email: "first@example.com", Data: map[string]interface{}{"total": "$10"}},
+					{Email: "second@example.com"},
+				}))
+			})
+
+			It("does not carry the list in the options of every delivery", func() {
+				emailStrategy.Dispatch(clientID, emailID, options, conn)
+
+				Expect(mailer.DeliverArguments["options"].(postal.Options).Recipients).To(BeNil())
+			})
+		})
 	})
 })
@@ -1,7 +1,6 @@
 package strategies
 
 import (
-	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/utilities"
@@ -9,41 +8,53 @@ import (
 
 const EveryoneEndorsement = "This message was sent to everyone."
 
+const EveryoneAudience = "everyone"
+
 type EveryoneStrategy struct {
 	tokenLoader postal.TokenLoaderInterface
 	allUsers    utilities.AllUsersInterface
-	mailer      MailerInterface
+	launcher    CampaignLauncherInterface
 }
 
-func NewEveryoneStrategy(tokenLoader postal.TokenLoaderInterface, allUsers utilities.AllUsersInterface, mailer MailerInterface) EveryoneStrategy {
+func NewEveryoneStrategy(tokenLoader postal.TokenLoaderInterface, allUsers utilities.AllUsersInterface, launcher CampaignLauncherInterface) EveryoneStrategy {
 	return EveryoneStrategy{
 		tokenLoader: tokenLoader,
 		allUsers:    allUsers,
-		mailer:      mailer,
+		launcher:    launcher,
 	}
 }
 
-func (strategy EveryoneStrategy) Dispatch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) ([]Response, error) {
-	responses := []Response{}
-	options.Endorsement = EveryoneEndorsement
-
+func (strategy EveryoneStrategy) Launch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) (CampaignResponse, error) {
 	_, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return CampaignResponse{}, err
 	}
 
-	// split this up so that it only loads user guids
-	userGUIDs, err := strategy.allUsers.AllUserGUIDs()
+	return strategy.launcher.Launch(conn, postal.Expansion{
+		Audience: EveryoneAudience,
+		ClientID: clientID,
+		Options:  options,
+	})
+}
+
+func (strategy EveryoneStrategy) Expand(guid string, options postal.Options) (Audience, error) {
+	options.Endorsement = EveryoneEndorsement
+
+	return Audience{
+		Options: options,
+	}, nil
+}
+
+func (strategy EveryoneStrategy) Recipients(guid string, options postal.Options, after string, count int) ([]User, error) {
+	_, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return []User{}, err
 	}
 
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+	userGUIDs, err := strategy.allUsers.AllUserGUIDs(after, count)
+	if err != nil {
+		return []User{}, err
 	}
 
-	responses = strategy.mailer.Deliver(conn, users, options, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, clientID, "")
-
-	return responses, nil
+	return usersWithGUIDs(userGUIDs), nil
 }
@@ -3,7 +3,6 @@ package strategies_test
 import (
 	"errors"
 
-	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/fakes"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
@@ -17,7 +16,7 @@ var _ = Describe("Everyone Strategy", func() {
 	var options postal.Options
 	var tokenLoader *fakes.TokenLoader
 	var allUsers *fakes.AllUsers
-	var mailer *fakes.Mailer
+	var launcher *fakes.CampaignLauncher
 	var clientID string
 	var conn *fakes.DBConn
 
@@ -37,64 +36,96 @@ var _ = Describe("Everyone Strategy", func() {
 		tokenLoader = fakes.NewTokenLoader()
 		tokenLoader.Token = fakes.BuildToken(tokenHeader, tokenClaims)
 
-		mailer = fakes.NewMailer()
+		launcher = fakes.NewCampaignLauncher()
 		allUsers = fakes.NewAllUsers()
 		allUsers.GUIDs = []string{"user-380", "user-319"}
 
-		strategy = strategies.NewEveryoneStrategy(tokenLoader, allUsers, mailer)
+		strategy = strategies.NewEveryoneStrategy(tokenLoader, allUsers, launcher)
 	})
 
-	Describe("Dispatch", func() {
-		BeforeEach(func() {
-			options = postal.Options{
-				KindID:            "welcome_user",
-				KindDescription:   "Your Official Welcome",
-				SourceDescription: "Welcome system",
-				Text:              "Welcome to the system, now get off my lawn.",
-				HTML:              postal.HTML{BodyContent: "<p>Welcome to the system, now get off my lawn.</p>"},
+	BeforeEach(func() {
+		options = postal.Options{
+			KindID:            "welcome_user",
+			KindDescription:   "Your Official Welcome",
+			SourceDescription: "Welcome system",
+			Text:              "Welcome to the system, now get off my lawn.",
+			HTML:              postal.HTML{BodyContent: "<p>Welcome to the system, now get off my lawn.</p>"},
+		}
+	})
+
+	Describe("Launch", func() {
+		It("launches a campaign for everyone", func() {
+			launcher.Response = strategies.CampaignResponse{
+				CampaignID: "campaign-001",
+				Status:     "expanding",
 			}
+
+			response, err := strategy.Launch(clientID, "", options, conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(launcher.Response))
+
+			Expect(launcher.LaunchArguments).To(Equal([]interface{}{conn, postal.Expansion{
+				Audience: strategies.EveryoneAudience,
+				ClientID: clientID,
+				Options:  options,
+			}}))
 		})
 
-		It("call mailer.Deliver with the correct arguments for an organization", func() {
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
+			})
+
+			It("returns an error when the launcher fails", func() {
+				launcher.LaunchError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+			})
+		})
+	})
+
+	Describe("Expand", func() {
+		It("returns the audience of everyone", func() {
 			Expect(options.Endorsement).To(BeEmpty())
-			_, err := strategy.Dispatch(clientID, "", options, conn)
-			if err != nil {
-				panic(err)
-			}
 
-			options.Endorsement = strategies.EveryoneEndorsement
-			var users []strategies.User
-			for _, guid := range allUsers.GUIDs {
-				users = append(users, strategies.User{GUID: guid})
-			}
+			audience, err := strategy.Expand("", options)
+			Expect(err).NotTo(HaveOccurred())
 
-			Expect(mailer.DeliverArguments).To(Equal(map[string]interface{}{
-				"connection": conn,
-				"users":      users,
-				"options":    options,
-				"space":      cf.CloudControllerSpace{},
-				"org":        cf.CloudControllerOrganization{},
-				"client":     clientID,
-				"scope":      "",
+			options.Endorsement = strategies.EveryoneEndorsement
+			Expect(audience).To(Equal(strategies.Audience{
+				Options: options,
 			}))
 		})
 	})
 
-	Context("failure cases", func() {
-		Context("when token loader fails to return a token", func() {
-			It("returns an error", func() {
+	Describe("Recipients", func() {
+		It("returns a page of every user in order of their GUIDs", func() {
+			users, err := strategy.Recipients("", options, "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-319"}}))
+
+			users, err = strategy.Recipients("", options, "user-319", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-380"}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
 				tokenLoader.LoadError = errors.New("BOOM!")
-				_, err := strategy.Dispatch(clientID, "", options, conn)
 
+				_, err := strategy.Recipients("", options, "", 1)
 				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
-		})
 
-		Context("when allUsers fails to load users", func() {
-			It("returns the error", func() {
+			It("returns an error when allUsers fails to load users", func() {
 				allUsers.LoadError = errors.New("BOOM!")
-				_, err := strategy.Dispatch(clientID, "", options, conn)
 
+				_, err := strategy.Recipients("", options, "", 1)
 				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 		})
@@ -1,6 +1,8 @@
 package strategies
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/models"
@@ -9,40 +11,87 @@ import (
 
 type MailerInterface interface {
 	Deliver(models.ConnectionInterface, []User, postal.Options, cf.CloudControllerSpace, cf.CloudControllerOrganization, string, string) []Response
+	Enqueue(models.ConnectionInterface, []User, postal.Options, cf.CloudControllerSpace, cf.CloudControllerOrganization, string, string) ([]Response, error)
 }
 
 type Mailer struct {
-	queue         gobble.QueueInterface
-	guidGenerator postal.GUIDGenerationFunc
-	messagesRepo  MessagesRepoInterface
+	queue             gobble.QueueInterface
+	guidGenerator     postal.GUIDGenerationFunc
+	messagesRepo      MessagesRepoInterface
+	messageEventsRepo models.MessageEventsRepoInterface
 }
 
 type MessagesRepoInterface interface {
 	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
 }
 
-func NewMailer(queue gobble.QueueInterface, guidGenerator postal.GUIDGenerationFunc, messagesRepo MessagesRepoInterface) Mailer {
+func NewMailer(queue gobble.QueueInterface, guidGenerator postal.GUIDGenerationFunc, messagesRepo MessagesRepoInterface,
+	messageEventsRepo models.MessageEventsRepoInterface) Mailer {
+
 	return Mailer{
-		queue:         queue,
-		guidGenerator: guidGenerator,
-		messagesRepo:  messagesRepo,
+		queue:             queue,
+		guidGenerator:     guidGenerator,
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
 	}
 }
 
+// Deliver enqueues a delivery for each of the users within a transaction of
+// its own. Nothing is enqueued when any of them fails.
 func (mailer Mailer) Deliver(conn models.ConnectionInterface, users []User,
 	options postal.Options, space cf.CloudControllerSpace,
 	organization cf.CloudControllerOrganization, clientID, scope string) []Response {
 
-	responses := []Response{}
-	jobsByMessageID := map[string]gobble.Job{}
-	for _, user := range users {
+	transaction := conn.Transaction()
+	transaction.Begin()
+
+	responses, err := mailer.Enqueue(transaction, users, options, space, organization, clientID, scope)
+	if err != nil {
+		transaction.Rollback()
+		return []Response{}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return []Response{}
+	}
+
+	return responses
+}
+
+// Enqueue records and enqueues a delivery for each of the users through the
+// caller's transaction, which the caller commits or rolls back.
+func (mailer Mailer) Enqueue(transaction models.ConnectionInterface, users []User,
+	options postal.Options, space cf.CloudControllerSpace,
+	organization cf.CloudControllerOrganization, clientID, scope string) ([]Response, error) {
+
+	status := postal.StatusQueued
+	scheduled := options.SendAt.After(time.Now())
+	if scheduled {
+		status = postal.StatusScheduled
+	}
+
+	priority := jobPriority(options)
+
+	if options.BatchID == "" {
 		guid, err := mailer.guidGenerator()
 		if err != nil {
 			panic(err)
 		}
-		messageID := guid.String()
+		options.BatchID = guid.String()
+	}
 
-		jobsByMessageID[messageID] = gobble.NewJob(postal.Delivery{
+	responses := []Response{}
+	jobsByMessageID := map[string]gobble.Job{}
+	recipientsByMessageID := map[string]string{}
+	copiesByMessageID := map[string][]postal.Recipient{}
+	for _, user := range users {
+		messageID := mailer.messageID()
+
+		cc := mailer.copies(options.CC)
+		bcc := mailer.copies(options.BCC)
+
+		job := gobble.NewJob(postal.Delivery{
 			Options:      options,
 			UserGUID:     user.GUID,
 			Email:        user.Email,
@@ -51,41 +100,125 @@ func (mailer Mailer) Deliver(conn models.ConnectionInterface, users []User,
 			ClientID:     clientID,
 			MessageID:    messageID,
 			Scope:        scope,
+			Data:         user.Data,
+			CC:           cc,
+			BCC:          bcc,
 		})
+		job.Priority = priority
+		if scheduled {
+			job.ActiveAt = options.SendAt
+		}
+		jobsByMessageID[messageID] = job
 
 		recipient := user.Email
 		if recipient == "" {
 			recipient = user.GUID
 		}
+		recipientsByMessageID[messageID] = recipient
 
 		responses = append(responses, Response{
-			Status:         postal.StatusQueued,
+			Status:         status,
 			NotificationID: messageID,
 			Recipient:      recipient,
+			BatchID:        options.BatchID,
 		})
+
+		copiesByMessageID[messageID] = append(cc, bcc...)
+		for _, copy := range copiesByMessageID[messageID] {
+			responses = append(responses, Response{
+				Status:         status,
+				NotificationID: copy.MessageID,
+				Recipient:      copy.Email,
+				BatchID:        options.BatchID,
+			})
+		}
 	}
 
-	transaction := conn.Transaction()
-	transaction.Begin()
 	for messageID, job := range jobsByMessageID {
-		_, err := mailer.queue.Enqueue(job)
+		job, err := mailer.queue.EnqueueWithTransaction(job, transaction)
 		if err != nil {
-			transaction.Rollback()
-			return []Response{}
+			return []Response{}, err
 		}
-		_, err = mailer.messagesRepo.Upsert(transaction, models.Message{
-			ID:     messageID,
-			Status: postal.StatusQueued,
-		})
+
+		message := models.Message{
+			ID:        messageID,
+			Status:    status,
+			BatchID:   options.BatchID,
+			ClientID:  clientID,
+			Recipient: recipientsByMessageID[messageID],
+		}
+		if scheduled {
+			message.JobID = job.ID
+		}
+
+		err = mailer.record(transaction, message)
 		if err != nil {
-			transaction.Rollback()
-			return []Response{}
+			return []Response{}, err
+		}
+
+		for _, copy := range copiesByMessageID[messageID] {
+			message.ID = copy.MessageID
+			message.Recipient = copy.Email
+
+			err = mailer.record(transaction, message)
+			if err != nil {
+				return []Response{}, err
+			}
 		}
 	}
-	err := transaction.Commit()
+
+	return responses, nil
+}
+
+func (mailer Mailer) messageID() string {
+	guid, err := mailer.guidGenerator()
 	if err != nil {
-		return []Response{}
+		panic(err)
 	}
+	return guid.String()
+}
 
-	return responses
+// copies gives every CC or BCC address a message ID of its own so that its
+// status can be tracked separately from the primary recipient.
+func (mailer Mailer) copies(addresses []string) []postal.Recipient {
+	var recipients []postal.Recipient
+	for _, address := range addresses {
+		recipients = append(recipients, postal.Recipient{
+			Email:     address,
+			MessageID: mailer.messageID(),
+		})
+	}
+	return recipients
+}
+
+func (mailer Mailer) record(conn models.ConnectionInterface, message models.Message) error {
+	_, err := mailer.messagesRepo.Upsert(conn, message)
+	if err != nil {
+		return err
+	}
+
+	_, err = mailer.messageEventsRepo.Create(conn, models.MessageEvent{
+		MessageID: message.ID,
+		Status:    message.Status,
+	})
+	return err
+}
+
+// jobPriority uses the priority requested by the sender when there is one,
+// and otherwise delivers critical notifications ahead of everything else.
+func jobPriority(options postal.Options) int {
+	switch options.Priority {
+	case postal.PriorityLow:
+		return gobble.PriorityLow
+	case postal.PriorityNormal:
+		return gobble.PriorityNormal
+	case postal.PriorityHigh:
+		return gobble.PriorityHigh
+	}
+
+	if options.Critical {
+		return gobble.PriorityHigh
+	}
+
+	return gobble.PriorityNormal
 }
@@ -1,12 +1,13 @@
 package strategies_test
 
 import (
-	"bytes"
 	"errors"
-	"log"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
 
@@ -16,21 +17,19 @@ import (
 
 var _ = Describe("Mailer", func() {
 	var mailer strategies.Mailer
-	var logger *log.Logger
-	var buffer *bytes.Buffer
 	var queue *fakes.Queue
 	var conn *fakes.DBConn
 	var space cf.CloudControllerSpace
 	var org cf.CloudControllerOrganization
 	var messagesRepo *fakes.MessagesRepo
+	var messageEventsRepo *fakes.MessageEventsRepo
 
 	BeforeEach(func() {
-		buffer = bytes.NewBuffer([]byte{})
-		logger = log.New(buffer, "", 0)
 		queue = fakes.NewQueue()
 		conn = fakes.NewDBConn()
 		messagesRepo = fakes.NewMessagesRepo()
-		mailer = strategies.NewMailer(queue, fakes.NewIncrementingGUIDGenerator().Generate, messagesRepo)
+		messageEventsRepo = fakes.NewMessageEventsRepo()
+		mailer = strategies.NewMailer(queue, fakes.NewIncrementingGUIDGenerator().Generate, messagesRepo, messageEventsRepo)
 		space = cf.CloudControllerSpace{Name: "the-space"}
 		org = cf.CloudControllerOrganization{Name: "the-org"}
 	})
@@ -45,22 +44,26 @@ var _ = Describe("Mailer", func() {
 				{
 					Status:         "queued",
 					Recipient:      "user-1",
-					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334455",
+					BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334456",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-2@example.com",
-					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334456",
+					BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334457",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-3",
-					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334457",
+					BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334458",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-4",
-					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334458",
+					BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+					NotificationID: "deadbeef-aabb-ccdd-eeff-001122334459",
 				},
 			}))
 		})
@@ -83,44 +86,98 @@ var _ = Describe("Mailer", func() {
 			Expect(deliveries).To(HaveLen(4))
 			Expect(deliveries).To(ConsistOf([]postal.Delivery{
 				{
-					Options:      postal.Options{},
+					Options:      postal.Options{BatchID: "deadbeef-aabb-ccdd-eeff-001122334455"},
 					UserGUID:     "user-1",
 					Space:        space,
 					Organization: org,
 					ClientID:     "the-client",
-					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334455",
+					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334456",
 					Scope:        "my.scope",
 				},
 				{
-					Options:      postal.Options{},
+					Options:      postal.Options{BatchID: "deadbeef-aabb-ccdd-eeff-001122334455"},
 					UserGUID:     "user-2",
 					Space:        space,
 					Organization: org,
 					ClientID:     "the-client",
-					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334456",
+					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334457",
 					Scope:        "my.scope",
 				},
 				{
-					Options:      postal.Options{},
+					Options:      postal.Options{BatchID: "deadbeef-aabb-ccdd-eeff-001122334455"},
 					UserGUID:     "user-3",
 					Space:        space,
 					Organization: org,
 					ClientID:     "the-client",
-					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334457",
+					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334458",
 					Scope:        "my.scope",
 				},
 				{
-					Options:      postal.Options{},
+					Options:      postal.Options{BatchID: "deadbeef-aabb-ccdd-eeff-001122334455"},
 					UserGUID:     "user-4",
 					Space:        space,
 					Organization: org,
 					ClientID:     "the-client",
-					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334458",
+					MessageID:    "deadbeef-aabb-ccdd-eeff-001122334459",
 					Scope:        "my.scope",
 				},
 			}))
 		})
 
+		It("gives each delivery the data of its user", func() {
+			users := []strategies.User{{Email: "user-1@example.com", Data: map[string]interface{}{"total": "$10"}}}
+			mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
+
+			job := <-queue.Reserve("me")
+			var delivery postal.Delivery
+			err := job.Unmarshal(&delivery)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(delivery.Data).To(Equal(map[string]interface{}{"total": "$10"}))
+		})
+
+		It("records each message with its batch, client and recipient", func() {
+			users := []strategies.User{{GUID: "user-1"}, {Email: "user-2@example.com"}}
+			mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
+
+			message, err := messagesRepo.FindByID(conn, "deadbeef-aabb-ccdd-eeff-001122334456")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.BatchID).To(Equal("deadbeef-aabb-ccdd-eeff-001122334455"))
+			Expect(message.ClientID).To(Equal("the-client"))
+			Expect(message.Recipient).To(Equal("user-1"))
+
+			message, err = messagesRepo.FindByID(conn, "deadbeef-aabb-ccdd-eeff-001122334457")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.BatchID).To(Equal("deadbeef-aabb-ccdd-eeff-001122334455"))
+			Expect(message.Recipient).To(Equal("user-2@example.com"))
+		})
+
+		It("starts the history of each message", func() {
+			users := []strategies.User{{GUID: "user-1"}}
+			responses := mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
+
+			events, err := messageEventsRepo.FindAllByMessageID(conn, responses[0].NotificationID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Status).To(Equal(postal.StatusQueued))
+			Expect(events[0].Attempt).To(Equal(0))
+		})
+
+		It("uses the batch given in the options when there is one", func() {
+			users := []strategies.User{{GUID: "user-1"}}
+			responses := mailer.Deliver(conn, users, postal.Options{BatchID: "the-batch"}, space, org, "the-client", "my.scope")
+
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0].BatchID).To(Equal("the-batch"))
+			Expect(responses[0].NotificationID).To(Equal("deadbeef-aabb-ccdd-eeff-001122334455"))
+
+			message, err := messagesRepo.FindByID(conn, responses[0].NotificationID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.BatchID).To(Equal("the-batch"))
+		})
+
 		It("Upserts a StatusQueued for each of the jobs", func() {
 			users := []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
 			mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
@@ -146,6 +203,160 @@ var _ = Describe("Mailer", func() {
 			Expect(statuses).To(ConsistOf([]string{postal.StatusQueued, postal.StatusQueued, postal.StatusQueued, postal.StatusQueued}))
 		})
 
+		Context("prioritizing jobs", func() {
+			It("enqueues jobs with normal priority by default", func() {
+				mailer.Deliver(conn, []strategies.User{{GUID: "user-1"}}, postal.Options{}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				Expect(job.Priority).To(Equal(gobble.PriorityNormal))
+			})
+
+			It("enqueues jobs for critical notifications with high priority", func() {
+				mailer.Deliver(conn, []strategies.User{{GUID: "user-1"}}, postal.Options{Critical: true}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				Expect(job.Priority).To(Equal(gobble.PriorityHigh))
+			})
+
+			It("enqueues jobs with the requested priority", func() {
+				mailer.Deliver(conn, []strategies.User{{GUID: "user-1"}}, postal.Options{Priority: postal.PriorityLow, Critical: true}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				Expect(job.Priority).To(Equal(gobble.PriorityLow))
+			})
+		})
+
+		Context("when the options include a send time in the future", func() {
+			var sendAt time.Time
+
+			BeforeEach(func() {
+				sendAt = time.Now().Add(2 * time.Hour).Truncate(time.Second)
+			})
+
+			It("schedules the jobs to become active at the send time", func() {
+				users := []strategies.User{{GUID: "user-1"}}
+				responses := mailer.Deliver(conn, users, postal.Options{SendAt: sendAt}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				Expect(job.ActiveAt).To(Equal(sendAt))
+				Expect(responses).To(HaveLen(1))
+				Expect(responses[0].Status).To(Equal(postal.StatusScheduled))
+			})
+
+			It("records the messages as scheduled along with their job", func() {
+				users := []strategies.User{{GUID: "user-1"}}
+				mailer.Deliver(conn, users, postal.Options{SendAt: sendAt}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				var delivery postal.Delivery
+				err := job.Unmarshal(&delivery)
+				if err != nil {
+					panic(err)
+				}
+
+				message, err := messagesRepo.FindByID(conn, delivery.MessageID)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(message.Status).To(Equal(postal.StatusScheduled))
+				Expect(message.JobID).To(Equal(job.ID))
+			})
+		})
+
+		Context("when the options include cc and bcc addresses", func() {
+			var options postal.Options
+
+			BeforeEach(func() {
+				options = postal.Options{
+					CC:  []string{"copy@example.com"},
+					BCC: []string{"hidden@example.com"},
+				}
+			})
+
+			It("responds with a message for each copy", func() {
+				users := []strategies.User{{Email: "user@example.com"}}
+				responses := mailer.Deliver(conn, users, options, space, org, "the-client", "my.scope")
+
+				Expect(responses).To(Equal([]strategies.Response{
+					{
+						Status:         "queued",
+						Recipient:      "user@example.com",
+						BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+						NotificationID: "deadbeef-aabb-ccdd-eeff-001122334456",
+					},
+					{
+						Status:         "queued",
+						Recipient:      "copy@example.com",
+						BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+						NotificationID: "deadbeef-aabb-ccdd-eeff-001122334457",
+					},
+					{
+						Status:         "queued",
+						Recipient:      "hidden@example.com",
+						BatchID:        "deadbeef-aabb-ccdd-eeff-001122334455",
+						NotificationID: "deadbeef-aabb-ccdd-eeff-001122334458",
+					},
+				}))
+			})
+
+			It("enqueues a single job that carries the copies", func() {
+				users := []strategies.User{{Email: "user@example.com"}}
+				mailer.Deliver(conn, users, options, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				var delivery postal.Delivery
+				err := job.Unmarshal(&delivery)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(delivery.MessageID).To(Equal("deadbeef-aabb-ccdd-eeff-001122334456"))
+				Expect(delivery.CC).To(Equal([]postal.Recipient{{Email: "copy@example.com", MessageID: "deadbeef-aabb-ccdd-eeff-001122334457"}}))
+				Expect(delivery.BCC).To(Equal([]postal.Recipient{{Email: "hidden@example.com", MessageID: "deadbeef-aabb-ccdd-eeff-001122334458"}}))
+				Consistently(queue.Reserve("me")).ShouldNot(Receive())
+			})
+
+			It("records each copy as a message with its own history", func() {
+				users := []strategies.User{{Email: "user@example.com"}}
+				options.SendAt = time.Now().Add(2 * time.Hour)
+				mailer.Deliver(conn, users, options, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+
+				for _, copy := range []string{"copy@example.com", "hidden@example.com"} {
+					var message models.Message
+					for _, m := range messagesRepo.Messages {
+						if m.Recipient == copy {
+							message = m
+						}
+					}
+
+					Expect(message.ID).NotTo(BeEmpty())
+					Expect(message.Status).To(Equal(postal.StatusScheduled))
+					Expect(message.BatchID).To(Equal("deadbeef-aabb-ccdd-eeff-001122334455"))
+					Expect(message.ClientID).To(Equal("the-client"))
+					Expect(message.JobID).To(Equal(job.ID))
+
+					events, err := messageEventsRepo.FindAllByMessageID(conn, message.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(events).To(HaveLen(1))
+					Expect(events[0].Status).To(Equal(postal.StatusScheduled))
+				}
+			})
+		})
+
+		Context("when the options include a send time in the past", func() {
+			It("queues the jobs for immediate delivery", func() {
+				users := []strategies.User{{GUID: "user-1"}}
+				responses := mailer.Deliver(conn, users, postal.Options{SendAt: time.Now().Add(-1 * time.Hour)}, space, org, "the-client", "my.scope")
+
+				job := <-queue.Reserve("me")
+				Expect(job.ActiveAt.IsZero()).To(BeTrue())
+				Expect(responses[0].Status).To(Equal(postal.StatusQueued))
+			})
+		})
+
 		Context("using a transaction", func() {
 			It("commits the transaction when everything goes well", func() {
 				users := []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
@@ -157,6 +368,14 @@ var _ = Describe("Mailer", func() {
 				Expect(responses).ToNot(BeEmpty())
 			})
 
+			It("enqueues the jobs through the transaction", func() {
+				users := []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}}
+				mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
+
+				Expect(queue.EnqueueTransactions).To(HaveLen(2))
+				Expect(queue.EnqueueTransactions[0]).To(Equal(conn))
+			})
+
 			It("rolls back the transaction when there is an error in queueing", func() {
 				queue.EnqueueError = errors.New("BOOM!")
 				users := []strategies.User{{GUID: "user-1"}}
@@ -177,6 +396,16 @@ var _ = Describe("Mailer", func() {
 				Expect(conn.RollbackWasCalled).To(BeTrue())
 			})
 
+			It("rolls back the transaction when there is an error in recording the message history", func() {
+				messageEventsRepo.CreateError = errors.New("BOOM!")
+				users := []strategies.User{{GUID: "user-1"}}
+				responses := mailer.Deliver(conn, users, postal.Options{}, space, org, "the-client", "my.scope")
+
+				Expect(conn.CommitWasCalled).To(BeFalse())
+				Expect(conn.RollbackWasCalled).To(BeTrue())
+				Expect(responses).To(Equal([]strategies.Response{}))
+			})
+
 			It("returns an empty []Response{} if transaction fails", func() {
 				conn.CommitError = "the commit blew up"
 				users := []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
@@ -189,4 +418,27 @@ var _ = Describe("Mailer", func() {
 			})
 		})
 	})
+
+	Describe("Enqueue", func() {
+		It("enqueues and records the deliveries within the caller's transaction", func() {
+			users := []strategies.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			responses, err := mailer.Enqueue(conn, users, postal.Options{}, space, org, "the-client", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responses).To(HaveLen(2))
+
+			Expect(queue.EnqueueTransactions).To(Equal([]gobble.ConnectionInterface{conn, conn}))
+			Expect(messagesRepo.Messages).To(HaveLen(2))
+			Expect(conn.BeginWasCalled).To(BeFalse())
+			Expect(conn.CommitWasCalled).To(BeFalse())
+			Expect(conn.RollbackWasCalled).To(BeFalse())
+		})
+
+		It("returns errors without rolling back the caller's transaction", func() {
+			queue.EnqueueError = errors.New("BOOM!")
+
+			_, err := mailer.Enqueue(conn, []strategies.User{{GUID: "user-1"}}, postal.Options{}, space, org, "the-client", "")
+			Expect(err).To(MatchError("BOOM!"))
+			Expect(conn.RollbackWasCalled).To(BeFalse())
+		})
+	})
 })
@@ -1,7 +1,6 @@
 package strategies
 
 import (
-	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/utilities"
@@ -10,53 +9,77 @@ import (
 const OrganizationEndorsement = "You received this message because you belong to the {{.Organization}} organization."
 const OrganizationRoleEndorsement = "You received this message because you are an {{.OrganizationRole}} in the {{.Organization}} organization."
 
+const OrganizationAudience = "organization"
+
 type OrganizationStrategy struct {
 	tokenLoader        postal.TokenLoaderInterface
 	organizationLoader utilities.OrganizationLoaderInterface
 	findsUserGUIDs     utilities.FindsUserGUIDsInterface
-	mailer             MailerInterface
+	launcher           CampaignLauncherInterface
 }
 
 func NewOrganizationStrategy(tokenLoader postal.TokenLoaderInterface, organizationLoader utilities.OrganizationLoaderInterface,
-	findsUserGUIDs utilities.FindsUserGUIDsInterface, mailer MailerInterface) OrganizationStrategy {
+	findsUserGUIDs utilities.FindsUserGUIDsInterface, launcher CampaignLauncherInterface) OrganizationStrategy {
 
 	return OrganizationStrategy{
 		tokenLoader:        tokenLoader,
 		organizationLoader: organizationLoader,
 		findsUserGUIDs:     findsUserGUIDs,
-		mailer:             mailer,
+		launcher:           launcher,
 	}
 }
 
-func (strategy OrganizationStrategy) Dispatch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) ([]Response, error) {
-	responses := []Response{}
-	options.Endorsement = OrganizationEndorsement
+func (strategy OrganizationStrategy) Launch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) (CampaignResponse, error) {
+	token, err := strategy.tokenLoader.Load()
+	if err != nil {
+		return CampaignResponse{}, err
+	}
+
+	_, err = strategy.organizationLoader.Load(guid, token)
+	if err != nil {
+		return CampaignResponse{}, err
+	}
 
+	return strategy.launcher.Launch(conn, postal.Expansion{
+		Audience: OrganizationAudience,
+		GUID:     guid,
+		ClientID: clientID,
+		Options:  options,
+	})
+}
+
+func (strategy OrganizationStrategy) Expand(guid string, options postal.Options) (Audience, error) {
+	options.Endorsement = OrganizationEndorsement
 	if options.Role != "" {
 		options.Endorsement = OrganizationRoleEndorsement
 	}
 
 	token, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return Audience{}, err
 	}
 
 	organization, err := strategy.organizationLoader.Load(guid, token)
 	if err != nil {
-		return responses, err
+		return Audience{}, err
 	}
 
-	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToOrganization(guid, options.Role, token)
+	return Audience{
+		Options:      options,
+		Organization: organization,
+	}, nil
+}
+
+func (strategy OrganizationStrategy) Recipients(guid string, options postal.Options, after string, count int) ([]User, error) {
+	token, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return []User{}, err
 	}
 
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToOrganization(guid, options.Role, token, after, count)
+	if err != nil {
+		return []User{}, err
 	}
 
-	responses = strategy.mailer.Deliver(conn, users, options, cf.CloudControllerSpace{}, organization, clientID, "")
-
-	return responses, nil
+	return usersWithGUIDs(userGUIDs), nil
 }
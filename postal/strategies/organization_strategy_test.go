@@ -17,7 +17,7 @@ var _ = Describe("Organization Strategy", func() {
 	var options postal.Options
 	var tokenLoader *fakes.TokenLoader
 	var organizationLoader *fakes.OrganizationLoader
-	var mailer *fakes.Mailer
+	var launcher *fakes.CampaignLauncher
 	var clientID string
 	var conn *fakes.DBConn
 	var findsUserGUIDs *fakes.FindsUserGUIDs
@@ -38,7 +38,7 @@ var _ = Describe("Organization Strategy", func() {
 		tokenLoader = fakes.NewTokenLoader()
 		tokenLoader.Token = fakes.BuildToken(tokenHeader, tokenClaims)
 
-		mailer = fakes.NewMailer()
+		launcher = fakes.NewCampaignLauncher()
 
 		findsUserGUIDs = fakes.NewFindsUserGUIDs()
 		findsUserGUIDs.OrganizationGuids["org-001"] = []string{"user-123", "user-456"}
@@ -49,90 +49,133 @@ var _ = Describe("Organization Strategy", func() {
 			GUID: "org-001",
 		}
 
-		strategy = strategies.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserGUIDs, mailer)
+		strategy = strategies.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserGUIDs, launcher)
 	})
 
-	Describe("Dispatch", func() {
-		Context("when the request is valid", func() {
-			BeforeEach(func() {
-				options = postal.Options{
-					KindID:            "forgot_password",
-					KindDescription:   "Password reminder",
-					SourceDescription: "Login system",
-					Text:              "Please reset your password by clicking on this link...",
-					HTML:              postal.HTML{BodyContent: "<p>Please reset your password by clicking on this link...</p>"},
-				}
+	BeforeEach(func() {
+		options = postal.Options{
+			KindID:            "forgot_password",
+			KindDescription:   "Password reminder",
+			SourceDescription: "Login system",
+			Text:              "Please reset your password by clicking on this link...",
+			HTML:              postal.HTML{BodyContent: "<p>Please reset your password by clicking on this link...</p>"},
+		}
+	})
+
+	Describe("Launch", func() {
+		It("launches a campaign for the organization", func() {
+			launcher.Response = strategies.CampaignResponse{
+				CampaignID: "campaign-001",
+				Status:     "expanding",
+			}
+
+			response, err := strategy.Launch(clientID, "org-001", options, conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(launcher.Response))
+
+			Expect(launcher.LaunchArguments).To(Equal([]interface{}{conn, postal.Expansion{
+				Audience: strategies.OrganizationAudience,
+				GUID:     "org-001",
+				ClientID: clientID,
+				Options:  options,
+			}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "org-001", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
 			})
 
-			It("call mailer.Deliver with the correct arguments for an organization", func() {
-				Expect(options.Endorsement).To(BeEmpty())
-
-				_, err := strategy.Dispatch(clientID, "org-001", options, conn)
-				if err != nil {
-					panic(err)
-				}
-
-				options.Endorsement = strategies.OrganizationEndorsement
-				users := []strategies.User{{GUID: "user-123"}, {GUID: "user-456"}}
-
-				Expect(mailer.DeliverArguments).To(Equal(map[string]interface{}{
-					"connection": conn,
-					"users":      users,
-					"options":    options,
-					"space":      cf.CloudControllerSpace{},
-					"org": cf.CloudControllerOrganization{
-						Name: "my-org",
-						GUID: "org-001",
-					},
-					"client": clientID,
-					"scope":  "",
-				}))
+			It("returns an error when the organization cannot be loaded", func() {
+				organizationLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "org-009", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
 			})
 
-			Context("when the org role field is set", func() {
-				It("calls mailer.Deliver with the correct arguments", func() {
-					options.Role = "OrgManager"
+			It("returns an error when the launcher fails", func() {
+				launcher.LaunchError = errors.New("BOOM!")
 
-					Expect(options.Endorsement).To(BeEmpty())
+				_, err := strategy.Launch(clientID, "org-001", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+			})
+		})
+	})
 
-					_, err := strategy.Dispatch(clientID, "org-001", options, conn)
-					if err != nil {
-						panic(err)
-					}
+	Describe("Expand", func() {
+		It("returns the organization", func() {
+			Expect(options.Endorsement).To(BeEmpty())
+
+			audience, err := strategy.Expand("org-001", options)
+			Expect(err).NotTo(HaveOccurred())
+
+			options.Endorsement = strategies.OrganizationEndorsement
+			Expect(audience).To(Equal(strategies.Audience{
+				Options: options,
+				Organization: cf.CloudControllerOrganization{
+					Name: "my-org",
+					GUID: "org-001",
+				},
+			}))
+		})
 
-					options.Endorsement = strategies.OrganizationRoleEndorsement
+		Context("when the org role field is set", func() {
+			It("uses the role endorsement", func() {
+				options.Role = "OrgManager"
 
-					Expect(mailer.DeliverArguments).To(ContainElement(options))
-				})
+				audience, err := strategy.Expand("org-001", options)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(audience.Options.Endorsement).To(Equal(strategies.OrganizationRoleEndorsement))
 			})
 		})
 
 		Context("failure cases", func() {
-			Context("when token loader fails to return a token", func() {
-				It("returns an error", func() {
-					tokenLoader.LoadError = errors.New("BOOM!")
-					_, err := strategy.Dispatch(clientID, "org-001", options, conn)
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Expand("org-001", options)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+			})
 
-					Expect(err).To(Equal(errors.New("BOOM!")))
-				})
+			It("returns an error when the organization cannot be loaded", func() {
+				organizationLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Expand("org-009", options)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 
-			Context("when organizationLoader fails to load an organization", func() {
-				It("returns the error", func() {
-					organizationLoader.LoadError = errors.New("BOOM!")
-					_, err := strategy.Dispatch(clientID, "org-009", options, conn)
+		})
+	})
+
+	Describe("Recipients", func() {
+		It("returns a page of the users of the organization after the given one", func() {
+			users, err := strategy.Recipients("org-001", options, "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-123"}}))
+
+			users, err = strategy.Recipients("org-001", options, "user-123", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-456"}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
 
-					Expect(err).To(Equal(errors.New("BOOM!")))
-				})
+				_, err := strategy.Recipients("org-001", options, "", 10)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 
-			Context("when finds user GUIDs returns an error", func() {
-				It("returns an error", func() {
-					findsUserGUIDs.UserGUIDsBelongingToOrganizationError = errors.New("BOOM!")
+			It("returns an error when findsUserGUIDs fails", func() {
+				findsUserGUIDs.UserGUIDsBelongingToOrganizationError = errors.New("BOOM!")
 
-					_, err := strategy.Dispatch(clientID, "org-001", options, conn)
-					Expect(err).ToNot(BeNil())
-				})
+				_, err := strategy.Recipients("org-001", options, "", 10)
+				Expect(err).To(Equal(findsUserGUIDs.UserGUIDsBelongingToOrganizationError))
 			})
 		})
 	})
@@ -4,4 +4,10 @@ type Response struct {
 	Status         string `json:"status"`
 	Recipient      string `json:"recipient"`
 	NotificationID string `json:"notification_id"`
+	BatchID        string `json:"batch_id"`
+}
+
+type CampaignResponse struct {
+	CampaignID string `json:"campaign_id"`
+	Status     string `json:"status"`
 }
@@ -8,56 +8,82 @@ import (
 
 const SpaceEndorsement = "You received this message because you belong to the {{.Space}} space in the {{.Organization}} organization."
 
+const SpaceAudience = "space"
+
 type SpaceStrategy struct {
 	tokenLoader        postal.TokenLoaderInterface
 	spaceLoader        utilities.SpaceLoaderInterface
 	organizationLoader utilities.OrganizationLoaderInterface
 	findsUserGUIDs     utilities.FindsUserGUIDsInterface
-	mailer             MailerInterface
+	launcher           CampaignLauncherInterface
 }
 
 func NewSpaceStrategy(tokenLoader postal.TokenLoaderInterface, spaceLoader utilities.SpaceLoaderInterface, organizationLoader utilities.OrganizationLoaderInterface,
-	findsUserGUIDs utilities.FindsUserGUIDsInterface, mailer MailerInterface) SpaceStrategy {
+	findsUserGUIDs utilities.FindsUserGUIDsInterface, launcher CampaignLauncherInterface) SpaceStrategy {
 
 	return SpaceStrategy{
 		tokenLoader:        tokenLoader,
 		spaceLoader:        spaceLoader,
 		organizationLoader: organizationLoader,
 		findsUserGUIDs:     findsUserGUIDs,
-		mailer:             mailer,
+		launcher:           launcher,
 	}
 }
 
-func (strategy SpaceStrategy) Dispatch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) ([]Response, error) {
-	responses := []Response{}
-	options.Endorsement = SpaceEndorsement
-
+func (strategy SpaceStrategy) Launch(clientID, guid string, options postal.Options, conn models.ConnectionInterface) (CampaignResponse, error) {
 	token, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return CampaignResponse{}, err
 	}
 
-	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToSpace(guid, token)
+	_, err = strategy.spaceLoader.Load(guid, token)
 	if err != nil {
-		return responses, err
+		return CampaignResponse{}, err
 	}
 
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+	return strategy.launcher.Launch(conn, postal.Expansion{
+		Audience: SpaceAudience,
+		GUID:     guid,
+		ClientID: clientID,
+		Options:  options,
+	})
+}
+
+func (strategy SpaceStrategy) Expand(guid string, options postal.Options) (Audience, error) {
+	options.Endorsement = SpaceEndorsement
+
+	token, err := strategy.tokenLoader.Load()
+	if err != nil {
+		return Audience{}, err
 	}
 
 	space, err := strategy.spaceLoader.Load(guid, token)
 	if err != nil {
-		return responses, err
+		return Audience{}, err
 	}
 
 	org, err := strategy.organizationLoader.Load(space.OrganizationGUID, token)
 	if err != nil {
-		return responses, err
+		return Audience{}, err
 	}
 
-	responses = strategy.mailer.Deliver(conn, users, options, space, org, clientID, "")
+	return Audience{
+		Options:      options,
+		Space:        space,
+		Organization: org,
+	}, nil
+}
+
+func (strategy SpaceStrategy) Recipients(guid string, options postal.Options, after string, count int) ([]User, error) {
+	token, err := strategy.tokenLoader.Load()
+	if err != nil {
+		return []User{}, err
+	}
+
+	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToSpace(guid, token, after, count)
+	if err != nil {
+		return []User{}, err
+	}
 
-	return responses, nil
+	return usersWithGUIDs(userGUIDs), nil
 }
@@ -18,7 +18,7 @@ var _ = Describe("Space Strategy", func() {
 	var tokenLoader *fakes.TokenLoader
 	var spaceLoader *fakes.SpaceLoader
 	var organizationLoader *fakes.OrganizationLoader
-	var mailer *fakes.Mailer
+	var launcher *fakes.CampaignLauncher
 	var clientID string
 	var conn *fakes.DBConn
 	var findsUserGUIDs *fakes.FindsUserGUIDs
@@ -39,7 +39,7 @@ var _ = Describe("Space Strategy", func() {
 		tokenLoader = fakes.NewTokenLoader()
 		tokenLoader.Token = fakes.BuildToken(tokenHeader, tokenClaims)
 
-		mailer = fakes.NewMailer()
+		launcher = fakes.NewCampaignLauncher()
 
 		findsUserGUIDs = fakes.NewFindsUserGUIDs()
 		findsUserGUIDs.SpaceGuids["space-001"] = []string{"user-123", "user-456"}
@@ -57,77 +57,124 @@ var _ = Describe("Space Strategy", func() {
 			GUID: "org-001",
 		}
 
-		strategy = strategies.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserGUIDs, mailer)
+		strategy = strategies.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserGUIDs, launcher)
 	})
 
-	Describe("Dispatch", func() {
-		Context("when the request is valid", func() {
-			BeforeEach(func() {
-				options = postal.Options{
-					KindID:            "forgot_password",
-					KindDescription:   "Password reminder",
-					SourceDescription: "Login system",
-					Text:              "Please reset your password by clicking on this link...",
-					HTML:              postal.HTML{BodyContent: "<p>Please reset your password by clicking on this link...</p>"},
-				}
+	BeforeEach(func() {
+		options = postal.Options{
+			KindID:            "forgot_password",
+			KindDescription:   "Password reminder",
+			SourceDescription: "Login system",
+			Text:              "Please reset your password by clicking on this link...",
+			HTML:              postal.HTML{BodyContent: "<p>Please reset your password by clicking on this link...</p>"},
+		}
+	})
+
+	Describe("Launch", func() {
+		It("launches a campaign for the space", func() {
+			launcher.Response = strategies.CampaignResponse{
+				CampaignID: "campaign-001",
+				Status:     "expanding",
+			}
+
+			response, err := strategy.Launch(clientID, "space-001", options, conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(launcher.Response))
+
+			Expect(launcher.LaunchArguments).To(Equal([]interface{}{conn, postal.Expansion{
+				Audience: strategies.SpaceAudience,
+				GUID:     "space-001",
+				ClientID: clientID,
+				Options:  options,
+			}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "space-001", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
+			})
+
+			It("returns an error when the space cannot be loaded", func() {
+				spaceLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "space-000", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
 			})
 
-			It("calls mailer.Deliver with the correct arguments for a space", func() {
-				Expect(options.Endorsement).To(BeEmpty())
-
-				_, err := strategy.Dispatch(clientID, "space-001", options, conn)
-				if err != nil {
-					panic(err)
-				}
-
-				users := []strategies.User{{GUID: "user-123"}, {GUID: "user-456"}}
-
-				options.Endorsement = strategies.SpaceEndorsement
-				Expect(mailer.DeliverArguments).To(Equal(map[string]interface{}{
-					"connection": conn,
-					"users":      users,
-					"options":    options,
-					"space": cf.CloudControllerSpace{
-						GUID:             "space-001",
-						Name:             "production",
-						OrganizationGUID: "org-001",
-					},
-					"org": cf.CloudControllerOrganization{
-						Name: "the-org",
-						GUID: "org-001",
-					},
-					"client": clientID,
-					"scope":  "",
-				}))
+			It("returns an error when the launcher fails", func() {
+				launcher.LaunchError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, "space-001", options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 		})
+	})
+
+	Describe("Expand", func() {
+		It("returns the space and its organization", func() {
+			Expect(options.Endorsement).To(BeEmpty())
+
+			audience, err := strategy.Expand("space-001", options)
+			Expect(err).NotTo(HaveOccurred())
+
+			options.Endorsement = strategies.SpaceEndorsement
+			Expect(audience).To(Equal(strategies.Audience{
+				Options: options,
+				Space: cf.CloudControllerSpace{
+					GUID:             "space-001",
+					Name:             "production",
+					OrganizationGUID: "org-001",
+				},
+				Organization: cf.CloudControllerOrganization{
+					Name: "the-org",
+					GUID: "org-001",
+				},
+			}))
+		})
 
 		Context("failure cases", func() {
-			Context("when token loader fails to return a token", func() {
-				It("returns an error", func() {
-					tokenLoader.LoadError = errors.New("BOOM!")
-					_, err := strategy.Dispatch(clientID, "space-001", options, conn)
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Expand("space-001", options)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+			})
+
+			It("returns an error when the space cannot be loaded", func() {
+				spaceLoader.LoadError = errors.New("BOOM!")
 
-					Expect(err).To(Equal(errors.New("BOOM!")))
-				})
+				_, err := strategy.Expand("space-000", options)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 
-			Context("when spaceLoader fails to load a space", func() {
-				It("returns an error", func() {
-					spaceLoader.LoadError = errors.New("BOOM!")
-					_, err := strategy.Dispatch(clientID, "space-000", options, conn)
+		})
+	})
+
+	Describe("Recipients", func() {
+		It("returns a page of the users of the space after the given one", func() {
+			users, err := strategy.Recipients("space-001", options, "user-123", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-456"}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
 
-					Expect(err).To(Equal(errors.New("BOOM!")))
-				})
+				_, err := strategy.Recipients("space-001", options, "", 10)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 
-			Context("when findsUserGUIDs returns an err", func() {
-				It("returns an error", func() {
-					findsUserGUIDs.UserGUIDsBelongingToSpaceError = errors.New("BOOM!")
+			It("returns an error when findsUserGUIDs fails", func() {
+				findsUserGUIDs.UserGUIDsBelongingToSpaceError = errors.New("BOOM!")
 
-					_, err := strategy.Dispatch(clientID, "space-001", options, conn)
-					Expect(err).To(Equal(findsUserGUIDs.UserGUIDsBelongingToSpaceError))
-				})
+				_, err := strategy.Recipients("space-001", options, "", 10)
+				Expect(err).To(Equal(findsUserGUIDs.UserGUIDsBelongingToSpaceError))
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 )
@@ -8,3 +9,34 @@ import (
 type StrategyInterface interface {
 	Dispatch(clientID string, guid string, options postal.Options, conn models.ConnectionInterface) ([]Response, error)
 }
+
+// CampaignStrategyInterface is implemented by strategies whose recipients are
+// too numerous to resolve within a request. Launch records a campaign and
+// leaves the recipients to be expanded by a worker.
+type CampaignStrategyInterface interface {
+	Launch(clientID string, guid string, options postal.Options, conn models.ConnectionInterface) (CampaignResponse, error)
+}
+
+// AudienceStrategyInterface is implemented by the strategies of campaigns.
+// Expand describes the audience, and Recipients returns its members a page
+// at a time, in order of their GUIDs, starting after the given GUID.
+type AudienceStrategyInterface interface {
+	Expand(guid string, options postal.Options) (Audience, error)
+	Recipients(guid string, options postal.Options, after string, count int) ([]User, error)
+}
+
+type Audience struct {
+	Options      postal.Options
+	Space        cf.CloudControllerSpace
+	Organization cf.CloudControllerOrganization
+	Scope        string
+}
+
+func usersWithGUIDs(guids []string) []User {
+	users := []User{}
+	for _, guid := range guids {
+		users = append(users, User{GUID: guid})
+	}
+
+	return users
+}
@@ -1,7 +1,6 @@
 package strategies
 
 import (
-	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/utilities"
@@ -9,10 +8,12 @@ import (
 
 const ScopeEndorsement = "You received this message because you have the {{.Scope}} scope."
 
+const ScopeAudience = "uaa_scope"
+
 type UAAScopeStrategy struct {
 	findsUserGUIDs utilities.FindsUserGUIDsInterface
 	tokenLoader    postal.TokenLoaderInterface
-	mailer         MailerInterface
+	launcher       CampaignLauncherInterface
 }
 
 type DefaultScopeError struct{}
@@ -22,41 +23,54 @@ func (d DefaultScopeError) Error() string {
 }
 
 func NewUAAScopeStrategy(tokenLoader postal.TokenLoaderInterface, findsUserGUIDs utilities.FindsUserGUIDsInterface,
-	mailer MailerInterface) UAAScopeStrategy {
+	launcher CampaignLauncherInterface) UAAScopeStrategy {
 
 	return UAAScopeStrategy{
 		findsUserGUIDs: findsUserGUIDs,
 		tokenLoader:    tokenLoader,
-		mailer:         mailer,
+		launcher:       launcher,
 	}
 }
 
-func (strategy UAAScopeStrategy) Dispatch(clientID, scope string, options postal.Options, conn models.ConnectionInterface) ([]Response, error) {
-	responses := []Response{}
-	options.Endorsement = ScopeEndorsement
-
+func (strategy UAAScopeStrategy) Launch(clientID, scope string, options postal.Options, conn models.ConnectionInterface) (CampaignResponse, error) {
 	if strategy.scopeIsDefault(scope) {
-		return responses, DefaultScopeError{}
+		return CampaignResponse{}, DefaultScopeError{}
 	}
 
-	_, err := strategy.tokenLoader.Load() // TODO: (rm) this triggers a weird side-effect that is required
+	_, err := strategy.tokenLoader.Load()
 	if err != nil {
-		return responses, err
+		return CampaignResponse{}, err
 	}
 
-	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToScope(scope)
+	return strategy.launcher.Launch(conn, postal.Expansion{
+		Audience: ScopeAudience,
+		GUID:     scope,
+		ClientID: clientID,
+		Options:  options,
+	})
+}
+
+func (strategy UAAScopeStrategy) Expand(scope string, options postal.Options) (Audience, error) {
+	options.Endorsement = ScopeEndorsement
+
+	return Audience{
+		Options: options,
+		Scope:   scope,
+	}, nil
+}
+
+func (strategy UAAScopeStrategy) Recipients(scope string, options postal.Options, after string, count int) ([]User, error) {
+	_, err := strategy.tokenLoader.Load() // TODO: (rm) this triggers a weird side-effect that is required
 	if err != nil {
-		return responses, err
+		return []User{}, err
 	}
 
-	var users []User
-	for _, guid := range userGUIDs {
-		users = append(users, User{GUID: guid})
+	userGUIDs, err := strategy.findsUserGUIDs.UserGUIDsBelongingToScope(scope, after, count)
+	if err != nil {
+		return []User{}, err
 	}
 
-	responses = strategy.mailer.Deliver(conn, users, options, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, clientID, scope)
-
-	return responses, nil
+	return usersWithGUIDs(userGUIDs), nil
 }
 
 func (strategy UAAScopeStrategy) scopeIsDefault(scope string) bool {
@@ -3,7 +3,6 @@ package strategies_test
 import (
 	"errors"
 
-	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/fakes"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
@@ -16,7 +15,7 @@ var _ = Describe("UAA Scope Strategy", func() {
 	var strategy strategies.UAAScopeStrategy
 	var options postal.Options
 	var tokenLoader *fakes.TokenLoader
-	var mailer *fakes.Mailer
+	var launcher *fakes.CampaignLauncher
 	var clientID string
 	var conn *fakes.DBConn
 	var findsUserGUIDs *fakes.FindsUserGUIDs
@@ -39,78 +38,111 @@ var _ = Describe("UAA Scope Strategy", func() {
 		tokenLoader = fakes.NewTokenLoader()
 		tokenLoader.Token = fakes.BuildToken(tokenHeader, tokenClaims)
 
-		mailer = fakes.NewMailer()
+		launcher = fakes.NewCampaignLauncher()
 
 		findsUserGUIDs = fakes.NewFindsUserGUIDs()
 		findsUserGUIDs.GUIDsWithScopes[scope] = []string{"user-311"}
 
-		strategy = strategies.NewUAAScopeStrategy(tokenLoader, findsUserGUIDs, mailer)
+		strategy = strategies.NewUAAScopeStrategy(tokenLoader, findsUserGUIDs, launcher)
 	})
 
-	Describe("Dispatch", func() {
-		Context("when the request is valid", func() {
-			BeforeEach(func() {
-				options = postal.Options{
-					KindID:            "forgot_waterbottle",
-					KindDescription:   "Water Bottle Reminder",
-					SourceDescription: "The Water Bottle System",
-					Text:              "Please make sure to leave your bottle in a place that is safe and dry",
-					HTML:              postal.HTML{BodyContent: "<p>The water bottle needs to be safe and dry</p>"},
-				}
+	BeforeEach(func() {
+		options = postal.Options{
+			KindID:            "forgot_waterbottle",
+			KindDescription:   "Water Bottle Reminder",
+			SourceDescription: "The Water Bottle System",
+			Text:              "Please make sure to leave your bottle in a place that is safe and dry",
+			HTML:              postal.HTML{BodyContent: "<p>The water bottle needs to be safe and dry</p>"},
+		}
+	})
+
+	Describe("Launch", func() {
+		It("launches a campaign for the scope", func() {
+			launcher.Response = strategies.CampaignResponse{
+				CampaignID: "campaign-001",
+				Status:     "expanding",
+			}
+
+			response, err := strategy.Launch(clientID, scope, options, conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(launcher.Response))
+
+			Expect(launcher.LaunchArguments).To(Equal([]interface{}{conn, postal.Expansion{
+				Audience: strategies.ScopeAudience,
+				GUID:     scope,
+				ClientID: clientID,
+				Options:  options,
+			}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
+
+				_, err := strategy.Launch(clientID, scope, options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+				Expect(launcher.LaunchArguments).To(BeNil())
 			})
 
-			It("call mailer.Deliver with the correct arguments for an UAA Scope", func() {
-				Expect(options.Endorsement).To(BeEmpty())
+			It("returns an error when the launcher fails", func() {
+				launcher.LaunchError = errors.New("BOOM!")
 
-				_, err := strategy.Dispatch(clientID, "great.scope", options, conn)
-				if err != nil {
-					panic(err)
-				}
+				_, err := strategy.Launch(clientID, scope, options, conn)
+				Expect(err).To(Equal(errors.New("BOOM!")))
+			})
 
-				options.Endorsement = strategies.ScopeEndorsement
-				users := []strategies.User{{GUID: "user-311"}}
-
-				Expect(mailer.DeliverArguments).To(Equal(map[string]interface{}{
-					"connection": conn,
-					"users":      users,
-					"options":    options,
-					"space":      cf.CloudControllerSpace{},
-					"org":        cf.CloudControllerOrganization{},
-					"client":     clientID,
-					"scope":      scope,
-				}))
+			It("returns an error when a default scope is passed", func() {
+				defaultScopes := []string{"cloud_controller.read", "cloud_controller.write", "openid", "approvals.me",
+					"cloud_controller_service_permissions.read", "scim.me", "uaa.user", "password.write", "scim.userids", "oauth.approvals"}
+				for _, scope := range defaultScopes {
+					_, err := strategy.Launch(clientID, scope, options, conn)
+					Expect(err).To(MatchError(strategies.DefaultScopeError{}))
+				}
+				Expect(launcher.LaunchArguments).To(BeNil())
 			})
 		})
+	})
 
-		Context("failure cases", func() {
-			Context("when token loader fails to return a token", func() {
-				It("returns an error", func() {
-					tokenLoader.LoadError = errors.New("BOOM!")
-					_, err := strategy.Dispatch(clientID, "great.scope", options, conn)
+	Describe("Expand", func() {
+		It("returns the audience of the scope", func() {
+			Expect(options.Endorsement).To(BeEmpty())
 
-					Expect(err).To(Equal(errors.New("BOOM!")))
-				})
-			})
+			audience, err := strategy.Expand(scope, options)
+			Expect(err).NotTo(HaveOccurred())
+
+			options.Endorsement = strategies.ScopeEndorsement
+			Expect(audience).To(Equal(strategies.Audience{
+				Options: options,
+				Scope:   scope,
+			}))
+		})
+
+	})
+
+	Describe("Recipients", func() {
+		It("returns a page of the users with the scope", func() {
+			users, err := strategy.Recipients(scope, options, "", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(Equal([]strategies.User{{GUID: "user-311"}}))
+
+			users, err = strategy.Recipients(scope, options, "user-311", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(BeEmpty())
+		})
 
-			Context("when finds user GUIDs returns an error", func() {
-				It("returns an error", func() {
-					findsUserGUIDs.UserGUIDsBelongingToScopeError = errors.New("BOOM!")
+		Context("failure cases", func() {
+			It("returns an error when the token loader fails to return a token", func() {
+				tokenLoader.LoadError = errors.New("BOOM!")
 
-					_, err := strategy.Dispatch(clientID, "great.scope", options, conn)
-					Expect(err).To(HaveOccurred())
-				})
+				_, err := strategy.Recipients(scope, options, "", 10)
+				Expect(err).To(Equal(errors.New("BOOM!")))
 			})
 
-			Context("when an invalid scope is passed", func() {
-				It("returns an error", func() {
-					defaultScopes := []string{"cloud_controller.read", "cloud_controller.write", "openid", "approvals.me",
-						"cloud_controller_service_permissions.read", "scim.me", "uaa.user", "password.write", "scim.userids", "oauth.approvals"}
-					for _, scope := range defaultScopes {
-						_, err := strategy.Dispatch(clientID, scope, options, conn)
-						Expect(err).To(HaveOccurred())
-						Expect(err).To(MatchError(strategies.DefaultScopeError{}))
-					}
-				})
+			It("returns an error when findsUserGUIDs fails", func() {
+				findsUserGUIDs.UserGUIDsBelongingToScopeError = errors.New("BOOM!")
+
+				_, err := strategy.Recipients(scope, options, "", 10)
+				Expect(err).To(HaveOccurred())
 			})
 		})
 	})
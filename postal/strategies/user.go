@@ -3,4 +3,5 @@ package strategies
 type User struct {
 	GUID  string
 	Email string
+	Data  map[string]interface{}
 }
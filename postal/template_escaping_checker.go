@@ -0,0 +1,328 @@
+package postal
+
+import (
+	"bytes"
+	"html"
+	"log"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type TemplateEscapingCheckerInterface interface {
+	Check() ([]FlaggedTemplate, error)
+	Report()
+}
+
+// FlaggedTemplate is a stored template whose HTML needs to be reviewed, with
+// the reason it was flagged.
+type FlaggedTemplate struct {
+	ID     string
+	Name   string
+	Reason string
+}
+
+// TemplateEscapingChecker finds the stored templates whose HTML renders
+// differently now that HTML parts are rendered with html/template, rather than
+// with text/template and a handful of escaped fields.
+type TemplateEscapingChecker struct {
+	templatesRepo models.TemplatesRepoInterface
+	database      models.DatabaseInterface
+	logger        *log.Logger
+}
+
+func NewTemplateEscapingChecker(templatesRepo models.TemplatesRepoInterface, database models.DatabaseInterface, logger *log.Logger) TemplateEscapingChecker {
+	return TemplateEscapingChecker{
+		templatesRepo: templatesRepo,
+		database:      database,
+		logger:        logger,
+	}
+}
+
+// Check renders the HTML of every stored template both ways and returns the
+// templates that render differently, or not at all.
+func (checker TemplateEscapingChecker) Check() ([]FlaggedTemplate, error) {
+	conn := checker.database.Connection()
+	flagged := []FlaggedTemplate{}
+
+	templates, err := checker.templatesRepo.ListIDsAndNames(conn)
+	if err != nil {
+		return flagged, err
+	}
+
+	for _, listed := range templates {
+		template, err := checker.templatesRepo.FindByID(conn, listed.ID)
+		if err != nil {
+			return flagged, err
+		}
+
+		reason := checkHTMLEscaping(template.HTML)
+		if reason != "" {
+			flagged = append(flagged, FlaggedTemplate{
+				ID:     template.ID,
+				Name:   template.Name,
+				Reason: reason,
+			})
+		}
+	}
+
+	return flagged, nil
+}
+
+// Report logs each template that renders differently, or not at all.
+func (checker TemplateEscapingChecker) Report() {
+	flagged, err := checker.Check()
+	if err != nil {
+		checker.logger.Printf("Could not check the escaping of templates: %s", err.Error())
+		return
+	}
+
+	for _, template := range flagged {
+		checker.logger.Printf("Template %s (%s) %s, please review it", template.ID, template.Name, template.Reason)
+	}
+}
+
+// checkHTMLEscaping describes how an HTML template is affected by contextual
+// escaping, or returns an empty string when it renders the same.
+func checkHTMLEscaping(theTemplate string) string {
+	context := escapingCheckContext()
+
+	trees, err := parse.Parse("html", theTemplate, "", "")
+	if err == nil {
+		context.Data = sampleData(trees)
+	}
+
+	before, err := compileLegacyHTMLTemplate(context, theTemplate)
+	if err != nil {
+		return "could not be checked: " + err.Error()
+	}
+
+	after, err := NewPackager().compileHTMLTemplate(context, "html", theTemplate)
+	if err != nil {
+		return "no longer renders HTML: " + err.Error()
+	}
+
+	if after != before {
+		return "renders HTML differently with contextual escaping"
+	}
+
+	return ""
+}
+
+// escapingSample is a value that holds the characters that need escaping, so
+// that any field rendered with it shows how it is escaped.
+func escapingSample(name string) string {
+	return `Tom & Jerry's "<` + name + `>"`
+}
+
+// escapingCheckContext holds values like those of a real notification, with
+// the characters that need escaping in every field.
+func escapingCheckContext() MessageContext {
+	return MessageContext{
+		From:    escapingSample("from") + " <no-reply@example.com>",
+		ReplyTo: escapingSample("reply-to") + " <reply-to@example.com>",
+		To:      escapingSample("to") + " <user@example.com>",
+		Subject: escapingSample("subject"),
+		Text:    escapingSample("text"),
+		HTML:    `<p>Tom &amp; Jerry's "html"</p>`,
+		HTMLComponents: HTML{
+			BodyContent:    `<p>Tom &amp; Jerry's "html"</p>`,
+			BodyAttributes: `class="Tom &amp; Jerry's"`,
+			Head:           `<title>Tom &amp; Jerry's "head"</title>`,
+			Doctype:        `<!DOCTYPE html>`,
+		},
+		KindDescription:   escapingSample("kind"),
+		SourceDescription: escapingSample("source"),
+		UserGUID:          escapingSample("user-guid"),
+		ClientID:          escapingSample("client-id"),
+		MessageID:         escapingSample("message-id"),
+		Space:             escapingSample("space"),
+		SpaceGUID:         escapingSample("space-guid"),
+		Organization:      escapingSample("organization"),
+		OrganizationGUID:  escapingSample("organization-guid"),
+		UnsubscribeID:     escapingSample("unsubscribe-id"),
+		UnsubscribeURL:    "https://notifications.example.com/unsubscribe/Tom%20&%20Jerry%27s?from=a&to=b",
+		UnsubscribeMailto: "mailto:unsubscribe@example.com?subject=unsubscribe+Tom+%26+Jerry%27s",
+		Scope:             escapingSample("scope"),
+		Endorsement:       "This message was sent to the " + escapingSample("space") + " space.",
+		OrganizationRole:  escapingSample("role"),
+	}
+}
+
+// sampleData builds the Data a template refers to, holding a sample value in
+// each field it renders. Fields ranged over hold a single element.
+func sampleData(trees map[string]*parse.Tree) map[string]interface{} {
+	data := map[string]interface{}{}
+	for _, tree := range trees {
+		addSampleFields(data, tree.Root, true)
+	}
+	return data
+}
+
+// addSampleFields adds the fields referred to by node to fields. At the top of
+// the template only the fields under .Data are added; inside a range or with
+// over one of them, the fields are relative to its value.
+func addSampleFields(fields map[string]interface{}, node parse.Node, top bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			addSampleFields(fields, child, top)
+		}
+	case *parse.ActionNode:
+		addSampleFields(fields, node.Pipe, top)
+	case *parse.TemplateNode:
+		addSampleFields(fields, node.Pipe, top)
+	case *parse.IfNode:
+		addSampleFields(fields, node.Pipe, top)
+		addSampleFields(fields, node.List, top)
+		addSampleFields(fields, node.ElseList, top)
+	case *parse.RangeNode:
+		addScopedSampleFields(fields, node.BranchNode, top, true)
+	case *parse.WithNode:
+		addScopedSampleFields(fields, node.BranchNode, top, false)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, command := range node.Cmds {
+			addSampleFields(fields, command, top)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			addSampleFields(fields, arg, top)
+		}
+	case *parse.FieldNode:
+		path := sampleFieldPath(node, top)
+		if len(path) > 0 {
+			setSampleField(fields, path, escapingSample(path[len(path)-1]))
+		}
+	}
+}
+
+func addScopedSampleFields(fields map[string]interface{}, branch parse.BranchNode, top, isRange bool) {
+	var path []string
+	if len(branch.Pipe.Cmds) == 1 && len(branch.Pipe.Cmds[0].Args) == 1 {
+		if field, ok := branch.Pipe.Cmds[0].Args[0].(*parse.FieldNode); ok {
+			path = sampleFieldPath(field, top)
+		}
+	}
+
+	if len(path) == 0 {
+		addSampleFields(fields, branch.Pipe, top)
+		addSampleFields(fields, branch.List, top)
+		addSampleFields(fields, branch.ElseList, top)
+		return
+	}
+
+	element := map[string]interface{}{}
+	addSampleFields(element, branch.List, false)
+
+	var value interface{} = element
+	if len(element) == 0 {
+		value = escapingSample(path[len(path)-1])
+	}
+
+	if isRange {
+		value = []interface{}{value}
+	}
+
+	setSampleField(fields, path, value)
+	addSampleFields(fields, branch.ElseList, top)
+}
+
+func sampleFieldPath(field *parse.FieldNode, top bool) []string {
+	if !top {
+		return field.Ident
+	}
+
+	if len(field.Ident) > 1 && field.Ident[0] == "Data" {
+		return field.Ident[1:]
+	}
+
+	return nil
+}
+
+// setSampleField sets the value at the path, creating the maps along it. A
+// field that other fields are nested under is kept as a map.
+func setSampleField(fields map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := fields[key].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			fields[key] = nested
+		}
+		fields = nested
+	}
+
+	key := path[len(path)-1]
+	if _, ok := fields[key].(map[string]interface{}); ok {
+		if _, ok := value.(string); ok {
+			return
+		}
+	}
+
+	fields[key] = value
+}
+
+// compileLegacyHTMLTemplate renders HTML the way it was rendered before
+// html/template was used.
+func compileLegacyHTMLTemplate(context MessageContext, theTemplate string) (string, error) {
+	context.From = html.EscapeString(context.From)
+	context.To = html.EscapeString(context.To)
+	context.ReplyTo = html.EscapeString(context.ReplyTo)
+	context.Subject = html.EscapeString(context.Subject)
+	context.Text = html.EscapeString(context.Text)
+	context.KindDescription = html.EscapeString(context.KindDescription)
+	context.SourceDescription = html.EscapeString(context.SourceDescription)
+	context.ClientID = html.EscapeString(context.ClientID)
+	context.MessageID = html.EscapeString(context.MessageID)
+	context.Space = html.EscapeString(context.Space)
+	context.Organization = html.EscapeString(context.Organization)
+	context.Endorsement = html.EscapeString(context.Endorsement)
+	context.UnsubscribeURL = html.EscapeString(context.UnsubscribeURL)
+	context.UnsubscribeMailto = html.EscapeString(context.UnsubscribeMailto)
+	if context.Data != nil {
+		context.Data = escapeLegacyData(context.Data).(map[string]interface{})
+	}
+
+	source, err := template.New("html").Parse(theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err = source.Execute(buffer, context)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// escapeLegacyData escapes the strings found anywhere in the data, the way
+// the data given by the sender was escaped before html/template was used.
+func escapeLegacyData(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return html.EscapeString(value)
+	case map[string]interface{}:
+		escaped := map[string]interface{}{}
+		for key, element := range value {
+			escaped[key] = escapeLegacyData(element)
+		}
+		return escaped
+	case []interface{}:
+		escaped := []interface{}{}
+		for _, element := range value {
+			escaped = append(escaped, escapeLegacyData(element))
+		}
+		return escaped
+	}
+
+	return value
+}
@@ -0,0 +1,126 @@
+package postal_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateEscapingChecker", func() {
+	var checker postal.TemplateEscapingChecker
+	var templatesRepo *fakes.TemplatesRepo
+	var buffer *bytes.Buffer
+
+	BeforeEach(func() {
+		templatesRepo = fakes.NewTemplatesRepo()
+		buffer = bytes.NewBuffer([]byte{})
+		checker = postal.NewTemplateEscapingChecker(templatesRepo, fakes.NewDatabase(), log.New(buffer, "", 0))
+	})
+
+	addTemplate := func(id, html string) {
+		template := models.Template{ID: id, Name: id + " name", HTML: html}
+		templatesRepo.Templates[id] = template
+		templatesRepo.TemplatesList = append(templatesRepo.TemplatesList, models.Template{ID: id, Name: template.Name})
+	}
+
+	flaggedIDs := func() []string {
+		flagged, err := checker.Check()
+		Expect(err).NotTo(HaveOccurred())
+
+		ids := []string{}
+		for _, template := range flagged {
+			ids = append(ids, template.ID)
+		}
+		return ids
+	}
+
+	Describe("Check", func() {
+		It("does not flag templates that render the same", func() {
+			addTemplate("default", `<p>{{.Endorsement}}</p>{{.HTML}}{{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>{{end}}`)
+			addTemplate("plain", `<h1 title="{{.Subject}}">{{.Subject}}</h1><p>{{.Text}} in {{.Space}}</p>`)
+			addTemplate("data", `<p>{{.Data.name}}</p>{{range .Data.items}}<li title="{{.title}}">{{.title}}</li>{{end}}{{with .Data.owner}}<p>{{.email}}</p>{{end}}`)
+
+			Expect(flaggedIDs()).To(BeEmpty())
+		})
+
+		It("flags templates that render differently", func() {
+			addTemplate("script", `<script>var subject = "{{.Subject}}";</script>`)
+			addTemplate("link", `<a href="{{.Text}}">details</a>`)
+
+			flagged, err := checker.Check()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged).To(Equal([]postal.FlaggedTemplate{
+				{ID: "script", Name: "script name", Reason: "renders HTML differently with contextual escaping"},
+				{ID: "link", Name: "link name", Reason: "renders HTML differently with contextual escaping"},
+			}))
+		})
+
+		It("flags templates that render fields that were not escaped before", func() {
+			addTemplate("user", `<p>{{.UserGUID}}</p>`)
+			addTemplate("scope", `<p>{{.Scope}}</p>`)
+			addTemplate("unsubscribe", `<p>{{.UnsubscribeID}}</p>`)
+
+			Expect(flaggedIDs()).To(Equal([]string{"user", "scope", "unsubscribe"}))
+		})
+
+		It("flags templates that render data differently", func() {
+			addTemplate("data-link", `<a href="{{.Data.profile.url}}">profile</a>`)
+			addTemplate("data-script", `{{range .Data.items}}<script>var title = "{{.title}}";</script>{{end}}`)
+
+			Expect(flaggedIDs()).To(Equal([]string{"data-link", "data-script"}))
+		})
+
+		It("flags templates that no longer render", func() {
+			addTemplate("broken", `<a href="{{.UnsubscribeURL}}`)
+
+			flagged, err := checker.Check()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged).To(HaveLen(1))
+			Expect(flagged[0].ID).To(Equal("broken"))
+			Expect(flagged[0].Reason).To(MatchRegexp("^no longer renders HTML"))
+		})
+
+		It("flags templates that could not be rendered before", func() {
+			addTemplate("missing", `<p>{{template "missing"}}</p>`)
+
+			flagged, err := checker.Check()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged).To(HaveLen(1))
+			Expect(flagged[0].ID).To(Equal("missing"))
+			Expect(flagged[0].Reason).To(MatchRegexp("^could not be checked"))
+		})
+
+		It("returns an error when the templates cannot be listed", func() {
+			templatesRepo.ListError = errors.New("database is down")
+
+			_, err := checker.Check()
+			Expect(err).To(MatchError("database is down"))
+		})
+	})
+
+	Describe("Report", func() {
+		It("logs the flagged templates", func() {
+			addTemplate("default", `<p>{{.Endorsement}}</p>{{.HTML}}`)
+			addTemplate("script", `<script>var subject = "{{.Subject}}";</script>`)
+
+			checker.Report()
+
+			Expect(buffer.String()).To(Equal("Template script (script name) renders HTML differently with contextual escaping, please review it\n"))
+		})
+
+		It("logs when the templates cannot be listed", func() {
+			templatesRepo.ListError = errors.New("database is down")
+
+			checker.Report()
+
+			Expect(buffer.String()).To(ContainSubstring("Could not check the escaping of templates: database is down"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package postal
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+// RenderedTemplate holds the parts of a message as they would be sent.
+type RenderedTemplate struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// TemplateRenderer compiles a stored template for a sample delivery, so that
+// template authors can preview it without sending a notification.
+type TemplateRenderer struct {
+	templatesRepo models.TemplatesRepoInterface
+	database      models.DatabaseInterface
+	sender        string
+	encryptionKey []byte
+	unsubscribe   UnsubscribeConfig
+}
+
+func NewTemplateRenderer(templatesRepo models.TemplatesRepoInterface, database models.DatabaseInterface,
+	sender string, encryptionKey []byte, unsubscribe UnsubscribeConfig) TemplateRenderer {
+
+	return TemplateRenderer{
+		templatesRepo: templatesRepo,
+		database:      database,
+		sender:        sender,
+		encryptionKey: encryptionKey,
+		unsubscribe:   unsubscribe,
+	}
+}
+
+// Render packs the delivery with the given template, just as a delivery worker
+// would, and returns the compiled subject, text and HTML. Templates that fail
+// to compile are reported as a TemplateError.
+func (renderer TemplateRenderer) Render(templateID string, delivery Delivery) (RenderedTemplate, error) {
+	message, err := renderer.Pack(templateID, delivery)
+	if err != nil {
+		return RenderedTemplate{}, err
+	}
+
+	rendered := RenderedTemplate{
+		Subject: message.Subject,
+	}
+
+	for _, part := range message.Body {
+		switch part.ContentType {
+		case "text/plain":
+			rendered.Text = part.Content
+		case "text/html":
+			rendered.HTML = part.Content
+		}
+	}
+
+	return rendered, nil
+}
+
+// Pack returns the message a delivery worker would send for the delivery,
+// compiled with the given template rather than the one assigned to its kind.
+func (renderer TemplateRenderer) Pack(templateID string, delivery Delivery) (mail.Message, error) {
+	return renderer.pack(templateID, delivery, renderer.unsubscribe)
+}
+
+// PackWithoutUnsubscribe is Pack for a message that carries no unsubscribe
+// links, as a critical notification would.
+func (renderer TemplateRenderer) PackWithoutUnsubscribe(templateID string, delivery Delivery) (mail.Message, error) {
+	return renderer.pack(templateID, delivery, UnsubscribeConfig{})
+}
+
+func (renderer TemplateRenderer) pack(templateID string, delivery Delivery, unsubscribe UnsubscribeConfig) (mail.Message, error) {
+	template, err := renderer.templatesRepo.FindByID(renderer.database.Connection(), templateID)
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	cloak, err := conceal.NewCloak(renderer.encryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
+	templates := Templates{
+		Name:    template.Name,
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+	}
+
+	context := NewMessageContext(delivery, renderer.sender, cloak, templates, unsubscribe)
+
+	return NewPackager().Pack(context)
+}
@@ -0,0 +1,112 @@
+package postal_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateRenderer", func() {
+	var renderer postal.TemplateRenderer
+	var templatesRepo *fakes.TemplatesRepo
+	var delivery postal.Delivery
+
+	BeforeEach(func() {
+		templatesRepo = fakes.NewTemplatesRepo()
+		templatesRepo.Templates["template-id"] = models.Template{
+			ID:      "template-id",
+			Name:    "Banana",
+			Subject: "Banana: {{.Subject}}",
+			Text:    "{{.Text}} for {{.Space}} ({{.Data.flavor}})",
+			HTML:    "<p>{{.Organization}}</p>{{.HTML}}",
+		}
+
+		delivery = postal.Delivery{
+			Options: postal.Options{
+				Subject: "ripe",
+				Text:    "text",
+				HTML:    postal.HTML{BodyContent: "<b>html</b>"},
+				KindID:  "kind-id",
+				Data:    map[string]interface{}{"flavor": "sweet"},
+			},
+			UserGUID:     "user-123",
+			Email:        "user@example.com",
+			ClientID:     "my-client",
+			Space:        cf.CloudControllerSpace{GUID: "space-001", Name: "dev"},
+			Organization: cf.CloudControllerOrganization{GUID: "org-001", Name: "<org>"},
+		}
+
+		renderer = postal.NewTemplateRenderer(templatesRepo, fakes.NewDatabase(), "from@example.com",
+			[]byte("0123456789abcdef"), postal.UnsubscribeConfig{})
+	})
+
+	It("returns the subject, text and html as they would be sent", func() {
+		rendered, err := renderer.Render("template-id", delivery)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(rendered.Subject).To(Equal("Banana: ripe"))
+		Expect(rendered.Text).To(Equal("text for dev (sweet)"))
+		Expect(rendered.HTML).To(ContainSubstring("<p>&lt;org&gt;</p><b>html</b>"))
+	})
+
+	It("omits the parts the delivery has no content for", func() {
+		delivery.Options.HTML = postal.HTML{}
+
+		rendered, err := renderer.Render("template-id", delivery)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(rendered.Text).To(Equal("text for dev (sweet)"))
+		Expect(rendered.HTML).To(BeEmpty())
+	})
+
+	It("returns the errors compiling the template", func() {
+		templatesRepo.Templates["template-id"] = models.Template{
+			ID:      "template-id",
+			Subject: "{{.Nope}}",
+		}
+
+		_, err := renderer.Render("template-id", delivery)
+		Expect(err).To(BeAssignableToTypeOf(postal.TemplateError{}))
+		Expect(err.(postal.TemplateError).Part).To(Equal("subject"))
+	})
+
+	It("returns an error when the template cannot be found", func() {
+		_, err := renderer.Render("missing-id", delivery)
+		Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+	})
+
+	Context("when unsubscribes are configured", func() {
+		BeforeEach(func() {
+			renderer = postal.NewTemplateRenderer(templatesRepo, fakes.NewDatabase(), "from@example.com",
+				[]byte("0123456789abcdef"), postal.UnsubscribeConfig{PublicURL: "https://notifications.example.com"})
+		})
+
+		It("packs the message with its unsubscribe links", func() {
+			message, err := renderer.Pack("template-id", delivery)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(message.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+		})
+
+		It("can pack the message without them", func() {
+			message, err := renderer.PackWithoutUnsubscribe("template-id", delivery)
+			if err != nil {
+				panic(err)
+			}
+
+			for _, header := range message.Headers {
+				Expect(header).NotTo(ContainSubstring("List-Unsubscribe"))
+			}
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package postal
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+const TestSendHeader = "X-CF-Test-Send"
+
+type testSendPackerInterface interface {
+	PackWithoutUnsubscribe(string, Delivery) (mail.Message, error)
+}
+
+// TestSender mails a template, compiled for a sample delivery, so that it can
+// be seen in a real mail client. Test sends are not queued or recorded as
+// messages, and they skip the unsubscribe and receipt checks of the delivery
+// workers. Suppressed addresses have hard-bounced, so they are not sent test
+// sends either.
+type TestSender struct {
+	packer           testSendPackerInterface
+	mailClient       mail.RouterInterface
+	suppressionsRepo models.SuppressionsRepoInterface
+	database         models.DatabaseInterface
+	logger           *log.Logger
+}
+
+func NewTestSender(packer testSendPackerInterface, mailClient mail.RouterInterface, suppressionsRepo models.SuppressionsRepoInterface,
+	database models.DatabaseInterface, logger *log.Logger) TestSender {
+
+	return TestSender{
+		packer:           packer,
+		mailClient:       mailClient,
+		suppressionsRepo: suppressionsRepo,
+		database:         database,
+		logger:           logger,
+	}
+}
+
+// Send compiles the delivery with the given template and mails it to the
+// address of the delivery. The message is tagged with the X-CF-Test-Send
+// header and carries no unsubscribe links, as there is no notification to
+// unsubscribe from. It returns a RecipientSuppressedError when the address is
+// suppressed.
+func (sender TestSender) Send(templateID string, delivery Delivery) error {
+	suppression, err := sender.suppressionsRepo.Find(sender.database.Connection(), delivery.Email)
+	if err == nil {
+		return RecipientSuppressedError(fmt.Sprintf("%s is suppressed: %s", delivery.Email, suppression.Reason))
+	}
+
+	if _, ok := err.(models.RecordNotFoundError); !ok {
+		return err
+	}
+
+	message, err := sender.packer.PackWithoutUnsubscribe(templateID, delivery)
+	if err != nil {
+		return err
+	}
+
+	message.Headers = append(message.Headers, TestSendHeader+": true")
+
+	err = sender.mailClient.Connect()
+	if err != nil {
+		sender.logger.Printf("Error Establishing SMTP Connection: %s", err.Error())
+		return TestSendError("The test email could not be sent: " + err.Error())
+	}
+
+	_, err = sender.mailClient.Send(message)
+	if err != nil {
+		sender.logger.Printf("Failed to deliver test email due to SMTP error: %s", err.Error())
+		return TestSendError("The test email could not be sent: " + err.Error())
+	}
+
+	sender.logger.Printf("Test email of template %s was sent to %s", templateID, message.To)
+
+	return nil
+}
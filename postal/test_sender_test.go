@@ -0,0 +1,133 @@
+package postal_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TestSender", func() {
+	var sender postal.TestSender
+	var mailClient fakes.MailClient
+	var templatesRepo *fakes.TemplatesRepo
+	var suppressionsRepo *fakes.SuppressionsRepo
+	var delivery postal.Delivery
+	var buffer *bytes.Buffer
+
+	BeforeEach(func() {
+		templatesRepo = fakes.NewTemplatesRepo()
+		templatesRepo.Templates["template-id"] = models.Template{
+			ID:      "template-id",
+			Subject: "Test: {{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		}
+
+		delivery = postal.Delivery{
+			Options: postal.Options{
+				Subject: "hello",
+				Text:    "the text",
+				KindID:  "kind-id",
+			},
+			UserGUID:  "user-123",
+			Email:     "designer@example.com",
+			ClientID:  "my-client",
+			MessageID: "message-id",
+		}
+
+		renderer := postal.NewTemplateRenderer(templatesRepo, fakes.NewDatabase(), "from@example.com",
+			[]byte("0123456789abcdef"), postal.UnsubscribeConfig{PublicURL: "https://notifications.example.com", Mailto: "unsubscribe@example.com"})
+		mailClient = fakes.NewMailClient()
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		buffer = bytes.NewBuffer([]byte{})
+		sender = postal.NewTestSender(renderer, &mailClient, suppressionsRepo, fakes.NewDatabase(), log.New(buffer, "", 0))
+	})
+
+	It("mails the compiled template to the address of the delivery", func() {
+		err := sender.Send("template-id", delivery)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(mailClient.Messages).To(HaveLen(1))
+		message := mailClient.Messages[0]
+		Expect(message.To).To(Equal("designer@example.com"))
+		Expect(message.From).To(Equal("from@example.com"))
+		Expect(message.Subject).To(Equal("Test: hello"))
+		Expect(message.Body[0].Content).To(Equal("the text"))
+	})
+
+	It("tags the message as a test send", func() {
+		err := sender.Send("template-id", delivery)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(mailClient.Messages[0].Headers).To(ContainElement("X-CF-Test-Send: true"))
+	})
+
+	It("leaves out the unsubscribe links", func() {
+		err := sender.Send("template-id", delivery)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, header := range mailClient.Messages[0].Headers {
+			Expect(header).NotTo(ContainSubstring("List-Unsubscribe"))
+		}
+	})
+
+	It("does not send to a suppressed address", func() {
+		suppressionsRepo.Suppressions["designer@example.com"] = models.Suppression{
+			Email:  "designer@example.com",
+			Reason: "bounced",
+		}
+
+		err := sender.Send("template-id", delivery)
+		Expect(err).To(Equal(postal.RecipientSuppressedError("designer@example.com is suppressed: bounced")))
+		Expect(mailClient.Messages).To(BeEmpty())
+	})
+
+	It("returns an error when the suppressions cannot be checked", func() {
+		suppressionsRepo.FindError = errors.New("BOOM!")
+
+		err := sender.Send("template-id", delivery)
+		Expect(err).To(Equal(errors.New("BOOM!")))
+		Expect(mailClient.Messages).To(BeEmpty())
+	})
+
+	It("returns errors compiling the template without sending", func() {
+		templatesRepo.Templates["template-id"] = models.Template{ID: "template-id", Subject: "{{.Nope}}"}
+
+		err := sender.Send("template-id", delivery)
+		Expect(err).To(BeAssignableToTypeOf(postal.TemplateError{}))
+		Expect(mailClient.Messages).To(BeEmpty())
+	})
+
+	It("returns an error when the template cannot be found", func() {
+		err := sender.Send("missing-id", delivery)
+		Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+	})
+
+	It("returns an error when the mail server cannot be reached", func() {
+		mailClient.ConnectError = errors.New("connection refused")
+
+		err := sender.Send("template-id", delivery)
+		Expect(err).To(Equal(postal.TestSendError("The test email could not be sent: connection refused")))
+		Expect(buffer.String()).To(ContainSubstring("Error Establishing SMTP Connection: connection refused"))
+	})
+
+	It("returns an error when the message cannot be sent", func() {
+		mailClient.SendError = errors.New("mailbox unavailable")
+
+		err := sender.Send("template-id", delivery)
+		Expect(err).To(Equal(postal.TestSendError("The test email could not be sent: mailbox unavailable")))
+	})
+})
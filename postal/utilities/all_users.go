@@ -1,30 +1,21 @@
 package utilities
 
+// AllUsersInterface returns every user in UAA a page at a time, in order of
+// their GUIDs. A page holds up to count GUIDs that sort after the given one.
 type AllUsersInterface interface {
-	AllUserGUIDs() ([]string, error)
+	AllUserGUIDs(after string, count int) ([]string, error)
 }
 
 type AllUsers struct {
-	uaa UAAInterface
+	uaa UAAUsersInterface
 }
 
-func NewAllUsers(uaa UAAInterface) AllUsers {
+func NewAllUsers(uaa UAAUsersInterface) AllUsers {
 	return AllUsers{
 		uaa: uaa,
 	}
 }
 
-func (allUsers AllUsers) AllUserGUIDs() ([]string, error) {
-	var guids []string
-
-	usersMap, err := allUsers.uaa.AllUsers()
-	if err != nil {
-		return guids, err
-	}
-
-	for _, user := range usersMap {
-		guids = append(guids, user.ID)
-	}
-
-	return guids, nil
+func (allUsers AllUsers) AllUserGUIDs(after string, count int) ([]string, error) {
+	return allUsers.uaa.UserGUIDs(afterFilter("", after), count)
 }
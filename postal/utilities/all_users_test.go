@@ -5,7 +5,6 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/fakes"
 	"github.com/cloudfoundry-incubator/notifications/postal/utilities"
-	"github.com/pivotal-cf/uaa-sso-golang/uaa"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -13,45 +12,36 @@ import (
 
 var _ = Describe("AllUserGUIDs", func() {
 	var allUsers utilities.AllUsers
-	var uaaClient *fakes.UAAClient
-	var users []uaa.User
+	var uaaUsers *fakes.UAAUsers
 
 	BeforeEach(func() {
-		uaaClient = fakes.NewUAAClient()
-		allUsers = utilities.NewAllUsers(uaaClient)
+		uaaUsers = fakes.NewUAAUsers()
+		allUsers = utilities.NewAllUsers(uaaUsers)
 	})
 
 	Context("when the request succeeds", func() {
-		BeforeEach(func() {
-			users = []uaa.User{
-				{
-					Emails: []string{"user-123@example.com"},
-					ID:     "user-123",
-				},
-				{
-					Emails: []string{"user-456@example.com"},
-					ID:     "user-456",
-				},
-				{
-					Emails: []string{"user-999@example.com"},
-					ID:     "user-999",
-				},
-			}
-
-			uaaClient.AllUsersData = users
+		It("returns the first page of user GUIDs", func() {
+			uaaUsers.Pages[""] = []string{"user-123", "user-456"}
+
+			guids, err := allUsers.AllUserGUIDs("", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guids).To(Equal([]string{"user-123", "user-456"}))
+			Expect(uaaUsers.UserGUIDsArguments).To(Equal([][]interface{}{{"", 2}}))
 		})
 
-		It("returns the UAAUsers, UserGUIDs, and an error", func() {
-			guids, err := allUsers.AllUserGUIDs()
+		It("returns the page of user GUIDs after the given one", func() {
+			uaaUsers.Pages[`id gt "user-456"`] = []string{"user-999"}
+
+			guids, err := allUsers.AllUserGUIDs("user-456", 2)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(guids).To(ConsistOf("user-456", "user-999", "user-123"))
+			Expect(guids).To(Equal([]string{"user-999"}))
 		})
 	})
 
 	Context("when the request to UAA fails", func() {
 		It("bubbles up the error", func() {
-			uaaClient.AllUsersError = errors.New("BOOM!")
-			_, err := allUsers.AllUserGUIDs()
+			uaaUsers.UserGUIDsError = errors.New("BOOM!")
+			_, err := allUsers.AllUserGUIDs("", 2)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(errors.New("BOOM!")))
 		})
@@ -1,42 +1,43 @@
 package utilities
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
+import (
+	"sort"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+)
 
 type FindsUserGUIDs struct {
 	cloudController cf.CloudControllerInterface
-	uaa             UAAInterface
+	uaa             UAAUsersInterface
 }
 
+// FindsUserGUIDsInterface returns the members of an audience a page at a time,
+// in order of their GUIDs. A page holds up to count GUIDs that sort after the
+// given one, and an empty page marks the end of the audience.
 type FindsUserGUIDsInterface interface {
-	UserGUIDsBelongingToSpace(string, string) ([]string, error)
-	UserGUIDsBelongingToOrganization(string, string, string) ([]string, error)
-	UserGUIDsBelongingToScope(string) ([]string, error)
+	UserGUIDsBelongingToSpace(string, string, string, int) ([]string, error)
+	UserGUIDsBelongingToOrganization(string, string, string, string, int) ([]string, error)
+	UserGUIDsBelongingToScope(string, string, int) ([]string, error)
 }
 
-func NewFindsUserGUIDs(cloudController cf.CloudControllerInterface, uaa UAAInterface) FindsUserGUIDs {
+func NewFindsUserGUIDs(cloudController cf.CloudControllerInterface, uaa UAAUsersInterface) FindsUserGUIDs {
 	return FindsUserGUIDs{
 		cloudController: cloudController,
 		uaa:             uaa,
 	}
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToSpace(spaceGUID, token string) ([]string, error) {
-	var userGUIDs []string
-
+func (finder FindsUserGUIDs) UserGUIDsBelongingToSpace(spaceGUID, token, after string, count int) ([]string, error) {
 	users, err := finder.cloudController.GetUsersBySpaceGuid(spaceGUID, token)
 	if err != nil {
-		return userGUIDs, err
-	}
-
-	for _, user := range users {
-		userGUIDs = append(userGUIDs, user.GUID)
+		return []string{}, err
 	}
 
-	return userGUIDs, nil
+	return pageOf(users, after, count), nil
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToOrganization(orgGUID, role, token string) ([]string, error) {
-	var userGUIDs []string
+func (finder FindsUserGUIDs) UserGUIDsBelongingToOrganization(orgGUID, role, token, after string, count int) ([]string, error) {
 	var users []cf.CloudControllerUser
 	var err error
 
@@ -52,23 +53,32 @@ func (finder FindsUserGUIDs) UserGUIDsBelongingToOrganization(orgGUID, role, tok
 	}
 
 	if err != nil {
-		return userGUIDs, err
+		return []string{}, err
 	}
 
-	for _, user := range users {
-		userGUIDs = append(userGUIDs, user.GUID)
-	}
+	return pageOf(users, after, count), nil
+}
 
-	return userGUIDs, nil
+func (finder FindsUserGUIDs) UserGUIDsBelongingToScope(scope, after string, count int) ([]string, error) {
+	return finder.uaa.UserGUIDs(afterFilter("groups.display eq "+strconv.Quote(scope), after), count)
 }
 
-func (finder FindsUserGUIDs) UserGUIDsBelongingToScope(scope string) ([]string, error) {
-	var userGUIDs []string
+// pageOf cuts a page out of the members of a space or organization. The Cloud
+// Controller cannot order or filter them by GUID, so every page is cut from
+// the full list of members; those lists are small next to the audiences that
+// UAA pages through.
+func pageOf(users []cf.CloudControllerUser, after string, count int) []string {
+	guids := []string{}
+	for _, user := range users {
+		if user.GUID > after {
+			guids = append(guids, user.GUID)
+		}
+	}
 
-	userGUIDs, err := finder.uaa.UsersGUIDsByScope(scope)
-	if err != nil {
-		return userGUIDs, err
+	sort.Strings(guids)
+	if len(guids) > count {
+		guids = guids[:count]
 	}
 
-	return userGUIDs, nil
+	return guids
 }
@@ -14,30 +14,39 @@ import (
 var _ = Describe("FindsUserGUIDs", func() {
 	var finder utilities.FindsUserGUIDs
 	var cc *fakes.CloudController
-	var uaa *fakes.UAAClient
+	var uaaUsers *fakes.UAAUsers
 
 	BeforeEach(func() {
 		cc = fakes.NewCloudController()
-		uaa = fakes.NewUAAClient()
-		finder = utilities.NewFindsUserGUIDs(cc, uaa)
+		uaaUsers = fakes.NewUAAUsers()
+		finder = utilities.NewFindsUserGUIDs(cc, uaaUsers)
 	})
 
 	Context("when looking for GUIDs that have a scope", func() {
 		BeforeEach(func() {
-			uaa.UsersGUIDsByScopeResponse["this.scope"] = []string{"user-402", "user-525"}
+			uaaUsers.Pages[`groups.display eq "this.scope"`] = []string{"user-402", "user-525"}
+			uaaUsers.Pages[`groups.display eq "this.scope" and id gt "user-525"`] = []string{"user-601"}
 		})
 
 		It("returns the userGUIDs that have that scope", func() {
-			guids, err := finder.UserGUIDsBelongingToScope("this.scope")
+			guids, err := finder.UserGUIDsBelongingToScope("this.scope", "", 2)
 
 			Expect(guids).To(Equal([]string{"user-402", "user-525"}))
 			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaUsers.UserGUIDsArguments).To(Equal([][]interface{}{{`groups.display eq "this.scope"`, 2}}))
+		})
+
+		It("returns the userGUIDs that have that scope after the given one", func() {
+			guids, err := finder.UserGUIDsBelongingToScope("this.scope", "user-525", 2)
+
+			Expect(guids).To(Equal([]string{"user-601"}))
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		Context("when uaa has an error", func() {
 			It("returns the error", func() {
-				uaa.UsersGUIDsByScopeError = errors.New("foobar")
-				_, err := finder.UserGUIDsBelongingToScope("this.scope")
+				uaaUsers.UserGUIDsError = errors.New("foobar")
+				_, err := finder.UserGUIDsBelongingToScope("this.scope", "", 2)
 
 				Expect(err).To(MatchError(errors.New("foobar")))
 			})
@@ -53,19 +62,33 @@ var _ = Describe("FindsUserGUIDs", func() {
 		})
 
 		It("returns the user GUIDs for the space", func() {
-			guids, err := finder.UserGUIDsBelongingToSpace("space-001", "token")
+			guids, err := finder.UserGUIDsBelongingToSpace("space-001", "token", "", 10)
 
 			Expect(guids).To(Equal([]string{"user-123", "user-789"}))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("returns a page of the user GUIDs in order after the given one", func() {
+			cc.UsersBySpaceGuid["space-001"] = []cf.CloudControllerUser{
+				cf.CloudControllerUser{GUID: "user-789"},
+				cf.CloudControllerUser{GUID: "user-123"},
+				cf.CloudControllerUser{GUID: "user-456"},
+				cf.CloudControllerUser{GUID: "user-001"},
+			}
+
+			guids, err := finder.UserGUIDsBelongingToSpace("space-001", "token", "user-001", 2)
+
+			Expect(guids).To(Equal([]string{"user-123", "user-456"}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		Context("when CloudController causes an error", func() {
 			BeforeEach(func() {
 				cc.GetUsersBySpaceGuidError = errors.New("BOOM!")
 			})
 
 			It("returns the error", func() {
-				_, err := finder.UserGUIDsBelongingToSpace("space-001", "token")
+				_, err := finder.UserGUIDsBelongingToSpace("space-001", "token", "", 10)
 
 				Expect(err).To(Equal(cc.GetUsersBySpaceGuidError))
 			})
@@ -81,9 +104,9 @@ var _ = Describe("FindsUserGUIDs", func() {
 		})
 
 		It("returns the user GUIDs for the organization", func() {
-			guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "", "token")
+			guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "", "token", "", 10)
 
-			Expect(guids).To(Equal([]string{"user-456", "user-001"}))
+			Expect(guids).To(Equal([]string{"user-001", "user-456"}))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -93,7 +116,7 @@ var _ = Describe("FindsUserGUIDs", func() {
 			})
 
 			It("returns the error", func() {
-				_, err := finder.UserGUIDsBelongingToOrganization("org-001", "", "token")
+				_, err := finder.UserGUIDsBelongingToOrganization("org-001", "", "token", "", 10)
 
 				Expect(err).To(Equal(cc.GetUsersByOrganizationGuidError))
 			})
@@ -108,7 +131,7 @@ var _ = Describe("FindsUserGUIDs", func() {
 			})
 
 			It("returns the organization managers for the organization", func() {
-				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "OrgManager", "token")
+				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "OrgManager", "token", "", 10)
 
 				Expect(guids).To(Equal([]string{"user-678", "user-xxx"}))
 				Expect(err).NotTo(HaveOccurred())
@@ -124,7 +147,7 @@ var _ = Describe("FindsUserGUIDs", func() {
 			})
 
 			It("returns the organization auditors for the organization", func() {
-				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "OrgAuditor", "token")
+				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "OrgAuditor", "token", "", 10)
 
 				Expect(guids).To(Equal([]string{"user-abc", "user-zzz"}))
 				Expect(err).NotTo(HaveOccurred())
@@ -140,9 +163,9 @@ var _ = Describe("FindsUserGUIDs", func() {
 			})
 
 			It("returns the billing managers for the organization", func() {
-				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "BillingManager", "token")
+				guids, err := finder.UserGUIDsBelongingToOrganization("org-001", "BillingManager", "token", "", 10)
 
-				Expect(guids).To(Equal([]string{"user-jkl", "user-aaa"}))
+				Expect(guids).To(Equal([]string{"user-aaa", "user-jkl"}))
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
@@ -1,8 +1,64 @@
 package utilities
 
-import "github.com/pivotal-cf/uaa-sso-golang/uaa"
+import (
+	"net/url"
+	"strconv"
 
-type UAAInterface interface {
-	uaa.UsersGUIDsByScopeInterface
-	uaa.AllUsersInterface
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+)
+
+type UAAUsersInterface interface {
+	UserGUIDs(filter string, count int) ([]string, error)
+}
+
+// UAAUsers pages through the users in UAA with a SCIM filter, which the UAA
+// client library does not support. Users are returned in order of their IDs.
+type UAAUsers struct {
+	client *uaa.UAA
+	host   string
+}
+
+func NewUAAUsers(client *uaa.UAA, host string) UAAUsers {
+	return UAAUsers{
+		client: client,
+		host:   host,
+	}
+}
+
+func (users UAAUsers) UserGUIDs(filter string, count int) ([]string, error) {
+	var guids []string
+
+	query := url.Values{}
+	query.Set("attributes", "id")
+	query.Set("sortBy", "id")
+	query.Set("count", strconv.Itoa(count))
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+
+	page, _, err := uaa.PaginatedUsersFromQuery(*users.client, users.host+"/Users?"+query.Encode())
+	if err != nil {
+		return guids, err
+	}
+
+	for _, user := range page {
+		guids = append(guids, user.ID)
+	}
+
+	return guids, nil
+}
+
+// afterFilter narrows a SCIM filter to the users whose IDs sort after the
+// given one.
+func afterFilter(filter, after string) string {
+	if after == "" {
+		return filter
+	}
+
+	clause := "id gt " + strconv.Quote(after)
+	if filter == "" {
+		return clause
+	}
+
+	return filter + " and " + clause
 }
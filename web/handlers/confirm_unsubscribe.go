@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+// ConfirmUnsubscribe serves the unsubscribe links embedded in delivered
+// messages. It only asks the recipient to confirm, as mail scanners and
+// prefetchers follow links without the recipient clicking them. The form on
+// the page posts back to the same link, where Unsubscribe consumes it.
+type ConfirmUnsubscribe struct {
+	unsubscriber services.UnsubscriberInterface
+	errorWriter  ErrorWriterInterface
+	database     models.DatabaseInterface
+}
+
+func NewConfirmUnsubscribe(unsubscriber services.UnsubscriberInterface, errorWriter ErrorWriterInterface, database models.DatabaseInterface) ConfirmUnsubscribe {
+	return ConfirmUnsubscribe{
+		unsubscriber: unsubscriber,
+		errorWriter:  errorWriter,
+		database:     database,
+	}
+}
+
+func (handler ConfirmUnsubscribe) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.Split(req.URL.Path, "/unsubscribe/")[1]
+
+	unsubscription, err := handler.unsubscriber.Find(handler.database.Connection(), unsubscribeID)
+	if err != nil {
+		writeUnsubscribeError(w, handler.errorWriter, err)
+		return
+	}
+
+	renderUnsubscribePage(w, http.StatusOK, unsubscribePageData{
+		Title:   "Unsubscribe",
+		Message: "Do you want to stop receiving \"" + unsubscription.KindDescription + "\" notifications by email?",
+		Confirm: true,
+	})
+}
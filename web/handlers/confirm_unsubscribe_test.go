@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfirmUnsubscribe", func() {
+	var handler handlers.ConfirmUnsubscribe
+	var unsubscriber *fakes.Unsubscriber
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+
+	BeforeEach(func() {
+		var err error
+
+		unsubscriber = fakes.NewUnsubscriber()
+		unsubscriber.Unsubscription = services.Unsubscription{
+			UserID:          "user-123",
+			ClientID:        "some-client",
+			KindID:          "some-kind",
+			KindDescription: "Daily <digest>",
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewConfirmUnsubscribe(unsubscriber, errorWriter, fakes.NewDatabase())
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("GET", "/unsubscribe/the-unsubscribe-id", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("asks the recipient to confirm without unsubscribing them", func() {
+		handler.ServeHTTP(writer, request, nil)
+
+		Expect(unsubscriber.FindArguments).To(Equal([]string{"the-unsubscribe-id"}))
+		Expect(unsubscriber.UnsubscribeArguments).To(BeEmpty())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(writer.Body.String()).To(ContainSubstring("Daily &lt;digest&gt;"))
+		Expect(writer.Body.String()).To(ContainSubstring(`<form method="post">`))
+	})
+
+	Context("when the unsubscribe ID is not valid", func() {
+		It("renders a not found page without a form", func() {
+			unsubscriber.FindError = services.InvalidUnsubscribeIDError("invalid")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(ContainSubstring("This unsubscribe link is not valid."))
+			Expect(writer.Body.String()).NotTo(ContainSubstring("<form"))
+		})
+	})
+
+	Context("when the kind is critical", func() {
+		It("renders a page explaining that it cannot be unsubscribed from", func() {
+			unsubscriber.FindError = services.CriticalKindError("critical")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(ContainSubstring("cannot be unsubscribed from"))
+		})
+	})
+
+	Context("when the unsubscription cannot be found for another reason", func() {
+		It("delegates to the error writer", func() {
+			unsubscriber.FindError = errors.New("database is gone")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(errorWriter.Error).To(Equal(unsubscriber.FindError))
+		})
+	})
+})
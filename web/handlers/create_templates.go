@@ -5,6 +5,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -29,7 +30,10 @@ func (handler CreateTemplate) ServeHTTP(w http.ResponseWriter, req *http.Request
 
 	template := templateParams.ToModel()
 
-	templateID, err := handler.Creator.Create(template)
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	templateID, err := handler.Creator.Create(template, clientID)
 	if err != nil {
 		handler.ErrorWriter.Write(w, params.TemplateCreateError{})
 		return
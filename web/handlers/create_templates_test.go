@@ -10,6 +10,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -31,6 +32,13 @@ var _ = Describe("CreateTemplate", func() {
 			errorWriter = fakes.NewErrorWriter()
 			handler = handlers.NewCreateTemplate(creator, errorWriter)
 			writer = httptest.NewRecorder()
+
+			context = stack.NewContext()
+			context.Set("token", &jwt.Token{
+				Claims: map[string]interface{}{
+					"client_id": "my-client",
+				},
+			})
 			body := []byte(`{"name": "Emergency Template", "text": "Message to: {{.To}}. Raptor Alert.", "html": "<p>{{.ClientID}} you should run.</p>", "subject": "Raptor Containment Unit Breached"}`)
 			request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer(body))
 			if err != nil {
@@ -49,6 +57,7 @@ var _ = Describe("CreateTemplate", func() {
 				Subject:  "Raptor Containment Unit Breached",
 				Metadata: "{}",
 			}))
+			Expect(creator.CreateAuthor).To(Equal("my-client"))
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(body).To(Equal(`{"template_id":"guid"}`))
 		})
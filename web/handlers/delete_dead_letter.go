@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteDeadLetter struct {
+	purger      services.DeadLetterPurgerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewDeleteDeadLetter(purger services.DeadLetterPurgerInterface, errorWriter ErrorWriterInterface) DeleteDeadLetter {
+	return DeleteDeadLetter{
+		purger:      purger,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler DeleteDeadLetter) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadLetterID := strings.Split(req.URL.Path, "/dead_letters/")[1]
+
+	err := handler.purger.Purge(deadLetterID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
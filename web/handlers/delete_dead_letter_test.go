@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteDeadLetter", func() {
+	var handler handlers.DeleteDeadLetter
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var purger *fakes.DeadLetterPurger
+
+	BeforeEach(func() {
+		var err error
+
+		purger = fakes.NewDeadLetterPurger()
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewDeleteDeadLetter(purger, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("DELETE", "/dead_letters/7", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("purges the dead letter", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(purger.PurgeArgument).To(Equal("7"))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("writes errors to the error writer", func() {
+		purger.PurgeError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(purger.PurgeError))
+	})
+})
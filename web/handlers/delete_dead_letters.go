@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteDeadLetters struct {
+	purger      services.DeadLetterPurgerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewDeleteDeadLetters(purger services.DeadLetterPurgerInterface, errorWriter ErrorWriterInterface) DeleteDeadLetters {
+	return DeleteDeadLetters{
+		purger:      purger,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler DeleteDeadLetters) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	count, err := handler.purger.PurgeAll()
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"purged": count,
+	})
+}
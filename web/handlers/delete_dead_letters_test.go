@@ -0,0 +1,54 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteDeadLetters", func() {
+	var handler handlers.DeleteDeadLetters
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var purger *fakes.DeadLetterPurger
+
+	BeforeEach(func() {
+		var err error
+
+		purger = fakes.NewDeadLetterPurger()
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewDeleteDeadLetters(purger, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("DELETE", "/dead_letters", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("purges every dead letter and reports how many were purged", func() {
+		purger.PurgeAllCount = 2
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(purger.PurgeAllWasCalled).To(BeTrue())
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"purged": 2}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		purger.PurgeAllError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(purger.PurgeAllError))
+	})
+})
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteMessages struct {
+	canceler    MessageCancelerInterface
+	errorWriter ErrorWriterInterface
+}
+
+type MessageCancelerInterface interface {
+	Cancel(string, string) error
+}
+
+func NewDeleteMessages(canceler MessageCancelerInterface, errorWriter ErrorWriterInterface) DeleteMessages {
+	return DeleteMessages{
+		canceler:    canceler,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler DeleteMessages) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	messageID := strings.Split(req.URL.Path, "/messages/")[1]
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	err := handler.canceler.Cancel(clientID, messageID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
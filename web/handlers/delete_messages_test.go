@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteMessages", func() {
+	var handler handlers.DeleteMessages
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var canceler *fakes.MessageCanceler
+	var context stack.Context
+
+	BeforeEach(func() {
+		var err error
+
+		errorWriter = fakes.NewErrorWriter()
+		canceler = fakes.NewMessageCanceler()
+		handler = handlers.NewDeleteMessages(canceler, errorWriter)
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("DELETE", "/messages/message-123", nil)
+		if err != nil {
+			panic(err)
+		}
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
+	})
+
+	Describe("ServeHTTP", func() {
+		It("cancels the given message on behalf of the client", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(canceler.CancelArguments).To(Equal([]string{"my-client", "message-123"}))
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+		})
+
+		It("writes errors to the error writer", func() {
+			canceler.CancelError = postal.MessageNotCancelableError("too late")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.Error).To(Equal(canceler.CancelError))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteSuppression", func() {
+	var handler handlers.DeleteSuppression
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var deleter *fakes.SuppressionDeleter
+
+	BeforeEach(func() {
+		var err error
+
+		deleter = fakes.NewSuppressionDeleter()
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewDeleteSuppression(deleter, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("DELETE", "/suppressions/user@example.com", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("removes the address from the suppression list", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(deleter.DeleteArgument).To(Equal("user@example.com"))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("writes errors to the error writer", func() {
+		deleter.DeleteError = models.NewRecordNotFoundError("Suppression of %q could not be found", "user@example.com")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(deleter.DeleteError))
+	})
+})
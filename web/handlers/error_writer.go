@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
@@ -34,6 +35,12 @@ func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 		writer.write(w, http.StatusBadGateway, []string{err.Error()})
 	case postal.UAAGenericError:
 		writer.write(w, http.StatusBadGateway, []string{err.Error()})
+	case postal.TemplateError:
+		writer.write(w, 422, []string{err.Error()})
+	case postal.TestSendError:
+		writer.write(w, http.StatusBadGateway, []string{err.Error()})
+	case postal.RecipientSuppressedError:
+		writer.write(w, 422, []string{err.Error()})
 	case postal.TemplateLoadError:
 		writer.write(w, http.StatusInternalServerError, []string{err.Error()})
 	case params.TemplateCreateError:
@@ -48,12 +55,16 @@ func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 		writer.write(w, 422, err.(params.ValidationError).Errors())
 	case params.SchemaError:
 		writer.write(w, http.StatusBadRequest, []string{err.Error()})
+	case postal.MessageNotCancelableError:
+		writer.write(w, http.StatusConflict, []string{err.Error()})
 	case postal.CriticalNotificationError:
 		writer.write(w, 422, []string{err.Error()})
 	case models.DuplicateRecordError:
 		writer.write(w, http.StatusConflict, []string{err.Error()})
 	case models.RecordNotFoundError:
 		writer.write(w, http.StatusNotFound, []string{err.Error()})
+	case gobble.DeadLetterNotFoundError:
+		writer.write(w, http.StatusNotFound, []string{err.Error()})
 	case models.TransactionCommitError:
 		writer.write(w, http.StatusInternalServerError, []string{err.Error()})
 	case strategies.DefaultScopeError:
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
@@ -98,6 +99,48 @@ var _ = Describe("ErrorWriter", func() {
 		Expect(body["errors"]).To(ContainElement("Your template doesn't exist!!!"))
 	})
 
+	It("returns a 422 when a template cannot be compiled", func() {
+		writer.Write(recorder, postal.TemplateError{Part: "text", Line: 2, Column: 4, Message: "bad field"})
+
+		Expect(recorder.Code).To(Equal(422))
+
+		body := make(map[string]interface{})
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["errors"]).To(ContainElement("template text:2:4: bad field"))
+	})
+
+	It("returns a 502 when a test email cannot be sent", func() {
+		writer.Write(recorder, postal.TestSendError("The test email could not be sent: connection refused"))
+
+		Expect(recorder.Code).To(Equal(http.StatusBadGateway))
+
+		body := make(map[string]interface{})
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["errors"]).To(ContainElement("The test email could not be sent: connection refused"))
+	})
+
+	It("returns a 422 when the address of a test email is suppressed", func() {
+		writer.Write(recorder, postal.RecipientSuppressedError("designer@example.com is suppressed: bounced"))
+
+		Expect(recorder.Code).To(Equal(422))
+
+		body := make(map[string]interface{})
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["errors"]).To(ContainElement("designer@example.com is suppressed: bounced"))
+	})
+
 	It("returns a 500 when there is a template create error", func() {
 		writer.Write(recorder, params.TemplateCreateError{})
 
@@ -225,6 +268,34 @@ var _ = Describe("ErrorWriter", func() {
 		Expect(body["errors"]).To(ContainElement("Record Not Found: hello"))
 	})
 
+	It("returns a 409 when a message can no longer be canceled", func() {
+		writer.Write(recorder, postal.MessageNotCancelableError("Message is already being delivered"))
+
+		Expect(recorder.Code).To(Equal(409))
+
+		body := make(map[string]interface{})
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["errors"]).To(ContainElement("Message is already being delivered"))
+	})
+
+	It("returns a 404 when a dead letter cannot be found", func() {
+		writer.Write(recorder, gobble.DeadLetterNotFoundError{ID: 42})
+
+		Expect(recorder.Code).To(Equal(404))
+
+		body := make(map[string]interface{})
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["errors"]).To(ContainElement("Dead letter with ID 42 could not be found"))
+	})
+
 	It("returns a 406 when a record cannot be found", func() {
 		writer.Write(recorder, strategies.DefaultScopeError{})
 		Expect(recorder.Code).To(Equal(406))
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+const (
+	DefaultBatchPerPage = 50
+	MaxBatchPerPage     = 500
+)
+
+var batchStatuses = []string{
+	postal.StatusQueued,
+	postal.StatusScheduled,
+	postal.StatusDelivered,
+	postal.StatusFailed,
+	postal.StatusUnavailable,
+	postal.StatusUndeliverable,
+	postal.StatusCanceled,
+	postal.StatusBounced,
+}
+
+type GetBatches struct {
+	finder      services.BatchFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewGetBatches(finder services.BatchFinderInterface, errorWriter ErrorWriterInterface) GetBatches {
+	return GetBatches{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler GetBatches) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	batchID := strings.Split(req.URL.Path, "/batches/")[1]
+
+	page, perPage, err := parsePagination(req, DefaultBatchPerPage, MaxBatchPerPage)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	batch, err := handler.finder.Find(clientID, batchID, page, perPage)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	type recipientDocument struct {
+		NotificationID string    `json:"notification_id"`
+		Recipient      string    `json:"recipient"`
+		Status         string    `json:"status"`
+		UpdatedAt      time.Time `json:"updated_at"`
+	}
+
+	var document struct {
+		BatchID        string              `json:"batch_id"`
+		Status         string              `json:"status"`
+		RecipientCount int                 `json:"recipient_count"`
+		Total          int                 `json:"total"`
+		Counts         map[string]int      `json:"counts"`
+		Page           int                 `json:"page"`
+		PerPage        int                 `json:"per_page"`
+		Recipients     []recipientDocument `json:"recipients"`
+	}
+	document.BatchID = batch.ID
+	document.Status = batch.Status
+	document.RecipientCount = batch.RecipientCount
+	document.Total = batch.Total
+	document.Page = page
+	document.PerPage = perPage
+
+	document.Counts = map[string]int{}
+	for _, status := range batchStatuses {
+		document.Counts[status] = 0
+	}
+	for status, count := range batch.Counts {
+		document.Counts[status] = count
+	}
+
+	document.Recipients = []recipientDocument{}
+	for _, message := range batch.Messages {
+		document.Recipients = append(document.Recipients, recipientDocument{
+			NotificationID: message.ID,
+			Recipient:      message.Recipient,
+			Status:         message.Status,
+			UpdatedAt:      message.UpdatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
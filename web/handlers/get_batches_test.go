@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetBatches", func() {
+	var handler handlers.GetBatches
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var finder *fakes.BatchFinder
+	var context stack.Context
+
+	BeforeEach(func() {
+		errorWriter = fakes.NewErrorWriter()
+		finder = fakes.NewBatchFinder()
+		handler = handlers.NewGetBatches(finder, errorWriter)
+		writer = httptest.NewRecorder()
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
+	})
+
+	Describe("ServeHTTP", func() {
+		It("returns the state, counts and recipients of the client's batch", func() {
+			updatedAt := time.Date(2014, time.November, 6, 20, 6, 27, 0, time.UTC)
+			finder.Batch = services.Batch{
+				ID:             "batch-123",
+				Status:         "expanding",
+				RecipientCount: 5,
+				Total:          3,
+				Counts: map[string]int{
+					"delivered": 2,
+					"queued":    1,
+				},
+				Messages: []models.Message{
+					{ID: "message-1", Recipient: "user-1", Status: "delivered", UpdatedAt: updatedAt},
+				},
+			}
+
+			request, err := http.NewRequest("GET", "/batches/batch-123?page=2&per_page=1", nil)
+			if err != nil {
+				panic(err)
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(finder.FindArguments).To(Equal([]interface{}{"my-client", "batch-123", 2, 1}))
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"batch_id": "batch-123",
+				"status": "expanding",
+				"recipient_count": 5,
+				"total": 3,
+				"counts": {
+					"queued": 1,
+					"scheduled": 0,
+					"delivered": 2,
+					"failed": 0,
+					"unavailable": 0,
+					"undeliverable": 0,
+					"canceled": 0,
+					"bounced": 0
+				},
+				"page": 2,
+				"per_page": 1,
+				"recipients": [{
+					"notification_id": "message-1",
+					"recipient": "user-1",
+					"status": "delivered",
+					"updated_at": "2014-11-06T20:06:27Z"
+				}]
+			}`))
+		})
+
+		It("defaults to the first page", func() {
+			request, err := http.NewRequest("GET", "/batches/batch-123", nil)
+			if err != nil {
+				panic(err)
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(finder.FindArguments).To(Equal([]interface{}{"my-client", "batch-123", 1, handlers.DefaultBatchPerPage}))
+		})
+
+		Context("when the pagination params are invalid", func() {
+			It("writes a validation error", func() {
+				request, err := http.NewRequest("GET", "/batches/batch-123?page=0&per_page=5000", nil)
+				if err != nil {
+					panic(err)
+				}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+				Expect(errorWriter.Error.(params.ValidationError).Errors()).To(HaveLen(2))
+				Expect(finder.FindArguments).To(BeNil())
+			})
+		})
+
+		Context("when the finder returns an error", func() {
+			It("writes the error", func() {
+				finder.FindError = errors.New("BOOM!")
+
+				request, err := http.NewRequest("GET", "/batches/batch-123", nil)
+				if err != nil {
+					panic(err)
+				}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.Error).To(Equal(finder.FindError))
+			})
+		})
+	})
+})
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type GetDeadLetter struct {
+	finder      services.DeadLetterFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewGetDeadLetter(finder services.DeadLetterFinderInterface, errorWriter ErrorWriterInterface) GetDeadLetter {
+	return GetDeadLetter{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler GetDeadLetter) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadLetterID := strings.Split(req.URL.Path, "/dead_letters/")[1]
+
+	deadLetter, err := handler.finder.Find(deadLetterID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewDeadLetterDocument(deadLetter))
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetDeadLetter", func() {
+	var handler handlers.GetDeadLetter
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var finder *fakes.DeadLetterFinder
+
+	BeforeEach(func() {
+		var err error
+
+		finder = fakes.NewDeadLetterFinder()
+		finder.DeadLetters = []gobble.DeadLetter{
+			{
+				ID:         7,
+				JobID:      42,
+				Payload:    `{"some":"payload"}`,
+				RetryCount: 10,
+				LastError:  "connection refused",
+				CreatedAt:  time.Date(2014, time.October, 1, 12, 0, 0, 0, time.UTC),
+			},
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewGetDeadLetter(finder, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/dead_letters/7", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("returns the dead letter including its payload", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(finder.FindArgument).To(Equal("7"))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": 7,
+			"job_id": 42,
+			"payload": "{\"some\":\"payload\"}",
+			"retry_count": 10,
+			"last_error": "connection refused",
+			"created_at": "2014-10-01T12:00:00Z"
+		}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.FindError = gobble.DeadLetterNotFoundError{ID: 7}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(finder.FindError))
+	})
+
+	It("writes unexpected errors to the error writer", func() {
+		finder.FindError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(finder.FindError))
+	})
+})
@@ -3,8 +3,10 @@ package handlers
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -14,7 +16,7 @@ type GetMessages struct {
 }
 
 type MessageFinderInterface interface {
-	Find(string) (services.Message, error)
+	Find(string, string) (services.Message, error)
 }
 
 func NewGetMessages(messageFinder MessageFinderInterface, errorWriter ErrorWriterInterface) GetMessages {
@@ -26,17 +28,41 @@ func NewGetMessages(messageFinder MessageFinderInterface, errorWriter ErrorWrite
 
 func (handler GetMessages) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	messageID := strings.Split(req.URL.Path, "/messages/")[1]
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
 
-	message, err := handler.Finder.Find(messageID)
+	message, err := handler.Finder.Find(clientID, messageID)
 	if err != nil {
 		handler.errorWriter.Write(w, err)
 		return
 	}
 
+	type event struct {
+		Status    string    `json:"status"`
+		Attempt   int       `json:"attempt"`
+		WorkerID  string    `json:"worker_id"`
+		Relay     string    `json:"relay"`
+		Error     string    `json:"error"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
 	var document struct {
-		Status string `json:"status"`
+		Status string  `json:"status"`
+		Events []event `json:"events"`
 	}
 	document.Status = message.Status
+	document.Events = []event{}
+
+	for _, messageEvent := range message.Events {
+		document.Events = append(document.Events, event{
+			Status:    messageEvent.Status,
+			Attempt:   messageEvent.Attempt,
+			WorkerID:  messageEvent.WorkerID,
+			Relay:     messageEvent.Relay,
+			Error:     messageEvent.Error,
+			CreatedAt: messageEvent.CreatedAt,
+		})
+	}
 
 	writeJSON(w, http.StatusOK, document)
 }
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/ryanmoran/stack"
 )
 
 var _ = Describe("GetMessages", func() {
@@ -20,6 +24,7 @@ var _ = Describe("GetMessages", func() {
 	var messageID string
 	var err error
 	var messageFinder *fakes.MessageFinder
+	var context stack.Context
 
 	BeforeEach(func() {
 		errorWriter = fakes.NewErrorWriter()
@@ -33,6 +38,12 @@ var _ = Describe("GetMessages", func() {
 			panic(err)
 		}
 
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
 	})
 
 	Describe("ServeHTTP", func() {
@@ -41,19 +52,84 @@ var _ = Describe("GetMessages", func() {
 				Status: "The generic status returned",
 			}
 
-			handler.ServeHTTP(writer, request, nil)
+			handler.ServeHTTP(writer, request, context)
 
 			Expect(writer.Code).To(Equal(http.StatusOK))
 			Expect(writer.Body.Bytes()).To(MatchJSON(`{
-				"status": "The generic status returned"
+				"status": "The generic status returned",
+				"events": []
 			}`))
 		})
 
+		It("returns the status history of the given message", func() {
+			createdAt := time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC)
+			messageFinder.Messages[messageID] = services.Message{
+				Status: "failed",
+				Events: []models.MessageEvent{
+					{
+						ID:        1,
+						MessageID: messageID,
+						Status:    "queued",
+						CreatedAt: createdAt,
+					},
+					{
+						ID:        2,
+						MessageID: messageID,
+						Status:    "failed",
+						Attempt:   1,
+						WorkerID:  "worker-2",
+						Relay:     "smtp.example.com:587",
+						Error:     "connection refused",
+						CreatedAt: createdAt.Add(time.Minute),
+					},
+				},
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"status": "failed",
+				"events": [
+					{
+						"status": "queued",
+						"attempt": 0,
+						"worker_id": "",
+						"relay": "",
+						"error": "",
+						"created_at": "2015-03-04T12:30:00Z"
+					},
+					{
+						"status": "failed",
+						"attempt": 1,
+						"worker_id": "worker-2",
+						"relay": "smtp.example.com:587",
+						"error": "connection refused",
+						"created_at": "2015-03-04T12:31:00Z"
+					}
+				]
+			}`))
+		})
+
+		It("looks up the message on behalf of the client", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(messageFinder.FindArguments).To(Equal([]string{"my-client", messageID}))
+		})
+
+		It("reports messages of other clients as not found", func() {
+			messageFinder.FindError = models.NewRecordNotFoundError("Message with ID %q could not be found", messageID)
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.Error).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
 		Context("When the finder errors", func() {
 			It("Delegates to the error writer", func() {
 				findError := errors.New("The finder returns a generic error")
 				messageFinder.FindError = findError
-				handler.ServeHTTP(writer, request, nil)
+				handler.ServeHTTP(writer, request, context)
 				Expect(errorWriter.Error).To(Equal(findError))
 			})
 		})
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type GetSuppression struct {
+	finder      services.SuppressionFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewGetSuppression(finder services.SuppressionFinderInterface, errorWriter ErrorWriterInterface) GetSuppression {
+	return GetSuppression{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler GetSuppression) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	email := strings.Split(req.URL.Path, "/suppressions/")[1]
+
+	suppression, err := handler.finder.Find(email)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewSuppressionDocument(suppression))
+}
@@ -0,0 +1,64 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetSuppression", func() {
+	var handler handlers.GetSuppression
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var finder *fakes.SuppressionFinder
+
+	BeforeEach(func() {
+		var err error
+
+		finder = fakes.NewSuppressionFinder()
+		finder.Suppressions = []models.Suppression{
+			{
+				Email:     "user@example.com",
+				Reason:    "legal request",
+				CreatedAt: time.Date(2014, time.October, 1, 12, 0, 0, 0, time.UTC),
+			},
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewGetSuppression(finder, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/suppressions/user@example.com", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("returns the suppression of the address", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(finder.FindArgument).To(Equal("user@example.com"))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"email": "user@example.com",
+			"reason": "legal request",
+			"created_at": "2014-10-01T12:00:00Z"
+		}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.FindError = models.NewRecordNotFoundError("Suppression of %q could not be found", "user@example.com")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(finder.FindError))
+	})
+})
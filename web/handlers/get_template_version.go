@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type GetTemplateVersion struct {
+	finder      services.TemplateVersionFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewGetTemplateVersion(finder services.TemplateVersionFinderInterface, errorWriter ErrorWriterInterface) GetTemplateVersion {
+	return GetTemplateVersion{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler GetTemplateVersion) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, number, err := templateVersionFromPath(req.URL.Path)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	version, err := handler.finder.Find(templateID, number)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := NewTemplateVersionOutput(version)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,81 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetTemplateVersion", func() {
+	var handler handlers.GetTemplateVersion
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var finder *fakes.TemplateVersionFinder
+
+	newRequest := func(path string) *http.Request {
+		request, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			panic(err)
+		}
+		return request
+	}
+
+	BeforeEach(func() {
+		errorWriter = fakes.NewErrorWriter()
+		finder = fakes.NewTemplateVersionFinder()
+		handler = handlers.NewGetTemplateVersion(finder, errorWriter)
+		writer = httptest.NewRecorder()
+	})
+
+	It("returns the content of the version", func() {
+		finder.Version = models.TemplateVersion{
+			TemplateID: "template-id",
+			Version:    3,
+			Name:       "My Template",
+			Subject:    "{{.Subject}}",
+			Text:       "{{.Text}}",
+			HTML:       "<p>{{.HTML}}</p>",
+			Metadata:   `{"tag": "<h1>"}`,
+			Author:     "my-client",
+			CreatedAt:  time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/3"), nil)
+
+		Expect(finder.FindTemplateID).To(Equal("template-id"))
+		Expect(finder.FindVersion).To(Equal(3))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 3,
+			"name": "My Template",
+			"subject": "{{.Subject}}",
+			"text": "{{.Text}}",
+			"html": "<p>{{.HTML}}</p>",
+			"metadata": {"tag": "<h1>"},
+			"author": "my-client",
+			"created_at": "2015-03-04T12:30:00Z"
+		}`))
+	})
+
+	It("does not find versions that are not numbers", func() {
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/latest"), nil)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		Expect(finder.FindTemplateID).To(BeEmpty())
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.FindError = models.NewRecordNotFoundError("Version 3 of template %q could not be found", "template-id")
+
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/3"), nil)
+
+		Expect(errorWriter.Error).To(Equal(finder.FindError))
+	})
+})
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+const (
+	DefaultDeadLetterPerPage = 50
+	MaxDeadLetterPerPage     = 500
+)
+
+type DeadLetterDocument struct {
+	ID         int       `json:"id"`
+	JobID      int       `json:"job_id"`
+	RetryCount int       `json:"retry_count"`
+	LastError  string    `json:"last_error"`
+	Payload    string    `json:"payload,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewDeadLetterDocument(deadLetter gobble.DeadLetter) DeadLetterDocument {
+	return DeadLetterDocument{
+		ID:         deadLetter.ID,
+		JobID:      deadLetter.JobID,
+		RetryCount: deadLetter.RetryCount,
+		LastError:  deadLetter.LastError,
+		Payload:    deadLetter.Payload,
+		CreatedAt:  deadLetter.CreatedAt,
+	}
+}
+
+type ListDeadLetters struct {
+	finder      services.DeadLetterFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewListDeadLetters(finder services.DeadLetterFinderInterface, errorWriter ErrorWriterInterface) ListDeadLetters {
+	return ListDeadLetters{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler ListDeadLetters) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	page, perPage, err := parsePagination(req, DefaultDeadLetterPerPage, MaxDeadLetterPerPage)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	deadLetters, err := handler.finder.List(page, perPage)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	var document struct {
+		Page        int                  `json:"page"`
+		PerPage     int                  `json:"per_page"`
+		DeadLetters []DeadLetterDocument `json:"dead_letters"`
+	}
+	document.Page = page
+	document.PerPage = perPage
+	document.DeadLetters = []DeadLetterDocument{}
+
+	for _, deadLetter := range deadLetters {
+		deadLetterDocument := NewDeadLetterDocument(deadLetter)
+		deadLetterDocument.Payload = ""
+		document.DeadLetters = append(document.DeadLetters, deadLetterDocument)
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
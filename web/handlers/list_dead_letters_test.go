@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListDeadLetters", func() {
+	var handler handlers.ListDeadLetters
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var finder *fakes.DeadLetterFinder
+	var createdAt time.Time
+
+	BeforeEach(func() {
+		var err error
+
+		createdAt = time.Date(2014, time.October, 1, 12, 0, 0, 0, time.UTC)
+		finder = fakes.NewDeadLetterFinder()
+		finder.DeadLetters = []gobble.DeadLetter{
+			{
+				ID:         1,
+				JobID:      42,
+				Payload:    `{"some":"payload"}`,
+				RetryCount: 10,
+				LastError:  "connection refused",
+				CreatedAt:  createdAt,
+			},
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewListDeadLetters(finder, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/dead_letters", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("returns the dead letters without their payloads", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"page": 1,
+			"per_page": 50,
+			"dead_letters": [
+				{
+					"id": 1,
+					"job_id": 42,
+					"retry_count": 10,
+					"last_error": "connection refused",
+					"created_at": "2014-10-01T12:00:00Z"
+				}
+			]
+		}`))
+	})
+
+	It("returns an empty list when there are no dead letters", func() {
+		finder.DeadLetters = []gobble.DeadLetter{}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"page": 1, "per_page": 50, "dead_letters": []}`))
+	})
+
+	It("returns the requested page", func() {
+		var err error
+		request, err = http.NewRequest("GET", "/dead_letters?page=3&per_page=20", nil)
+		if err != nil {
+			panic(err)
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(finder.ListArguments).To(Equal([]int{3, 20}))
+
+		var document struct {
+			Page    int `json:"page"`
+			PerPage int `json:"per_page"`
+		}
+		err = json.Unmarshal(writer.Body.Bytes(), &document)
+		if err != nil {
+			panic(err)
+		}
+		Expect(document.Page).To(Equal(3))
+		Expect(document.PerPage).To(Equal(20))
+	})
+
+	It("writes a validation error when the pagination is out of range", func() {
+		var err error
+		request, err = http.NewRequest("GET", "/dead_letters?page=0&per_page=501", nil)
+		if err != nil {
+			panic(err)
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+		Expect(finder.ListArguments).To(BeNil())
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.ListError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(finder.ListError))
+	})
+
+	It("renders a document that survives a round trip", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		var document struct {
+			DeadLetters []handlers.DeadLetterDocument `json:"dead_letters"`
+		}
+		err := json.Unmarshal(writer.Body.Bytes(), &document)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(document.DeadLetters[0].CreatedAt).To(Equal(createdAt))
+	})
+})
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/ryanmoran/stack"
+)
+
+type FlaggedTemplateDocument struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type ListFlaggedTemplates struct {
+	checker     postal.TemplateEscapingCheckerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewListFlaggedTemplates(checker postal.TemplateEscapingCheckerInterface, errorWriter ErrorWriterInterface) ListFlaggedTemplates {
+	return ListFlaggedTemplates{
+		checker:     checker,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler ListFlaggedTemplates) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	flagged, err := handler.checker.Check()
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	var document struct {
+		Templates []FlaggedTemplateDocument `json:"templates"`
+	}
+	document.Templates = []FlaggedTemplateDocument{}
+
+	for _, template := range flagged {
+		document.Templates = append(document.Templates, FlaggedTemplateDocument{
+			ID:     template.ID,
+			Name:   template.Name,
+			Reason: template.Reason,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListFlaggedTemplates", func() {
+	var handler handlers.ListFlaggedTemplates
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var checker *fakes.TemplateEscapingChecker
+
+	BeforeEach(func() {
+		var err error
+
+		checker = fakes.NewTemplateEscapingChecker()
+		checker.Flagged = []postal.FlaggedTemplate{
+			{
+				ID:     "template-id",
+				Name:   "Template Name",
+				Reason: "renders HTML differently with contextual escaping",
+			},
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewListFlaggedTemplates(checker, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/flagged_templates", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("returns the templates that need to be reviewed", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"templates": [
+				{
+					"id": "template-id",
+					"name": "Template Name",
+					"reason": "renders HTML differently with contextual escaping"
+				}
+			]
+		}`))
+	})
+
+	It("returns an empty list when no templates are flagged", func() {
+		checker.Flagged = []postal.FlaggedTemplate{}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"templates": []}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		checker.CheckError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(checker.CheckError))
+	})
+})
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type SuppressionDocument struct {
+	Email     string    `json:"email"`
+	Reason    string    `json:"reason"`
+	MessageID string    `json:"message_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewSuppressionDocument(suppression models.Suppression) SuppressionDocument {
+	return SuppressionDocument{
+		Email:     suppression.Email,
+		Reason:    suppression.Reason,
+		MessageID: suppression.MessageID,
+		CreatedAt: suppression.CreatedAt,
+	}
+}
+
+type ListSuppressions struct {
+	finder      services.SuppressionFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewListSuppressions(finder services.SuppressionFinderInterface, errorWriter ErrorWriterInterface) ListSuppressions {
+	return ListSuppressions{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler ListSuppressions) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	suppressions, err := handler.finder.List()
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	var document struct {
+		Suppressions []SuppressionDocument `json:"suppressions"`
+	}
+	document.Suppressions = []SuppressionDocument{}
+
+	for _, suppression := range suppressions {
+		document.Suppressions = append(document.Suppressions, NewSuppressionDocument(suppression))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
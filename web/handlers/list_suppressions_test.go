@@ -0,0 +1,89 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListSuppressions", func() {
+	var handler handlers.ListSuppressions
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var finder *fakes.SuppressionFinder
+
+	BeforeEach(func() {
+		var err error
+
+		finder = fakes.NewSuppressionFinder()
+		finder.Suppressions = []models.Suppression{
+			{
+				Email:     "bounced@example.com",
+				Reason:    "5.1.1 550 User unknown",
+				MessageID: "message-123",
+				CreatedAt: time.Date(2014, time.October, 1, 12, 0, 0, 0, time.UTC),
+			},
+			{
+				Email:     "complained@example.com",
+				Reason:    "legal request",
+				CreatedAt: time.Date(2014, time.October, 2, 12, 0, 0, 0, time.UTC),
+			},
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewListSuppressions(finder, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/suppressions", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("returns the suppressed addresses with their reasons", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"suppressions": [
+				{
+					"email": "bounced@example.com",
+					"reason": "5.1.1 550 User unknown",
+					"message_id": "message-123",
+					"created_at": "2014-10-01T12:00:00Z"
+				},
+				{
+					"email": "complained@example.com",
+					"reason": "legal request",
+					"created_at": "2014-10-02T12:00:00Z"
+				}
+			]
+		}`))
+	})
+
+	It("returns an empty list when no address is suppressed", func() {
+		finder.Suppressions = []models.Suppression{}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"suppressions": []}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.ListError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(finder.ListError))
+	})
+})
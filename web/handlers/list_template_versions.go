@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type ListTemplateVersions struct {
+	finder      services.TemplateVersionFinderInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewListTemplateVersions(finder services.TemplateVersionFinderInterface, errorWriter ErrorWriterInterface) ListTemplateVersions {
+	return ListTemplateVersions{
+		finder:      finder,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler ListTemplateVersions) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := strings.TrimSuffix(strings.Split(req.URL.Path, "/templates/")[1], "/versions")
+
+	versions, err := handler.finder.FindAll(templateID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	summaries := []TemplateVersionSummary{}
+	for _, version := range versions {
+		summaries = append(summaries, TemplateVersionSummary{
+			Version:   version.Version,
+			Name:      version.Name,
+			Author:    version.Author,
+			CreatedAt: version.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": summaries,
+	})
+}
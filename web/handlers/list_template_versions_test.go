@@ -0,0 +1,64 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListTemplateVersions", func() {
+	var handler handlers.ListTemplateVersions
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var finder *fakes.TemplateVersionFinder
+
+	BeforeEach(func() {
+		var err error
+
+		errorWriter = fakes.NewErrorWriter()
+		finder = fakes.NewTemplateVersionFinder()
+		handler = handlers.NewListTemplateVersions(finder, errorWriter)
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("GET", "/templates/template-id/versions", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("lists the versions of the template", func() {
+		createdAt := time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC)
+		finder.Versions = []models.TemplateVersion{
+			{TemplateID: "template-id", Version: 2, Name: "second", HTML: "<p>second</p>", Author: "my-client", CreatedAt: createdAt},
+			{TemplateID: "template-id", Version: 1, Name: "first", HTML: "<p>first</p>", Author: "other-client", CreatedAt: createdAt},
+		}
+
+		handler.ServeHTTP(writer, request, nil)
+
+		Expect(finder.FindAllArgument).To(Equal("template-id"))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{"version": 2, "name": "second", "author": "my-client", "created_at": "2015-03-04T12:30:00Z"},
+				{"version": 1, "name": "first", "author": "other-client", "created_at": "2015-03-04T12:30:00Z"}
+			]
+		}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		finder.FindAllError = errors.New("Boom!")
+
+		handler.ServeHTTP(writer, request, nil)
+
+		Expect(errorWriter.Error).To(Equal(finder.FindAllError))
+	})
+})
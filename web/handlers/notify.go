@@ -15,6 +15,7 @@ import (
 
 type NotifyInterface interface {
 	Execute(models.ConnectionInterface, *http.Request, stack.Context, string, strategies.StrategyInterface, ValidatorInterface) ([]byte, error)
+	Launch(models.ConnectionInterface, *http.Request, stack.Context, string, strategies.CampaignStrategyInterface, ValidatorInterface) ([]byte, error)
 }
 
 type Notify struct {
@@ -35,40 +36,39 @@ type ValidatorInterface interface {
 
 func (handler Notify) Execute(connection models.ConnectionInterface, req *http.Request, context stack.Context,
 	guid string, strategy strategies.StrategyInterface, validator ValidatorInterface) ([]byte, error) {
-	parameters, err := params.NewNotify(req.Body)
+
+	clientID, options, err := handler.prepare(connection, req, context, validator)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	if !validator.Validate(&parameters) {
-		return []byte{}, params.ValidationError(parameters.Errors)
-	}
-
-	token := context.Get("token").(*jwt.Token)
-	clientID := token.Claims["client_id"].(string)
-
-	client, kind, err := handler.finder.ClientAndKind(clientID, parameters.KindID)
+	responses, err := strategy.Dispatch(clientID, guid, options, connection)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	if kind.Critical && !handler.hasCriticalNotificationsWriteScope(token.Claims["scope"]) {
-		return []byte{}, postal.NewCriticalNotificationError(kind.ID)
+	output, err := json.Marshal(responses)
+	if err != nil {
+		panic(err)
 	}
 
-	err = handler.registrar.Register(connection, client, []models.Kind{kind})
+	return output, nil
+}
+
+func (handler Notify) Launch(connection models.ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy strategies.CampaignStrategyInterface, validator ValidatorInterface) ([]byte, error) {
+
+	clientID, options, err := handler.prepare(connection, req, context, validator)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	var responses []strategies.Response
-
-	responses, err = strategy.Dispatch(clientID, guid, parameters.ToOptions(client, kind), connection)
+	response, err := strategy.Launch(clientID, guid, options, connection)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	output, err := json.Marshal(responses)
+	output, err := json.Marshal(response)
 	if err != nil {
 		panic(err)
 	}
@@ -76,6 +76,38 @@ func (handler Notify) Execute(connection models.ConnectionInterface, req *http.R
 	return output, nil
 }
 
+func (handler Notify) prepare(connection models.ConnectionInterface, req *http.Request, context stack.Context,
+	validator ValidatorInterface) (string, postal.Options, error) {
+
+	parameters, err := params.NewNotify(req.Body)
+	if err != nil {
+		return "", postal.Options{}, err
+	}
+
+	if !validator.Validate(&parameters) {
+		return "", postal.Options{}, params.ValidationError(parameters.Errors)
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	client, kind, err := handler.finder.ClientAndKind(clientID, parameters.KindID)
+	if err != nil {
+		return "", postal.Options{}, err
+	}
+
+	if kind.Critical && !handler.hasCriticalNotificationsWriteScope(token.Claims["scope"]) {
+		return "", postal.Options{}, postal.NewCriticalNotificationError(kind.ID)
+	}
+
+	err = handler.registrar.Register(connection, client, []models.Kind{kind})
+	if err != nil {
+		return "", postal.Options{}, err
+	}
+
+	return clientID, parameters.ToOptions(client, kind), nil
+}
+
 func (handler Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
 	for _, elem := range elements.([]interface{}) {
 		if elem.(string) == "critical_notifications.write" {
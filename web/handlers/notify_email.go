@@ -5,7 +5,6 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
-	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/ryanmoran/stack"
 )
 
@@ -13,14 +12,18 @@ type NotifyEmail struct {
 	errorWriter ErrorWriterInterface
 	notify      NotifyInterface
 	strategy    strategies.StrategyInterface
+	validator   ValidatorInterface
 	database    models.DatabaseInterface
 }
 
-func NewNotifyEmail(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.StrategyInterface, database models.DatabaseInterface) NotifyEmail {
+func NewNotifyEmail(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.StrategyInterface,
+	validator ValidatorInterface, database models.DatabaseInterface) NotifyEmail {
+
 	return NotifyEmail{
 		errorWriter: errorWriter,
 		notify:      notify,
 		strategy:    strategy,
+		validator:   validator,
 		database:    database,
 	}
 }
@@ -35,7 +38,7 @@ func (handler NotifyEmail) ServeHTTP(w http.ResponseWriter, req *http.Request, c
 }
 
 func (handler NotifyEmail) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface, context stack.Context) error {
-	output, err := handler.notify.Execute(connection, req, context, "", handler.strategy, params.EmailValidator{})
+	output, err := handler.notify.Execute(connection, req, context, "", handler.strategy, handler.validator)
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/fakes"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -28,7 +29,7 @@ var _ = Describe("NotifyEmail", func() {
 			database := fakes.NewDatabase()
 
 			notify = fakes.NewNotify()
-			handler = handlers.NewNotifyEmail(notify, errorWriter, nil, database)
+			handler = handlers.NewNotifyEmail(notify, errorWriter, nil, params.EmailValidator{BulkLimit: 50}, database)
 		})
 
 		Context("when notify.Execute returns a proper response", func() {
@@ -42,6 +43,12 @@ var _ = Describe("NotifyEmail", func() {
 				body := string(writer.Body.Bytes())
 				Expect(body).To(Equal("whut"))
 			})
+
+			It("validates the request with the email validator it was given", func() {
+				handler.Execute(writer, nil, nil, context)
+
+				Expect(notify.Validator).To(Equal(params.EmailValidator{BulkLimit: 50}))
+			})
 		})
 
 		Context("when notify.Execute errors", func() {
@@ -12,12 +12,12 @@ import (
 type NotifyEveryone struct {
 	errorWriter ErrorWriterInterface
 	notify      NotifyInterface
-	strategy    strategies.StrategyInterface
+	strategy    strategies.CampaignStrategyInterface
 	database    models.DatabaseInterface
 }
 
 func NewNotifyEveryone(notify NotifyInterface, errorWriter ErrorWriterInterface,
-	strategy strategies.StrategyInterface, database models.DatabaseInterface) NotifyEveryone {
+	strategy strategies.CampaignStrategyInterface, database models.DatabaseInterface) NotifyEveryone {
 	return NotifyEveryone{
 		errorWriter: errorWriter,
 		notify:      notify,
@@ -36,14 +36,14 @@ func (handler NotifyEveryone) ServeHTTP(w http.ResponseWriter, req *http.Request
 }
 
 func (handler NotifyEveryone) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface, context stack.Context,
-	strategy strategies.StrategyInterface) error {
+	strategy strategies.CampaignStrategyInterface) error {
 
-	output, err := handler.notify.Execute(connection, req, context, "", strategy, params.GUIDValidator{})
+	output, err := handler.notify.Launch(connection, req, context, "", strategy, params.GUIDValidator{})
 	if err != nil {
 		return err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 	return nil
 }
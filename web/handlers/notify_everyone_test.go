@@ -35,19 +35,19 @@ var _ = Describe("NotifyEveryone", func() {
 			handler = handlers.NewNotifyEveryone(notify, errorWriter, nil, fakeDatabase)
 		})
 
-		Context("when notify.Execute returns a successful response", func() {
+		Context("when notify.Launch returns a successful response", func() {
 			It("returns the JSON representation of the response", func() {
 				notify.Response = []byte("hello")
 
 				handler.Execute(writer, request, nil, nil, strategies.EveryoneStrategy{})
 
-				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Code).To(Equal(http.StatusAccepted))
 				body := string(writer.Body.Bytes())
 				Expect(body).To(Equal("hello"))
 			})
 		})
 
-		Context("when notify.Execute returns an error", func() {
+		Context("when notify.Launch returns an error", func() {
 			It("propagates the error", func() {
 				notify.Error = errors.New("BOOM!")
 
@@ -13,11 +13,11 @@ import (
 type NotifyOrganization struct {
 	errorWriter ErrorWriterInterface
 	notify      NotifyInterface
-	strategy    strategies.StrategyInterface
+	strategy    strategies.CampaignStrategyInterface
 	database    models.DatabaseInterface
 }
 
-func NewNotifyOrganization(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.StrategyInterface, database models.DatabaseInterface) NotifyOrganization {
+func NewNotifyOrganization(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.CampaignStrategyInterface, database models.DatabaseInterface) NotifyOrganization {
 	return NotifyOrganization{
 		errorWriter: errorWriter,
 		notify:      notify,
@@ -36,16 +36,16 @@ func (handler NotifyOrganization) ServeHTTP(w http.ResponseWriter, req *http.Req
 }
 
 func (handler NotifyOrganization) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface,
-	context stack.Context, strategy strategies.StrategyInterface) error {
+	context stack.Context, strategy strategies.CampaignStrategyInterface) error {
 
 	organizationGUID := strings.TrimPrefix(req.URL.Path, "/organizations/")
 
-	output, err := handler.notify.Execute(connection, req, context, organizationGUID, strategy, params.GUIDValidator{})
+	output, err := handler.notify.Launch(connection, req, context, organizationGUID, strategy, params.GUIDValidator{})
 	if err != nil {
 		return err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 
 	return nil
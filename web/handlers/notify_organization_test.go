@@ -34,14 +34,14 @@ var _ = Describe("NotifyOrganization", func() {
 			handler = handlers.NewNotifyOrganization(notify, nil, nil, fakeDatabase)
 		})
 
-		Context("when the notify.Execute returns a successful response", func() {
+		Context("when the notify.Launch returns a successful response", func() {
 			It("returns the JSON representation of the response", func() {
 				notify.Response = []byte("whatever")
 				strategy := strategies.OrganizationStrategy{}
 
 				handler.Execute(writer, request, nil, nil, strategy)
 
-				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Code).To(Equal(http.StatusAccepted))
 				Expect(notify.GUID).To(Equal("org-001"))
 
 				body := string(writer.Body.Bytes())
@@ -49,7 +49,7 @@ var _ = Describe("NotifyOrganization", func() {
 			})
 		})
 
-		Context("when the notify.Execute returns an error", func() {
+		Context("when the notify.Launch returns an error", func() {
 			It("propagates the error", func() {
 				notify.Error = errors.New("the error")
 				strategy := strategies.OrganizationStrategy{}
@@ -13,11 +13,11 @@ import (
 type NotifySpace struct {
 	errorWriter ErrorWriterInterface
 	notify      NotifyInterface
-	strategy    strategies.StrategyInterface
+	strategy    strategies.CampaignStrategyInterface
 	database    models.DatabaseInterface
 }
 
-func NewNotifySpace(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.StrategyInterface, database models.DatabaseInterface) NotifySpace {
+func NewNotifySpace(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.CampaignStrategyInterface, database models.DatabaseInterface) NotifySpace {
 	return NotifySpace{
 		errorWriter: errorWriter,
 		notify:      notify,
@@ -36,16 +36,16 @@ func (handler NotifySpace) ServeHTTP(w http.ResponseWriter, req *http.Request, c
 }
 
 func (handler NotifySpace) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface,
-	context stack.Context, strategy strategies.StrategyInterface) error {
+	context stack.Context, strategy strategies.CampaignStrategyInterface) error {
 
 	spaceGUID := strings.TrimPrefix(req.URL.Path, "/spaces/")
 
-	output, err := handler.notify.Execute(connection, req, context, spaceGUID, strategy, params.GUIDValidator{})
+	output, err := handler.notify.Launch(connection, req, context, spaceGUID, strategy, params.GUIDValidator{})
 	if err != nil {
 		return err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 
 	return nil
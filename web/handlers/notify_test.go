@@ -112,6 +112,7 @@ var _ = Describe("Notify", func() {
 						Text:              "This is the plain text body of the email",
 						HTML:              postal.HTML{BodyAttributes: "", BodyContent: "<p>This is the HTML Body of the email</p>"},
 						KindID:            "test_email",
+						Critical:          true,
 					},
 				}))
 			})
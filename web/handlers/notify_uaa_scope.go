@@ -13,11 +13,11 @@ import (
 type NotifyUAAScope struct {
 	errorWriter ErrorWriterInterface
 	notify      NotifyInterface
-	strategy    strategies.StrategyInterface
+	strategy    strategies.CampaignStrategyInterface
 	database    models.DatabaseInterface
 }
 
-func NewNotifyUAAScope(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.StrategyInterface, database models.DatabaseInterface) NotifyUAAScope {
+func NewNotifyUAAScope(notify NotifyInterface, errorWriter ErrorWriterInterface, strategy strategies.CampaignStrategyInterface, database models.DatabaseInterface) NotifyUAAScope {
 	return NotifyUAAScope{
 		errorWriter: errorWriter,
 		notify:      notify,
@@ -35,15 +35,15 @@ func (handler NotifyUAAScope) ServeHTTP(w http.ResponseWriter, req *http.Request
 	}
 }
 
-func (handler NotifyUAAScope) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface, context stack.Context, strategy strategies.StrategyInterface) error {
+func (handler NotifyUAAScope) Execute(w http.ResponseWriter, req *http.Request, connection models.ConnectionInterface, context stack.Context, strategy strategies.CampaignStrategyInterface) error {
 	scope := strings.TrimPrefix(req.URL.Path, "/uaa_scopes/")
 
-	output, err := handler.notify.Execute(connection, req, context, scope, strategy, params.GUIDValidator{})
+	output, err := handler.notify.Launch(connection, req, context, scope, strategy, params.GUIDValidator{})
 	if err != nil {
 		return err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 
 	return nil
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+)
+
+// parsePagination reads the page and per_page query parameters, returning a
+// params.ValidationError when either is out of range.
+func parsePagination(req *http.Request, defaultPerPage, maxPerPage int) (int, int, error) {
+	errors := params.ValidationError{}
+	query := req.URL.Query()
+
+	page := 1
+	if value := query.Get("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			errors = append(errors, `"page" must be a positive integer`)
+		}
+		page = parsed
+	}
+
+	perPage := defaultPerPage
+	if value := query.Get("per_page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPerPage {
+			errors = append(errors, `"per_page" must be an integer between 1 and `+strconv.Itoa(maxPerPage))
+		}
+		perPage = parsed
+	}
+
+	if len(errors) > 0 {
+		return 0, 0, errors
+	}
+
+	return page, perPage, nil
+}
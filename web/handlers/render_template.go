@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type RenderTemplate struct {
+	renderer    TemplateRendererInterface
+	errorWriter ErrorWriterInterface
+}
+
+type TemplateRendererInterface interface {
+	Render(string, postal.Delivery) (postal.RenderedTemplate, error)
+}
+
+func NewRenderTemplate(renderer TemplateRendererInterface, errorWriter ErrorWriterInterface) RenderTemplate {
+	return RenderTemplate{
+		renderer:    renderer,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler RenderTemplate) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := models.DefaultTemplateID
+	if strings.HasPrefix(req.URL.Path, "/templates/") {
+		templateID = strings.TrimSuffix(strings.Split(req.URL.Path, "/templates/")[1], "/render")
+	}
+
+	render, err := params.NewRender(req.Body)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	delivery := render.ToDelivery(clientID)
+	delivery.Options.Endorsement = endorsementFor(render)
+
+	rendered, err := handler.renderer.Render(templateID, delivery)
+	if err != nil {
+		if templateErr, ok := err.(postal.TemplateError); ok {
+			writeTemplateError(w, templateErr)
+			return
+		}
+
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rendered)
+}
+
+// writeTemplateError responds with where a template failed to compile, along
+// with the usual list of errors.
+func writeTemplateError(w http.ResponseWriter, err postal.TemplateError) {
+	writeJSON(w, 422, map[string]interface{}{
+		"errors":         []string{err.Error()},
+		"template_error": err,
+	})
+}
+
+// endorsementFor picks the endorsement of the strategy that would have sent
+// the sample notification, judging by the context it is rendered with.
+func endorsementFor(render params.Render) string {
+	switch {
+	case render.Scope != "":
+		return strategies.ScopeEndorsement
+	case render.Space.GUID != "" || render.Space.Name != "":
+		return strategies.SpaceEndorsement
+	case render.Notify.Role != "":
+		return strategies.OrganizationRoleEndorsement
+	case render.Organization.GUID != "" || render.Organization.Name != "":
+		return strategies.OrganizationEndorsement
+	case render.User.GUID != "":
+		return strategies.UserEndorsement
+	default:
+		return strategies.EmailEndorsement
+	}
+}
@@ -0,0 +1,136 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RenderTemplate", func() {
+	var handler handlers.RenderTemplate
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var renderer *fakes.TemplateRenderer
+	var context stack.Context
+
+	newRequest := func(path, body string) *http.Request {
+		request, err := http.NewRequest("POST", path, strings.NewReader(body))
+		if err != nil {
+			panic(err)
+		}
+		return request
+	}
+
+	BeforeEach(func() {
+		errorWriter = fakes.NewErrorWriter()
+		renderer = fakes.NewTemplateRenderer()
+		renderer.Rendered = postal.RenderedTemplate{
+			Subject: "the subject",
+			Text:    "the text",
+			HTML:    "<p>the html</p>",
+		}
+		handler = handlers.NewRenderTemplate(renderer, errorWriter)
+		writer = httptest.NewRecorder()
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
+	})
+
+	It("renders the template with the sample notification", func() {
+		request := newRequest("/templates/template-id/render", `{
+			"notification": {"kind_id": "kind-id", "text": "hello"},
+			"user": {"guid": "user-123", "email": "user@example.com"}
+		}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(renderer.RenderTemplateID).To(Equal("template-id"))
+		Expect(renderer.RenderDelivery.ClientID).To(Equal("my-client"))
+		Expect(renderer.RenderDelivery.UserGUID).To(Equal("user-123"))
+		Expect(renderer.RenderDelivery.Options.Text).To(Equal("hello"))
+		Expect(renderer.RenderDelivery.Options.Endorsement).To(Equal(strategies.UserEndorsement))
+
+		var body map[string]string
+		err := json.Unmarshal(writer.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body).To(Equal(map[string]string{
+			"subject": "the subject",
+			"text":    "the text",
+			"html":    "<p>the html</p>",
+		}))
+	})
+
+	It("renders the default template", func() {
+		request := newRequest("/default_template/render", `{"notification": {"text": "hello"}}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(renderer.RenderTemplateID).To(Equal(models.DefaultTemplateID))
+		Expect(renderer.RenderDelivery.Options.Endorsement).To(Equal(strategies.EmailEndorsement))
+	})
+
+	It("uses the endorsement of the space when a space is given", func() {
+		request := newRequest("/templates/template-id/render", `{
+			"notification": {"text": "hello"},
+			"user": {"guid": "user-123"},
+			"space": {"guid": "space-001", "name": "development"},
+			"organization": {"guid": "org-001", "name": "banana"}
+		}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(renderer.RenderDelivery.Options.Endorsement).To(Equal(strategies.SpaceEndorsement))
+	})
+
+	It("returns where the template failed to compile", func() {
+		renderer.RenderError = postal.TemplateError{Part: "html", Line: 3, Column: 7, Message: "bad field"}
+		request := newRequest("/templates/template-id/render", `{"notification": {"text": "hello"}}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(422))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"errors": ["template html:3:7: bad field"],
+			"template_error": {"part": "html", "line": 3, "column": 7, "message": "bad field"}
+		}`))
+	})
+
+	It("writes invalid requests to the error writer", func() {
+		request := newRequest("/templates/template-id/render", `{"notification": {}}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+	})
+
+	It("writes other errors to the error writer", func() {
+		renderer.RenderError = models.NewRecordNotFoundError("Template %q could not be found", "template-id")
+		request := newRequest("/templates/template-id/render", `{"notification": {"text": "hello"}}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(renderer.RenderError))
+	})
+})
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type RequeueDeadLetter struct {
+	requeuer    services.DeadLetterRequeuerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewRequeueDeadLetter(requeuer services.DeadLetterRequeuerInterface, errorWriter ErrorWriterInterface) RequeueDeadLetter {
+	return RequeueDeadLetter{
+		requeuer:    requeuer,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler RequeueDeadLetter) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadLetterID := strings.TrimSuffix(strings.Split(req.URL.Path, "/dead_letters/")[1], "/requeue")
+
+	err := handler.requeuer.Requeue(deadLetterID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
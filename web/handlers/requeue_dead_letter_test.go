@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequeueDeadLetter", func() {
+	var handler handlers.RequeueDeadLetter
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var requeuer *fakes.DeadLetterRequeuer
+
+	BeforeEach(func() {
+		var err error
+
+		requeuer = fakes.NewDeadLetterRequeuer()
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewRequeueDeadLetter(requeuer, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("POST", "/dead_letters/7/requeue", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("requeues the dead letter", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(requeuer.RequeueArgument).To(Equal("7"))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("writes errors to the error writer", func() {
+		requeuer.RequeueError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(requeuer.RequeueError))
+	})
+})
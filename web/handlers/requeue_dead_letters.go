@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type RequeueDeadLetters struct {
+	requeuer    services.DeadLetterRequeuerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewRequeueDeadLetters(requeuer services.DeadLetterRequeuerInterface, errorWriter ErrorWriterInterface) RequeueDeadLetters {
+	return RequeueDeadLetters{
+		requeuer:    requeuer,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler RequeueDeadLetters) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	count, err := handler.requeuer.RequeueAll()
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"requeued": count,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequeueDeadLetters", func() {
+	var handler handlers.RequeueDeadLetters
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+	var context stack.Context
+	var requeuer *fakes.DeadLetterRequeuer
+
+	BeforeEach(func() {
+		var err error
+
+		requeuer = fakes.NewDeadLetterRequeuer()
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewRequeueDeadLetters(requeuer, errorWriter)
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("POST", "/dead_letters/requeue", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("requeues every dead letter and reports how many were requeued", func() {
+		requeuer.RequeueAllCount = 3
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(requeuer.RequeueAllWasCalled).To(BeTrue())
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"requeued": 3}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		requeuer.RequeueAllError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.Error).To(Equal(requeuer.RequeueAllError))
+	})
+})
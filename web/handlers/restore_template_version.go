@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type RestoreTemplateVersion struct {
+	restorer    services.TemplateRestorerInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewRestoreTemplateVersion(restorer services.TemplateRestorerInterface, errorWriter ErrorWriterInterface) RestoreTemplateVersion {
+	return RestoreTemplateVersion{
+		restorer:    restorer,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler RestoreTemplateVersion) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, number, err := templateVersionFromPath(req.URL.Path)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	restored, err := handler.restorer.Restore(templateID, number, clientID)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := NewTemplateVersionOutput(restored)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
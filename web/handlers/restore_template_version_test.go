@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RestoreTemplateVersion", func() {
+	var handler handlers.RestoreTemplateVersion
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var restorer *fakes.TemplateRestorer
+	var context stack.Context
+
+	newRequest := func(path string) *http.Request {
+		request, err := http.NewRequest("POST", path, nil)
+		if err != nil {
+			panic(err)
+		}
+		return request
+	}
+
+	BeforeEach(func() {
+		errorWriter = fakes.NewErrorWriter()
+		restorer = fakes.NewTemplateRestorer()
+		handler = handlers.NewRestoreTemplateVersion(restorer, errorWriter)
+		writer = httptest.NewRecorder()
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
+	})
+
+	It("restores the version on behalf of the client and returns the new version", func() {
+		restorer.Restored = models.TemplateVersion{
+			TemplateID: "template-id",
+			Version:    5,
+			Name:       "Good Template",
+			HTML:       "<p>good</p>",
+			Metadata:   "{}",
+			Author:     "my-client",
+		}
+
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/2/restore"), context)
+
+		Expect(restorer.RestoreTemplateID).To(Equal("template-id"))
+		Expect(restorer.RestoreVersion).To(Equal(2))
+		Expect(restorer.RestoreAuthor).To(Equal("my-client"))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		var body map[string]interface{}
+		err := json.Unmarshal(writer.Body.Bytes(), &body)
+		if err != nil {
+			panic(err)
+		}
+
+		Expect(body["version"]).To(Equal(float64(5)))
+		Expect(body["name"]).To(Equal("Good Template"))
+		Expect(body["author"]).To(Equal("my-client"))
+	})
+
+	It("does not find versions numbered below 1", func() {
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/0/restore"), context)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		Expect(restorer.RestoreTemplateID).To(BeEmpty())
+	})
+
+	It("writes errors to the error writer", func() {
+		restorer.RestoreError = errors.New("Boom!")
+
+		handler.ServeHTTP(writer, newRequest("/templates/template-id/versions/2/restore"), context)
+
+		Expect(errorWriter.Error).To(Equal(restorer.RestoreError))
+	})
+})
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+type TemplateVersionSummary struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TemplateVersionOutput struct {
+	Version   int                    `json:"version"`
+	Name      string                 `json:"name"`
+	Subject   string                 `json:"subject"`
+	Text      string                 `json:"text"`
+	HTML      string                 `json:"html"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	Author    string                 `json:"author"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func NewTemplateVersionOutput(version models.TemplateVersion) (TemplateVersionOutput, error) {
+	var metadata map[string]interface{}
+	err := json.Unmarshal([]byte(version.Metadata), &metadata)
+	if err != nil {
+		return TemplateVersionOutput{}, err
+	}
+
+	return TemplateVersionOutput{
+		Version:   version.Version,
+		Name:      version.Name,
+		Subject:   version.Subject,
+		Text:      version.Text,
+		HTML:      version.HTML,
+		Metadata:  metadata,
+		Author:    version.Author,
+		CreatedAt: version.CreatedAt,
+	}, nil
+}
+
+// templateVersionFromPath reads the template ID and version number from paths
+// like /templates/{template_id}/versions/{version}.
+func templateVersionFromPath(path string) (string, int, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/templates/"), "/")
+	templateID := parts[0]
+
+	number, err := strconv.Atoi(parts[2])
+	if err != nil || number < 1 {
+		return templateID, 0, models.NewRecordNotFoundError("Version %q of template %q could not be found", parts[2], templateID)
+	}
+
+	return templateID, number, nil
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type TestSendTemplate struct {
+	sender      TestSenderInterface
+	errorWriter ErrorWriterInterface
+}
+
+type TestSenderInterface interface {
+	Send(string, postal.Delivery) error
+}
+
+func NewTestSendTemplate(sender TestSenderInterface, errorWriter ErrorWriterInterface) TestSendTemplate {
+	return TestSendTemplate{
+		sender:      sender,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler TestSendTemplate) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := strings.TrimSuffix(strings.Split(req.URL.Path, "/templates/")[1], "/test_send")
+
+	testSend, err := params.NewTestSend(req.Body)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	delivery := testSend.ToDelivery(clientID)
+	delivery.Options.Endorsement = endorsementFor(testSend.Render)
+
+	err = handler.sender.Send(templateID, delivery)
+	if err != nil {
+		if templateErr, ok := err.(postal.TemplateError); ok {
+			writeTemplateError(w, templateErr)
+			return
+		}
+
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
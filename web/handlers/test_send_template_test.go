@@ -0,0 +1,92 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TestSendTemplate", func() {
+	var handler handlers.TestSendTemplate
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var sender *fakes.TestSender
+	var context stack.Context
+
+	newRequest := func(body string) *http.Request {
+		request, err := http.NewRequest("POST", "/templates/template-id/test_send", strings.NewReader(body))
+		if err != nil {
+			panic(err)
+		}
+		return request
+	}
+
+	BeforeEach(func() {
+		errorWriter = fakes.NewErrorWriter()
+		sender = fakes.NewTestSender()
+		handler = handlers.NewTestSendTemplate(sender, errorWriter)
+		writer = httptest.NewRecorder()
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
+	})
+
+	It("mails the template to the given address", func() {
+		request := newRequest(`{
+			"to": "designer@example.com",
+			"notification": {"kind_id": "kind-id", "text": "hello"},
+			"organization": {"guid": "org-001", "name": "banana"}
+		}`)
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(sender.SendTemplateID).To(Equal("template-id"))
+		Expect(sender.SendDelivery.Email).To(Equal("designer@example.com"))
+		Expect(sender.SendDelivery.ClientID).To(Equal("my-client"))
+		Expect(sender.SendDelivery.Options.Text).To(Equal("hello"))
+		Expect(sender.SendDelivery.Options.Endorsement).To(Equal(strategies.OrganizationEndorsement))
+	})
+
+	It("returns where the template failed to compile", func() {
+		sender.SendError = postal.TemplateError{Part: "subject", Line: 1, Column: 2, Message: "bad field"}
+
+		handler.ServeHTTP(writer, newRequest(`{"to": "designer@example.com", "notification": {"text": "hello"}}`), context)
+
+		Expect(writer.Code).To(Equal(422))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"errors": ["template subject:1:2: bad field"],
+			"template_error": {"part": "subject", "line": 1, "column": 2, "message": "bad field"}
+		}`))
+	})
+
+	It("writes invalid requests to the error writer", func() {
+		handler.ServeHTTP(writer, newRequest(`{"notification": {"text": "hello"}}`), context)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+		Expect(sender.SendTemplateID).To(BeEmpty())
+	})
+
+	It("writes other errors to the error writer", func() {
+		sender.SendError = postal.TestSendError("The test email could not be sent: connection refused")
+
+		handler.ServeHTTP(writer, newRequest(`{"to": "designer@example.com", "notification": {"text": "hello"}}`), context)
+
+		Expect(errorWriter.Error).To(Equal(sender.SendError))
+	})
+})
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<title>{{.Title}}</title>
+	</head>
+	<body>
+		<h1>{{.Title}}</h1>
+		<p>{{.Message}}</p>
+		{{if .Confirm}}<form method="post">
+			<button type="submit">Unsubscribe</button>
+		</form>{{end}}
+	</body>
+</html>
+`))
+
+type unsubscribePageData struct {
+	Title   string
+	Message string
+	Confirm bool
+}
+
+// Unsubscribe consumes the unsubscribe IDs that ConfirmUnsubscribe posts back,
+// and that mail clients post to in one click. It is not authenticated:
+// possession of the unsubscribe ID is the proof that the request comes from
+// the recipient.
+type Unsubscribe struct {
+	unsubscriber services.UnsubscriberInterface
+	errorWriter  ErrorWriterInterface
+	database     models.DatabaseInterface
+}
+
+func NewUnsubscribe(unsubscriber services.UnsubscriberInterface, errorWriter ErrorWriterInterface, database models.DatabaseInterface) Unsubscribe {
+	return Unsubscribe{
+		unsubscriber: unsubscriber,
+		errorWriter:  errorWriter,
+		database:     database,
+	}
+}
+
+func (handler Unsubscribe) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.Split(req.URL.Path, "/unsubscribe/")[1]
+
+	transaction := handler.database.Connection().Transaction()
+	transaction.Begin()
+
+	unsubscription, err := handler.unsubscriber.Unsubscribe(transaction, unsubscribeID)
+	if err != nil {
+		transaction.Rollback()
+
+		writeUnsubscribeError(w, handler.errorWriter, err)
+		return
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		handler.errorWriter.Write(w, models.NewTransactionCommitError(err.Error()))
+		return
+	}
+
+	renderUnsubscribePage(w, http.StatusOK, unsubscribePageData{
+		Title:   "Unsubscribed",
+		Message: "You will no longer receive \"" + unsubscription.KindDescription + "\" notifications by email.",
+	})
+}
+
+func writeUnsubscribeError(w http.ResponseWriter, errorWriter ErrorWriterInterface, err error) {
+	switch err.(type) {
+	case services.InvalidUnsubscribeIDError, services.MissingKindOrClientError:
+		renderUnsubscribePage(w, http.StatusNotFound, unsubscribePageData{
+			Title:   "Unsubscribe failed",
+			Message: "This unsubscribe link is not valid.",
+		})
+	case services.CriticalKindError:
+		renderUnsubscribePage(w, 422, unsubscribePageData{
+			Title:   "Unsubscribe failed",
+			Message: "These notifications are critical and cannot be unsubscribed from.",
+		})
+	default:
+		errorWriter.Write(w, err)
+	}
+}
+
+func renderUnsubscribePage(w http.ResponseWriter, status int, data unsubscribePageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := unsubscribePage.Execute(w, data)
+	if err != nil {
+		panic(err)
+	}
+}
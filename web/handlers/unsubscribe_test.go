@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unsubscribe", func() {
+	var handler handlers.Unsubscribe
+	var unsubscriber *fakes.Unsubscriber
+	var errorWriter *fakes.ErrorWriter
+	var database *fakes.Database
+	var writer *httptest.ResponseRecorder
+	var request *http.Request
+
+	BeforeEach(func() {
+		var err error
+
+		unsubscriber = fakes.NewUnsubscriber()
+		unsubscriber.Unsubscription = services.Unsubscription{
+			UserID:          "user-123",
+			ClientID:        "some-client",
+			KindID:          "some-kind",
+			KindDescription: "Daily <digest>",
+		}
+		errorWriter = fakes.NewErrorWriter()
+		database = fakes.NewDatabase()
+		handler = handlers.NewUnsubscribe(unsubscriber, errorWriter, database)
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("POST", "/unsubscribe/the-unsubscribe-id", nil)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	It("unsubscribes using the ID in the path", func() {
+		handler.ServeHTTP(writer, request, nil)
+
+		Expect(unsubscriber.UnsubscribeArguments).To(Equal([]string{"the-unsubscribe-id"}))
+		Expect(database.Conn.CommitWasCalled).To(BeTrue())
+	})
+
+	It("renders a page confirming the unsubscription", func() {
+		handler.ServeHTTP(writer, request, nil)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(writer.Body.String()).To(ContainSubstring("<h1>Unsubscribed</h1>"))
+		Expect(writer.Body.String()).To(ContainSubstring("Daily &lt;digest&gt;"))
+		Expect(writer.Body.String()).NotTo(ContainSubstring("<form"))
+	})
+
+	Context("when the unsubscribe ID is not valid", func() {
+		It("renders a not found page", func() {
+			unsubscriber.UnsubscribeError = services.InvalidUnsubscribeIDError("invalid")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(ContainSubstring("This unsubscribe link is not valid."))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		})
+	})
+
+	Context("when the kind no longer exists", func() {
+		It("renders a not found page", func() {
+			unsubscriber.UnsubscribeError = services.MissingKindOrClientError("missing")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the kind is critical", func() {
+		It("renders a page explaining that it cannot be unsubscribed from", func() {
+			unsubscriber.UnsubscribeError = services.CriticalKindError("critical")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(ContainSubstring("cannot be unsubscribed from"))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		})
+	})
+
+	Context("when the unsubscriber fails unexpectedly", func() {
+		It("delegates to the error writer", func() {
+			unsubscriber.UnsubscribeError = errors.New("database is gone")
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(errorWriter.Error).To(Equal(unsubscriber.UnsubscribeError))
+		})
+	})
+
+	Context("when the transaction cannot be committed", func() {
+		It("delegates to the error writer", func() {
+			database.Conn.CommitError = "commit failed"
+
+			handler.ServeHTTP(writer, request, nil)
+
+			Expect(errorWriter.Error).To(MatchError("commit failed"))
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -28,7 +29,10 @@ func (handler UpdateDefaultTemplate) ServeHTTP(w http.ResponseWriter, req *http.
 		return
 	}
 
-	err = handler.updater.Update(models.DefaultTemplateID, template.ToModel())
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	err = handler.updater.Update(models.DefaultTemplateID, template.ToModel(), clientID)
 	if err != nil {
 		handler.errorWriter.Write(w, err)
 	}
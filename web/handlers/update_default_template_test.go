@@ -10,6 +10,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -30,6 +31,13 @@ var _ = Describe("UpdateDefaultTemplate", func() {
 		errorWriter = fakes.NewErrorWriter()
 		handler = handlers.NewUpdateDefaultTemplate(updater, errorWriter)
 		writer = httptest.NewRecorder()
+
+		context = stack.NewContext()
+		context.Set("token", &jwt.Token{
+			Claims: map[string]interface{}{
+				"client_id": "my-client",
+			},
+		})
 		request, err = http.NewRequest("PUT", "/default_template", strings.NewReader(`{
 			"name": "Defaultish Template",
 			"subject": "{{.Subject}}",
@@ -53,6 +61,7 @@ var _ = Describe("UpdateDefaultTemplate", func() {
 			Text:     "something",
 			Metadata: `{"hello": true}`,
 		}))
+		Expect(updater.UpdateAuthor).To(Equal("my-client"))
 		Expect(writer.Code).To(Equal(http.StatusNoContent))
 	})
 
@@ -54,14 +54,23 @@ func (handler UpdatePreferences) Execute(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	deliveryAddress, setDeliveryAddress, err := builder.ToDeliveryAddress(userID)
+	if err != nil {
+		handler.errorWriter.Write(w, params.ValidationError([]string{err.Error()}))
+		return
+	}
+
 	transaction := connection.Transaction()
 	transaction.Begin()
 	err = handler.preferenceUpdater.Execute(transaction, preferences, builder.GlobalUnsubscribe, userID)
+	if err == nil && setDeliveryAddress {
+		err = handler.preferenceUpdater.SetDeliveryAddress(transaction, deliveryAddress)
+	}
 	if err != nil {
 		transaction.Rollback()
 
 		switch err.(type) {
-		case services.MissingKindOrClientError, services.CriticalKindError:
+		case services.MissingKindOrClientError, services.CriticalKindError, services.InvalidDeliveryAddressError:
 			handler.errorWriter.Write(w, params.ValidationError([]string{err.Error()}))
 		default:
 			handler.errorWriter.Write(w, err)
@@ -118,6 +118,52 @@ var _ = Describe("UpdatePreferences", func() {
 			Expect(writer.Code).To(Equal(http.StatusNoContent))
 		})
 
+		Context("when the delivery address is given", func() {
+			newRequest := func(body string) *http.Request {
+				req, err := http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(body))
+				if err != nil {
+					panic(err)
+				}
+
+				return req
+			}
+
+			It("sets the delivery address of the user", func() {
+				handler.Execute(writer, newRequest(`{"delivery_address": {"mode": "specific", "email": "work@example.com"}}`), conn, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNoContent))
+				Expect(updater.DeliveryAddress).To(Equal(models.DeliveryAddress{
+					UserID: "correct-user",
+					Mode:   models.DeliveryAddressSpecific,
+					Email:  "work@example.com",
+				}))
+				Expect(conn.CommitWasCalled).To(BeTrue())
+			})
+
+			It("leaves the delivery address alone when it is not given", func() {
+				handler.Execute(writer, request, conn, context)
+
+				Expect(updater.DeliveryAddress).To(Equal(models.DeliveryAddress{}))
+			})
+
+			It("writes a params.ValidationError for an invalid delivery address", func() {
+				handler.Execute(writer, newRequest(`{"delivery_address": {"mode": "specific"}}`), conn, context)
+
+				Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+				Expect(conn.BeginWasCalled).To(BeFalse())
+			})
+
+			It("writes a params.ValidationError when the address is not one of the user's", func() {
+				updater.SetDeliveryAddressError = services.InvalidDeliveryAddressError("BOOM!")
+
+				handler.Execute(writer, newRequest(`{"delivery_address": {"mode": "specific", "email": "someone-else@example.com"}}`), conn, context)
+
+				Expect(errorWriter.Error).To(Equal(params.ValidationError([]string{"BOOM!"})))
+				Expect(conn.RollbackWasCalled).To(BeTrue())
+				Expect(conn.CommitWasCalled).To(BeFalse())
+			})
+		})
+
 		Context("Failure cases", func() {
 			It("returns an error when the clients key is missing", func() {
 				jsonBody := `{"raptor-client": {"containment-unit-breach": {"email": false}}}`
@@ -47,14 +47,23 @@ func (handler UpdateSpecificUserPreferences) Execute(w http.ResponseWriter, req
 		return
 	}
 
+	deliveryAddress, setDeliveryAddress, err := builder.ToDeliveryAddress(userGUID)
+	if err != nil {
+		handler.errorWriter.Write(w, params.ValidationError([]string{err.Error()}))
+		return
+	}
+
 	transaction := conn.Transaction()
 	transaction.Begin()
 	err = handler.preferenceUpdater.Execute(transaction, preferences, builder.GlobalUnsubscribe, userGUID)
+	if err == nil && setDeliveryAddress {
+		err = handler.preferenceUpdater.SetDeliveryAddress(transaction, deliveryAddress)
+	}
 	if err != nil {
 		transaction.Rollback()
 
 		switch err.(type) {
-		case services.MissingKindOrClientError, services.CriticalKindError:
+		case services.MissingKindOrClientError, services.CriticalKindError, services.InvalidDeliveryAddressError:
 			handler.errorWriter.Write(w, params.ValidationError([]string{err.Error()}))
 		default:
 			handler.errorWriter.Write(w, err)
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/ryanmoran/stack"
+)
+
+type UpdateSuppression struct {
+	updater     services.SuppressionUpdaterInterface
+	errorWriter ErrorWriterInterface
+}
+
+func NewUpdateSuppression(updater services.SuppressionUpdaterInterface, errorWriter ErrorWriterInterface) UpdateSuppression {
+	return UpdateSuppression{
+		updater:     updater,
+		errorWriter: errorWriter,
+	}
+}
+
+func (handler UpdateSuppression) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	email := strings.Split(req.URL.Path, "/suppressions/")[1]
+
+	suppressionParams, err := params.NewSuppressionParams(email, req.Body)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	suppression, err := handler.updater.Suppress(suppressionParams.Email, suppressionParams.Reason)
+	if err != nil {
+		handler.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewSuppressionDocument(suppression))
+}
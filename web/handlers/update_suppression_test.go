@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/handlers"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateSuppression", func() {
+	var handler handlers.UpdateSuppression
+	var errorWriter *fakes.ErrorWriter
+	var writer *httptest.ResponseRecorder
+	var context stack.Context
+	var updater *fakes.SuppressionUpdater
+
+	newRequest := func(path, body string) *http.Request {
+		request, err := http.NewRequest("PUT", path, bytes.NewBufferString(body))
+		if err != nil {
+			panic(err)
+		}
+
+		return request
+	}
+
+	BeforeEach(func() {
+		updater = fakes.NewSuppressionUpdater()
+		updater.Suppression = models.Suppression{
+			Email:     "user@example.com",
+			Reason:    "legal request",
+			CreatedAt: time.Date(2014, time.October, 1, 12, 0, 0, 0, time.UTC),
+		}
+		errorWriter = fakes.NewErrorWriter()
+		handler = handlers.NewUpdateSuppression(updater, errorWriter)
+		writer = httptest.NewRecorder()
+	})
+
+	It("suppresses the address for the given reason", func() {
+		handler.ServeHTTP(writer, newRequest("/suppressions/user@example.com", `{"reason":"legal request"}`), context)
+
+		Expect(updater.SuppressArguments).To(Equal([]string{"user@example.com", "legal request"}))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"email": "user@example.com",
+			"reason": "legal request",
+			"created_at": "2014-10-01T12:00:00Z"
+		}`))
+	})
+
+	It("writes validation errors to the error writer", func() {
+		handler.ServeHTTP(writer, newRequest("/suppressions/user@example.com", `{}`), context)
+
+		Expect(errorWriter.Error).To(BeAssignableToTypeOf(params.ValidationError{}))
+		Expect(updater.SuppressArguments).To(BeNil())
+	})
+
+	It("writes errors from the updater to the error writer", func() {
+		updater.SuppressError = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, newRequest("/suppressions/user@example.com", `{"reason":"legal request"}`), context)
+
+		Expect(errorWriter.Error).To(Equal(updater.SuppressError))
+	})
+})
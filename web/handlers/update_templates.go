@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -30,7 +31,10 @@ func (handler UpdateTemplates) ServeHTTP(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	err = handler.updater.Update(templateID, templateParams.ToModel())
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	err = handler.updater.Update(templateID, templateParams.ToModel(), clientID)
 	if err != nil {
 		handler.ErrorWriter.Write(w, err)
 		return
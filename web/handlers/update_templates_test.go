@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/params"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -30,6 +31,13 @@ var _ = Describe("UpdateTemplates", func() {
 			errorWriter = fakes.NewErrorWriter()
 			handler = handlers.NewUpdateTemplates(updater, errorWriter)
 			writer = httptest.NewRecorder()
+
+			context = stack.NewContext()
+			context.Set("token", &jwt.Token{
+				Claims: map[string]interface{}{
+					"client_id": "my-client",
+				},
+			})
 			body := []byte(`{"name":"An Interesting Template", "subject":"very interesting subject", "text":"Here's the msg {{.Text}}", "html":"<p>turkey gobble</p>"}`)
 			request, err = http.NewRequest("PUT", "/templates/a-template-id", bytes.NewBuffer(body))
 			if err != nil {
@@ -48,6 +56,7 @@ var _ = Describe("UpdateTemplates", func() {
 				HTML:     "<p>turkey gobble</p>",
 				Metadata: "{}",
 			}))
+			Expect(updater.UpdateAuthor).To(Equal("my-client"))
 		})
 
 		It("can update a template without a subject field", func() {
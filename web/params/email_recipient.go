@@ -0,0 +1,20 @@
+package params
+
+import "encoding/json"
+
+// EmailRecipient is one of the recipients of a bulk send to /emails. It is
+// given either as a bare address or as an object that also carries data for
+// the templates of that recipient's message.
+type EmailRecipient struct {
+	Email string                 `json:"email"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+func (recipient *EmailRecipient) UnmarshalJSON(body []byte) error {
+	if len(body) > 0 && body[0] == '"' {
+		return json.Unmarshal(body, &recipient.Email)
+	}
+
+	type emailRecipient EmailRecipient
+	return json.Unmarshal(body, (*emailRecipient)(recipient))
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/cloudfoundry-incubator/notifications/models"
@@ -26,8 +27,15 @@ type Notify struct {
 	KindDescription   string
 	SourceDescription string
 	Errors            []string
-	To                string `json:"to"`
-	Role              string `json:"role"`
+	To                string                 `json:"-"`
+	Recipients        []EmailRecipient       `json:"-"`
+	CC                []string               `json:"cc"`
+	BCC               []string               `json:"bcc"`
+	Role              string                 `json:"role"`
+	SendAt            string                 `json:"send_at"`
+	Priority          string                 `json:"priority"`
+	Data              map[string]interface{} `json:"data"`
+	ParsedSendAt      time.Time
 }
 
 func NewNotify(body io.Reader) (Notify, error) {
@@ -39,6 +47,7 @@ func NewNotify(body io.Reader) (Notify, error) {
 	}
 
 	notify.formatEmail()
+	notify.parseSendAt()
 
 	err = notify.extractHTML()
 	if err != nil {
@@ -49,21 +58,47 @@ func NewNotify(body io.Reader) (Notify, error) {
 }
 
 func (notify *Notify) formatEmail() {
-	if notify.To == "" {
-		return
+	if notify.To != "" {
+		notify.To = formatAddress(notify.To)
+	}
+
+	for i, recipient := range notify.Recipients {
+		notify.Recipients[i].Email = formatAddress(recipient.Email)
+	}
+
+	for i, address := range notify.CC {
+		notify.CC[i] = formatAddress(address)
 	}
+
+	for i, address := range notify.BCC {
+		notify.BCC[i] = formatAddress(address)
+	}
+}
+
+func formatAddress(address string) string {
 	regex := regexp.MustCompile("[^<]*<([^@]*@[^@]*)>|([^<][^@]*@[^@]*)")
-	email := regex.FindStringSubmatch(notify.To)
+	email := regex.FindStringSubmatch(address)
 	if len(email) == 0 {
-		notify.To = InvalidEmail
-		return
+		return InvalidEmail
 	}
 
 	if email[1] != "" {
-		notify.To = email[1]
-	} else {
-		notify.To = email[2]
+		return email[1]
 	}
+	return email[2]
+}
+
+func (notify *Notify) parseSendAt() {
+	if notify.SendAt == "" {
+		return
+	}
+
+	sendAt, err := time.Parse(time.RFC3339, notify.SendAt)
+	if err != nil {
+		return
+	}
+
+	notify.ParsedSendAt = sendAt
 }
 
 func (notify *Notify) parseRequestBody(body io.Reader) error {
@@ -74,11 +109,49 @@ func (notify *Notify) parseRequestBody(body io.Reader) error {
 		if err != nil {
 			return ParseError{}
 		}
+
+		err = notify.parseTo(buffer.Bytes())
+		if err != nil {
+			return ParseError{}
+		}
 	}
 	return nil
 }
 
+// parseTo reads the "to" field, which is either a single address or a list of
+// recipients for a bulk send.
+func (notify *Notify) parseTo(body []byte) error {
+	var document struct {
+		To json.RawMessage `json:"to"`
+	}
+
+	err := json.Unmarshal(body, &document)
+	if err != nil {
+		return err
+	}
+
+	to := bytes.TrimSpace(document.To)
+	if len(to) == 0 || bytes.Equal(to, []byte("null")) {
+		return nil
+	}
+
+	if to[0] == '[' {
+		notify.Recipients = []EmailRecipient{}
+		return json.Unmarshal(to, &notify.Recipients)
+	}
+
+	return json.Unmarshal(to, &notify.To)
+}
+
 func (notify *Notify) ToOptions(client models.Client, kind models.Kind) postal.Options {
+	var recipients []postal.EmailRecipient
+	for _, recipient := range notify.Recipients {
+		recipients = append(recipients, postal.EmailRecipient{
+			Email: recipient.Email,
+			Data:  recipient.Data,
+		})
+	}
+
 	return postal.Options{
 		ReplyTo:           notify.ReplyTo,
 		Subject:           notify.Subject,
@@ -88,7 +161,14 @@ func (notify *Notify) ToOptions(client models.Client, kind models.Kind) postal.O
 		HTML:              notify.ParsedHTML,
 		KindID:            notify.KindID,
 		To:                notify.To,
+		Recipients:        recipients,
+		CC:                notify.CC,
+		BCC:               notify.BCC,
 		Role:              notify.Role,
+		SendAt:            notify.ParsedSendAt,
+		Priority:          notify.Priority,
+		Critical:          kind.Critical,
+		Data:              notify.Data,
 	}
 }
 
@@ -3,6 +3,7 @@ package params_test
 import (
 	"io"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/postal"
@@ -97,6 +98,117 @@ var _ = Describe("Notify", func() {
 			})
 		})
 
+		Describe("data field parsing", func() {
+			It("reads the data for the templates", func() {
+				body := strings.NewReader(`{
+                    "data": {"invoice_total": "$12.50", "items": [{"name": "apples"}]}
+                }`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.Data).To(Equal(map[string]interface{}{
+					"invoice_total": "$12.50",
+					"items":         []interface{}{map[string]interface{}{"name": "apples"}},
+				}))
+			})
+
+			It("returns a ParseError when the data is not an object", func() {
+				body := strings.NewReader(`{"data": ["apples"]}`)
+
+				_, err := params.NewNotify(body)
+				Expect(err).To(BeAssignableToTypeOf(params.ParseError{}))
+			})
+		})
+
+		Describe("bulk to field parsing", func() {
+			It("reads a list of addresses and recipients with data", func() {
+				body := strings.NewReader(`{
+                    "to": [
+                        "The User <user@example.com>",
+                        {"email": "other@example.com", "data": {"total": "$10", "items": ["apples"]}},
+                        "not an address"
+                    ]
+                }`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.To).To(BeEmpty())
+				Expect(parameters.Recipients).To(Equal([]params.EmailRecipient{
+					{Email: "user@example.com"},
+					{Email: "other@example.com", Data: map[string]interface{}{"total": "$10", "items": []interface{}{"apples"}}},
+					{Email: params.InvalidEmail},
+				}))
+			})
+
+			It("returns a ParseError when the list cannot be read", func() {
+				body := strings.NewReader(`{"to": [42]}`)
+
+				_, err := params.NewNotify(body)
+				Expect(err).To(BeAssignableToTypeOf(params.ParseError{}))
+			})
+		})
+
+		Describe("cc and bcc field parsing", func() {
+			It("parses each address the same way as the to field", func() {
+				body := strings.NewReader(`{
+                    "to": "user@example.com",
+                    "cc": ["The Copy <copy@example.com>", "other-copy@example.com"],
+                    "bcc": ["hidden@example.com", "<The User"]
+                }`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.CC).To(Equal([]string{"copy@example.com", "other-copy@example.com"}))
+				Expect(parameters.BCC).To(Equal([]string{"hidden@example.com", params.InvalidEmail}))
+			})
+		})
+
+		Describe("send_at field parsing", func() {
+			It("leaves the send time empty if it is not specified", func() {
+				body := strings.NewReader(`{"kind_id": "test_email"}`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.SendAt).To(Equal(""))
+				Expect(parameters.ParsedSendAt.IsZero()).To(BeTrue())
+			})
+
+			It("parses an RFC3339 timestamp", func() {
+				body := strings.NewReader(`{"kind_id": "test_email", "send_at": "2014-10-31T12:30:00-07:00"}`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.ParsedSendAt.Equal(time.Date(2014, time.October, 31, 19, 30, 0, 0, time.UTC))).To(BeTrue())
+			})
+
+			It("leaves the parsed send time empty when the timestamp is malformed", func() {
+				body := strings.NewReader(`{"kind_id": "test_email", "send_at": "next tuesday"}`)
+
+				parameters, err := params.NewNotify(body)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parameters.SendAt).To(Equal("next tuesday"))
+				Expect(parameters.ParsedSendAt.IsZero()).To(BeTrue())
+			})
+		})
+
 		Describe("role field parsing", func() {
 			It("sets the role field to empty if it is not specificed", func() {
 				body := strings.NewReader(`{}`)
@@ -351,7 +463,13 @@ var _ = Describe("Notify", func() {
                 "subject": "Summary of contents",
                 "text": "Contents of the email message",
                 "html": "<div>Some HTML</div>",
-                "role": "OrgManager"
+                "role": "OrgManager",
+                "send_at": "2014-10-31T12:30:00Z",
+                "priority": "low",
+                "cc": ["copy@example.com"],
+                "bcc": ["hidden@example.com"],
+                "to": [{"email": "user@example.com", "data": {"total": "$10"}}],
+                "data": {"month": "May"}
             }`)
 
 			parameters, err := params.NewNotify(body)
@@ -367,6 +485,7 @@ var _ = Describe("Notify", func() {
 				ID:          "test_email",
 				ClientID:    "client-id",
 				Description: "Descriptive Kind Name",
+				Critical:    true,
 			}
 			options := parameters.ToOptions(client, kind)
 			Expect(options).To(Equal(postal.Options{
@@ -378,6 +497,15 @@ var _ = Describe("Notify", func() {
 				Text:              "Contents of the email message",
 				HTML:              postal.HTML{BodyAttributes: "", BodyContent: "<div>Some HTML</div>"},
 				Role:              "OrgManager",
+				SendAt:            time.Date(2014, time.October, 31, 12, 30, 0, 0, time.UTC),
+				Priority:          "low",
+				Critical:          true,
+				CC:                []string{"copy@example.com"},
+				BCC:               []string{"hidden@example.com"},
+				Data:              map[string]interface{}{"month": "May"},
+				Recipients: []postal.EmailRecipient{
+					{Email: "user@example.com", Data: map[string]interface{}{"total": "$10"}},
+				},
 			}))
 		})
 	})
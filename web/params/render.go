@@ -0,0 +1,109 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+)
+
+// SampleMessageID stands in for the ID of the message in rendered templates,
+// as no message is created when a template is rendered.
+const SampleMessageID = "00000000-0000-0000-0000-000000000000"
+
+// Render holds a sample notification, along with the user, space and
+// organization it is rendered for.
+type Render struct {
+	Notify            Notify
+	KindDescription   string
+	SourceDescription string
+	Scope             string
+	User              RenderUser
+	Space             RenderSpace
+	Organization      RenderOrganization
+}
+
+type RenderUser struct {
+	GUID  string `json:"guid"`
+	Email string `json:"email"`
+}
+
+type RenderSpace struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type RenderOrganization struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+// NewRender reads a render request. The "notification" field takes the same
+// payload as the notify endpoints do.
+func NewRender(body io.Reader) (Render, error) {
+	var document struct {
+		Notification      json.RawMessage    `json:"notification"`
+		KindDescription   string             `json:"kind_description"`
+		SourceDescription string             `json:"source_description"`
+		Scope             string             `json:"scope"`
+		User              RenderUser         `json:"user"`
+		Space             RenderSpace        `json:"space"`
+		Organization      RenderOrganization `json:"organization"`
+	}
+
+	err := json.NewDecoder(body).Decode(&document)
+	if err != nil {
+		return Render{}, ParseError{}
+	}
+
+	notify, err := NewNotify(bytes.NewReader(document.Notification))
+	if err != nil {
+		return Render{}, err
+	}
+
+	if missingTextOrHTMLFields(&notify) {
+		return Render{}, ValidationError([]string{`"notification" must supply "text" or "html" fields`})
+	}
+
+	return Render{
+		Notify:            notify,
+		KindDescription:   document.KindDescription,
+		SourceDescription: document.SourceDescription,
+		Scope:             document.Scope,
+		User:              document.User,
+		Space:             document.Space,
+		Organization:      document.Organization,
+	}, nil
+}
+
+// ToDelivery builds the delivery a worker would pack for the sample
+// notification. The recipient is the sample user, or else the "to" address of
+// the notification.
+func (render Render) ToDelivery(clientID string) postal.Delivery {
+	options := render.Notify.ToOptions(models.Client{
+		ID:          clientID,
+		Description: render.SourceDescription,
+	}, models.Kind{
+		ID:          render.Notify.KindID,
+		Description: render.KindDescription,
+	})
+
+	email := render.User.Email
+	if email == "" {
+		email = options.To
+	}
+
+	return postal.Delivery{
+		Options:      options,
+		UserGUID:     render.User.GUID,
+		Email:        email,
+		Space:        cf.CloudControllerSpace{GUID: render.Space.GUID, Name: render.Space.Name, OrganizationGUID: render.Organization.GUID},
+		Organization: cf.CloudControllerOrganization{GUID: render.Organization.GUID, Name: render.Organization.Name},
+		ClientID:     clientID,
+		MessageID:    SampleMessageID,
+		Scope:        render.Scope,
+	}
+}
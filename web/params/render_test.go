@@ -0,0 +1,88 @@
+package params_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	var body string
+
+	BeforeEach(func() {
+		body = `{
+			"notification": {
+				"kind_id": "forgot-password",
+				"subject": "Reset your password",
+				"text": "Follow the link",
+				"html": "<p>Follow the link</p>",
+				"data": {"link": "https://example.com/reset"}
+			},
+			"kind_description": "Password reminder",
+			"source_description": "Login system",
+			"user": {"guid": "user-123", "email": "user@example.com"},
+			"space": {"guid": "space-001", "name": "development"},
+			"organization": {"guid": "org-001", "name": "banana"}
+		}`
+	})
+
+	Describe("NewRender", func() {
+		It("reads the sample notification and its context", func() {
+			render, err := params.NewRender(strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(render.Notify.KindID).To(Equal("forgot-password"))
+			Expect(render.Notify.Text).To(Equal("Follow the link"))
+			Expect(render.Notify.ParsedHTML.BodyContent).To(Equal("<p>Follow the link</p>"))
+			Expect(render.KindDescription).To(Equal("Password reminder"))
+			Expect(render.User).To(Equal(params.RenderUser{GUID: "user-123", Email: "user@example.com"}))
+			Expect(render.Space).To(Equal(params.RenderSpace{GUID: "space-001", Name: "development"}))
+			Expect(render.Organization).To(Equal(params.RenderOrganization{GUID: "org-001", Name: "banana"}))
+		})
+
+		It("returns a validation error when the notification has no text or html", func() {
+			_, err := params.NewRender(strings.NewReader(`{"notification": {"subject": "hi"}}`))
+			Expect(err).To(Equal(params.ValidationError([]string{`"notification" must supply "text" or "html" fields`})))
+		})
+
+		It("returns a parse error when the json is malformed", func() {
+			_, err := params.NewRender(strings.NewReader(`{"notification":`))
+			Expect(err).To(BeAssignableToTypeOf(params.ParseError{}))
+		})
+	})
+
+	Describe("ToDelivery", func() {
+		It("builds the delivery for the sample user", func() {
+			render, err := params.NewRender(strings.NewReader(body))
+			if err != nil {
+				panic(err)
+			}
+
+			delivery := render.ToDelivery("my-client")
+			Expect(delivery.ClientID).To(Equal("my-client"))
+			Expect(delivery.UserGUID).To(Equal("user-123"))
+			Expect(delivery.Email).To(Equal("user@example.com"))
+			Expect(delivery.MessageID).To(Equal(params.SampleMessageID))
+			Expect(delivery.Space).To(Equal(cf.CloudControllerSpace{GUID: "space-001", Name: "development", OrganizationGUID: "org-001"}))
+			Expect(delivery.Organization).To(Equal(cf.CloudControllerOrganization{GUID: "org-001", Name: "banana"}))
+			Expect(delivery.Options.KindID).To(Equal("forgot-password"))
+			Expect(delivery.Options.KindDescription).To(Equal("Password reminder"))
+			Expect(delivery.Options.SourceDescription).To(Equal("Login system"))
+			Expect(delivery.Options.Data).To(Equal(map[string]interface{}{"link": "https://example.com/reset"}))
+		})
+
+		It("sends to the address of the notification when there is no sample user", func() {
+			render, err := params.NewRender(strings.NewReader(`{"notification": {"to": "other@example.com", "text": "hi"}}`))
+			if err != nil {
+				panic(err)
+			}
+
+			delivery := render.ToDelivery("my-client")
+			Expect(delivery.Email).To(Equal("other@example.com"))
+		})
+	})
+})
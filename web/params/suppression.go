@@ -0,0 +1,44 @@
+package params
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/valiant"
+)
+
+var suppressionEmailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+
+type SuppressionParams struct {
+	Email  string `json:"-"`
+	Reason string `json:"reason" validate-required:"true"`
+}
+
+// NewSuppressionParams reads the reason an address is suppressed for from the
+// request body. The address itself comes from the path of the request.
+func NewSuppressionParams(email string, body io.Reader) (SuppressionParams, error) {
+	params := SuppressionParams{Email: email}
+
+	if !suppressionEmailRegex.MatchString(email) {
+		return params, ValidationError([]string{fmt.Sprintf("%q is not a valid email address", email)})
+	}
+
+	validator := valiant.NewValidator(body)
+	err := validator.Validate(&params)
+	if err != nil {
+		switch err.(type) {
+		case valiant.RequiredFieldError:
+			return params, ValidationError([]string{err.Error()})
+		default:
+			return params, ParseError{}
+		}
+	}
+
+	if strings.TrimSpace(params.Reason) == "" {
+		return params, ValidationError([]string{"Reason must not be blank"})
+	}
+
+	return params, nil
+}
@@ -0,0 +1,43 @@
+package params_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Suppression", func() {
+	Describe("NewSuppressionParams", func() {
+		It("reads the reason from the body", func() {
+			suppressionParams, err := params.NewSuppressionParams("user@example.com", strings.NewReader(`{"reason":"legal request"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressionParams).To(Equal(params.SuppressionParams{
+				Email:  "user@example.com",
+				Reason: "legal request",
+			}))
+		})
+
+		It("returns a validation error when the reason is missing", func() {
+			_, err := params.NewSuppressionParams("user@example.com", strings.NewReader(`{}`))
+			Expect(err).To(BeAssignableToTypeOf(params.ValidationError{}))
+		})
+
+		It("returns a validation error when the reason is blank", func() {
+			_, err := params.NewSuppressionParams("user@example.com", strings.NewReader(`{"reason":"  "}`))
+			Expect(err).To(Equal(params.ValidationError([]string{"Reason must not be blank"})))
+		})
+
+		It("returns a validation error when the address is not an email address", func() {
+			_, err := params.NewSuppressionParams("not-an-email", strings.NewReader(`{"reason":"legal request"}`))
+			Expect(err).To(Equal(params.ValidationError([]string{`"not-an-email" is not a valid email address`})))
+		})
+
+		It("returns a parse error when the json is malformed", func() {
+			_, err := params.NewSuppressionParams("user@example.com", strings.NewReader(`{"reason":`))
+			Expect(err).To(BeAssignableToTypeOf(params.ParseError{}))
+		})
+	})
+})
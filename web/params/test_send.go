@@ -0,0 +1,60 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+)
+
+// TestSend is a render request along with the address the rendered template
+// is mailed to.
+type TestSend struct {
+	Render
+	To string
+}
+
+// NewTestSend reads a test send request, which has the fields of a render
+// request and a "to" address.
+func NewTestSend(body io.Reader) (TestSend, error) {
+	buffer := bytes.NewBuffer([]byte{})
+	buffer.ReadFrom(body)
+
+	var document struct {
+		To string `json:"to"`
+	}
+
+	err := json.Unmarshal(buffer.Bytes(), &document)
+	if err != nil {
+		return TestSend{}, ParseError{}
+	}
+
+	render, err := NewRender(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		return TestSend{}, err
+	}
+
+	if document.To == "" {
+		return TestSend{}, ValidationError([]string{`"to" is a required field`})
+	}
+
+	to := formatAddress(document.To)
+	if to == InvalidEmail {
+		return TestSend{}, ValidationError([]string{`"to" is improperly formatted`})
+	}
+
+	return TestSend{
+		Render: render,
+		To:     to,
+	}, nil
+}
+
+// ToDelivery builds the delivery for the sample notification, addressed to the
+// recipient of the test send.
+func (testSend TestSend) ToDelivery(clientID string) postal.Delivery {
+	delivery := testSend.Render.ToDelivery(clientID)
+	delivery.Email = testSend.To
+
+	return delivery
+}
@@ -0,0 +1,65 @@
+package params_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/web/params"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TestSend", func() {
+	Describe("NewTestSend", func() {
+		It("reads the address along with the sample notification", func() {
+			testSend, err := params.NewTestSend(strings.NewReader(`{
+				"to": "Designer <designer@example.com>",
+				"notification": {"subject": "hi", "text": "hello"},
+				"user": {"guid": "user-123", "email": "user@example.com"}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testSend.To).To(Equal("designer@example.com"))
+			Expect(testSend.Notify.Text).To(Equal("hello"))
+			Expect(testSend.User.GUID).To(Equal("user-123"))
+		})
+
+		It("returns a validation error when the address is missing", func() {
+			_, err := params.NewTestSend(strings.NewReader(`{"notification": {"text": "hello"}}`))
+			Expect(err).To(Equal(params.ValidationError([]string{`"to" is a required field`})))
+		})
+
+		It("returns a validation error when the address is not an email address", func() {
+			_, err := params.NewTestSend(strings.NewReader(`{"to": "nope", "notification": {"text": "hello"}}`))
+			Expect(err).To(Equal(params.ValidationError([]string{`"to" is improperly formatted`})))
+		})
+
+		It("returns the errors of the sample notification", func() {
+			_, err := params.NewTestSend(strings.NewReader(`{"to": "designer@example.com", "notification": {}}`))
+			Expect(err).To(BeAssignableToTypeOf(params.ValidationError{}))
+		})
+
+		It("returns a parse error when the json is malformed", func() {
+			_, err := params.NewTestSend(strings.NewReader(`{"to":`))
+			Expect(err).To(BeAssignableToTypeOf(params.ParseError{}))
+		})
+	})
+
+	Describe("ToDelivery", func() {
+		It("addresses the delivery to the recipient of the test send", func() {
+			testSend, err := params.NewTestSend(strings.NewReader(`{
+				"to": "designer@example.com",
+				"notification": {"text": "hello"},
+				"user": {"guid": "user-123", "email": "user@example.com"}
+			}`))
+			if err != nil {
+				panic(err)
+			}
+
+			delivery := testSend.ToDelivery("my-client")
+			Expect(delivery.Email).To(Equal("designer@example.com"))
+			Expect(delivery.UserGUID).To(Equal("user-123"))
+			Expect(delivery.ClientID).To(Equal("my-client"))
+		})
+	})
+})
@@ -1,22 +1,52 @@
 package params
 
-type EmailValidator struct{}
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+)
+
+// EmailValidator validates sends to /emails. BulkLimit is the number of
+// recipients that "to" may list in a single request.
+type EmailValidator struct {
+	BulkLimit int
+}
 
 func (validator EmailValidator) Validate(notify *Notify) bool {
 	notify.Errors = []string{}
 
-	if notify.To == "" {
+	if notify.To == "" && len(notify.Recipients) == 0 {
 		notify.Errors = append(notify.Errors, `"to" is a required field`)
 	}
 
-	if notify.To == InvalidEmail {
+	if notify.To == InvalidEmail || invalidRecipients(notify.Recipients) {
 		notify.Errors = append(notify.Errors, `"to" is improperly formatted`)
 	}
 
+	if len(notify.Recipients) > validator.BulkLimit {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"to" must not list more than %d recipients`, validator.BulkLimit))
+	}
+
+	if invalidAddresses(notify.CC) {
+		notify.Errors = append(notify.Errors, `"cc" is improperly formatted`)
+	}
+
+	if invalidAddresses(notify.BCC) {
+		notify.Errors = append(notify.Errors, `"bcc" is improperly formatted`)
+	}
+
 	if missingTextOrHTMLFields(notify) {
 		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
 	}
 
+	if invalidSendAtField(notify) {
+		notify.Errors = append(notify.Errors, `"send_at" must be an RFC3339 timestamp`)
+	}
+
+	if invalidPriorityField(notify) {
+		notify.Errors = append(notify.Errors, `"priority" must be "low", "normal", "high" or unset`)
+	}
+
 	return len(notify.Errors) == 0
 }
 
@@ -31,10 +61,22 @@ func (validator GUIDValidator) Validate(notify *Notify) bool {
 		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
 	}
 
+	if len(notify.CC) > 0 || len(notify.BCC) > 0 {
+		notify.Errors = append(notify.Errors, `"cc" and "bcc" are only supported when sending to an email address`)
+	}
+
 	if validator.invalidRoleField(notify.Role) {
 		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
 	}
 
+	if invalidSendAtField(notify) {
+		notify.Errors = append(notify.Errors, `"send_at" must be an RFC3339 timestamp`)
+	}
+
+	if invalidPriorityField(notify) {
+		notify.Errors = append(notify.Errors, `"priority" must be "low", "normal", "high" or unset`)
+	}
+
 	return len(notify.Errors) == 0
 }
 
@@ -42,6 +84,36 @@ func missingTextOrHTMLFields(notify *Notify) bool {
 	return notify.Text == "" && notify.ParsedHTML.BodyContent == ""
 }
 
+func invalidAddresses(addresses []string) bool {
+	for _, address := range addresses {
+		if address == "" || address == InvalidEmail {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidRecipients(recipients []EmailRecipient) bool {
+	for _, recipient := range recipients {
+		if recipient.Email == "" || recipient.Email == InvalidEmail {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidSendAtField(notify *Notify) bool {
+	return notify.SendAt != "" && notify.ParsedSendAt.IsZero()
+}
+
+func invalidPriorityField(notify *Notify) bool {
+	switch notify.Priority {
+	case "", postal.PriorityLow, postal.PriorityNormal, postal.PriorityHigh:
+		return false
+	}
+	return true
+}
+
 func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 	if roleName == "" {
 		return false
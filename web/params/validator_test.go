@@ -1,6 +1,8 @@
 package params_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/web/params"
 
@@ -17,7 +19,7 @@ var _ = Describe("Validator", func() {
 				Text: "my silly text",
 				To:   "bob@example.com",
 			}
-			validator = params.EmailValidator{}
+			validator = params.EmailValidator{BulkLimit: 2}
 		})
 
 		Describe("Validate", func() {
@@ -54,6 +56,76 @@ var _ = Describe("Validator", func() {
 					Expect(notify.Errors).To(ContainElement(`"to" is improperly formatted`))
 				})
 			})
+
+			It("validates a list of recipients", func() {
+				notify.To = ""
+				notify.Recipients = []params.EmailRecipient{{Email: "first@example.com"}, {Email: "second@example.com"}}
+
+				Expect(validator.Validate(notify)).To(BeTrue())
+				Expect(len(notify.Errors)).To(Equal(0))
+
+				notify.Recipients = []params.EmailRecipient{{Email: "first@example.com"}, {Email: params.InvalidEmail}}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(notify.Errors).To(Equal([]string{`"to" is improperly formatted`}))
+
+				notify.Recipients = []params.EmailRecipient{}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(notify.Errors).To(Equal([]string{`"to" is a required field`}))
+			})
+
+			It("validates that the list of recipients is within the bulk limit", func() {
+				notify.To = ""
+				notify.Recipients = []params.EmailRecipient{{Email: "first@example.com"}, {Email: "second@example.com"}, {Email: "third@example.com"}}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(notify.Errors).To(Equal([]string{`"to" must not list more than 2 recipients`}))
+			})
+
+			It("validates that the cc and bcc addresses are properly formatted", func() {
+				notify.CC = []string{"copy@example.com"}
+				notify.BCC = []string{"hidden@example.com"}
+
+				Expect(validator.Validate(notify)).To(BeTrue())
+				Expect(len(notify.Errors)).To(Equal(0))
+
+				notify.CC = []string{"copy@example.com", params.InvalidEmail}
+				notify.BCC = []string{""}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(2))
+				Expect(notify.Errors).To(ContainElement(`"cc" is improperly formatted`))
+				Expect(notify.Errors).To(ContainElement(`"bcc" is improperly formatted`))
+			})
+
+			It("validates that send_at is a timestamp when it is provided", func() {
+				notify.SendAt = "2014-10-31T12:00:00Z"
+				notify.ParsedSendAt = time.Date(2014, time.October, 31, 12, 0, 0, 0, time.UTC)
+
+				Expect(validator.Validate(notify)).To(BeTrue())
+				Expect(len(notify.Errors)).To(Equal(0))
+
+				notify.SendAt = "next tuesday"
+				notify.ParsedSendAt = time.Time{}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(1))
+				Expect(notify.Errors).To(ContainElement(`"send_at" must be an RFC3339 timestamp`))
+			})
+
+			It("validates that the priority must be low, normal, high, or empty", func() {
+				for _, priority := range []string{"low", "normal", "high", ""} {
+					notify.Priority = priority
+					Expect(validator.Validate(notify)).To(BeTrue())
+					Expect(len(notify.Errors)).To(Equal(0))
+				}
+
+				notify.Priority = "urgent"
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(1))
+				Expect(notify.Errors).To(ContainElement(`"priority" must be "low", "normal", "high" or unset`))
+			})
 		})
 	})
 
@@ -94,6 +166,14 @@ var _ = Describe("Validator", func() {
 				Expect(len(notify.Errors)).To(Equal(0))
 			})
 
+			It("rejects cc and bcc addresses", func() {
+				notify.CC = []string{"copy@example.com"}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(1))
+				Expect(notify.Errors).To(ContainElement(`"cc" and "bcc" are only supported when sending to an email address`))
+			})
+
 			It("validates that KindID is properly formatted", func() {
 				notify.KindID = "A_valid.id-99"
 
@@ -119,6 +199,34 @@ var _ = Describe("Validator", func() {
 				Expect(len(notify.Errors)).To(Equal(1))
 				Expect(notify.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates that send_at is a timestamp when it is provided", func() {
+				notify.SendAt = "2014-10-31T12:00:00Z"
+				notify.ParsedSendAt = time.Date(2014, time.October, 31, 12, 0, 0, 0, time.UTC)
+
+				Expect(validator.Validate(notify)).To(BeTrue())
+				Expect(len(notify.Errors)).To(Equal(0))
+
+				notify.SendAt = "next tuesday"
+				notify.ParsedSendAt = time.Time{}
+
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(1))
+				Expect(notify.Errors).To(ContainElement(`"send_at" must be an RFC3339 timestamp`))
+			})
+
+			It("validates that the priority must be low, normal, high, or empty", func() {
+				for _, priority := range []string{"low", "normal", "high", ""} {
+					notify.Priority = priority
+					Expect(validator.Validate(notify)).To(BeTrue())
+					Expect(len(notify.Errors)).To(Equal(0))
+				}
+
+				notify.Priority = "urgent"
+				Expect(validator.Validate(notify)).To(BeFalse())
+				Expect(len(notify.Errors)).To(Equal(1))
+				Expect(notify.Errors).To(ContainElement(`"priority" must be "low", "normal", "high" or unset`))
+			})
 		})
 	})
 })
@@ -4,9 +4,11 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/postal/strategies"
 	"github.com/cloudfoundry-incubator/notifications/web/handlers"
 	"github.com/cloudfoundry-incubator/notifications/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web/params"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
 	"github.com/gorilla/mux"
 	"github.com/ryanmoran/stack"
@@ -15,6 +17,7 @@ import (
 type MotherInterface interface {
 	Registrar() services.Registrar
 	EmailStrategy() strategies.EmailStrategy
+	EmailValidator() params.EmailValidator
 	UserStrategy() strategies.UserStrategy
 	SpaceStrategy() strategies.SpaceStrategy
 	OrganizationStrategy() strategies.OrganizationStrategy
@@ -25,7 +28,16 @@ type MotherInterface interface {
 	PreferencesFinder() *services.PreferencesFinder
 	PreferenceUpdater() services.PreferenceUpdater
 	MessageFinder() services.MessageFinder
+	MessageCanceler() postal.MessageCanceler
+	BatchFinder() services.BatchFinder
+	Unsubscriber() services.Unsubscriber
+	TemplateRenderer() postal.TemplateRenderer
+	TemplateEscapingChecker() postal.TemplateEscapingCheckerInterface
+	TestSender() postal.TestSender
+	TemplateVersionServiceObjects() (services.TemplateVersionFinder, services.TemplateRestorer)
 	TemplateServiceObjects() (services.TemplateCreator, services.TemplateFinder, services.TemplateUpdater, services.TemplateDeleter, services.TemplateLister, services.TemplateAssigner, services.TemplateAssociationLister)
+	DeadLetterServiceObjects() (services.DeadLetterFinder, services.DeadLetterRequeuer, services.DeadLetterPurger)
+	SuppressionServiceObjects() (services.SuppressionFinder, services.SuppressionUpdater, services.SuppressionDeleter)
 	Database() models.DatabaseInterface
 	Logging() stack.Middleware
 	ErrorWriter() handlers.ErrorWriter
@@ -42,6 +54,7 @@ func NewRouter(mother MotherInterface) Router {
 	registrar := mother.Registrar()
 	notificationsFinder := mother.NotificationsFinder()
 	emailStrategy := mother.EmailStrategy()
+	emailValidator := mother.EmailValidator()
 	userStrategy := mother.UserStrategy()
 	spaceStrategy := mother.SpaceStrategy()
 	organizationStrategy := mother.OrganizationStrategy()
@@ -51,8 +64,17 @@ func NewRouter(mother MotherInterface) Router {
 	preferencesFinder := mother.PreferencesFinder()
 	preferenceUpdater := mother.PreferenceUpdater()
 	templateCreator, templateFinder, templateUpdater, templateDeleter, templateLister, templateAssigner, templateAssociationLister := mother.TemplateServiceObjects()
+	templateVersionFinder, templateRestorer := mother.TemplateVersionServiceObjects()
+	templateRenderer := mother.TemplateRenderer()
+	templateEscapingChecker := mother.TemplateEscapingChecker()
+	testSender := mother.TestSender()
 	notificationsUpdater := mother.NotificationsUpdater()
 	messageFinder := mother.MessageFinder()
+	messageCanceler := mother.MessageCanceler()
+	batchFinder := mother.BatchFinder()
+	unsubscriber := mother.Unsubscriber()
+	deadLetterFinder, deadLetterRequeuer, deadLetterPurger := mother.DeadLetterServiceObjects()
+	suppressionFinder, suppressionUpdater, suppressionDeleter := mother.SuppressionServiceObjects()
 	logging := mother.Logging()
 	errorWriter := mother.ErrorWriter()
 	notificationsWriteAuthenticator := mother.Authenticator("notifications.write")
@@ -72,16 +94,16 @@ func NewRouter(mother MotherInterface) Router {
 	return Router{
 		router: router,
 		stacks: map[string]stack.Stack{
-			"GET /info":                                                         stack.NewStack(handlers.NewGetInfo()).Use(logging, requestCounter),
-			"POST /users/{user_id}":                                             stack.NewStack(handlers.NewNotifyUser(notify, errorWriter, userStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"POST /spaces/{space_id}":                                           stack.NewStack(handlers.NewNotifySpace(notify, errorWriter, spaceStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"POST /organizations/{org_id}":                                      stack.NewStack(handlers.NewNotifyOrganization(notify, errorWriter, organizationStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"POST /everyone":                                                    stack.NewStack(handlers.NewNotifyEveryone(notify, errorWriter, everyoneStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"POST /uaa_scopes/{scope}":                                          stack.NewStack(handlers.NewNotifyUAAScope(notify, errorWriter, uaaScopeStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"POST /emails":                                                      stack.NewStack(handlers.NewNotifyEmail(notify, errorWriter, emailStrategy, database)).Use(logging, requestCounter, emailsWriteAuthenticator),
-			"PUT /registration":                                                 stack.NewStack(handlers.NewRegisterNotifications(registrar, errorWriter, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"PUT /notifications":                                                stack.NewStack(handlers.NewRegisterClientWithNotifications(registrar, errorWriter, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
-			"PUT /clients/{client_id}/notifications/{notification_id}":          stack.NewStack(handlers.NewUpdateNotifications(notificationsUpdater, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"GET /info":                    stack.NewStack(handlers.NewGetInfo()).Use(logging, requestCounter),
+			"POST /users/{user_id}":        stack.NewStack(handlers.NewNotifyUser(notify, errorWriter, userStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"POST /spaces/{space_id}":      stack.NewStack(handlers.NewNotifySpace(notify, errorWriter, spaceStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"POST /organizations/{org_id}": stack.NewStack(handlers.NewNotifyOrganization(notify, errorWriter, organizationStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"POST /everyone":               stack.NewStack(handlers.NewNotifyEveryone(notify, errorWriter, everyoneStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"POST /uaa_scopes/{scope}":     stack.NewStack(handlers.NewNotifyUAAScope(notify, errorWriter, uaaScopeStrategy, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"POST /emails":                 stack.NewStack(handlers.NewNotifyEmail(notify, errorWriter, emailStrategy, emailValidator, database)).Use(logging, requestCounter, emailsWriteAuthenticator),
+			"PUT /registration":            stack.NewStack(handlers.NewRegisterNotifications(registrar, errorWriter, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"PUT /notifications":           stack.NewStack(handlers.NewRegisterClientWithNotifications(registrar, errorWriter, database)).Use(logging, requestCounter, notificationsWriteAuthenticator),
+			"PUT /clients/{client_id}/notifications/{notification_id}": stack.NewStack(handlers.NewUpdateNotifications(notificationsUpdater, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
 			"GET /notifications":                                                stack.NewStack(handlers.NewGetAllNotifications(notificationsFinder, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
 			"OPTIONS /user_preferences":                                         stack.NewStack(handlers.NewOptionsPreferences()).Use(logging, requestCounter, cors),
 			"OPTIONS /user_preferences/{user_id}":                               stack.NewStack(handlers.NewOptionsPreferences()).Use(logging, requestCounter, cors),
@@ -95,11 +117,32 @@ func NewRouter(mother MotherInterface) Router {
 			"GET /templates/{template_id}":                                      stack.NewStack(handlers.NewGetTemplates(templateFinder, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
 			"PUT /templates/{template_id}":                                      stack.NewStack(handlers.NewUpdateTemplates(templateUpdater, errorWriter)).Use(logging, requestCounter, notificationsTemplateWriteAuthenticator),
 			"DELETE /templates/{template_id}":                                   stack.NewStack(handlers.NewDeleteTemplates(templateDeleter, errorWriter)).Use(logging, requestCounter, notificationsTemplateWriteAuthenticator),
+			"POST /templates/{template_id}/render":                              stack.NewStack(handlers.NewRenderTemplate(templateRenderer, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
+			"POST /templates/{template_id}/test_send":                           stack.NewStack(handlers.NewTestSendTemplate(testSender, errorWriter)).Use(logging, requestCounter, notificationsTemplateWriteAuthenticator),
+			"GET /templates/{template_id}/versions":                             stack.NewStack(handlers.NewListTemplateVersions(templateVersionFinder, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
+			"GET /templates/{template_id}/versions/{version}":                   stack.NewStack(handlers.NewGetTemplateVersion(templateVersionFinder, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
+			"POST /templates/{template_id}/versions/{version}/restore":          stack.NewStack(handlers.NewRestoreTemplateVersion(templateRestorer, errorWriter)).Use(logging, requestCounter, notificationsTemplateWriteAuthenticator),
+			"POST /default_template/render":                                     stack.NewStack(handlers.NewRenderTemplate(templateRenderer, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
 			"GET /templates":                                                    stack.NewStack(handlers.NewListTemplates(templateLister, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
+			"GET /flagged_templates":                                            stack.NewStack(handlers.NewListFlaggedTemplates(templateEscapingChecker, errorWriter)).Use(logging, requestCounter, notificationsTemplateReadAuthenticator),
 			"PUT /clients/{client_id}/template":                                 stack.NewStack(handlers.NewAssignClientTemplate(templateAssigner, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
 			"PUT /clients/{client_id}/notifications/{notification_id}/template": stack.NewStack(handlers.NewAssignNotificationTemplate(templateAssigner, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
 			"GET /templates/{template_id}/associations":                         stack.NewStack(handlers.NewListTemplateAssociations(templateAssociationLister, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
 			"GET /messages/{message_id}":                                        stack.NewStack(handlers.NewGetMessages(messageFinder, errorWriter)).Use(logging, requestCounter, notificationsWriteOrEmailsWriteAuthenticator),
+			"DELETE /messages/{message_id}":                                     stack.NewStack(handlers.NewDeleteMessages(messageCanceler, errorWriter)).Use(logging, requestCounter, notificationsWriteOrEmailsWriteAuthenticator),
+			"GET /batches/{batch_id}":                                           stack.NewStack(handlers.NewGetBatches(batchFinder, errorWriter)).Use(logging, requestCounter, notificationsWriteOrEmailsWriteAuthenticator),
+			"GET /dead_letters":                                                 stack.NewStack(handlers.NewListDeadLetters(deadLetterFinder, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"DELETE /dead_letters":                                              stack.NewStack(handlers.NewDeleteDeadLetters(deadLetterPurger, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"POST /dead_letters/requeue":                                        stack.NewStack(handlers.NewRequeueDeadLetters(deadLetterRequeuer, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"GET /dead_letters/{dead_letter_id}":                                stack.NewStack(handlers.NewGetDeadLetter(deadLetterFinder, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"DELETE /dead_letters/{dead_letter_id}":                             stack.NewStack(handlers.NewDeleteDeadLetter(deadLetterPurger, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"POST /dead_letters/{dead_letter_id}/requeue":                       stack.NewStack(handlers.NewRequeueDeadLetter(deadLetterRequeuer, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"GET /suppressions":                                                 stack.NewStack(handlers.NewListSuppressions(suppressionFinder, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"GET /suppressions/{email}":                                         stack.NewStack(handlers.NewGetSuppression(suppressionFinder, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"PUT /suppressions/{email}":                                         stack.NewStack(handlers.NewUpdateSuppression(suppressionUpdater, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"DELETE /suppressions/{email}":                                      stack.NewStack(handlers.NewDeleteSuppression(suppressionDeleter, errorWriter)).Use(logging, requestCounter, notificationsManageAuthenticator),
+			"GET /unsubscribe/{unsubscribe_id}":                                 stack.NewStack(handlers.NewConfirmUnsubscribe(unsubscriber, errorWriter, database)).Use(logging, requestCounter),
+			"POST /unsubscribe/{unsubscribe_id}":                                stack.NewStack(handlers.NewUnsubscribe(unsubscriber, errorWriter, database)).Use(logging, requestCounter),
 		},
 	}
 }
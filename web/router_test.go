@@ -212,6 +212,17 @@ var _ = Describe("Router", func() {
 		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
 	})
 
+	It("routes GET /flagged_templates", func() {
+		s := router.Routes().Get("GET /flagged_templates").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.ListFlaggedTemplates{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+	})
+
 	It("routes GET /templates/{template_id}", func() {
 		s := router.Routes().Get("GET /templates/{template_id}").GetHandler().(stack.Stack)
 		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetTemplates{}))
@@ -311,6 +322,72 @@ var _ = Describe("Router", func() {
 		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
 	})
 
+	It("routes POST /templates/{template_id}/render", func() {
+		s := router.Routes().Get("POST /templates/{template_id}/render").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.RenderTemplate{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+	})
+
+	It("routes POST /default_template/render", func() {
+		s := router.Routes().Get("POST /default_template/render").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.RenderTemplate{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+	})
+
+	It("routes POST /templates/{template_id}/test_send", func() {
+		s := router.Routes().Get("POST /templates/{template_id}/test_send").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.TestSendTemplate{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+	})
+
+	It("routes GET /templates/{template_id}/versions", func() {
+		s := router.Routes().Get("GET /templates/{template_id}/versions").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.ListTemplateVersions{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+	})
+
+	It("routes GET /templates/{template_id}/versions/{version}", func() {
+		s := router.Routes().Get("GET /templates/{template_id}/versions/{version}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetTemplateVersion{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+	})
+
+	It("routes POST /templates/{template_id}/versions/{version}/restore", func() {
+		s := router.Routes().Get("POST /templates/{template_id}/versions/{version}/restore").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.RestoreTemplateVersion{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+	})
+
 	It("routes GET /messages/{message_id}", func() {
 		s := router.Routes().Get("GET /messages/{message_id}").GetHandler().(stack.Stack)
 		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetMessages{}))
@@ -321,4 +398,152 @@ var _ = Describe("Router", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
 	})
+
+	It("routes DELETE /messages/{message_id}", func() {
+		s := router.Routes().Get("DELETE /messages/{message_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.DeleteMessages{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
+
+	It("routes GET /batches/{batch_id}", func() {
+		s := router.Routes().Get("GET /batches/{batch_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetBatches{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
+
+	It("routes GET /suppressions", func() {
+		s := router.Routes().Get("GET /suppressions").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.ListSuppressions{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes GET /suppressions/{email}", func() {
+		s := router.Routes().Get("GET /suppressions/{email}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetSuppression{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes PUT /suppressions/{email}", func() {
+		s := router.Routes().Get("PUT /suppressions/{email}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.UpdateSuppression{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /suppressions/{email}", func() {
+		s := router.Routes().Get("DELETE /suppressions/{email}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.DeleteSuppression{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes GET /unsubscribe/{unsubscribe_id}", func() {
+		s := router.Routes().Get("GET /unsubscribe/{unsubscribe_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.ConfirmUnsubscribe{}))
+		Expect(s.Middleware).To(HaveLen(2))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+	})
+
+	It("routes POST /unsubscribe/{unsubscribe_id}", func() {
+		s := router.Routes().Get("POST /unsubscribe/{unsubscribe_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.Unsubscribe{}))
+		Expect(s.Middleware).To(HaveLen(2))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+	})
+
+	It("routes GET /dead_letters", func() {
+		s := router.Routes().Get("GET /dead_letters").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.ListDeadLetters{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /dead_letters", func() {
+		s := router.Routes().Get("DELETE /dead_letters").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.DeleteDeadLetters{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes POST /dead_letters/requeue", func() {
+		s := router.Routes().Get("POST /dead_letters/requeue").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.RequeueDeadLetters{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes GET /dead_letters/{dead_letter_id}", func() {
+		s := router.Routes().Get("GET /dead_letters/{dead_letter_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.GetDeadLetter{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /dead_letters/{dead_letter_id}", func() {
+		s := router.Routes().Get("DELETE /dead_letters/{dead_letter_id}").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.DeleteDeadLetter{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes POST /dead_letters/{dead_letter_id}/requeue", func() {
+		s := router.Routes().Get("POST /dead_letters/{dead_letter_id}/requeue").GetHandler().(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(handlers.RequeueDeadLetter{}))
+		Expect(s.Middleware[0]).To(BeAssignableToTypeOf(stack.Logging{}))
+		Expect(s.Middleware[1]).To(BeAssignableToTypeOf(middleware.RequestCounter{}))
+		Expect(s.Middleware[2]).To(BeAssignableToTypeOf(middleware.Authenticator{}))
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
 })
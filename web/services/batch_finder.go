@@ -0,0 +1,87 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type Batch struct {
+	ID             string
+	Status         string
+	RecipientCount int
+	Counts         map[string]int
+	Total          int
+	Messages       []models.Message
+}
+
+type BatchMessagesRepoInterface interface {
+	FindAllByBatchID(models.ConnectionInterface, string, string, int, int) ([]models.Message, error)
+	CountByStatusForBatchID(models.ConnectionInterface, string, string) (map[string]int, error)
+}
+
+type BatchFinderInterface interface {
+	Find(clientID, batchID string, page, perPage int) (Batch, error)
+}
+
+type BatchFinder struct {
+	messagesRepo  BatchMessagesRepoInterface
+	campaignsRepo models.CampaignsRepoInterface
+	database      models.DatabaseInterface
+}
+
+func NewBatchFinder(messagesRepo BatchMessagesRepoInterface, campaignsRepo models.CampaignsRepoInterface, database models.DatabaseInterface) BatchFinder {
+	return BatchFinder{
+		messagesRepo:  messagesRepo,
+		campaignsRepo: campaignsRepo,
+		database:      database,
+	}
+}
+
+// Find returns the status counts of every message the client sent in the
+// batch along with one page of its messages. Pages are numbered from 1. A
+// batch that is a campaign reports whether it is still expanding and how many
+// recipients it has resolved; other batches are complete once they are sent.
+// Batches sent by other clients are reported as not found.
+func (finder BatchFinder) Find(clientID, batchID string, page, perPage int) (Batch, error) {
+	conn := finder.database.Connection()
+
+	counts, err := finder.messagesRepo.CountByStatusForBatchID(conn, clientID, batchID)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	batch := Batch{
+		ID:             batchID,
+		Status:         models.CampaignStatusComplete,
+		RecipientCount: total,
+		Counts:         counts,
+		Total:          total,
+	}
+
+	campaign, err := finder.campaignsRepo.FindByID(conn, batchID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); !ok {
+			return Batch{}, err
+		}
+
+		if total == 0 {
+			return Batch{}, models.NewRecordNotFoundError("Batch with ID %q could not be found", batchID)
+		}
+	} else {
+		if campaign.ClientID != clientID {
+			return Batch{}, models.NewRecordNotFoundError("Batch with ID %q could not be found", batchID)
+		}
+
+		batch.Status = campaign.Status
+		batch.RecipientCount = campaign.RecipientCount
+	}
+
+	batch.Messages, err = finder.messagesRepo.FindAllByBatchID(conn, clientID, batchID, (page-1)*perPage, perPage)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return batch, nil
+}
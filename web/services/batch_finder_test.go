@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchFinder", func() {
+	var finder services.BatchFinder
+	var messagesRepo *fakes.MessagesRepo
+	var campaignsRepo *fakes.CampaignsRepo
+
+	BeforeEach(func() {
+		messagesRepo = fakes.NewMessagesRepo()
+		campaignsRepo = fakes.NewCampaignsRepo()
+		finder = services.NewBatchFinder(messagesRepo, campaignsRepo, fakes.NewDatabase())
+
+		messagesRepo.Messages["message-1"] = models.Message{ID: "message-1", BatchID: "batch-123", ClientID: "my-client", Status: postal.StatusDelivered, Recipient: "user-1"}
+		messagesRepo.Messages["message-2"] = models.Message{ID: "message-2", BatchID: "batch-123", ClientID: "my-client", Status: postal.StatusQueued, Recipient: "user-2"}
+		messagesRepo.Messages["message-3"] = models.Message{ID: "message-3", BatchID: "batch-123", ClientID: "my-client", Status: postal.StatusDelivered, Recipient: "user-3"}
+		messagesRepo.Messages["message-4"] = models.Message{ID: "message-4", BatchID: "batch-456", ClientID: "my-client", Status: postal.StatusFailed, Recipient: "user-1"}
+	})
+
+	Describe("Find", func() {
+		It("returns the status counts of the batch", func() {
+			batch, err := finder.Find("my-client", "batch-123", 1, 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batch.ID).To(Equal("batch-123"))
+			Expect(batch.Status).To(Equal(models.CampaignStatusComplete))
+			Expect(batch.RecipientCount).To(Equal(3))
+			Expect(batch.Total).To(Equal(3))
+			Expect(batch.Counts).To(Equal(map[string]int{
+				postal.StatusDelivered: 2,
+				postal.StatusQueued:    1,
+			}))
+		})
+
+		It("returns the requested page of messages", func() {
+			batch, err := finder.Find("my-client", "batch-123", 2, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batch.Total).To(Equal(3))
+			Expect(batch.Messages).To(Equal([]models.Message{messagesRepo.Messages["message-3"]}))
+		})
+
+		Context("when the batch is a campaign", func() {
+			BeforeEach(func() {
+				campaignsRepo.Campaigns["campaign-001"] = models.Campaign{
+					ID:             "campaign-001",
+					ClientID:       "my-client",
+					Status:         models.CampaignStatusExpanding,
+					RecipientCount: 1000,
+				}
+			})
+
+			It("returns the state of the campaign", func() {
+				messagesRepo.Messages["message-5"] = models.Message{ID: "message-5", BatchID: "campaign-001", ClientID: "my-client", Status: postal.StatusQueued, Recipient: "user-1"}
+
+				batch, err := finder.Find("my-client", "campaign-001", 1, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(batch.Status).To(Equal(models.CampaignStatusExpanding))
+				Expect(batch.RecipientCount).To(Equal(1000))
+				Expect(batch.Total).To(Equal(1))
+			})
+
+			It("returns an empty batch when the campaign has no messages yet", func() {
+				batch, err := finder.Find("my-client", "campaign-001", 1, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(batch.Total).To(Equal(0))
+				Expect(batch.Messages).To(BeEmpty())
+			})
+
+			It("returns a RecordNotFoundError when the campaign was launched by another client", func() {
+				_, err := finder.Find("other-client", "campaign-001", 1, 10)
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			})
+		})
+
+		Context("when the batch does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := finder.Find("my-client", "missing-batch", 1, 10)
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			})
+		})
+
+		Context("when the batch was sent by another client", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := finder.Find("other-client", "batch-123", 1, 10)
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			})
+		})
+
+		Context("when the campaigns repo returns an error", func() {
+			It("bubbles up the error", func() {
+				campaignsRepo.FindByIDError = errors.New("BOOM!")
+
+				_, err := finder.Find("my-client", "batch-123", 1, 10)
+				Expect(err).To(MatchError(campaignsRepo.FindByIDError))
+			})
+		})
+
+		Context("when the repo returns an error", func() {
+			It("bubbles up the error", func() {
+				messagesRepo.CountByStatusError = errors.New("BOOM!")
+
+				_, err := finder.Find("my-client", "batch-123", 1, 10)
+				Expect(err).To(MatchError(messagesRepo.CountByStatusError))
+			})
+		})
+	})
+})
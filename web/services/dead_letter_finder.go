@@ -0,0 +1,76 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+// DeadLetterPageSize is the number of dead letters read at a time when all
+// of them are requeued or purged.
+const DeadLetterPageSize = 500
+
+type DeadLetterQueueInterface interface {
+	DeadLetters(int, int) ([]gobble.DeadLetter, error)
+	FindDeadLetter(int) (gobble.DeadLetter, error)
+	RequeueDeadLetter(int) (gobble.Job, error)
+	PurgeDeadLetter(int) error
+}
+
+type DeadLetterFinderInterface interface {
+	List(int, int) ([]gobble.DeadLetter, error)
+	Find(string) (gobble.DeadLetter, error)
+}
+
+type DeadLetterFinder struct {
+	queue DeadLetterQueueInterface
+}
+
+func NewDeadLetterFinder(queue DeadLetterQueueInterface) DeadLetterFinder {
+	return DeadLetterFinder{
+		queue: queue,
+	}
+}
+
+func (finder DeadLetterFinder) List(page, perPage int) ([]gobble.DeadLetter, error) {
+	return finder.queue.DeadLetters(page, perPage)
+}
+
+func (finder DeadLetterFinder) Find(deadLetterID string) (gobble.DeadLetter, error) {
+	id, err := parseDeadLetterID(deadLetterID)
+	if err != nil {
+		return gobble.DeadLetter{}, err
+	}
+
+	return finder.queue.FindDeadLetter(id)
+}
+
+// deadLetterIDs pages through the dead letters and returns their IDs, so that
+// all of them can be requeued or purged without holding their payloads.
+func deadLetterIDs(queue DeadLetterQueueInterface) ([]int, error) {
+	ids := []int{}
+	for page := 1; ; page++ {
+		deadLetters, err := queue.DeadLetters(page, DeadLetterPageSize)
+		if err != nil {
+			return ids, err
+		}
+
+		for _, deadLetter := range deadLetters {
+			ids = append(ids, deadLetter.ID)
+		}
+
+		if len(deadLetters) < DeadLetterPageSize {
+			return ids, nil
+		}
+	}
+}
+
+func parseDeadLetterID(deadLetterID string) (int, error) {
+	id, err := strconv.Atoi(deadLetterID)
+	if err != nil {
+		return 0, models.NewRecordNotFoundError("Dead letter with ID %q could not be found", deadLetterID)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetterFinder", func() {
+	var finder services.DeadLetterFinder
+	var queue *fakes.DeadLetterQueue
+
+	BeforeEach(func() {
+		queue = fakes.NewDeadLetterQueue()
+		queue.Letters[1] = gobble.DeadLetter{ID: 1, JobID: 10, Payload: `{"first":true}`}
+		queue.Letters[2] = gobble.DeadLetter{ID: 2, JobID: 20, Payload: `{"second":true}`}
+
+		finder = services.NewDeadLetterFinder(queue)
+	})
+
+	Describe("List", func() {
+		It("returns the requested page of the dead letters in the queue", func() {
+			deadLetters, err := finder.List(1, 50)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(deadLetters).To(Equal([]gobble.DeadLetter{
+				{ID: 1, JobID: 10, Payload: `{"first":true}`},
+				{ID: 2, JobID: 20, Payload: `{"second":true}`},
+			}))
+
+			deadLetters, err = finder.List(2, 1)
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(deadLetters).To(Equal([]gobble.DeadLetter{
+				{ID: 2, JobID: 20, Payload: `{"second":true}`},
+			}))
+		})
+
+		It("returns an error when the queue cannot list its dead letters", func() {
+			queue.DeadLettersError = errors.New("BOOM!")
+
+			_, err := finder.List(1, 50)
+			Expect(err).To(Equal(queue.DeadLettersError))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the dead letter with the given ID", func() {
+			deadLetter, err := finder.Find("2")
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(deadLetter).To(Equal(gobble.DeadLetter{ID: 2, JobID: 20, Payload: `{"second":true}`}))
+		})
+
+		It("returns an error when the dead letter does not exist", func() {
+			_, err := finder.Find("3")
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 3}))
+		})
+
+		It("returns a record not found error when the ID is not numeric", func() {
+			_, err := finder.Find("banana")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type DeadLetterPurgerInterface interface {
+	Purge(string) error
+	PurgeAll() (int, error)
+}
+
+type DeadLetterPurger struct {
+	queue DeadLetterQueueInterface
+}
+
+func NewDeadLetterPurger(queue DeadLetterQueueInterface) DeadLetterPurger {
+	return DeadLetterPurger{
+		queue: queue,
+	}
+}
+
+func (purger DeadLetterPurger) Purge(deadLetterID string) error {
+	id, err := parseDeadLetterID(deadLetterID)
+	if err != nil {
+		return err
+	}
+
+	return purger.queue.PurgeDeadLetter(id)
+}
+
+// PurgeAll skips dead letters that were requeued or purged by another request
+// since they were listed.
+func (purger DeadLetterPurger) PurgeAll() (int, error) {
+	ids, err := deadLetterIDs(purger.queue)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		err := purger.queue.PurgeDeadLetter(id)
+		if _, ok := err.(gobble.DeadLetterNotFoundError); ok {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetterPurger", func() {
+	var purger services.DeadLetterPurger
+	var queue *fakes.DeadLetterQueue
+
+	BeforeEach(func() {
+		queue = fakes.NewDeadLetterQueue()
+		queue.Letters[1] = gobble.DeadLetter{ID: 1}
+		queue.Letters[2] = gobble.DeadLetter{ID: 2}
+
+		purger = services.NewDeadLetterPurger(queue)
+	})
+
+	Describe("Purge", func() {
+		It("deletes the dead letter", func() {
+			err := purger.Purge("1")
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(queue.PurgeDeadLetterIDs).To(Equal([]int{1}))
+			Expect(queue.Letters).NotTo(HaveKey(1))
+		})
+
+		It("returns an error when the dead letter does not exist", func() {
+			err := purger.Purge("3")
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 3}))
+		})
+
+		It("returns a record not found error when the ID is not numeric", func() {
+			err := purger.Purge("banana")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			Expect(queue.PurgeDeadLetterIDs).To(BeEmpty())
+		})
+	})
+
+	Describe("PurgeAll", func() {
+		It("deletes every dead letter", func() {
+			count, err := purger.PurgeAll()
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(count).To(Equal(2))
+			Expect(queue.PurgeDeadLetterIDs).To(Equal([]int{1, 2}))
+			Expect(queue.Letters).To(BeEmpty())
+		})
+
+		It("pages through the dead letters", func() {
+			for id := 3; id <= services.DeadLetterPageSize+1; id++ {
+				queue.Letters[id] = gobble.DeadLetter{ID: id}
+			}
+
+			count, err := purger.PurgeAll()
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(count).To(Equal(services.DeadLetterPageSize + 1))
+			Expect(queue.DeadLettersArguments).To(Equal([][]int{
+				{1, services.DeadLetterPageSize},
+				{2, services.DeadLetterPageSize},
+			}))
+			Expect(queue.Letters).To(BeEmpty())
+		})
+
+		It("skips dead letters that were removed by another request", func() {
+			queue.ConcurrentlyRemovedIDs[1] = true
+
+			count, err := purger.PurgeAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+			Expect(queue.PurgeDeadLetterIDs).To(Equal([]int{2}))
+		})
+
+		It("returns an error when the dead letters cannot be listed", func() {
+			queue.DeadLettersError = errors.New("BOOM!")
+
+			_, err := purger.PurgeAll()
+			Expect(err).To(Equal(queue.DeadLettersError))
+		})
+
+		It("returns an error when a dead letter cannot be purged", func() {
+			queue.PurgeDeadLetterError = errors.New("BOOM!")
+
+			count, err := purger.PurgeAll()
+			Expect(err).To(Equal(queue.PurgeDeadLetterError))
+			Expect(count).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type DeadLetterRequeuerInterface interface {
+	Requeue(string) error
+	RequeueAll() (int, error)
+}
+
+type DeadLetterRequeuer struct {
+	queue DeadLetterQueueInterface
+}
+
+func NewDeadLetterRequeuer(queue DeadLetterQueueInterface) DeadLetterRequeuer {
+	return DeadLetterRequeuer{
+		queue: queue,
+	}
+}
+
+func (requeuer DeadLetterRequeuer) Requeue(deadLetterID string) error {
+	id, err := parseDeadLetterID(deadLetterID)
+	if err != nil {
+		return err
+	}
+
+	_, err = requeuer.queue.RequeueDeadLetter(id)
+	return err
+}
+
+// RequeueAll skips dead letters that were requeued or purged by another
+// request since they were listed.
+func (requeuer DeadLetterRequeuer) RequeueAll() (int, error) {
+	ids, err := deadLetterIDs(requeuer.queue)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		_, err := requeuer.queue.RequeueDeadLetter(id)
+		if _, ok := err.(gobble.DeadLetterNotFoundError); ok {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
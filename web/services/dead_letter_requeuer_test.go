@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetterRequeuer", func() {
+	var requeuer services.DeadLetterRequeuer
+	var queue *fakes.DeadLetterQueue
+
+	BeforeEach(func() {
+		queue = fakes.NewDeadLetterQueue()
+		queue.Letters[1] = gobble.DeadLetter{ID: 1}
+		queue.Letters[2] = gobble.DeadLetter{ID: 2}
+
+		requeuer = services.NewDeadLetterRequeuer(queue)
+	})
+
+	Describe("Requeue", func() {
+		It("moves the dead letter back onto the queue", func() {
+			err := requeuer.Requeue("2")
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(queue.RequeueDeadLetterIDs).To(Equal([]int{2}))
+			Expect(queue.Letters).NotTo(HaveKey(2))
+		})
+
+		It("returns an error when the dead letter does not exist", func() {
+			err := requeuer.Requeue("3")
+			Expect(err).To(Equal(gobble.DeadLetterNotFoundError{ID: 3}))
+		})
+
+		It("returns a record not found error when the ID is not numeric", func() {
+			err := requeuer.Requeue("banana")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			Expect(queue.RequeueDeadLetterIDs).To(BeEmpty())
+		})
+	})
+
+	Describe("RequeueAll", func() {
+		It("moves every dead letter back onto the queue", func() {
+			count, err := requeuer.RequeueAll()
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(count).To(Equal(2))
+			Expect(queue.RequeueDeadLetterIDs).To(Equal([]int{1, 2}))
+			Expect(queue.Letters).To(BeEmpty())
+		})
+
+		It("pages through the dead letters", func() {
+			for id := 3; id <= services.DeadLetterPageSize+1; id++ {
+				queue.Letters[id] = gobble.DeadLetter{ID: id}
+			}
+
+			count, err := requeuer.RequeueAll()
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(count).To(Equal(services.DeadLetterPageSize + 1))
+			Expect(queue.DeadLettersArguments).To(Equal([][]int{
+				{1, services.DeadLetterPageSize},
+				{2, services.DeadLetterPageSize},
+			}))
+			Expect(queue.Letters).To(BeEmpty())
+		})
+
+		It("skips dead letters that were removed by another request", func() {
+			queue.ConcurrentlyRemovedIDs[1] = true
+
+			count, err := requeuer.RequeueAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+			Expect(queue.RequeueDeadLetterIDs).To(Equal([]int{2}))
+		})
+
+		It("returns an error when the dead letters cannot be listed", func() {
+			queue.DeadLettersError = errors.New("BOOM!")
+
+			_, err := requeuer.RequeueAll()
+			Expect(err).To(Equal(queue.DeadLettersError))
+		})
+
+		It("returns an error when a dead letter cannot be requeued", func() {
+			queue.RequeueDeadLetterError = errors.New("BOOM!")
+
+			count, err := requeuer.RequeueAll()
+			Expect(err).To(Equal(queue.RequeueDeadLetterError))
+			Expect(count).To(Equal(0))
+		})
+	})
+})
@@ -29,3 +29,15 @@ type TemplateAssignmentError string
 func (err TemplateAssignmentError) Error() string {
 	return string(err)
 }
+
+type InvalidUnsubscribeIDError string
+
+func (err InvalidUnsubscribeIDError) Error() string {
+	return string(err)
+}
+
+type InvalidDeliveryAddressError string
+
+func (err InvalidDeliveryAddressError) Error() string {
+	return string(err)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+)
+
+// MailUnsubscriber consumes the unsubscribe IDs of the requests that are
+// mailed to the List-Unsubscribe mailto address.
+type MailUnsubscriber struct {
+	unsubscriber UnsubscriberInterface
+	database     models.DatabaseInterface
+	logger       *log.Logger
+}
+
+func NewMailUnsubscriber(unsubscriber UnsubscriberInterface, database models.DatabaseInterface, logger *log.Logger) MailUnsubscriber {
+	return MailUnsubscriber{
+		unsubscriber: unsubscriber,
+		database:     database,
+		logger:       logger,
+	}
+}
+
+// HandleUnsubscribe unsubscribes the user the ID names. Requests that can
+// never succeed, because the ID is not valid or names a kind that is critical
+// or no longer exists, are logged and dropped. Only errors worth retrying the
+// request for are returned.
+func (unsubscriber MailUnsubscriber) HandleUnsubscribe(unsubscribeID string) error {
+	transaction := unsubscriber.database.Connection().Transaction()
+	transaction.Begin()
+
+	unsubscription, err := unsubscriber.unsubscriber.Unsubscribe(transaction, unsubscribeID)
+	if err != nil {
+		transaction.Rollback()
+
+		switch err.(type) {
+		case InvalidUnsubscribeIDError, MissingKindOrClientError, CriticalKindError:
+			unsubscriber.logger.Printf("Dropping mailed unsubscribe request: %s", err.Error())
+			return nil
+		default:
+			return err
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return models.NewTransactionCommitError(err.Error())
+	}
+
+	unsubscriber.logger.Printf("Unsubscribed user %s from %q notifications of %s by mail", unsubscription.UserID, unsubscription.KindID, unsubscription.ClientID)
+
+	return nil
+}
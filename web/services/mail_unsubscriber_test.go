@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MailUnsubscriber", func() {
+	var mailUnsubscriber services.MailUnsubscriber
+	var unsubscriber *fakes.Unsubscriber
+	var database *fakes.Database
+	var buffer *bytes.Buffer
+
+	BeforeEach(func() {
+		unsubscriber = fakes.NewUnsubscriber()
+		unsubscriber.Unsubscription = services.Unsubscription{
+			UserID:   "user-123",
+			ClientID: "some-client",
+			KindID:   "some-kind",
+		}
+		database = fakes.NewDatabase()
+		buffer = bytes.NewBuffer([]byte{})
+		mailUnsubscriber = services.NewMailUnsubscriber(unsubscriber, database, log.New(buffer, "", 0))
+	})
+
+	It("unsubscribes the user in a transaction", func() {
+		err := mailUnsubscriber.HandleUnsubscribe("the-unsubscribe-id")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(unsubscriber.UnsubscribeArguments).To(Equal([]string{"the-unsubscribe-id"}))
+		Expect(database.Conn.CommitWasCalled).To(BeTrue())
+		Expect(buffer.String()).To(ContainSubstring(`Unsubscribed user user-123 from "some-kind" notifications of some-client by mail`))
+	})
+
+	It("drops requests that can never succeed", func() {
+		for _, unsubscribeErr := range []error{
+			services.InvalidUnsubscribeIDError("invalid"),
+			services.MissingKindOrClientError("missing"),
+			services.CriticalKindError("critical"),
+		} {
+			unsubscriber.UnsubscribeError = unsubscribeErr
+
+			err := mailUnsubscriber.HandleUnsubscribe("the-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		}
+
+		Expect(buffer.String()).To(ContainSubstring("Dropping mailed unsubscribe request: critical"))
+	})
+
+	It("returns other errors, so that the request is retried", func() {
+		unsubscriber.UnsubscribeError = errors.New("database is down")
+
+		err := mailUnsubscriber.HandleUnsubscribe("the-unsubscribe-id")
+		Expect(err).To(MatchError("database is down"))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+	})
+
+	It("returns the error when the transaction cannot be committed", func() {
+		database.Conn.CommitError = "commit failed"
+
+		err := mailUnsubscriber.HandleUnsubscribe("the-unsubscribe-id")
+		Expect(err).To(MatchError("commit failed"))
+	})
+})
@@ -4,29 +4,52 @@ import "github.com/cloudfoundry-incubator/notifications/models"
 
 type Message struct {
 	Status string
+	Events []models.MessageEvent
 }
 
 type MessagesRepoInterface interface {
 	FindByID(models.ConnectionInterface, string) (models.Message, error)
 }
 
+type MessageEventsRepoInterface interface {
+	FindAllByMessageID(models.ConnectionInterface, string) ([]models.MessageEvent, error)
+}
+
 type MessageFinder struct {
-	repo     MessagesRepoInterface
-	database models.DatabaseInterface
+	repo       MessagesRepoInterface
+	eventsRepo MessageEventsRepoInterface
+	database   models.DatabaseInterface
 }
 
-func NewMessageFinder(repo MessagesRepoInterface, database models.DatabaseInterface) MessageFinder {
+func NewMessageFinder(repo MessagesRepoInterface, eventsRepo MessageEventsRepoInterface, database models.DatabaseInterface) MessageFinder {
 	return MessageFinder{
-		repo:     repo,
-		database: database,
+		repo:       repo,
+		eventsRepo: eventsRepo,
+		database:   database,
 	}
 }
 
-func (finder MessageFinder) Find(messageID string) (Message, error) {
-	message, err := finder.repo.FindByID(finder.database.Connection(), messageID)
+// Find returns the status and history of a message. Messages sent by other
+// clients are reported as not found.
+func (finder MessageFinder) Find(clientID, messageID string) (Message, error) {
+	conn := finder.database.Connection()
+
+	message, err := finder.repo.FindByID(conn, messageID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if message.ClientID != clientID {
+		return Message{}, models.NewRecordNotFoundError("Message with ID %q could not be found", messageID)
+	}
+
+	events, err := finder.eventsRepo.FindAllByMessageID(conn, messageID)
 	if err != nil {
 		return Message{}, err
 	}
 
-	return Message{Status: message.Status}, nil
+	return Message{
+		Status: message.Status,
+		Events: events,
+	}, nil
 }
@@ -15,33 +15,75 @@ var _ = Describe("MessageFinder.Find", func() {
 
 	var finder services.MessageFinder
 	var messagesRepo *fakes.MessagesRepo
+	var eventsRepo *fakes.MessageEventsRepo
 	var messageID string
 
 	BeforeEach(func() {
 		messagesRepo = fakes.NewMessagesRepo()
-		finder = services.NewMessageFinder(messagesRepo, fakes.NewDatabase())
+		eventsRepo = fakes.NewMessageEventsRepo()
+		finder = services.NewMessageFinder(messagesRepo, eventsRepo, fakes.NewDatabase())
 		messageID = "a-message-id"
 	})
 
 	Context("when a message exists with the given id", func() {
 		It("Returns the right Message struct", func() {
 
-			messagesRepo.Messages[messageID] = models.Message{Status: postal.StatusDelivered}
+			messagesRepo.Messages[messageID] = models.Message{ClientID: "my-client", Status: postal.StatusDelivered}
 
-			message, err := finder.Find(messageID)
+			message, err := finder.Find("my-client", messageID)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(message.Status).To(Equal(postal.StatusDelivered))
 		})
+
+		It("includes the status history of the message", func() {
+			messagesRepo.Messages[messageID] = models.Message{ClientID: "my-client", Status: postal.StatusDelivered}
+			eventsRepo.Events = []models.MessageEvent{
+				{ID: 1, MessageID: messageID, Status: postal.StatusQueued, Attempt: 0},
+				{ID: 2, MessageID: "another-message-id", Status: postal.StatusQueued, Attempt: 0},
+				{ID: 3, MessageID: messageID, Status: postal.StatusDelivered, Attempt: 1, WorkerID: "worker-1"},
+			}
+
+			message, err := finder.Find("my-client", messageID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.Events).To(Equal([]models.MessageEvent{
+				{ID: 1, MessageID: messageID, Status: postal.StatusQueued, Attempt: 0},
+				{ID: 3, MessageID: messageID, Status: postal.StatusDelivered, Attempt: 1, WorkerID: "worker-1"},
+			}))
+		})
+	})
+
+	Context("when the message was sent by another client", func() {
+		It("returns a RecordNotFoundError", func() {
+			messagesRepo.Messages[messageID] = models.Message{ClientID: "other-client", Status: postal.StatusDelivered}
+			eventsRepo.Events = []models.MessageEvent{
+				{ID: 1, MessageID: messageID, Status: postal.StatusFailed, Error: "550 no such user other@example.com"},
+			}
+
+			message, err := finder.Find("my-client", messageID)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+			Expect(message.Events).To(BeEmpty())
+		})
 	})
 
 	Context("when the underlying repo returns an error", func() {
 		It("bubbles up the error", func() {
 			messagesRepo.FindByIDError = errors.New("generic repo error (it could be anything!)")
 
-			_, err := finder.Find(messageID)
+			_, err := finder.Find("my-client", messageID)
 			Expect(err).To(MatchError(messagesRepo.FindByIDError))
 		})
 	})
 
+	Context("when the history cannot be loaded", func() {
+		It("bubbles up the error", func() {
+			messagesRepo.Messages[messageID] = models.Message{ClientID: "my-client", Status: postal.StatusDelivered}
+			eventsRepo.FindAllError = errors.New("message events table is gone")
+
+			_, err := finder.Find("my-client", messageID)
+			Expect(err).To(MatchError(eventsRepo.FindAllError))
+		})
+	})
+
 })
@@ -2,25 +2,44 @@ package services
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
 )
 
 type PreferenceUpdaterInterface interface {
 	Execute(models.ConnectionInterface, []models.Preference, bool, string) error
+	SetDeliveryAddress(models.ConnectionInterface, models.DeliveryAddress) error
+}
+
+type PreferenceUserLoaderInterface interface {
+	Load([]string, string) (map[string]uaa.User, error)
+}
+
+type PreferenceTokenLoaderInterface interface {
+	Load() (string, error)
 }
 
 type PreferenceUpdater struct {
 	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface
 	unsubscribesRepo       models.UnsubscribesRepoInterface
 	kindsRepo              models.KindsRepoInterface
+	deliveryAddressesRepo  models.DeliveryAddressesRepoInterface
+	userLoader             PreferenceUserLoaderInterface
+	tokenLoader            PreferenceTokenLoaderInterface
 }
 
-func NewPreferenceUpdater(globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface, unsubscribesRepo models.UnsubscribesRepoInterface, kindsRepo models.KindsRepoInterface) PreferenceUpdater {
+func NewPreferenceUpdater(globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface, unsubscribesRepo models.UnsubscribesRepoInterface, kindsRepo models.KindsRepoInterface,
+	deliveryAddressesRepo models.DeliveryAddressesRepoInterface, userLoader PreferenceUserLoaderInterface, tokenLoader PreferenceTokenLoaderInterface) PreferenceUpdater {
+
 	return PreferenceUpdater{
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
 		unsubscribesRepo:       unsubscribesRepo,
 		kindsRepo:              kindsRepo,
+		deliveryAddressesRepo:  deliveryAddressesRepo,
+		userLoader:             userLoader,
+		tokenLoader:            tokenLoader,
 	}
 }
 
@@ -64,3 +83,38 @@ func (updater PreferenceUpdater) Execute(conn models.ConnectionInterface, prefer
 	}
 	return nil
 }
+
+// SetDeliveryAddress stores which of the user's email addresses notifications
+// are sent to. A specific address must be one that UAA holds for the user, so
+// that users cannot direct their notifications to an address they do not own.
+func (updater PreferenceUpdater) SetDeliveryAddress(conn models.ConnectionInterface, address models.DeliveryAddress) error {
+	if address.Mode == models.DeliveryAddressSpecific {
+		token, err := updater.tokenLoader.Load()
+		if err != nil {
+			return err
+		}
+
+		users, err := updater.userLoader.Load([]string{address.UserID}, token)
+		if err != nil {
+			return err
+		}
+
+		email, ok := findEmail(users[address.UserID].Emails, address.Email)
+		if !ok {
+			return InvalidDeliveryAddressError(fmt.Sprintf("%q is not one of the email addresses of user %q", address.Email, address.UserID))
+		}
+		address.Email = email
+	}
+
+	return updater.deliveryAddressesRepo.Set(conn, address)
+}
+
+func findEmail(emails []string, email string) (string, bool) {
+	for _, candidate := range emails {
+		if strings.EqualFold(candidate, email) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
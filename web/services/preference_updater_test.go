@@ -6,6 +6,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/fakes"
 	"github.com/cloudfoundry-incubator/notifications/models"
 	"github.com/cloudfoundry-incubator/notifications/web/services"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -26,7 +27,7 @@ var _ = Describe("PreferenceUpdater", func() {
 			unsubscribesRepo = fakes.NewUnsubscribesRepo()
 			kindsRepo = fakes.NewKindsRepo()
 			fakeGlobalUnsubscribesRepo = fakes.NewGlobalUnsubscribesRepo()
-			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo, fakes.NewDeliveryAddressesRepo(), fakes.NewUserLoader(), fakes.NewTokenLoader())
 		})
 
 		Context("when globally unsubscribing", func() {
@@ -265,4 +266,65 @@ var _ = Describe("PreferenceUpdater", func() {
 			})
 		})
 	})
+
+	Describe("SetDeliveryAddress", func() {
+		var deliveryAddressesRepo *fakes.DeliveryAddressesRepo
+		var userLoader *fakes.UserLoader
+		var tokenLoader *fakes.TokenLoader
+		var conn *fakes.DBConn
+		var updater services.PreferenceUpdater
+
+		BeforeEach(func() {
+			conn = fakes.NewDBConn()
+			deliveryAddressesRepo = fakes.NewDeliveryAddressesRepo()
+			userLoader = fakes.NewUserLoader()
+			userLoader.Users["user-123"] = uaa.User{
+				ID:     "user-123",
+				Emails: []string{"personal@example.com", "Work@Example.com"},
+			}
+			tokenLoader = fakes.NewTokenLoader()
+			updater = services.NewPreferenceUpdater(fakes.NewGlobalUnsubscribesRepo(), fakes.NewUnsubscribesRepo(), fakes.NewKindsRepo(),
+				deliveryAddressesRepo, userLoader, tokenLoader)
+		})
+
+		It("stores the choice to deliver to all addresses without asking UAA", func() {
+			err := updater.SetDeliveryAddress(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressAll})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deliveryAddressesRepo.Addresses["user-123"]).To(Equal(models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressAll}))
+			Expect(tokenLoader.LoadWasCalled).To(BeFalse())
+		})
+
+		It("stores a specific address the user has in UAA, as UAA spells it", func() {
+			err := updater.SetDeliveryAddress(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "work@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(userLoader.LoadedGUIDs).To(Equal([]string{"user-123"}))
+			Expect(deliveryAddressesRepo.Addresses["user-123"]).To(Equal(models.DeliveryAddress{
+				UserID: "user-123",
+				Mode:   models.DeliveryAddressSpecific,
+				Email:  "Work@Example.com",
+			}))
+		})
+
+		It("rejects a specific address the user does not have", func() {
+			err := updater.SetDeliveryAddress(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "someone-else@example.com"})
+			Expect(err).To(BeAssignableToTypeOf(services.InvalidDeliveryAddressError("")))
+			Expect(deliveryAddressesRepo.Addresses).To(BeEmpty())
+		})
+
+		It("returns errors from UAA", func() {
+			userLoader.LoadError = errors.New("UAA is down")
+
+			err := updater.SetDeliveryAddress(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressSpecific, Email: "work@example.com"})
+			Expect(err).To(MatchError(userLoader.LoadError))
+		})
+
+		It("returns errors from the repo", func() {
+			deliveryAddressesRepo.SetError = errors.New("database is down")
+
+			err := updater.SetDeliveryAddress(conn, models.DeliveryAddress{UserID: "user-123", Mode: models.DeliveryAddressPrimary})
+			Expect(err).To(MatchError(deliveryAddressesRepo.SetError))
+		})
+	})
 })
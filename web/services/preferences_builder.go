@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/models"
 )
@@ -16,9 +17,17 @@ type Kind struct {
 type ClientMap map[string]Kind
 type ClientsMap map[string]ClientMap
 
+// DeliveryAddress selects which of a user's email addresses notifications are
+// sent to. Email is given only with the "specific" mode.
+type DeliveryAddress struct {
+	Mode  string `json:"mode"`
+	Email string `json:"email,omitempty"`
+}
+
 type PreferencesBuilder struct {
-	GlobalUnsubscribe bool       `json:"global_unsubscribe"`
-	Clients           ClientsMap `json:"clients"`
+	GlobalUnsubscribe bool             `json:"global_unsubscribe"`
+	Clients           ClientsMap       `json:"clients"`
+	DeliveryAddress   *DeliveryAddress `json:"delivery_address,omitempty"`
 }
 
 func NewPreferencesBuilder() PreferencesBuilder {
@@ -75,3 +84,32 @@ func (pref PreferencesBuilder) ToPreferences() ([]models.Preference, error) {
 
 	return preferences, nil
 }
+
+// ToDeliveryAddress returns the delivery address preference of the user, and
+// false when the preferences leave it unchanged.
+func (pref PreferencesBuilder) ToDeliveryAddress(userID string) (models.DeliveryAddress, bool, error) {
+	if pref.DeliveryAddress == nil {
+		return models.DeliveryAddress{}, false, nil
+	}
+
+	address := models.DeliveryAddress{
+		UserID: userID,
+		Mode:   pref.DeliveryAddress.Mode,
+		Email:  strings.TrimSpace(pref.DeliveryAddress.Email),
+	}
+
+	switch address.Mode {
+	case models.DeliveryAddressPrimary, models.DeliveryAddressAll:
+		if address.Email != "" {
+			return address, false, errors.New("The delivery address email is only valid with the \"specific\" mode")
+		}
+	case models.DeliveryAddressSpecific:
+		if address.Email == "" {
+			return address, false, errors.New("Missing the delivery address email field")
+		}
+	default:
+		return address, false, errors.New("The delivery address mode must be one of \"primary\", \"all\" or \"specific\"")
+	}
+
+	return address, true, nil
+}
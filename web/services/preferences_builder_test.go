@@ -223,4 +223,46 @@ var _ = Describe("NotificationsPreferences", func() {
 			})
 		})
 	})
+
+	Describe("ToDeliveryAddress", func() {
+		It("leaves the delivery address unchanged when none is given", func() {
+			_, ok, err := services.NewPreferencesBuilder().ToDeliveryAddress("user-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns the chosen delivery address of the user", func() {
+			builder := services.NewPreferencesBuilder()
+			builder.DeliveryAddress = &services.DeliveryAddress{Mode: "specific", Email: " work@example.com "}
+
+			address, ok, err := builder.ToDeliveryAddress("user-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(address).To(Equal(models.DeliveryAddress{
+				UserID: "user-123",
+				Mode:   models.DeliveryAddressSpecific,
+				Email:  "work@example.com",
+			}))
+		})
+
+		It("requires an email with the specific mode and only then", func() {
+			builder := services.NewPreferencesBuilder()
+
+			builder.DeliveryAddress = &services.DeliveryAddress{Mode: "specific"}
+			_, _, err := builder.ToDeliveryAddress("user-123")
+			Expect(err).To(MatchError("Missing the delivery address email field"))
+
+			builder.DeliveryAddress = &services.DeliveryAddress{Mode: "all", Email: "work@example.com"}
+			_, _, err = builder.ToDeliveryAddress("user-123")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects unknown modes", func() {
+			builder := services.NewPreferencesBuilder()
+			builder.DeliveryAddress = &services.DeliveryAddress{Mode: "every-other"}
+
+			_, _, err := builder.ToDeliveryAddress("user-123")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
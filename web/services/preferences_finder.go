@@ -5,6 +5,7 @@ import "github.com/cloudfoundry-incubator/notifications/models"
 type PreferencesFinder struct {
 	preferencesRepo        models.PreferencesRepoInterface
 	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface
+	deliveryAddressesRepo  models.DeliveryAddressesRepoInterface
 	database               models.DatabaseInterface
 }
 
@@ -12,10 +13,13 @@ type PreferencesFinderInterface interface {
 	Find(string) (PreferencesBuilder, error)
 }
 
-func NewPreferencesFinder(preferencesRepo models.PreferencesRepoInterface, globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface, database models.DatabaseInterface) *PreferencesFinder {
+func NewPreferencesFinder(preferencesRepo models.PreferencesRepoInterface, globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface,
+	deliveryAddressesRepo models.DeliveryAddressesRepoInterface, database models.DatabaseInterface) *PreferencesFinder {
+
 	return &PreferencesFinder{
 		preferencesRepo:        preferencesRepo,
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
+		deliveryAddressesRepo:  deliveryAddressesRepo,
 		database:               database,
 	}
 }
@@ -34,7 +38,16 @@ func (finder PreferencesFinder) Find(userGUID string) (PreferencesBuilder, error
 		return builder, err
 	}
 
+	deliveryAddress, err := finder.deliveryAddressesRepo.Get(conn, userGUID)
+	if err != nil {
+		return builder, err
+	}
+
 	builder.GlobalUnsubscribe = globallyUnsubscribed
+	builder.DeliveryAddress = &DeliveryAddress{
+		Mode:  deliveryAddress.Mode,
+		Email: deliveryAddress.Email,
+	}
 	for _, preference := range preferences {
 		builder.Add(preference)
 	}
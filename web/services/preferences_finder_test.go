@@ -14,6 +14,7 @@ import (
 var _ = Describe("PreferencesFinder", func() {
 	var finder *services.PreferencesFinder
 	var preferencesRepo *fakes.PreferencesRepo
+	var deliveryAddressesRepo *fakes.DeliveryAddressesRepo
 	var preferences []models.Preference
 
 	BeforeEach(func() {
@@ -40,7 +41,8 @@ var _ = Describe("PreferencesFinder", func() {
 		fakeGlobalUnsubscribesRepo.Set(fakes.NewDBConn(), "correct-user", true)
 		preferencesRepo = fakes.NewPreferencesRepo(preferences)
 		fakeDatabase := fakes.NewDatabase()
-		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo, fakeDatabase)
+		deliveryAddressesRepo = fakes.NewDeliveryAddressesRepo()
+		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo, deliveryAddressesRepo, fakeDatabase)
 	})
 
 	Describe("Find", func() {
@@ -49,6 +51,7 @@ var _ = Describe("PreferencesFinder", func() {
 			expectedResult.Add(preferences[0])
 			expectedResult.Add(preferences[1])
 			expectedResult.GlobalUnsubscribe = true
+			expectedResult.DeliveryAddress = &services.DeliveryAddress{Mode: models.DeliveryAddressPrimary}
 
 			resultPreferences, err := finder.Find("correct-user")
 			if err != nil {
@@ -58,6 +61,30 @@ var _ = Describe("PreferencesFinder", func() {
 			Expect(resultPreferences).To(Equal(expectedResult))
 		})
 
+		It("returns the delivery address the user chose", func() {
+			deliveryAddressesRepo.Addresses["correct-user"] = models.DeliveryAddress{
+				UserID: "correct-user",
+				Mode:   models.DeliveryAddressSpecific,
+				Email:  "work@example.com",
+			}
+
+			resultPreferences, err := finder.Find("correct-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resultPreferences.DeliveryAddress).To(Equal(&services.DeliveryAddress{
+				Mode:  models.DeliveryAddressSpecific,
+				Email: "work@example.com",
+			}))
+		})
+
+		Context("when the delivery addresses repo returns an error", func() {
+			It("should propagate the error", func() {
+				deliveryAddressesRepo.GetError = errors.New("BOOM!")
+				_, err := finder.Find("correct-user")
+
+				Expect(err).To(Equal(deliveryAddressesRepo.GetError))
+			})
+		})
+
 		Context("when the preferences repo returns an error", func() {
 			It("should propagate the error", func() {
 				preferencesRepo.FindError = errors.New("BOOM!")
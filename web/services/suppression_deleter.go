@@ -0,0 +1,23 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type SuppressionDeleterInterface interface {
+	Delete(string) error
+}
+
+type SuppressionDeleter struct {
+	suppressionsRepo models.SuppressionsRepoInterface
+	database         models.DatabaseInterface
+}
+
+func NewSuppressionDeleter(suppressionsRepo models.SuppressionsRepoInterface, database models.DatabaseInterface) SuppressionDeleter {
+	return SuppressionDeleter{
+		suppressionsRepo: suppressionsRepo,
+		database:         database,
+	}
+}
+
+func (deleter SuppressionDeleter) Delete(email string) error {
+	return deleter.suppressionsRepo.Destroy(deleter.database.Connection(), email)
+}
@@ -0,0 +1,35 @@
+package services_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionDeleter", func() {
+	var deleter services.SuppressionDeleter
+	var suppressionsRepo *fakes.SuppressionsRepo
+
+	BeforeEach(func() {
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		deleter = services.NewSuppressionDeleter(suppressionsRepo, fakes.NewDatabase())
+
+		suppressionsRepo.Suppressions["user@example.com"] = models.Suppression{Email: "user@example.com", Reason: "complained"}
+	})
+
+	Describe("Delete", func() {
+		It("removes the address from the suppression list", func() {
+			err := deleter.Delete("User@Example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressionsRepo.Suppressions).To(BeEmpty())
+		})
+
+		It("returns a RecordNotFoundError when the address is not suppressed", func() {
+			err := deleter.Delete("other@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type SuppressionFinderInterface interface {
+	List() ([]models.Suppression, error)
+	Find(string) (models.Suppression, error)
+}
+
+type SuppressionFinder struct {
+	suppressionsRepo models.SuppressionsRepoInterface
+	database         models.DatabaseInterface
+}
+
+func NewSuppressionFinder(suppressionsRepo models.SuppressionsRepoInterface, database models.DatabaseInterface) SuppressionFinder {
+	return SuppressionFinder{
+		suppressionsRepo: suppressionsRepo,
+		database:         database,
+	}
+}
+
+func (finder SuppressionFinder) List() ([]models.Suppression, error) {
+	return finder.suppressionsRepo.FindAll(finder.database.Connection())
+}
+
+func (finder SuppressionFinder) Find(email string) (models.Suppression, error) {
+	return finder.suppressionsRepo.Find(finder.database.Connection(), email)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionFinder", func() {
+	var finder services.SuppressionFinder
+	var suppressionsRepo *fakes.SuppressionsRepo
+
+	BeforeEach(func() {
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		finder = services.NewSuppressionFinder(suppressionsRepo, fakes.NewDatabase())
+
+		suppressionsRepo.Suppressions["second@example.com"] = models.Suppression{Email: "second@example.com", Reason: "complained"}
+		suppressionsRepo.Suppressions["first@example.com"] = models.Suppression{Email: "first@example.com", Reason: "bounced"}
+	})
+
+	Describe("List", func() {
+		It("returns every suppressed address", func() {
+			suppressions, err := finder.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressions).To(Equal([]models.Suppression{
+				{Email: "first@example.com", Reason: "bounced"},
+				{Email: "second@example.com", Reason: "complained"},
+			}))
+		})
+
+		It("bubbles up errors from the repo", func() {
+			suppressionsRepo.FindAllError = errors.New("BOOM!")
+
+			_, err := finder.List()
+			Expect(err).To(MatchError(suppressionsRepo.FindAllError))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the suppression of the address", func() {
+			suppression, err := finder.Find("First@Example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(models.Suppression{Email: "first@example.com", Reason: "bounced"}))
+		})
+
+		It("returns a RecordNotFoundError when the address is not suppressed", func() {
+			_, err := finder.Find("other@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
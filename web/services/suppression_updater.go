@@ -0,0 +1,29 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type SuppressionUpdaterInterface interface {
+	Suppress(email, reason string) (models.Suppression, error)
+}
+
+type SuppressionUpdater struct {
+	suppressionsRepo models.SuppressionsRepoInterface
+	database         models.DatabaseInterface
+}
+
+func NewSuppressionUpdater(suppressionsRepo models.SuppressionsRepoInterface, database models.DatabaseInterface) SuppressionUpdater {
+	return SuppressionUpdater{
+		suppressionsRepo: suppressionsRepo,
+		database:         database,
+	}
+}
+
+// Suppress adds the address to the suppression list, or replaces the reason
+// it is already suppressed for. Suppressions added by an operator are not tied
+// to any message.
+func (updater SuppressionUpdater) Suppress(email, reason string) (models.Suppression, error) {
+	return updater.suppressionsRepo.Upsert(updater.database.Connection(), models.Suppression{
+		Email:  email,
+		Reason: reason,
+	})
+}
@@ -0,0 +1,41 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionUpdater", func() {
+	var updater services.SuppressionUpdater
+	var suppressionsRepo *fakes.SuppressionsRepo
+
+	BeforeEach(func() {
+		suppressionsRepo = fakes.NewSuppressionsRepo()
+		updater = services.NewSuppressionUpdater(suppressionsRepo, fakes.NewDatabase())
+	})
+
+	Describe("Suppress", func() {
+		It("suppresses the address for the given reason", func() {
+			suppression, err := updater.Suppress("User@Example.com", "legal request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(models.Suppression{Email: "user@example.com", Reason: "legal request"}))
+
+			Expect(suppressionsRepo.Suppressions).To(Equal(map[string]models.Suppression{
+				"user@example.com": {Email: "user@example.com", Reason: "legal request"},
+			}))
+		})
+
+		It("bubbles up errors from the repo", func() {
+			suppressionsRepo.UpsertError = errors.New("BOOM!")
+
+			_, err := updater.Suppress("user@example.com", "legal request")
+			Expect(err).To(MatchError(suppressionsRepo.UpsertError))
+		})
+	})
+})
@@ -3,26 +3,45 @@ package services
 import "github.com/cloudfoundry-incubator/notifications/models"
 
 type TemplateCreatorInterface interface {
-	Create(models.Template) (string, error)
+	Create(models.Template, string) (string, error)
 }
 
 type TemplateCreator struct {
-	repo     models.TemplatesRepoInterface
-	database models.DatabaseInterface
+	repo         models.TemplatesRepoInterface
+	versionsRepo models.TemplateVersionsRepoInterface
+	database     models.DatabaseInterface
 }
 
-func NewTemplateCreator(repo models.TemplatesRepoInterface, database models.DatabaseInterface) TemplateCreator {
+func NewTemplateCreator(repo models.TemplatesRepoInterface, versionsRepo models.TemplateVersionsRepoInterface, database models.DatabaseInterface) TemplateCreator {
 	return TemplateCreator{
-		repo:     repo,
-		database: database,
+		repo:         repo,
+		versionsRepo: versionsRepo,
+		database:     database,
 	}
 }
 
-func (creator TemplateCreator) Create(template models.Template) (string, error) {
-	newTemplate, err := creator.repo.Create(creator.database.Connection(), template)
+// Create saves a new template, along with its first version, which records
+// the client that created it.
+func (creator TemplateCreator) Create(template models.Template, author string) (string, error) {
+	transaction := creator.database.Connection().Transaction()
+	transaction.Begin()
+
+	newTemplate, err := creator.repo.Create(transaction, template)
+	if err != nil {
+		transaction.Rollback()
+		return "", err
+	}
+
+	_, err = creator.versionsRepo.Create(transaction, models.NewTemplateVersion(newTemplate, author))
 	if err != nil {
+		transaction.Rollback()
 		return "", err
 	}
 
+	err = transaction.Commit()
+	if err != nil {
+		return "", models.NewTransactionCommitError(err.Error())
+	}
+
 	return newTemplate.ID, nil
 }
@@ -13,11 +13,15 @@ import (
 var _ = Describe("Creator", func() {
 	Describe("Create", func() {
 		var templatesRepo *fakes.TemplatesRepo
+		var versionsRepo *fakes.TemplateVersionsRepo
+		var database *fakes.Database
 		var template models.Template
 		var creator services.TemplateCreator
 
 		BeforeEach(func() {
 			templatesRepo = fakes.NewTemplatesRepo()
+			versionsRepo = fakes.NewTemplateVersionsRepo()
+			database = fakes.NewDatabase()
 			template = models.Template{
 				Name:    "Big Hero 6 Template",
 				Text:    "Adorable robot.",
@@ -25,27 +29,52 @@ var _ = Describe("Creator", func() {
 				Subject: "Robots and Heroes",
 			}
 
-			creator = services.NewTemplateCreator(templatesRepo, fakes.NewDatabase())
+			creator = services.NewTemplateCreator(templatesRepo, versionsRepo, database)
 		})
 
 		It("Creates a new template via the templates repo", func() {
 			Expect(templatesRepo.Templates).ToNot(ContainElement(template))
-			_, err := creator.Create(template)
+			_, err := creator.Create(template, "my-client")
 			if err != nil {
 				panic(err)
 			}
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(templatesRepo.Templates).To(ContainElement(template))
+			Expect(database.Conn.CommitWasCalled).To(BeTrue())
+		})
+
+		It("records the template as its first version, written by the client", func() {
+			_, err := creator.Create(template, "my-client")
+			if err != nil {
+				panic(err)
+			}
+
+			Expect(versionsRepo.Created).To(HaveLen(1))
+			Expect(versionsRepo.Created[0].Version).To(Equal(1))
+			Expect(versionsRepo.Created[0].Name).To(Equal("Big Hero 6 Template"))
+			Expect(versionsRepo.Created[0].HTML).To(Equal("<p>Many heroes.</p>"))
+			Expect(versionsRepo.Created[0].Author).To(Equal("my-client"))
 		})
 
 		It("propagates errors from repo", func() {
 			expectedErr := errors.New("Boom!")
 
 			templatesRepo.CreateError = expectedErr
-			_, err := creator.Create(template)
+			_, err := creator.Create(template, "my-client")
 
 			Expect(err).To(Equal(expectedErr))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		})
+
+		It("rolls back the template when its version cannot be recorded", func() {
+			versionsRepo.CreateError = errors.New("Boom!")
+
+			_, err := creator.Create(template, "my-client")
+
+			Expect(err).To(Equal(versionsRepo.CreateError))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+			Expect(database.Conn.CommitWasCalled).To(BeFalse())
 		})
 	})
 })
@@ -0,0 +1,54 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type TemplateRestorerInterface interface {
+	Restore(string, int, string) (models.TemplateVersion, error)
+}
+
+type TemplateRestorer struct {
+	templatesRepo models.TemplatesRepoInterface
+	versionsRepo  models.TemplateVersionsRepoInterface
+	database      models.DatabaseInterface
+}
+
+func NewTemplateRestorer(templatesRepo models.TemplatesRepoInterface, versionsRepo models.TemplateVersionsRepoInterface, database models.DatabaseInterface) TemplateRestorer {
+	return TemplateRestorer{
+		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+		database:      database,
+	}
+}
+
+// Restore makes the content of an earlier version the current content of the
+// template. Versions are never rewritten, so the restored content is recorded
+// as a new version, written by the given client, which is returned.
+func (restorer TemplateRestorer) Restore(templateID string, number int, author string) (models.TemplateVersion, error) {
+	transaction := restorer.database.Connection().Transaction()
+	transaction.Begin()
+
+	version, err := restorer.versionsRepo.FindByVersion(transaction, templateID, number)
+	if err != nil {
+		transaction.Rollback()
+		return models.TemplateVersion{}, err
+	}
+
+	template, err := restorer.templatesRepo.Update(transaction, templateID, version.ToTemplate())
+	if err != nil {
+		transaction.Rollback()
+		return models.TemplateVersion{}, err
+	}
+
+	restored, err := restorer.versionsRepo.Create(transaction, models.NewTemplateVersion(template, author))
+	if err != nil {
+		transaction.Rollback()
+		return models.TemplateVersion{}, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return models.TemplateVersion{}, models.NewTransactionCommitError(err.Error())
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateRestorer", func() {
+	var restorer services.TemplateRestorer
+	var templatesRepo *fakes.TemplatesRepo
+	var versionsRepo *fakes.TemplateVersionsRepo
+	var database *fakes.Database
+
+	BeforeEach(func() {
+		templatesRepo = fakes.NewTemplatesRepo()
+		templatesRepo.Templates["template-id"] = models.Template{ID: "template-id", Name: "broken", HTML: "{{"}
+
+		versionsRepo = fakes.NewTemplateVersionsRepo()
+		versionsRepo.Versions["template-id"] = []models.TemplateVersion{
+			{TemplateID: "template-id", Version: 1, Name: "good", Subject: "{{.Subject}}", Text: "{{.Text}}", HTML: "{{.HTML}}", Metadata: "{}", Author: "designer"},
+			{TemplateID: "template-id", Version: 2, Name: "broken", HTML: "{{", Author: "intern"},
+		}
+
+		database = fakes.NewDatabase()
+		restorer = services.NewTemplateRestorer(templatesRepo, versionsRepo, database)
+	})
+
+	It("makes the content of the version the current content of the template", func() {
+		_, err := restorer.Restore("template-id", 1, "my-client")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(templatesRepo.Templates["template-id"]).To(Equal(models.Template{
+			ID:       "template-id",
+			Name:     "good",
+			Subject:  "{{.Subject}}",
+			Text:     "{{.Text}}",
+			HTML:     "{{.HTML}}",
+			Metadata: "{}",
+		}))
+		Expect(database.Conn.CommitWasCalled).To(BeTrue())
+	})
+
+	It("records the restored content as a new version, written by the client", func() {
+		restored, err := restorer.Restore("template-id", 1, "my-client")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(restored.Version).To(Equal(3))
+		Expect(restored.Name).To(Equal("good"))
+		Expect(restored.Author).To(Equal("my-client"))
+		Expect(versionsRepo.Versions["template-id"]).To(HaveLen(3))
+		Expect(versionsRepo.Versions["template-id"][0].Author).To(Equal("designer"))
+	})
+
+	It("returns an error when the version does not exist", func() {
+		_, err := restorer.Restore("template-id", 7, "my-client")
+
+		Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+	})
+
+	It("rolls back when the template cannot be updated", func() {
+		templatesRepo.UpdateError = errors.New("Boom!")
+
+		_, err := restorer.Restore("template-id", 1, "my-client")
+
+		Expect(err).To(Equal(templatesRepo.UpdateError))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		Expect(versionsRepo.Versions["template-id"]).To(HaveLen(2))
+	})
+
+	It("rolls back when the new version cannot be recorded", func() {
+		versionsRepo.CreateError = errors.New("Boom!")
+
+		_, err := restorer.Restore("template-id", 1, "my-client")
+
+		Expect(err).To(Equal(versionsRepo.CreateError))
+		Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+		Expect(database.Conn.CommitWasCalled).To(BeFalse())
+	})
+})
@@ -3,25 +3,45 @@ package services
 import "github.com/cloudfoundry-incubator/notifications/models"
 
 type TemplateUpdaterInterface interface {
-	Update(string, models.Template) error
+	Update(string, models.Template, string) error
 }
 
 type TemplateUpdater struct {
-	repo     models.TemplatesRepoInterface
-	database models.DatabaseInterface
+	repo         models.TemplatesRepoInterface
+	versionsRepo models.TemplateVersionsRepoInterface
+	database     models.DatabaseInterface
 }
 
-func NewTemplateUpdater(repo models.TemplatesRepoInterface, database models.DatabaseInterface) TemplateUpdater {
+func NewTemplateUpdater(repo models.TemplatesRepoInterface, versionsRepo models.TemplateVersionsRepoInterface, database models.DatabaseInterface) TemplateUpdater {
 	return TemplateUpdater{
-		repo:     repo,
-		database: database,
+		repo:         repo,
+		versionsRepo: versionsRepo,
+		database:     database,
 	}
 }
 
-func (updater TemplateUpdater) Update(templateID string, template models.Template) error {
-	_, err := updater.repo.Update(updater.database.Connection(), templateID, template)
+// Update replaces the content of a template and records it as the next
+// version, written by the given client.
+func (updater TemplateUpdater) Update(templateID string, template models.Template, author string) error {
+	transaction := updater.database.Connection().Transaction()
+	transaction.Begin()
+
+	updatedTemplate, err := updater.repo.Update(transaction, templateID, template)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	_, err = updater.versionsRepo.Create(transaction, models.NewTemplateVersion(updatedTemplate, author))
 	if err != nil {
+		transaction.Rollback()
 		return err
 	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return models.NewTransactionCommitError(err.Error())
+	}
+
 	return nil
 }
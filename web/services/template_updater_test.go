@@ -13,34 +13,62 @@ import (
 var _ = Describe("Updater", func() {
 	Describe("#Update", func() {
 		var templatesRepo *fakes.TemplatesRepo
+		var versionsRepo *fakes.TemplateVersionsRepo
+		var database *fakes.Database
 		var template models.Template
 		var updater services.TemplateUpdater
 
 		BeforeEach(func() {
 			templatesRepo = fakes.NewTemplatesRepo()
+			versionsRepo = fakes.NewTemplateVersionsRepo()
+			database = fakes.NewDatabase()
 			template = models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
 			}
 
-			updater = services.NewTemplateUpdater(templatesRepo, fakes.NewDatabase())
+			updater = services.NewTemplateUpdater(templatesRepo, versionsRepo, database)
 		})
 
 		It("Inserts templates into the templates repo", func() {
 			Expect(templatesRepo.Templates).ToNot(ContainElement(template))
-			err := updater.Update("my-awesome-id", template)
+			err := updater.Update("my-awesome-id", template, "my-client")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(templatesRepo.Templates).To(ContainElement(template))
+			Expect(database.Conn.CommitWasCalled).To(BeTrue())
+		})
+
+		It("records the update as a version, written by the client", func() {
+			err := updater.Update("my-awesome-id", template, "my-client")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versionsRepo.Created).To(HaveLen(1))
+			Expect(versionsRepo.Created[0].Name).To(Equal("gobble template"))
+			Expect(versionsRepo.Created[0].Text).To(Equal("gobble"))
+			Expect(versionsRepo.Created[0].HTML).To(Equal("<p>gobble</p>"))
+			Expect(versionsRepo.Created[0].Author).To(Equal("my-client"))
 		})
 
 		It("propagates errors from repo", func() {
 			expectedErr := errors.New("Boom!")
 
 			templatesRepo.UpdateError = expectedErr
-			err := updater.Update("unimportant", template)
+			err := updater.Update("unimportant", template, "my-client")
 
 			Expect(err).To(Equal(expectedErr))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+			Expect(versionsRepo.Created).To(BeEmpty())
+		})
+
+		It("rolls back the update when its version cannot be recorded", func() {
+			versionsRepo.CreateError = errors.New("Boom!")
+
+			err := updater.Update("my-awesome-id", template, "my-client")
+
+			Expect(err).To(Equal(versionsRepo.CreateError))
+			Expect(database.Conn.RollbackWasCalled).To(BeTrue())
+			Expect(database.Conn.CommitWasCalled).To(BeFalse())
 		})
 	})
 })
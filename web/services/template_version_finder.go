@@ -0,0 +1,46 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/models"
+
+type TemplateVersionFinderInterface interface {
+	FindAll(string) ([]models.TemplateVersion, error)
+	Find(string, int) (models.TemplateVersion, error)
+}
+
+type TemplateVersionFinder struct {
+	templatesRepo models.TemplatesRepoInterface
+	versionsRepo  models.TemplateVersionsRepoInterface
+	database      models.DatabaseInterface
+}
+
+func NewTemplateVersionFinder(templatesRepo models.TemplatesRepoInterface, versionsRepo models.TemplateVersionsRepoInterface, database models.DatabaseInterface) TemplateVersionFinder {
+	return TemplateVersionFinder{
+		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+		database:      database,
+	}
+}
+
+// FindAll returns the versions of a template, newest first. Templates that
+// have been deleted are not found.
+func (finder TemplateVersionFinder) FindAll(templateID string) ([]models.TemplateVersion, error) {
+	conn := finder.database.Connection()
+
+	_, err := finder.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return []models.TemplateVersion{}, err
+	}
+
+	return finder.versionsRepo.FindAllByTemplateID(conn, templateID)
+}
+
+func (finder TemplateVersionFinder) Find(templateID string, number int) (models.TemplateVersion, error) {
+	conn := finder.database.Connection()
+
+	_, err := finder.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return models.TemplateVersion{}, err
+	}
+
+	return finder.versionsRepo.FindByVersion(conn, templateID, number)
+}
@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionFinder", func() {
+	var finder services.TemplateVersionFinder
+	var templatesRepo *fakes.TemplatesRepo
+	var versionsRepo *fakes.TemplateVersionsRepo
+
+	BeforeEach(func() {
+		templatesRepo = fakes.NewTemplatesRepo()
+		templatesRepo.Templates["template-id"] = models.Template{ID: "template-id", Name: "current"}
+
+		versionsRepo = fakes.NewTemplateVersionsRepo()
+		versionsRepo.Versions["template-id"] = []models.TemplateVersion{
+			{TemplateID: "template-id", Version: 1, Name: "first"},
+			{TemplateID: "template-id", Version: 2, Name: "current"},
+		}
+
+		finder = services.NewTemplateVersionFinder(templatesRepo, versionsRepo, fakes.NewDatabase())
+	})
+
+	Describe("FindAll", func() {
+		It("returns the versions of the template, newest first", func() {
+			versions, err := finder.FindAll("template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]models.TemplateVersion{
+				{TemplateID: "template-id", Version: 2, Name: "current"},
+				{TemplateID: "template-id", Version: 1, Name: "first"},
+			}))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			_, err := finder.FindAll("missing-id")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
+		It("propagates errors from the versions repo", func() {
+			versionsRepo.FindAllError = errors.New("Boom!")
+
+			_, err := finder.FindAll("template-id")
+			Expect(err).To(Equal(versionsRepo.FindAllError))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the numbered version of the template", func() {
+			version, err := finder.Find("template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Name).To(Equal("first"))
+		})
+
+		It("returns an error when the version does not exist", func() {
+			_, err := finder.Find("template-id", 3)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			_, err := finder.Find("missing-id", 1)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError("")))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+type Unsubscription struct {
+	UserID          string
+	ClientID        string
+	KindID          string
+	KindDescription string
+}
+
+type UnsubscriberInterface interface {
+	Find(models.ConnectionInterface, string) (Unsubscription, error)
+	Unsubscribe(models.ConnectionInterface, string) (Unsubscription, error)
+}
+
+// Unsubscriber consumes the unsubscribe IDs that are embedded in delivered
+// messages. An unsubscribe ID is the veiled form of "userGUID|clientID|kindID".
+type Unsubscriber struct {
+	cloak                  conceal.CloakInterface
+	preferenceUpdater      PreferenceUpdaterInterface
+	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface
+	kindsRepo              models.KindsRepoInterface
+}
+
+func NewUnsubscriber(cloak conceal.CloakInterface, preferenceUpdater PreferenceUpdaterInterface,
+	globalUnsubscribesRepo models.GlobalUnsubscribesRepoInterface, kindsRepo models.KindsRepoInterface) Unsubscriber {
+
+	return Unsubscriber{
+		cloak:                  cloak,
+		preferenceUpdater:      preferenceUpdater,
+		globalUnsubscribesRepo: globalUnsubscribesRepo,
+		kindsRepo:              kindsRepo,
+	}
+}
+
+// Find describes the unsubscription an unsubscribe ID stands for, without
+// consuming it. Like Unsubscribe, it fails when the kind no longer exists or is
+// critical.
+func (unsubscriber Unsubscriber) Find(conn models.ConnectionInterface, unsubscribeID string) (Unsubscription, error) {
+	unsubscription, err := unsubscriber.parse(unsubscribeID)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	kind, err := unsubscriber.kindsRepo.Find(conn, unsubscription.KindID, unsubscription.ClientID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return Unsubscription{}, MissingKindOrClientError(fmt.Sprintf("The kind '%s' cannot be found for client '%s'", unsubscription.KindID, unsubscription.ClientID))
+		}
+		return Unsubscription{}, err
+	}
+
+	if kind.Critical {
+		return Unsubscription{}, CriticalKindError(fmt.Sprintf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", unsubscription.KindID, unsubscription.ClientID))
+	}
+
+	unsubscription.KindDescription = describeKind(kind)
+
+	return unsubscription, nil
+}
+
+func (unsubscriber Unsubscriber) Unsubscribe(conn models.ConnectionInterface, unsubscribeID string) (Unsubscription, error) {
+	unsubscription, err := unsubscriber.parse(unsubscribeID)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	globalUnsubscribe, err := unsubscriber.globalUnsubscribesRepo.Get(conn, unsubscription.UserID)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	err = unsubscriber.preferenceUpdater.Execute(conn, []models.Preference{
+		{
+			ClientID: unsubscription.ClientID,
+			KindID:   unsubscription.KindID,
+			Email:    false,
+		},
+	}, globalUnsubscribe, unsubscription.UserID)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	unsubscription.KindDescription = unsubscription.KindID
+	kind, err := unsubscriber.kindsRepo.Find(conn, unsubscription.KindID, unsubscription.ClientID)
+	if err == nil {
+		unsubscription.KindDescription = describeKind(kind)
+	}
+
+	return unsubscription, nil
+}
+
+func describeKind(kind models.Kind) string {
+	if kind.Description != "" {
+		return kind.Description
+	}
+
+	return kind.ID
+}
+
+func (unsubscriber Unsubscriber) parse(unsubscribeID string) (Unsubscription, error) {
+	invalid := InvalidUnsubscribeIDError("The unsubscribe link is not valid")
+
+	decoded, err := unsubscriber.cloak.Unveil([]byte(unsubscribeID))
+	if err != nil {
+		return Unsubscription{}, invalid
+	}
+
+	parts := strings.Split(string(decoded), "|")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return Unsubscription{}, invalid
+	}
+
+	return Unsubscription{
+		UserID:   parts[0],
+		ClientID: parts[1],
+		KindID:   parts[2],
+	}, nil
+}
@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/fakes"
+	"github.com/cloudfoundry-incubator/notifications/models"
+	"github.com/cloudfoundry-incubator/notifications/web/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unsubscriber", func() {
+	var unsubscriber services.Unsubscriber
+	var cloak *fakes.Cloak
+	var preferenceUpdater *fakes.PreferenceUpdater
+	var globalUnsubscribesRepo *fakes.GlobalUnsubscribesRepo
+	var kindsRepo *fakes.KindsRepo
+	var conn *fakes.DBConn
+
+	BeforeEach(func() {
+		conn = fakes.NewDBConn()
+		cloak = &fakes.Cloak{
+			DecryptedResult: []byte("user-123|some-client|some-kind"),
+		}
+		preferenceUpdater = fakes.NewPreferenceUpdater()
+		globalUnsubscribesRepo = fakes.NewGlobalUnsubscribesRepo()
+		kindsRepo = fakes.NewKindsRepo()
+		unsubscriber = services.NewUnsubscriber(cloak, preferenceUpdater, globalUnsubscribesRepo, kindsRepo)
+	})
+
+	Describe("Unsubscribe", func() {
+		It("unsubscribes the user from the kind named in the unsubscribe ID", func() {
+			unsubscription, err := unsubscriber.Unsubscribe(conn, "the-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloak.DataToDecrypt).To(Equal([]byte("the-unsubscribe-id")))
+			Expect(preferenceUpdater.ExecuteArguments).To(Equal([]interface{}{
+				[]models.Preference{
+					{
+						ClientID: "some-client",
+						KindID:   "some-kind",
+						Email:    false,
+					},
+				},
+				false,
+				"user-123",
+			}))
+			Expect(unsubscription).To(Equal(services.Unsubscription{
+				UserID:          "user-123",
+				ClientID:        "some-client",
+				KindID:          "some-kind",
+				KindDescription: "some-kind",
+			}))
+		})
+
+		It("keeps the user globally unsubscribed when they already are", func() {
+			err := globalUnsubscribesRepo.Set(conn, "user-123", true)
+			if err != nil {
+				panic(err)
+			}
+
+			_, err = unsubscriber.Unsubscribe(conn, "the-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(preferenceUpdater.ExecuteArguments[1]).To(BeTrue())
+		})
+
+		It("describes the kind when it has a description", func() {
+			_, err := kindsRepo.Create(conn, models.Kind{
+				ID:          "some-kind",
+				ClientID:    "some-client",
+				Description: "Daily digest",
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			unsubscription, err := unsubscriber.Unsubscribe(conn, "the-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unsubscription.KindDescription).To(Equal("Daily digest"))
+		})
+
+		Context("when the unsubscribe ID cannot be unveiled", func() {
+			It("returns an InvalidUnsubscribeIDError", func() {
+				cloak.UnveilError = errors.New("illegal base64 data")
+
+				_, err := unsubscriber.Unsubscribe(conn, "garbage")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError("")))
+				Expect(preferenceUpdater.ExecuteArguments).To(BeEmpty())
+			})
+		})
+
+		Context("when the unsubscribe ID is missing a part", func() {
+			It("returns an InvalidUnsubscribeIDError", func() {
+				for _, decrypted := range []string{"user-123|some-client", "|some-client|some-kind", "user-123|some-client|"} {
+					cloak.DecryptedResult = []byte(decrypted)
+
+					_, err := unsubscriber.Unsubscribe(conn, "the-unsubscribe-id")
+					Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError("")))
+				}
+				Expect(preferenceUpdater.ExecuteArguments).To(BeEmpty())
+			})
+		})
+
+		Context("when the preference cannot be updated", func() {
+			It("returns the error", func() {
+				preferenceUpdater.ExecuteError = services.CriticalKindError("critical")
+
+				_, err := unsubscriber.Unsubscribe(conn, "the-unsubscribe-id")
+				Expect(err).To(Equal(services.CriticalKindError("critical")))
+			})
+		})
+	})
+
+	Describe("Find", func() {
+		BeforeEach(func() {
+			_, err := kindsRepo.Create(conn, models.Kind{
+				ID:          "some-kind",
+				ClientID:    "some-client",
+				Description: "Daily digest",
+			})
+			if err != nil {
+				panic(err)
+			}
+		})
+
+		It("describes the unsubscription without unsubscribing the user", func() {
+			unsubscription, err := unsubscriber.Find(conn, "the-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(unsubscription).To(Equal(services.Unsubscription{
+				UserID:          "user-123",
+				ClientID:        "some-client",
+				KindID:          "some-kind",
+				KindDescription: "Daily digest",
+			}))
+			Expect(preferenceUpdater.ExecuteArguments).To(BeEmpty())
+		})
+
+		It("returns an InvalidUnsubscribeIDError when the unsubscribe ID is not valid", func() {
+			cloak.UnveilError = errors.New("illegal base64 data")
+
+			_, err := unsubscriber.Find(conn, "garbage")
+			Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError("")))
+		})
+
+		It("returns a MissingKindOrClientError when the kind no longer exists", func() {
+			cloak.DecryptedResult = []byte("user-123|some-client|another-kind")
+
+			_, err := unsubscriber.Find(conn, "the-unsubscribe-id")
+			Expect(err).To(BeAssignableToTypeOf(services.MissingKindOrClientError("")))
+		})
+
+		It("returns a CriticalKindError when the kind is critical", func() {
+			_, err := kindsRepo.Update(conn, models.Kind{
+				ID:       "some-kind",
+				ClientID: "some-client",
+				Critical: true,
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			_, err = unsubscriber.Find(conn, "the-unsubscribe-id")
+			Expect(err).To(BeAssignableToTypeOf(services.CriticalKindError("")))
+		})
+	})
+})